@@ -169,18 +169,19 @@ var File_app_v1_authn_authn_proto protoreflect.FileDescriptor
 
 const file_app_v1_authn_authn_proto_rawDesc = "" +
 	"\n" +
-	"\x18app/v1/authn/authn.proto\x12\x06app.v1\x1a\x15kernel/resource.proto\"\xb6\x01\n" +
+	"\x18app/v1/authn/authn.proto\x12\x06app.v1\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xb6\x01\n" +
 	"\x18AuthenticationMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x128\n" +
-	"\x04Spec\x18\x04 \x01(\v2$.app.v1.AuthenticationMiddlewareSpecR\x04spec\"\xaf\x01\n" +
-	"\x1cAuthenticationMiddlewareSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12-\n" +
-	"\bHandlers\x18\x03 \x03(\v2\x11.kernel.ReferenceR\bhandlersB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
+	"\x04Spec\x18\x04 \x01(\v2$.app.v1.AuthenticationMiddlewareSpecR\x04spec\"\xf5\x01\n" +
+	"\x1cAuthenticationMiddlewareSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12L\n" +
+	"\bHandlers\x18\x03 \x03(\v2\x11.kernel.ReferenceB\x1d\xd2\xf3\x18\x19app.AuthenticationHandlerR\bhandlersB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
 	file_app_v1_authn_authn_proto_rawDescOnce sync.Once
@@ -453,22 +453,23 @@ var File_app_v1_authn_basic_proto protoreflect.FileDescriptor
 
 const file_app_v1_authn_basic_proto_rawDesc = "" +
 	"\n" +
-	"\x18app/v1/authn/basic.proto\x12\x06app.v1\x1a\x16kernel/commonkey.proto\x1a\x15kernel/password.proto\x1a\x15kernel/resource.proto\x1a\x15kernel/encoding.proto\"\xae\x01\n" +
+	"\x18app/v1/authn/basic.proto\x12\x06app.v1\x1a\x16kernel/commonkey.proto\x1a\x15kernel/password.proto\x1a\x15kernel/resource.proto\x1a\x15kernel/encoding.proto\x1a\x14kernel/options.proto\"\xae\x01\n" +
 	"\x14BasicAuthnMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x124\n" +
-	"\x04Spec\x18\x04 \x01(\v2 .app.v1.BasicAuthnMiddlewareSpecR\x04spec\"\xc1\x04\n" +
-	"\x18BasicAuthnMiddlewareSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12\x1c\n" +
+	"\x04Spec\x18\x04 \x01(\v2 .app.v1.BasicAuthnMiddlewareSpecR\x04spec\"\xee\x04\n" +
+	"\x18BasicAuthnMiddlewareSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12\x1c\n" +
 	"\tClaimsKey\x18\x03 \x01(\tR\tclaimsKey\x12(\n" +
 	"\x0fKeepCredentials\x18\x04 \x01(\bR\x0fkeepCredentials\x12?\n" +
 	"\rPasswordCrypt\x18\x05 \x01(\v2\x19.kernel.PasswordCryptSpecR\rpasswordCrypt\x12J\n" +
-	"\x12CommonKeyCryptType\x18\x06 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12 \n" +
-	"\vCryptSecret\x18\a \x01(\tR\vcryptSecret\x12\x14\n" +
+	"\x12CommonKeyCryptType\x18\x06 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12&\n" +
+	"\vCryptSecret\x18\a \x01(\tB\x04\xc0\xf3\x18\x01R\vcryptSecret\x12\x14\n" +
 	"\x05Realm\x18\b \x01(\tR\x05realm\x12 \n" +
 	"\vPreferError\x18\t \x01(\bR\vpreferError\x12A\n" +
 	"\vEnvProvider\x18\n" +
@@ -478,9 +479,9 @@ const file_app_v1_authn_basic_proto_rawDesc = "" +
 	"\x15BasicAuthnEnvProvider\x12&\n" +
 	"\x0eUsernamePrefix\x18\x01 \x01(\tR\x0eusernamePrefix\x12&\n" +
 	"\x0ePasswordPrefix\x18\x02 \x01(\tR\x0epasswordPrefix\x120\n" +
-	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"`\n" +
-	"\x16BasicAuthnFileProvider\x12\x14\n" +
-	"\x05Paths\x18\x01 \x03(\tR\x05paths\x120\n" +
+	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"f\n" +
+	"\x16BasicAuthnFileProvider\x12\x1a\n" +
+	"\x05Paths\x18\x01 \x03(\tB\x04\xc8\xf3\x18\x01R\x05paths\x120\n" +
 	"\bEncoding\x18\x02 \x01(\x0e2\x14.kernel.EncodingTypeR\bencodingB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
@@ -574,21 +574,21 @@ var File_app_v1_middleware_csrf_proto protoreflect.FileDescriptor
 
 const file_app_v1_middleware_csrf_proto_rawDesc = "" +
 	"\n" +
-	"\x1capp/v1/middleware/csrf.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x15kernel/resource.proto\x1a\x11kernel/hash.proto\"\xa2\x01\n" +
+	"\x1capp/v1/middleware/csrf.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x15kernel/resource.proto\x1a\x11kernel/hash.proto\x1a\x14kernel/options.proto\"\xa2\x01\n" +
 	"\x0eCSRFMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12.\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x1a.app.v1.CSRFMiddlewareSpecR\x04spec\"\xec\x04\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x1a.app.v1.CSRFMiddlewareSpecR\x04spec\"\xf0\x04\n" +
 	"\x12CSRFMiddlewareSpec\x12\x1a\n" +
 	"\bPatterns\x18\x01 \x03(\tR\bpatterns\x12-\n" +
 	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodR\amethods\x125\n" +
 	"\vSkipMethods\x18\x03 \x03(\x0e2\x13.core.v1.HTTPMethodR\vskipMethods\x12(\n" +
 	"\x0fProxyHeaderName\x18\x04 \x01(\tR\x0fproxyHeaderName\x12\x1a\n" +
-	"\bIssueNew\x18\x05 \x01(\bR\bissueNew\x12.\n" +
-	"\x06Secret\x18\x06 \x01(\tB\x16\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+R\x06secret\x12#\n" +
+	"\bIssueNew\x18\x05 \x01(\bR\bissueNew\x122\n" +
+	"\x06Secret\x18\x06 \x01(\tB\x1a\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+\xc0\xf3\x18\x01R\x06secret\x12#\n" +
 	"\bSeedSize\x18\a \x01(\x05B\a\xbaH\x04\x1a\x02(\x05R\bseedSize\x12)\n" +
 	"\aHashAlg\x18\b \x01(\x0e2\x0f.kernel.HashAlgR\ahashAlg\x12S\n" +
 	"\x13CustomRequestHeader\x18\n" +
@@ -458,22 +458,23 @@ var File_app_v1_authn_digest_proto protoreflect.FileDescriptor
 
 const file_app_v1_authn_digest_proto_rawDesc = "" +
 	"\n" +
-	"\x19app/v1/authn/digest.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/encoding.proto\x1a\x16kernel/commonkey.proto\x1a\x15kernel/password.proto\x1a\x15kernel/resource.proto\"\xb0\x01\n" +
+	"\x19app/v1/authn/digest.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/encoding.proto\x1a\x16kernel/commonkey.proto\x1a\x15kernel/password.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/options.proto\"\xb0\x01\n" +
 	"\x15DigestAuthnMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x125\n" +
-	"\x04Spec\x18\x04 \x01(\v2!.app.v1.DigestAuthnMiddlewareSpecR\x04spec\"\xe2\x04\n" +
-	"\x19DigestAuthnMiddlewareSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12\x1c\n" +
+	"\x04Spec\x18\x04 \x01(\v2!.app.v1.DigestAuthnMiddlewareSpecR\x04spec\"\x8f\x05\n" +
+	"\x19DigestAuthnMiddlewareSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12\x1c\n" +
 	"\tClaimsKey\x18\x03 \x01(\tR\tclaimsKey\x12(\n" +
 	"\x0fKeepCredentials\x18\x04 \x01(\bR\x0fkeepCredentials\x12?\n" +
 	"\rPasswordCrypt\x18\x05 \x01(\v2\x19.kernel.PasswordCryptSpecR\rpasswordCrypt\x12J\n" +
-	"\x12CommonKeyCryptType\x18\x06 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12 \n" +
-	"\vCryptSecret\x18\a \x01(\tR\vcryptSecret\x12\x14\n" +
+	"\x12CommonKeyCryptType\x18\x06 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12&\n" +
+	"\vCryptSecret\x18\a \x01(\tB\x04\xc0\xf3\x18\x01R\vcryptSecret\x12\x14\n" +
 	"\x05Realm\x18\b \x01(\tR\x05realm\x12>\n" +
 	"\tAlgorithm\x18\t \x01(\tB \xbaH\x1dr\x1bR\x03MD5R\aSHA-256R\vSHA-512-256R\talgorithm\x12B\n" +
 	"\vEnvProvider\x18\n" +
@@ -483,9 +484,9 @@ const file_app_v1_authn_digest_proto_rawDesc = "" +
 	"\x16DigestAuthnEnvProvider\x12&\n" +
 	"\x0eUsernamePrefix\x18\x01 \x01(\tR\x0eusernamePrefix\x12&\n" +
 	"\x0ePasswordPrefix\x18\x02 \x01(\tR\x0epasswordPrefix\x120\n" +
-	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"a\n" +
-	"\x17DigestAuthnFileProvider\x12\x14\n" +
-	"\x05Paths\x18\x01 \x03(\tR\x05paths\x120\n" +
+	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"g\n" +
+	"\x17DigestAuthnFileProvider\x12\x1a\n" +
+	"\x05Paths\x18\x01 \x03(\tB\x04\xc8\xf3\x18\x01R\x05paths\x120\n" +
 	"\bEncoding\x18\x02 \x01(\x0e2\x14.kernel.EncodingTypeR\bencodingB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
@@ -106,8 +106,15 @@ type HeaderCertMiddlewareSpec struct {
 	// FingerprintHeader specifies the header name for the fingerprint.
 	// Default is not set.
 	FingerprintpHeader string `protobuf:"bytes,3,opt,name=FingerprintpHeader,json=fingerprintHeader,proto3" json:"FingerprintpHeader,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// [OPTIONAL]
+	// Revocation is the configuration of revocation checking
+	// of the client certificates.
+	// Revoked certificates are rejected with 401 Unauthorized.
+	// Revocation is not checked when not set.
+	// Default is not set.
+	Revocation    *kernel.RevocationConfig `protobuf:"bytes,4,opt,name=Revocation,json=revocation,proto3" json:"Revocation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HeaderCertMiddlewareSpec) Reset() {
@@ -161,24 +168,34 @@ func (x *HeaderCertMiddlewareSpec) GetFingerprintpHeader() string {
 	return ""
 }
 
+func (x *HeaderCertMiddlewareSpec) GetRevocation() *kernel.RevocationConfig {
+	if x != nil {
+		return x.Revocation
+	}
+	return nil
+}
+
 var File_app_v1_middleware_headercert_proto protoreflect.FileDescriptor
 
 const file_app_v1_middleware_headercert_proto_rawDesc = "" +
 	"\n" +
-	"\"app/v1/middleware/headercert.proto\x12\x06app.v1\x1a\x15kernel/resource.proto\"\xae\x01\n" +
+	"\"app/v1/middleware/headercert.proto\x12\x06app.v1\x1a\x14kernel/network.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xae\x01\n" +
 	"\x14HeaderCertMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x124\n" +
-	"\x04Spec\x18\x04 \x01(\v2 .app.v1.HeaderCertMiddlewareSpecR\x04spec\"\x83\x01\n" +
-	"\x18HeaderCertMiddlewareSpec\x12\x18\n" +
-	"\aRootCAs\x18\x01 \x03(\tR\arootCAs\x12\x1e\n" +
+	"\x04Spec\x18\x04 \x01(\v2 .app.v1.HeaderCertMiddlewareSpecR\x04spec\"\xc3\x01\n" +
+	"\x18HeaderCertMiddlewareSpec\x12\x1e\n" +
+	"\aRootCAs\x18\x01 \x03(\tB\x04\xc8\xf3\x18\x01R\arootCAs\x12\x1e\n" +
 	"\n" +
 	"CertHeader\x18\x02 \x01(\tR\n" +
 	"certHeader\x12-\n" +
-	"\x12FingerprintpHeader\x18\x03 \x01(\tR\x11fingerprintHeaderB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
+	"\x12FingerprintpHeader\x18\x03 \x01(\tR\x11fingerprintHeader\x128\n" +
+	"\n" +
+	"Revocation\x18\x04 \x01(\v2\x18.kernel.RevocationConfigR\n" +
+	"revocationB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
 	file_app_v1_middleware_headercert_proto_rawDescOnce sync.Once
@@ -197,15 +214,17 @@ var file_app_v1_middleware_headercert_proto_goTypes = []any{
 	(*HeaderCertMiddleware)(nil),     // 0: app.v1.HeaderCertMiddleware
 	(*HeaderCertMiddlewareSpec)(nil), // 1: app.v1.HeaderCertMiddlewareSpec
 	(*kernel.Metadata)(nil),          // 2: kernel.Metadata
+	(*kernel.RevocationConfig)(nil),  // 3: kernel.RevocationConfig
 }
 var file_app_v1_middleware_headercert_proto_depIdxs = []int32{
 	2, // 0: app.v1.HeaderCertMiddleware.Metadata:type_name -> kernel.Metadata
 	1, // 1: app.v1.HeaderCertMiddleware.Spec:type_name -> app.v1.HeaderCertMiddlewareSpec
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 2: app.v1.HeaderCertMiddlewareSpec.Revocation:type_name -> kernel.RevocationConfig
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_app_v1_middleware_headercert_proto_init() }
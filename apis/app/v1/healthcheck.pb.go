@@ -196,20 +196,20 @@ var File_app_v1_handler_healthcheck_proto protoreflect.FileDescriptor
 
 const file_app_v1_handler_healthcheck_proto_rawDesc = "" +
 	"\n" +
-	" app/v1/handler/healthcheck.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x15kernel/resource.proto\"\xaa\x01\n" +
+	" app/v1/handler/healthcheck.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xaa\x01\n" +
 	"\x12HealthCheckHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x122\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x1e.app.v1.HealthCheckHandlerSpecR\x04spec\"\xf8\x01\n" +
-	"\x16HealthCheckHandlerSpec\x125\n" +
-	"\fErrorHandler\x18\x01 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12\x1a\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x1e.app.v1.HealthCheckHandlerSpecR\x04spec\"\xa6\x02\n" +
+	"\x16HealthCheckHandlerSpec\x12L\n" +
+	"\fErrorHandler\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12\x1a\n" +
 	"\bPatterns\x18\x02 \x03(\tR\bpatterns\x12-\n" +
 	"\aMethods\x18\x03 \x03(\x0e2\x13.core.v1.HTTPMethodR\amethods\x12!\n" +
-	"\aTimeout\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\atimeout\x129\n" +
-	"\x0eExternalProbes\x18\x05 \x03(\v2\x11.kernel.ReferenceR\x0eexternalProbesB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
+	"\aTimeout\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\atimeout\x12P\n" +
+	"\x0eExternalProbes\x18\x05 \x03(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11app.HealthCheckerR\x0eexternalProbesB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
 	file_app_v1_handler_healthcheck_proto_rawDescOnce sync.Once
@@ -466,37 +466,38 @@ var File_app_v1_authn_idkey_proto protoreflect.FileDescriptor
 
 const file_app_v1_authn_idkey_proto_rawDesc = "" +
 	"\n" +
-	"\x18app/v1/authn/idkey.proto\x12\x06app.v1\x1a\x16kernel/commonkey.proto\x1a\x15kernel/encoding.proto\x1a\x11kernel/hash.proto\x1a\x15kernel/resource.proto\"\xae\x01\n" +
+	"\x18app/v1/authn/idkey.proto\x12\x06app.v1\x1a\x16kernel/commonkey.proto\x1a\x15kernel/encoding.proto\x1a\x11kernel/hash.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/options.proto\"\xae\x01\n" +
 	"\x14IDKeyAuthnMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x124\n" +
-	"\x04Spec\x18\x04 \x01(\v2 .app.v1.IDKeyAuthnMiddlewareSpecR\x04spec\"\xdd\x04\n" +
-	"\x18IDKeyAuthnMiddlewareSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12\x1c\n" +
+	"\x04Spec\x18\x04 \x01(\v2 .app.v1.IDKeyAuthnMiddlewareSpecR\x04spec\"\x90\x05\n" +
+	"\x18IDKeyAuthnMiddlewareSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12\x1c\n" +
 	"\tClaimsKey\x18\x03 \x01(\tR\tclaimsKey\x12(\n" +
 	"\x0fKeepCredentials\x18\x04 \x01(\bR\x0fkeepCredentials\x12$\n" +
 	"\rKeyHeaderName\x18\x05 \x01(\tR\rkeyHeaderName\x12\"\n" +
 	"\fIDHeaderName\x18\x06 \x01(\tR\fidHeaderName\x12)\n" +
-	"\aHashAlg\x18\a \x01(\x0e2\x0f.kernel.HashAlgR\ahashAlg\x12\x1e\n" +
+	"\aHashAlg\x18\a \x01(\x0e2\x0f.kernel.HashAlgR\ahashAlg\x12$\n" +
 	"\n" +
-	"HMACSecret\x18\b \x01(\tR\n" +
+	"HMACSecret\x18\b \x01(\tB\x04\xc0\xf3\x18\x01R\n" +
 	"hmacSecret\x12J\n" +
-	"\x12CommonKeyCryptType\x18\t \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12 \n" +
+	"\x12CommonKeyCryptType\x18\t \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12&\n" +
 	"\vCryptSecret\x18\n" +
-	" \x01(\tR\vcryptSecret\x12A\n" +
+	" \x01(\tB\x04\xc0\xf3\x18\x01R\vcryptSecret\x12A\n" +
 	"\vEnvProvider\x18\x0f \x01(\v2\x1d.app.v1.IDKeyAuthnEnvProviderH\x00R\venvProvider\x12D\n" +
 	"\fFileProvider\x18\x10 \x01(\v2\x1e.app.v1.IDKeyAuthnFileProviderH\x00R\ffileProviderB\v\n" +
 	"\tProviders\"\x83\x01\n" +
 	"\x15IDKeyAuthnEnvProvider\x12\x1c\n" +
 	"\tKeyPrefix\x18\x01 \x01(\tR\tkeyPrefix\x12\x1a\n" +
 	"\bIDPrefix\x18\x02 \x01(\tR\bidPrefix\x120\n" +
-	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"`\n" +
-	"\x16IDKeyAuthnFileProvider\x12\x14\n" +
-	"\x05Paths\x18\x01 \x03(\tR\x05paths\x120\n" +
+	"\bEncoding\x18\x03 \x01(\x0e2\x14.kernel.EncodingTypeR\bencoding\"f\n" +
+	"\x16IDKeyAuthnFileProvider\x12\x1a\n" +
+	"\x05Paths\x18\x01 \x03(\tB\x04\xc8\xf3\x18\x01R\x05paths\x120\n" +
 	"\bEncoding\x18\x02 \x01(\x0e2\x14.kernel.EncodingTypeR\bencodingB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
 
 var (
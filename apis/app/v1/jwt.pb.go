@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -353,13 +354,13 @@ var File_app_v1_jwt_proto protoreflect.FileDescriptor
 
 const file_app_v1_jwt_proto_rawDesc = "" +
 	"\n" +
-	"\x10app/v1/jwt.proto\x12\x06app.v1\"\xd6\x02\n" +
+	"\x10app/v1/jwt.proto\x12\x06app.v1\x1a\x14kernel/options.proto\"\xe2\x02\n" +
 	"\x0eSigningKeySpec\x12\x14\n" +
 	"\x05KeyID\x18\x01 \x01(\tR\x05keyID\x129\n" +
 	"\tAlgorithm\x18\x02 \x01(\x0e2\x1b.app.v1.SigningKeyAlgorithmR\talgorithm\x120\n" +
-	"\aKeyType\x18\x03 \x01(\x0e2\x16.app.v1.SigningKeyTypeR\akeyType\x12 \n" +
-	"\vKeyFilePath\x18\x04 \x01(\tR\vkeyFilePath\x12\x1c\n" +
-	"\tKeyString\x18\x05 \x01(\tR\tkeyString\x12C\n" +
+	"\aKeyType\x18\x03 \x01(\x0e2\x16.app.v1.SigningKeyTypeR\akeyType\x12&\n" +
+	"\vKeyFilePath\x18\x04 \x01(\tB\x04\xc8\xf3\x18\x01R\vkeyFilePath\x12\"\n" +
+	"\tKeyString\x18\x05 \x01(\tB\x04\xc0\xf3\x18\x01R\tkeyString\x12C\n" +
 	"\tJWTHeader\x18\x06 \x03(\v2%.app.v1.SigningKeySpec.JWTHeaderEntryR\tjwtHeader\x1a<\n" +
 	"\x0eJWTHeaderEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -1620,17 +1620,18 @@ var File_app_v1_authn_oauth_proto protoreflect.FileDescriptor
 
 const file_app_v1_authn_oauth_proto_rawDesc = "" +
 	"\n" +
-	"\x18app/v1/authn/oauth.proto\x12\x06app.v1\x1a\x10app/v1/jwt.proto\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\"\xba\x01\n" +
+	"\x18app/v1/authn/oauth.proto\x12\x06app.v1\x1a\x10app/v1/jwt.proto\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/options.proto\"\xba\x01\n" +
 	"\x1aOAuthAuthenticationHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12:\n" +
-	"\x04Spec\x18\x04 \x01(\v2&.app.v1.OAuthAuthenticationHandlerSpecR\x04spec\"\xe8\x04\n" +
-	"\x1eOAuthAuthenticationHandlerSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12+\n" +
+	"\x04Spec\x18\x04 \x01(\v2&.app.v1.OAuthAuthenticationHandlerSpecR\x04spec\"\x8f\x05\n" +
+	"\x1eOAuthAuthenticationHandlerSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12+\n" +
 	"\bContexts\x18\x03 \x03(\v2\x0f.app.v1.ContextR\bcontexts\x12(\n" +
 	"\x0fContextQueryKey\x18\x04 \x01(\tR\x0fcontextQueryKey\x12*\n" +
 	"\x10ContextHeaderKey\x18\x05 \x01(\tR\x10contextHeaderKey\x12^\n" +
@@ -1649,22 +1650,22 @@ const file_app_v1_authn_oauth_proto_rawDesc = "" +
 	"Revocation\x18\x05 \x01(\tR\n" +
 	"revocation\x12\x12\n" +
 	"\x04JWKs\x18\x06 \x01(\tR\x04jwks\x12\x1c\n" +
-	"\tDiscovery\x18\a \x01(\tR\tdiscovery\"\xb1\x01\n" +
+	"\tDiscovery\x18\a \x01(\tR\tdiscovery\"\xc8\x01\n" +
 	"\rOAuthProvider\x12\x16\n" +
 	"\x06Issuer\x18\x01 \x01(\tR\x06issuer\x12\x18\n" +
 	"\aBaseURL\x18\x02 \x01(\tR\abaseURL\x127\n" +
-	"\tEndpoints\x18\x03 \x01(\v2\x19.app.v1.ProviderEndpointsR\tendpoints\x125\n" +
-	"\fRoundTripper\x18\x04 \x01(\v2\x11.kernel.ReferenceR\froundTripper\"\xa1\x01\n" +
+	"\tEndpoints\x18\x03 \x01(\v2\x19.app.v1.ProviderEndpointsR\tendpoints\x12L\n" +
+	"\fRoundTripper\x18\x04 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11http.RoundTripperR\froundTripper\"\xa7\x01\n" +
 	"\vOAuthClient\x12\x0e\n" +
-	"\x02ID\x18\x01 \x01(\tR\x02id\x12\x16\n" +
-	"\x06Secret\x18\x02 \x01(\tR\x06secret\x12\x1a\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\x06Secret\x18\x02 \x01(\tB\x04\xc0\xf3\x18\x01R\x06secret\x12\x1a\n" +
 	"\bAudience\x18\x03 \x01(\tR\baudience\x12\x16\n" +
 	"\x06Scopes\x18\x04 \x03(\tR\x06scopes\x126\n" +
 	"\n" +
 	"JWTHandler\x18\x05 \x01(\v2\x16.app.v1.JWTHandlerSpecR\n" +
-	"jwtHandler\"\xa2\x03\n" +
-	"\x0fClientRequester\x125\n" +
-	"\fRoundTripper\x18\x01 \x01(\v2\x11.kernel.ReferenceR\froundTripper\x12D\n" +
+	"jwtHandler\"\xb9\x03\n" +
+	"\x0fClientRequester\x12L\n" +
+	"\fRoundTripper\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11http.RoundTripperR\froundTripper\x12D\n" +
 	"\x10ClientAuthMethod\x18\x02 \x01(\x0e2\x18.app.v1.ClientAuthMethodR\x10clientAuthMethod\x12J\n" +
 	"\vExtraHeader\x18\x03 \x03(\v2(.app.v1.ClientRequester.ExtraHeaderEntryR\vextraHeader\x12G\n" +
 	"\n" +
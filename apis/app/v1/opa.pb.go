@@ -900,33 +900,34 @@ var File_app_v1_authz_opa_proto protoreflect.FileDescriptor
 
 const file_app_v1_authz_opa_proto_rawDesc = "" +
 	"\n" +
-	"\x16app/v1/authz/opa.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\"\xaa\x01\n" +
+	"\x16app/v1/authz/opa.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xaa\x01\n" +
 	"\x12OPAAuthzMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x122\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x1e.app.v1.OPAAuthzMiddlewareSpecR\x04spec\"\xab\x02\n" +
-	"\x16OPAAuthzMiddlewareSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x126\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x1e.app.v1.OPAAuthzMiddlewareSpecR\x04spec\"\xd2\x02\n" +
+	"\x16OPAAuthzMiddlewareSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x126\n" +
 	"\tClaimsKey\x18\x03 \x01(\tB\x18\xbaH\x15r\x132\x11^[0-9A-Za-z-_.]+$R\tclaimsKey\x12-\n" +
 	"\aEnvData\x18\x05 \x01(\v2\x13.app.v1.EnvDataSpecR\aenvData\x12&\n" +
 	"\x05Regos\x18\x06 \x03(\v2\x10.app.v1.RegoSpecR\x05regos\x12 \n" +
-	"\vEnableTrace\x18\a \x01(\bR\venableTrace\"\xd3\x05\n" +
+	"\vEnableTrace\x18\a \x01(\bR\venableTrace\"\xf4\x05\n" +
 	"\bRegoSpec\x12/\n" +
-	"\x0eQueryParameter\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x0equeryParameter\x12*\n" +
-	"\vPolicyFiles\x18\x02 \x03(\tB\b\xbaH\x05\x92\x01\x02\b\x01R\vpolicyFiles\x12 \n" +
-	"\vBundlePaths\x18\x03 \x03(\tR\vbundlePaths\x12N\n" +
+	"\x0eQueryParameter\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x0equeryParameter\x12.\n" +
+	"\vPolicyFiles\x18\x02 \x03(\tB\f\xbaH\x05\x92\x01\x02\b\x01\xc8\xf3\x18\x01R\vpolicyFiles\x12&\n" +
+	"\vBundlePaths\x18\x03 \x03(\tB\x04\xc8\xf3\x18\x01R\vbundlePaths\x12N\n" +
 	"\x12BundleVerification\x18\x04 \x01(\v2\x1e.app.v1.BundleVerificationSpecR\x12bundleVerification\x126\n" +
 	"\x16SkipBundleVerification\x18\x05 \x01(\bR\x16skipBundleVerification\x124\n" +
 	"\x15EnablePrintStatements\x18\x06 \x01(\bR\x15enablePrintStatements\x12(\n" +
 	"\x0fShallowInlining\x18\a \x01(\bR\x0fshallowInlining\x12\x16\n" +
 	"\x06Strict\x18\b \x01(\bR\x06strict\x120\n" +
-	"\x13StrictBuiltinErrors\x18\t \x01(\bR\x13strictBuiltinErrors\x125\n" +
+	"\x13StrictBuiltinErrors\x18\t \x01(\bR\x13strictBuiltinErrors\x12L\n" +
 	"\fRoundTripper\x18\n" +
-	" \x01(\v2\x11.kernel.ReferenceR\froundTripper\x124\n" +
+	" \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11http.RoundTripperR\froundTripper\x124\n" +
 	"\x06Header\x18\v \x03(\v2\x1c.app.v1.RegoSpec.HeaderEntryR\x06header\x121\n" +
 	"\tFileStore\x18\x0f \x01(\v2\x11.app.v1.FileStoreH\x00R\tfileStore\x121\n" +
 	"\tHTTPStore\x18\x10 \x01(\v2\x11.app.v1.HTTPStoreH\x00R\thttpStore\x1a9\n" +
@@ -938,12 +939,12 @@ const file_app_v1_authz_opa_proto_rawDesc = "" +
 	"\x10VerificationKeys\x18\x01 \x03(\v2\x1b.app.v1.VerificationKeySpecR\x10verificationKeys\x12\x14\n" +
 	"\x05KeyID\x18\x02 \x01(\tR\x05keyID\x12\x14\n" +
 	"\x05Scope\x18\x03 \x01(\tR\x05scope\x12\x1a\n" +
-	"\bExcludes\x18\x04 \x03(\tR\bexcludes\"y\n" +
+	"\bExcludes\x18\x04 \x03(\tR\bexcludes\"\x7f\n" +
 	"\x13VerificationKeySpec\x12\x14\n" +
 	"\x05KeyID\x18\x01 \x01(\tR\x05keyID\x12\x14\n" +
 	"\x05Scope\x18\x02 \x01(\tR\x05scope\x12\x1c\n" +
-	"\tAlgorithm\x18\x03 \x01(\tR\talgorithm\x12\x18\n" +
-	"\aKeyFile\x18\x04 \x01(\tR\akeyFile\"k\n" +
+	"\tAlgorithm\x18\x03 \x01(\tR\talgorithm\x12\x1e\n" +
+	"\aKeyFile\x18\x04 \x01(\tB\x04\xc8\xf3\x18\x01R\akeyFile\"k\n" +
 	"\vEnvDataSpec\x12\x12\n" +
 	"\x04Vars\x18\x01 \x03(\tR\x04vars\x12\x10\n" +
 	"\x03PID\x18\x02 \x01(\bR\x03pid\x12\x12\n" +
@@ -725,7 +725,7 @@ var File_app_v1_o11y_otelmeter_proto protoreflect.FileDescriptor
 
 const file_app_v1_o11y_otelmeter_proto_rawDesc = "" +
 	"\n" +
-	"\x1bapp/v1/o11y/otelmeter.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/network.proto\"\xaa\x01\n" +
+	"\x1bapp/v1/o11y/otelmeter.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/network.proto\x1a\x14kernel/options.proto\"\xaa\x01\n" +
 	"\x12OpenTelemetryMeter\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
@@ -741,10 +741,10 @@ const file_app_v1_o11y_otelmeter_proto_rawDesc = "" +
 	" \x01(\v2\x1f.app.v1.HTTPMetricsExporterSpecH\x00R\fhttpExporter\x12I\n" +
 	"\x10GRPCExporterSpec\x18\v \x01(\v2\x1f.app.v1.GRPCMetricsExporterSpecH\x00R\fgrpcExporter\x12O\n" +
 	"\x12StdoutExporterSpec\x18\f \x01(\v2!.app.v1.StdoutMetricsExporterSpecH\x00R\x0estdoutExporterB\v\n" +
-	"\tExporters\"\xfe\x02\n" +
+	"\tExporters\"\x84\x03\n" +
 	"\x17HTTPMetricsExporterSpec\x12 \n" +
-	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12F\n" +
-	"\aHeaders\x18\x02 \x03(\v2,.app.v1.HTTPMetricsExporterSpec.HeadersEntryR\aheaders\x12\x1a\n" +
+	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12L\n" +
+	"\aHeaders\x18\x02 \x03(\v2,.app.v1.HTTPMetricsExporterSpec.HeadersEntryB\x04\xc0\xf3\x18\x01R\aheaders\x12\x1a\n" +
 	"\bCompress\x18\x03 \x01(\bR\bcompress\x12\x1a\n" +
 	"\bInsecure\x18\x04 \x01(\bR\binsecure\x12/\n" +
 	"\tTLSConfig\x18\x05 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\x18\n" +
@@ -752,10 +752,10 @@ const file_app_v1_o11y_otelmeter_proto_rawDesc = "" +
 	"\tOTLPRetry\x18\a \x01(\v2\x1c.app.v1.OTLPMetricsRetrySpecR\totlpRetry\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd4\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xda\x03\n" +
 	"\x17GRPCMetricsExporterSpec\x12 \n" +
-	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12F\n" +
-	"\aHeaders\x18\x02 \x03(\v2,.app.v1.GRPCMetricsExporterSpec.HeadersEntryR\aheaders\x12\x1a\n" +
+	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12L\n" +
+	"\aHeaders\x18\x02 \x03(\v2,.app.v1.GRPCMetricsExporterSpec.HeadersEntryB\x04\xc0\xf3\x18\x01R\aheaders\x12\x1a\n" +
 	"\bCompress\x18\x03 \x01(\bR\bcompress\x12\x1a\n" +
 	"\bInsecure\x18\x04 \x01(\bR\binsecure\x12/\n" +
 	"\tTLSConfig\x18\x05 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\x18\n" +
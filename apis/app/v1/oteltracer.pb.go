@@ -1423,7 +1423,7 @@ var File_app_v1_o11y_oteltracer_proto protoreflect.FileDescriptor
 
 const file_app_v1_o11y_oteltracer_proto_rawDesc = "" +
 	"\n" +
-	"\x1capp/v1/o11y/oteltracer.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/network.proto\x1a\x15kernel/resource.proto\"\xac\x01\n" +
+	"\x1capp/v1/o11y/oteltracer.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/network.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/options.proto\"\xac\x01\n" +
 	"\x13OpenTelemetryTracer\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
@@ -1457,10 +1457,10 @@ const file_app_v1_o11y_oteltracer_proto_rawDesc = "" +
 	"\x0fEventCountLimit\x18\x03 \x01(\x05R\x0feventCountLimit\x12&\n" +
 	"\x0eLinkCountLimit\x18\x04 \x01(\x05R\x0elinkCountLimit\x12@\n" +
 	"\x1bAttributePerEventCountLimit\x18\x05 \x01(\x05R\x1battributePerEventCountLimit\x12>\n" +
-	"\x1aAttributePerLinkCountLimit\x18\x06 \x01(\x05R\x1aattributePerLinkCountLimit\"\xf8\x02\n" +
+	"\x1aAttributePerLinkCountLimit\x18\x06 \x01(\x05R\x1aattributePerLinkCountLimit\"\xfe\x02\n" +
 	"\x15HTTPTraceExporterSpec\x12 \n" +
-	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12D\n" +
-	"\aHeaders\x18\x02 \x03(\v2*.app.v1.HTTPTraceExporterSpec.HeadersEntryR\aheaders\x12\x1a\n" +
+	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12J\n" +
+	"\aHeaders\x18\x02 \x03(\v2*.app.v1.HTTPTraceExporterSpec.HeadersEntryB\x04\xc0\xf3\x18\x01R\aheaders\x12\x1a\n" +
 	"\bCompress\x18\x03 \x01(\bR\bcompress\x12\x1a\n" +
 	"\bInsecure\x18\x04 \x01(\bR\binsecure\x12/\n" +
 	"\tTLSConfig\x18\x05 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\x18\n" +
@@ -1468,10 +1468,10 @@ const file_app_v1_o11y_oteltracer_proto_rawDesc = "" +
 	"\tOTLPRetry\x18\a \x01(\v2\x1a.app.v1.OTLPTraceRetrySpecR\totlpRetry\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xce\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd4\x03\n" +
 	"\x15GRPCTraceExporterSpec\x12 \n" +
-	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12D\n" +
-	"\aHeaders\x18\x02 \x03(\v2*.app.v1.GRPCTraceExporterSpec.HeadersEntryR\aheaders\x12\x1a\n" +
+	"\vEndpointURL\x18\x01 \x01(\tR\vendpointURL\x12J\n" +
+	"\aHeaders\x18\x02 \x03(\v2*.app.v1.GRPCTraceExporterSpec.HeadersEntryB\x04\xc0\xf3\x18\x01R\aheaders\x12\x1a\n" +
 	"\bCompress\x18\x03 \x01(\bR\bcompress\x12\x1a\n" +
 	"\bInsecure\x18\x04 \x01(\bR\binsecure\x12/\n" +
 	"\tTLSConfig\x18\x05 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\x18\n" +
@@ -1484,9 +1484,9 @@ const file_app_v1_o11y_oteltracer_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"i\n" +
 	"\x17StdoutTraceExporterSpec\x12 \n" +
 	"\vPrettyPrint\x18\x01 \x01(\bR\vprettyPrint\x12,\n" +
-	"\x11WithoutTimestamps\x18\x02 \x01(\bR\x11withoutTimestamps\"\xbf\x01\n" +
-	"\x17ZipkinTraceExporterSpec\x12F\n" +
-	"\aHeaders\x18\x01 \x03(\v2,.app.v1.ZipkinTraceExporterSpec.HeadersEntryR\aheaders\x12 \n" +
+	"\x11WithoutTimestamps\x18\x02 \x01(\bR\x11withoutTimestamps\"\xc5\x01\n" +
+	"\x17ZipkinTraceExporterSpec\x12L\n" +
+	"\aHeaders\x18\x01 \x03(\v2,.app.v1.ZipkinTraceExporterSpec.HeadersEntryB\x04\xc0\xf3\x18\x01R\aheaders\x12 \n" +
 	"\vEndpointURL\x18\x02 \x01(\tR\vendpointURL\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
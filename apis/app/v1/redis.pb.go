@@ -366,22 +366,22 @@ var File_app_v1_redis_proto protoreflect.FileDescriptor
 
 const file_app_v1_redis_proto_rawDesc = "" +
 	"\n" +
-	"\x12app/v1/redis.proto\x12\x06app.v1\x1a\x14kernel/network.proto\x1a\x15kernel/resource.proto\"\x9c\x01\n" +
+	"\x12app/v1/redis.proto\x12\x06app.v1\x1a\x14kernel/network.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x9c\x01\n" +
 	"\vRedisClient\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12+\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x17.app.v1.RedisClientSpecR\x04spec\"\xfc\a\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x17.app.v1.RedisClientSpecR\x04spec\"\x88\b\n" +
 	"\x0fRedisClientSpec\x12\x14\n" +
 	"\x05Addrs\x18\x01 \x03(\tR\x05addrs\x12\x12\n" +
 	"\x04Name\x18\x02 \x01(\tR\x04name\x12\x0e\n" +
 	"\x02DB\x18\x03 \x01(\x05R\x02db\x12\x1a\n" +
-	"\bUsername\x18\x04 \x01(\tR\busername\x12\x1a\n" +
-	"\bPassword\x18\x05 \x01(\tR\bpassword\x12*\n" +
-	"\x10SentinelUsername\x18\x06 \x01(\tR\x10sentinelUsername\x12*\n" +
-	"\x10SentinelPassword\x18\a \x01(\tR\x10sentinelPassword\x12\x1e\n" +
+	"\bUsername\x18\x04 \x01(\tR\busername\x12 \n" +
+	"\bPassword\x18\x05 \x01(\tB\x04\xc0\xf3\x18\x01R\bpassword\x12*\n" +
+	"\x10SentinelUsername\x18\x06 \x01(\tR\x10sentinelUsername\x120\n" +
+	"\x10SentinelPassword\x18\a \x01(\tB\x04\xc0\xf3\x18\x01R\x10sentinelPassword\x12\x1e\n" +
 	"\n" +
 	"MaxRetries\x18\b \x01(\x05R\n" +
 	"maxRetries\x12(\n" +
@@ -236,8 +236,7 @@ type SecureEncoderSpec struct {
 	// from the stand point of security.
 	// Never disable HMAC when using the encoder for cookie values or something exposed to clients.
 	DisableHMAC bool `protobuf:"varint,7,opt,name=DisableHMAC,json=disableHMAC,proto3" json:"DisableHMAC,omitempty"`
-	//	[OPTIONAL] DisableEncryption is the flag to disable common key encryption.
-	//
+	//  [OPTIONAL] DisableEncryption is the flag to disable common key encryption.
 	// Disabling encryption slightly increase the performance but it is not recommended
 	// from the stand point of security.
 	// Never disable encryption when using the encoder for cookie values or something exposed to clients.
@@ -329,30 +328,31 @@ var File_app_v1_middleware_session_proto protoreflect.FileDescriptor
 
 const file_app_v1_middleware_session_proto_rawDesc = "" +
 	"\n" +
-	"\x1fapp/v1/middleware/session.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x16kernel/commonkey.proto\x1a\x11kernel/hash.proto\x1a\x15kernel/resource.proto\"\xa8\x01\n" +
+	"\x1fapp/v1/middleware/session.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x16kernel/commonkey.proto\x1a\x11kernel/hash.proto\x1a\x15kernel/resource.proto\x1a\x14kernel/options.proto\"\xa8\x01\n" +
 	"\x11SessionMiddleware\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x121\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x1d.app.v1.SessionMiddlewareSpecR\x04spec\"\xc8\x02\n" +
-	"\x15SessionMiddlewareSpec\x12+\n" +
-	"\aStorage\x18\x01 \x01(\v2\x11.kernel.ReferenceR\astorage\x120\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x1d.app.v1.SessionMiddlewareSpecR\x04spec\"\xeb\x02\n" +
+	"\x15SessionMiddlewareSpec\x12>\n" +
+	"\aStorage\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x11\xd2\xf3\x18\rkvs.CommanderR\astorage\x120\n" +
 	"\x06Prefix\x18\x02 \x01(\tB\x18\xbaH\x15r\x132\x11^[0-9a-zA-Z-_:]*$R\x06prefix\x127\n" +
 	"\n" +
 	"CookieName\x18\x03 \x01(\tB\x17\xbaH\x14r\x122\x10^[0-9a-zA-Z-_]+$R\n" +
 	"cookieName\x12+\n" +
 	"\x06Cookie\x18\x04 \x01(\v2\x13.core.v1.CookieSpecR\x06cookie\x12?\n" +
-	"\rSecureEncoder\x18\x05 \x01(\v2\x19.app.v1.SecureEncoderSpecR\rsecureEncoder\x12)\n" +
-	"\x06Tracer\x18\x06 \x01(\v2\x11.kernel.ReferenceR\x06tracer\"\xfa\x02\n" +
+	"\rSecureEncoder\x18\x05 \x01(\v2\x19.app.v1.SecureEncoderSpecR\rsecureEncoder\x129\n" +
+	"\x06Tracer\x18\x06 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"app.TracerR\x06tracer\"\x82\x03\n" +
 	"\x11SecureEncoderSpec\x12)\n" +
-	"\aHashAlg\x18\x01 \x01(\x0e2\x0f.kernel.HashAlgR\ahashAlg\x126\n" +
+	"\aHashAlg\x18\x01 \x01(\x0e2\x0f.kernel.HashAlgR\ahashAlg\x12:\n" +
 	"\n" +
-	"HMACSecret\x18\x02 \x01(\tB\x16\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+R\n" +
+	"HMACSecret\x18\x02 \x01(\tB\x1a\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+\xc0\xf3\x18\x01R\n" +
 	"hmacSecret\x12J\n" +
-	"\x12CommonKeyCryptType\x18\x03 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x128\n" +
-	"\vCryptSecret\x18\x04 \x01(\tB\x16\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+R\vcryptSecret\x12,\n" +
+	"\x12CommonKeyCryptType\x18\x03 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeR\x12commonKeyCryptType\x12<\n" +
+	"\vCryptSecret\x18\x04 \x01(\tB\x1a\xbaH\x13r\x112\x0f[0-9a-zA-Z+/=]+\xc0\xf3\x18\x01R\vcryptSecret\x12,\n" +
 	"\x11EnableCompression\x18\x06 \x01(\bR\x11enableCompression\x12 \n" +
 	"\vDisableHMAC\x18\a \x01(\bR\vdisableHMAC\x12,\n" +
 	"\x11DisableEncryption\x18\b \x01(\bR\x11disableEncryptionB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
@@ -232,20 +232,20 @@ var File_app_v1_middleware_skipper_proto protoreflect.FileDescriptor
 
 const file_app_v1_middleware_skipper_proto_rawDesc = "" +
 	"\n" +
-	"\x1fapp/v1/middleware/skipper.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/resource.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/matcher.proto\"\x94\x01\n" +
+	"\x1fapp/v1/middleware/skipper.proto\x12\x06app.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/matcher.proto\"\x94\x01\n" +
 	"\aSkipper\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12'\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x13.app.v1.SkipperSpecR\x04spec\"\xb8\x01\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x13.app.v1.SkipperSpecR\x04spec\"\xe3\x01\n" +
 	"\vSkipperSpec\x12A\n" +
-	"\x0eSkipConditions\x18\x01 \x03(\v2\x19.app.v1.SkipConditionSpecR\x0eskipConditions\x121\n" +
+	"\x0eSkipConditions\x18\x01 \x03(\v2\x19.app.v1.SkipConditionSpecR\x0eskipConditions\x12F\n" +
 	"\n" +
-	"Middleware\x18\x02 \x03(\v2\x11.kernel.ReferenceR\n" +
-	"middleware\x123\n" +
-	"\vTripperware\x18\x03 \x03(\v2\x11.kernel.ReferenceR\vtripperware\"{\n" +
+	"Middleware\x18\x02 \x03(\v2\x11.kernel.ReferenceB\x13\xd2\xf3\x18\x0fcore.MiddlewareR\n" +
+	"middleware\x12I\n" +
+	"\vTripperware\x18\x03 \x03(\v2\x11.kernel.ReferenceB\x14\xd2\xf3\x18\x10core.TripperwareR\vtripperware\"{\n" +
 	"\x11SkipConditionSpec\x12-\n" +
 	"\aMatcher\x18\x01 \x01(\v2\x13.kernel.MatcherSpecR\amatcher\x127\n" +
 	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethodsB8Z6github.com/aileron-gateway/aileron-gateway/apis/app/v1b\x06proto3"
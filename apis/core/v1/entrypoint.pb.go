@@ -233,7 +233,7 @@ var File_core_v1_entrypoint_proto protoreflect.FileDescriptor
 
 const file_core_v1_entrypoint_proto_rawDesc = "" +
 	"\n" +
-	"\x18core/v1/entrypoint.proto\x12\acore.v1\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
+	"\x18core/v1/entrypoint.proto\x12\acore.v1\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
 	"\n" +
 	"Entrypoint\x12\x1e\n" +
 	"\n" +
@@ -241,16 +241,18 @@ const file_core_v1_entrypoint_proto_rawDesc = "" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12+\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.EntrypointSpecR\x04spec\"\x8b\x03\n" +
-	"\x0eEntrypointSpec\x127\n" +
-	"\rDefaultLogger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\rdefaultLogger\x12+\n" +
-	"\aLoggers\x18\x02 \x03(\v2\x11.kernel.ReferenceR\aloggers\x12C\n" +
-	"\x13DefaultErrorHandler\x18\x03 \x01(\v2\x11.kernel.ReferenceR\x13defaultErrorHandler\x127\n" +
-	"\rErrorHandlers\x18\x04 \x03(\v2\x11.kernel.ReferenceR\rerrorHandlers\x12+\n" +
-	"\aRunners\x18\x05 \x03(\v2\x11.kernel.ReferenceR\arunners\x125\n" +
-	"\fInitializers\x18\x06 \x03(\v2\x11.kernel.ReferenceR\finitializers\x121\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.EntrypointSpecR\x04spec\"\x94\x04\n" +
+	"\x0eEntrypointSpec\x12G\n" +
+	"\rDefaultLogger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\rdefaultLogger\x12;\n" +
+	"\aLoggers\x18\x02 \x03(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\aloggers\x12Z\n" +
+	"\x13DefaultErrorHandler\x18\x03 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\x13defaultErrorHandler\x12N\n" +
+	"\rErrorHandlers\x18\x04 \x03(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\rerrorHandlers\x12<\n" +
+	"\aRunners\x18\x05 \x03(\v2\x11.kernel.ReferenceB\x0f\xd2\xf3\x18\vcore.RunnerR\arunners\x12K\n" +
+	"\fInitializers\x18\x06 \x03(\v2\x11.kernel.ReferenceB\x14\xd2\xf3\x18\x10core.InitializerR\finitializers\x12E\n" +
 	"\n" +
-	"Finalizers\x18\a \x03(\v2\x11.kernel.ReferenceR\n" +
+	"Finalizers\x18\a \x03(\v2\x11.kernel.ReferenceB\x12\xd2\xf3\x18\x0ecore.FinalizerR\n" +
 	"finalizersB9Z7github.com/aileron-gateway/aileron-gateway/apis/core/v1b\x06proto3"
 
 var (
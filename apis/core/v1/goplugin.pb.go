@@ -155,17 +155,17 @@ var File_core_v1_goplugin_proto protoreflect.FileDescriptor
 
 const file_core_v1_goplugin_proto_rawDesc = "" +
 	"\n" +
-	"\x16core/v1/goplugin.proto\x12\acore.v1\x1a\x15kernel/resource.proto\"\x97\x01\n" +
+	"\x16core/v1/goplugin.proto\x12\acore.v1\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x97\x01\n" +
 	"\bGoPlugin\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12)\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x15.core.v1.GoPluginSpecR\x04spec\"N\n" +
-	"\fGoPluginSpec\x12\x1e\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x15.core.v1.GoPluginSpecR\x04spec\"T\n" +
+	"\fGoPluginSpec\x12$\n" +
 	"\n" +
-	"PluginPath\x18\x01 \x01(\tR\n" +
+	"PluginPath\x18\x01 \x01(\tB\x04\xc8\xf3\x18\x01R\n" +
 	"pluginPath\x12\x1e\n" +
 	"\n" +
 	"SymbolName\x18\x02 \x01(\tR\n" +
@@ -313,7 +313,7 @@ var File_core_v1_httpclient_proto protoreflect.FileDescriptor
 
 const file_core_v1_httpclient_proto_rawDesc = "" +
 	"\n" +
-	"\x18core/v1/httpclient.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/network.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
+	"\x18core/v1/httpclient.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/network.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
 	"\n" +
 	"HTTPClient\x12\x1e\n" +
 	"\n" +
@@ -321,9 +321,9 @@ const file_core_v1_httpclient_proto_rawDesc = "" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12+\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPClientSpecR\x04spec\"\x86\x03\n" +
-	"\x0eHTTPClientSpec\x125\n" +
-	"\fTripperwares\x18\x01 \x03(\v2\x11.kernel.ReferenceR\ftripperwares\x126\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPClientSpecR\x04spec\"\x9c\x03\n" +
+	"\x0eHTTPClientSpec\x12K\n" +
+	"\fTripperwares\x18\x01 \x03(\v2\x11.kernel.ReferenceB\x14\xd2\xf3\x18\x10core.TripperwareR\ftripperwares\x126\n" +
 	"\vRetryConfig\x18\x02 \x01(\v2\x14.core.v1.RetryConfigR\vretryConfig\x12O\n" +
 	"\x13HTTPTransportConfig\x18\x03 \x01(\v2\x1b.kernel.HTTPTransportConfigH\x00R\x13httpTransportConfig\x12R\n" +
 	"\x14HTTP2TransportConfig\x18\x04 \x01(\v2\x1c.kernel.HTTP2TransportConfigH\x00R\x14http2TransportConfig\x12R\n" +
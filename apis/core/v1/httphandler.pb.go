@@ -168,20 +168,20 @@ var File_core_v1_httphandler_proto protoreflect.FileDescriptor
 
 const file_core_v1_httphandler_proto_rawDesc = "" +
 	"\n" +
-	"\x19core/v1/httphandler.proto\x12\acore.v1\x1a\x15kernel/resource.proto\"\x9d\x01\n" +
+	"\x19core/v1/httphandler.proto\x12\acore.v1\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x9d\x01\n" +
 	"\vHTTPHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12,\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x18.core.v1.HTTPHandlerSpecR\x04spec\"\x8b\x01\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x18.core.v1.HTTPHandlerSpecR\x04spec\"\xb2\x01\n" +
 	"\x0fHTTPHandlerSpec\x12\x18\n" +
-	"\aPattern\x18\x01 \x01(\tR\apattern\x121\n" +
+	"\aPattern\x18\x01 \x01(\tR\apattern\x12F\n" +
 	"\n" +
-	"Middleware\x18\x02 \x03(\v2\x11.kernel.ReferenceR\n" +
-	"middleware\x12+\n" +
-	"\aHandler\x18\x03 \x01(\v2\x11.kernel.ReferenceR\ahandlerB9Z7github.com/aileron-gateway/aileron-gateway/apis/core/v1b\x06proto3"
+	"Middleware\x18\x02 \x03(\v2\x11.kernel.ReferenceB\x13\xd2\xf3\x18\x0fcore.MiddlewareR\n" +
+	"middleware\x12=\n" +
+	"\aHandler\x18\x03 \x01(\v2\x11.kernel.ReferenceB\x10\xd2\xf3\x18\fhttp.HandlerR\ahandlerB9Z7github.com/aileron-gateway/aileron-gateway/apis/core/v1b\x06proto3"
 
 var (
 	file_core_v1_httphandler_proto_rawDescOnce sync.Once
@@ -517,7 +517,7 @@ var File_core_v1_httplogger_proto protoreflect.FileDescriptor
 
 const file_core_v1_httplogger_proto_rawDesc = "" +
 	"\n" +
-	"\x18core/v1/httplogger.proto\x12\acore.v1\x1a\x15kernel/replacer.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
+	"\x18core/v1/httplogger.proto\x12\acore.v1\x1a\x14kernel/options.proto\x1a\x15kernel/replacer.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
 	"\n" +
 	"HTTPLogger\x12\x1e\n" +
 	"\n" +
@@ -525,10 +525,11 @@ const file_core_v1_httplogger_proto_rawDesc = "" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12+\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPLoggerSpecR\x04spec\"\xaa\x02\n" +
-	"\x0eHTTPLoggerSpec\x12)\n" +
-	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceR\x06logger\x125\n" +
-	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceR\ferrorHandler\x12\x18\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPLoggerSpecR\x04spec\"\xd1\x02\n" +
+	"\x0eHTTPLoggerSpec\x129\n" +
+	"\x06Logger\x18\x01 \x01(\v2\x11.kernel.ReferenceB\x0e\xd2\xf3\x18\n" +
+	"log.LoggerR\x06logger\x12L\n" +
+	"\fErrorHandler\x18\x02 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11core.ErrorHandlerR\ferrorHandler\x12\x18\n" +
 	"\aJournal\x18\x03 \x01(\bR\ajournal\x12\x1a\n" +
 	"\bTimezone\x18\x04 \x01(\tR\btimezone\x12\x1e\n" +
 	"\n" +
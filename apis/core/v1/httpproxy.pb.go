@@ -89,6 +89,7 @@ const (
 	HTTPHasherSpec_Cookie     HTTPHasherSpec_HashSourceType = 2 // Cookie uses cookie value.
 	HTTPHasherSpec_Query      HTTPHasherSpec_HashSourceType = 3 // Query uses URL query parameter.
 	HTTPHasherSpec_PathParam  HTTPHasherSpec_HashSourceType = 4 // PathParam uses path parameter.
+	HTTPHasherSpec_Claim      HTTPHasherSpec_HashSourceType = 5 // Claim uses authentication claim.
 )
 
 // Enum value maps for HTTPHasherSpec_HashSourceType.
@@ -99,6 +100,7 @@ var (
 		2: "Cookie",
 		3: "Query",
 		4: "PathParam",
+		5: "Claim",
 	}
 	HTTPHasherSpec_HashSourceType_value = map[string]int32{
 		"ClientAddr": 0,
@@ -106,6 +108,7 @@ var (
 		"Cookie":     2,
 		"Query":      3,
 		"PathParam":  4,
+		"Claim":      5,
 	}
 )
 
@@ -386,7 +389,27 @@ type LoadBalancerSpec struct {
 	// [OPTIONAL]
 	// Hasher is the hashing methods for hash-based load balancers.
 	// Default is not set.
-	Hasher        *HTTPHasherSpec `protobuf:"bytes,10,opt,name=Hasher,json=hasher,proto3" json:"Hasher,omitempty"`
+	Hasher *HTTPHasherSpec `protobuf:"bytes,10,opt,name=Hasher,json=hasher,proto3" json:"Hasher,omitempty"`
+	// [OPTIONAL]
+	// ClaimMatchers is the claim value matcher to check
+	// if this loadbalancer can accept the target request.
+	// Claims are the values saved in the request context by
+	// authentication middleware with the key specified by ClaimsKey.
+	// The Key of each matcher is a JSON path to the target claim
+	// such as "at_claims.tenant" for OAuthAuthenticationMiddleware.
+	// See https://github.com/tidwall/gjson/blob/master/SYNTAX.md for path syntax.
+	// If the claim was an array, elements are joined with a comma ","
+	// and aggregated to a singled string.
+	// Listed matchers are evaluated by AND condition.
+	// If OR matching condition is necessary, set the condition within a single matcher.
+	// Default is not set.
+	ClaimMatchers []*ParamMatcherSpec `protobuf:"bytes,11,rep,name=ClaimMatchers,json=claimMatchers,proto3" json:"ClaimMatchers,omitempty"`
+	// [OPTIONAL]
+	// ClaimsKey is the key to get claims from the request context.
+	// This field is used by ClaimMatchers.
+	// Set the same value with the ClaimsKey of authentication middleware.
+	// Default is ["AuthnClaims"].
+	ClaimsKey     string `protobuf:"bytes,12,opt,name=ClaimsKey,json=claimsKey,proto3" json:"ClaimsKey,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -491,6 +514,20 @@ func (x *LoadBalancerSpec) GetHasher() *HTTPHasherSpec {
 	return nil
 }
 
+func (x *LoadBalancerSpec) GetClaimMatchers() []*ParamMatcherSpec {
+	if x != nil {
+		return x.ClaimMatchers
+	}
+	return nil
+}
+
+func (x *LoadBalancerSpec) GetClaimsKey() string {
+	if x != nil {
+		return x.ClaimsKey
+	}
+	return ""
+}
+
 // + PathMatcherSpec
 // PathMatcherSpec is the specification of PathMatcher object
 // used for path matching of incoming HTTP requests.
@@ -823,11 +860,18 @@ type HTTPHasherSpec struct {
 	// [OPTIONAL]
 	// Key is the data source key.
 	// This is the header name for Header source type,
-	// query parameter name for Query
-	// and path parameter name for PathParam.
+	// query parameter name for Query,
+	// path parameter name for PathParam
+	// and JSON path to the claim for Claim.
 	// ClientAddr and MultiHeader hasher ignore this field.
 	// Default is not set.
-	Key           string `protobuf:"bytes,2,opt,name=Key,json=key,proto3" json:"Key,omitempty"`
+	Key string `protobuf:"bytes,2,opt,name=Key,json=key,proto3" json:"Key,omitempty"`
+	// [OPTIONAL]
+	// ClaimsKey is the key to get claims from the request context.
+	// This field is used only for Claim source type.
+	// Set the same value with the ClaimsKey of authentication middleware.
+	// Default is ["AuthnClaims"].
+	ClaimsKey     string `protobuf:"bytes,3,opt,name=ClaimsKey,json=claimsKey,proto3" json:"ClaimsKey,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -876,24 +920,31 @@ func (x *HTTPHasherSpec) GetKey() string {
 	return ""
 }
 
+func (x *HTTPHasherSpec) GetClaimsKey() string {
+	if x != nil {
+		return x.ClaimsKey
+	}
+	return ""
+}
+
 var File_core_v1_httpproxy_proto protoreflect.FileDescriptor
 
 const file_core_v1_httpproxy_proto_rawDesc = "" +
 	"\n" +
-	"\x17core/v1/httpproxy.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/matcher.proto\x1a\x15kernel/resource.proto\"\xad\x01\n" +
+	"\x17core/v1/httpproxy.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/matcher.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xad\x01\n" +
 	"\x13ReverseProxyHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x124\n" +
-	"\x04Spec\x18\x04 \x01(\v2 .core.v1.ReverseProxyHandlerSpecR\x04spec\"\xa7\x02\n" +
+	"\x04Spec\x18\x04 \x01(\v2 .core.v1.ReverseProxyHandlerSpecR\x04spec\"\xd4\x02\n" +
 	"\x17ReverseProxyHandlerSpec\x12$\n" +
 	"\bPatterns\x18\x01 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\bpatterns\x127\n" +
-	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x125\n" +
-	"\fTripperwares\x18\x03 \x03(\v2\x11.kernel.ReferenceR\ftripperwares\x125\n" +
-	"\fRoundTripper\x18\x04 \x01(\v2\x11.kernel.ReferenceR\froundTripper\x12?\n" +
-	"\rLoadBalancers\x18\x05 \x03(\v2\x19.core.v1.LoadBalancerSpecR\rloadBalancers\"\xd0\x04\n" +
+	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x12K\n" +
+	"\fTripperwares\x18\x03 \x03(\v2\x11.kernel.ReferenceB\x14\xd2\xf3\x18\x10core.TripperwareR\ftripperwares\x12L\n" +
+	"\fRoundTripper\x18\x04 \x01(\v2\x11.kernel.ReferenceB\x15\xd2\xf3\x18\x11http.RoundTripperR\froundTripper\x12?\n" +
+	"\rLoadBalancers\x18\x05 \x03(\v2\x19.core.v1.LoadBalancerSpecR\rloadBalancers\"\xc9\x05\n" +
 	"\x10LoadBalancerSpec\x126\n" +
 	"\vLBAlgorithm\x18\x01 \x01(\x0e2\x14.core.v1.LBAlgorithmR\vlbAlgorithm\x123\n" +
 	"\tUpstreams\x18\x02 \x03(\v2\x15.core.v1.UpstreamSpecR\tupstreams\x12:\n" +
@@ -905,7 +956,9 @@ const file_core_v1_httpproxy_proto_rawDesc = "" +
 	"\x0eHeaderMatchers\x18\b \x03(\v2\x19.core.v1.ParamMatcherSpecR\x0eheaderMatchers\x12?\n" +
 	"\rQueryMatchers\x18\t \x03(\v2\x19.core.v1.ParamMatcherSpecR\rqueryMatchers\x12/\n" +
 	"\x06Hasher\x18\n" +
-	" \x01(\v2\x17.core.v1.HTTPHasherSpecR\x06hasher\"\xb6\x01\n" +
+	" \x01(\v2\x17.core.v1.HTTPHasherSpecR\x06hasher\x12?\n" +
+	"\rClaimMatchers\x18\v \x03(\v2\x19.core.v1.ParamMatcherSpecR\rclaimMatchers\x126\n" +
+	"\tClaimsKey\x18\f \x01(\tB\x18\xbaH\x15r\x132\x11^[0-9A-Za-z-_.]*$R\tclaimsKey\"\xb6\x01\n" +
 	"\x0fPathMatcherSpec\x12\x14\n" +
 	"\x05Match\x18\x01 \x01(\tR\x05match\x12/\n" +
 	"\tMatchType\x18\x02 \x01(\x0e2\x11.kernel.MatchTypeR\tmatchType\x12\x18\n" +
@@ -925,12 +978,13 @@ const file_core_v1_httpproxy_proto_rawDesc = "" +
 	"\fEnableActive\x18\x04 \x01(\bR\fenableActive\x12\"\n" +
 	"\fInitialDelay\x18\a \x01(\x05R\finitialDelay\x120\n" +
 	"\x13HealthCheckInterval\x18\b \x01(\x05R\x13healthCheckInterval\x12(\n" +
-	"\x0fHealthCheckAddr\x18\t \x01(\tR\x0fhealthCheckAddr\"\xbe\x01\n" +
+	"\x0fHealthCheckAddr\x18\t \x01(\tR\x0fhealthCheckAddr\"\x81\x02\n" +
 	"\x0eHTTPHasherSpec\x12F\n" +
 	"\n" +
 	"HashSource\x18\x01 \x01(\x0e2&.core.v1.HTTPHasherSpec.HashSourceTypeR\n" +
 	"hashSource\x12\x10\n" +
-	"\x03Key\x18\x02 \x01(\tR\x03key\"R\n" +
+	"\x03Key\x18\x02 \x01(\tR\x03key\x126\n" +
+	"\tClaimsKey\x18\x03 \x01(\tB\x18\xbaH\x15r\x132\x11^[0-9A-Za-z-_.]*$R\tclaimsKey\"]\n" +
 	"\x0eHashSourceType\x12\x0e\n" +
 	"\n" +
 	"ClientAddr\x10\x00\x12\n" +
@@ -939,7 +993,8 @@ const file_core_v1_httpproxy_proto_rawDesc = "" +
 	"\n" +
 	"\x06Cookie\x10\x02\x12\t\n" +
 	"\x05Query\x10\x03\x12\r\n" +
-	"\tPathParam\x10\x04*S\n" +
+	"\tPathParam\x10\x04\x12\t\n" +
+	"\x05Claim\x10\x05*S\n" +
 	"\vLBAlgorithm\x12\x0e\n" +
 	"\n" +
 	"RoundRobin\x10\x00\x12\n" +
@@ -996,14 +1051,15 @@ var file_core_v1_httpproxy_proto_depIdxs = []int32{
 	6,  // 12: core.v1.LoadBalancerSpec.HeaderMatchers:type_name -> core.v1.ParamMatcherSpec
 	6,  // 13: core.v1.LoadBalancerSpec.QueryMatchers:type_name -> core.v1.ParamMatcherSpec
 	8,  // 14: core.v1.LoadBalancerSpec.Hasher:type_name -> core.v1.HTTPHasherSpec
-	12, // 15: core.v1.PathMatcherSpec.MatchType:type_name -> kernel.MatchType
-	12, // 16: core.v1.ParamMatcherSpec.MatchType:type_name -> kernel.MatchType
-	1,  // 17: core.v1.HTTPHasherSpec.HashSource:type_name -> core.v1.HTTPHasherSpec.HashSourceType
-	18, // [18:18] is the sub-list for method output_type
-	18, // [18:18] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	6,  // 15: core.v1.LoadBalancerSpec.ClaimMatchers:type_name -> core.v1.ParamMatcherSpec
+	12, // 16: core.v1.PathMatcherSpec.MatchType:type_name -> kernel.MatchType
+	12, // 17: core.v1.ParamMatcherSpec.MatchType:type_name -> kernel.MatchType
+	1,  // 18: core.v1.HTTPHasherSpec.HashSource:type_name -> core.v1.HTTPHasherSpec.HashSourceType
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_core_v1_httpproxy_proto_init() }
@@ -103,6 +103,9 @@ type HTTPServerSpec struct {
 	// See more address example at https://pkg.go.dev/net#Dial.
 	// To use unix domain socket such like, "/var/run/gateway.sock" or "@gateway",
 	// set Network and Address in the ListenConfig of HTTP2Config field.
+	// To use the socket passed by systemd socket activation, set "systemd://<Name>"
+	// where the Name is the FileDescriptorName of the socket unit.
+	// HTTP/3 servers use the datagram socket and others use the stream socket with the name.
 	// Default is [":8080"].
 	Addr string `protobuf:"bytes,1,opt,name=Addr,json=addr,proto3" json:"Addr,omitempty"`
 	// [OPTIONAL]
@@ -145,7 +148,6 @@ type HTTPServerSpec struct {
 	//   - pprof.Profile at "GET /debug/pprof/profile"
 	//   - pprof.Symbol at "GET /debug/pprof/symbol"
 	//   - pprof.Trace at "GET /debug/pprof/trace"
-	//
 	// See https://pkg.go.dev/net/http/pprof.
 	// DO NOT enable this on production environment.
 	// Default is [false].
@@ -702,11 +704,31 @@ type VirtualHostSpec struct {
 	// [OPTIONAL]
 	// Hosts is the list of hostname to accept. Vertual hostnames in other words.
 	// Because the Host headers of requests are used for routing, list all FQDN here including sub domains.
+	// Wildcard hosts which start with "*." such as "*.example.com" are also accepted.
+	// Wildcard matches one or more labels, so "*.example.com" matches "foo.example.com"
+	// and "foo.bar.example.com" but does not match "example.com".
+	// The matched part is available from the downstream handlers
+	// with the name "wildcard" through the host params of the request context.
+	// Exact hosts take precedence over wildcard hosts
+	// and longer wildcard hosts take precedence over shorter ones.
 	// All FQDN must be unique for among the server.
-	// When no hosts are set, handler are registered to the default mux.
+	// When no hosts and no host patterns are set, handler are registered to the default mux.
 	// Default is not set.
 	Hosts []string `protobuf:"bytes,1,rep,name=Hosts,json=hosts,proto3" json:"Hosts,omitempty"`
 	// [OPTIONAL]
+	// HostPatterns is the list of regular expressions of hostname to accept.
+	// Patterns must match the entire hostname without port.
+	// Patterns are matched case-insensitively and captured values are lower cased.
+	// Patterns are evaluated in the order of the list
+	// after exact and wildcard hosts did not match.
+	// Captured groups are available from the downstream handlers
+	// through the host params of the request context.
+	// Named groups are available with their names and
+	// all groups are available with their index such as "1".
+	// See https://pkg.go.dev/regexp/syntax for the syntax.
+	// Default is not set.
+	HostPatterns []string `protobuf:"bytes,6,rep,name=HostPatterns,json=hostPatterns,proto3" json:"HostPatterns,omitempty"`
+	// [OPTIONAL]
 	// Pattern is the path pattern for this hosts.
 	// The specified pattern will be added as a prefix
 	// to the path patterns of all handlers.
@@ -769,6 +791,13 @@ func (x *VirtualHostSpec) GetHosts() []string {
 	return nil
 }
 
+func (x *VirtualHostSpec) GetHostPatterns() []string {
+	if x != nil {
+		return x.HostPatterns
+	}
+	return nil
+}
+
 func (x *VirtualHostSpec) GetPattern() string {
 	if x != nil {
 		return x.Pattern
@@ -801,7 +830,7 @@ var File_core_v1_httpserver_proto protoreflect.FileDescriptor
 
 const file_core_v1_httpserver_proto_rawDesc = "" +
 	"\n" +
-	"\x18core/v1/httpserver.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x19core/v1/httphandler.proto\x1a\x14kernel/network.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
+	"\x18core/v1/httpserver.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x19core/v1/httphandler.proto\x1a\x14kernel/network.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\x9b\x01\n" +
 	"\n" +
 	"HTTPServer\x12\x1e\n" +
 	"\n" +
@@ -809,7 +838,7 @@ const file_core_v1_httpserver_proto_rawDesc = "" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x12+\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPServerSpecR\x04spec\"\xae\x03\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x17.core.v1.HTTPServerSpecR\x04spec\"\xc3\x03\n" +
 	"\x0eHTTPServerSpec\x12\x12\n" +
 	"\x04Addr\x18\x01 \x01(\tR\x04addr\x12(\n" +
 	"\x0fShutdownTimeout\x18\x02 \x01(\x05R\x0fshutdownTimeout\x123\n" +
@@ -817,9 +846,9 @@ const file_core_v1_httpserver_proto_rawDesc = "" +
 	"HTTPConfig\x18\x03 \x01(\v2\x13.core.v1.HTTPConfigR\n" +
 	"httpConfig\x126\n" +
 	"\vHTTP2Config\x18\x04 \x01(\v2\x14.core.v1.HTTP2ConfigR\vhttp2Config\x126\n" +
-	"\vHTTP3Config\x18\x05 \x01(\v2\x14.core.v1.HTTP3ConfigR\vhttp3Config\x121\n" +
+	"\vHTTP3Config\x18\x05 \x01(\v2\x14.core.v1.HTTP3ConfigR\vhttp3Config\x12F\n" +
 	"\n" +
-	"Middleware\x18\x06 \x03(\v2\x11.kernel.ReferenceR\n" +
+	"Middleware\x18\x06 \x03(\v2\x11.kernel.ReferenceB\x13\xd2\xf3\x18\x0fcore.MiddlewareR\n" +
 	"middleware\x12<\n" +
 	"\fVirtualHosts\x18\a \x03(\v2\x18.core.v1.VirtualHostSpecR\fvirtualHosts\x12$\n" +
 	"\rEnableProfile\x18\b \x01(\bR\renableProfile\x12\"\n" +
@@ -857,13 +886,14 @@ const file_core_v1_httpserver_proto_rawDesc = "" +
 	"quicConfig\x12/\n" +
 	"\tTLSConfig\x18\x02 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12&\n" +
 	"\x0eMaxHeaderBytes\x18\x03 \x01(\x05R\x0emaxHeaderBytes\x12\x16\n" +
-	"\x06AltSvc\x18\x04 \x01(\tR\x06altSvc\"\x83\x02\n" +
-	"\x0fVirtualHostSpec\x124\n" +
-	"\x05Hosts\x18\x01 \x03(\tB\x1e\xbaH\x1b\x92\x01\x18\x18\x01\"\x14r\x122\x10^[0-9a-zA-Z.-]+$R\x05hosts\x12\x18\n" +
+	"\x06AltSvc\x18\x04 \x01(\tR\x06altSvc\"\xd3\x02\n" +
+	"\x0fVirtualHostSpec\x12;\n" +
+	"\x05Hosts\x18\x01 \x03(\tB%\xbaH\"\x92\x01\x1f\x18\x01\"\x1br\x192\x17^(\\*\\.)?[0-9a-zA-Z.-]+$R\x05hosts\x122\n" +
+	"\fHostPatterns\x18\x06 \x03(\tB\x0e\xbaH\v\x92\x01\b\x18\x01\"\x04r\x02\x10\x01R\fhostPatterns\x12\x18\n" +
 	"\aPattern\x18\x02 \x01(\tR\apattern\x127\n" +
-	"\aMethods\x18\x03 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x121\n" +
+	"\aMethods\x18\x03 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x12F\n" +
 	"\n" +
-	"Middleware\x18\x04 \x03(\v2\x11.kernel.ReferenceR\n" +
+	"Middleware\x18\x04 \x03(\v2\x11.kernel.ReferenceB\x13\xd2\xf3\x18\x0fcore.MiddlewareR\n" +
 	"middleware\x124\n" +
 	"\bHandlers\x18\x05 \x03(\v2\x18.core.v1.HTTPHandlerSpecR\bhandlersB9Z7github.com/aileron-gateway/aileron-gateway/apis/core/v1b\x06proto3"
 
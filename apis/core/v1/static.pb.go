@@ -211,18 +211,18 @@ var File_core_v1_static_proto protoreflect.FileDescriptor
 
 const file_core_v1_static_proto_rawDesc = "" +
 	"\n" +
-	"\x14core/v1/static.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x15kernel/resource.proto\"\xa9\x01\n" +
+	"\x14core/v1/static.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xa9\x01\n" +
 	"\x11StaticFileHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
 	"apiVersion\x12\x12\n" +
 	"\x04Kind\x18\x02 \x01(\tR\x04kind\x12,\n" +
 	"\bMetadata\x18\x03 \x01(\v2\x10.kernel.MetadataR\bmetadata\x122\n" +
-	"\x04Spec\x18\x04 \x01(\v2\x1e.core.v1.StaticFileHandlerSpecR\x04spec\"\xd7\x02\n" +
+	"\x04Spec\x18\x04 \x01(\v2\x1e.core.v1.StaticFileHandlerSpecR\x04spec\"\xdd\x02\n" +
 	"\x15StaticFileHandlerSpec\x12$\n" +
 	"\bPatterns\x18\x01 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\bpatterns\x127\n" +
-	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x12\x18\n" +
-	"\aRootDir\x18\x03 \x01(\tR\arootDir\x12 \n" +
+	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x12\x1e\n" +
+	"\aRootDir\x18\x03 \x01(\tB\x04\xc8\xf3\x18\x01R\arootDir\x12 \n" +
 	"\vStripPrefix\x18\x04 \x01(\tR\vstripPrefix\x12$\n" +
 	"\rEnableListing\x18\x05 \x01(\bR\renableListing\x12B\n" +
 	"\x06Header\x18\x06 \x03(\v2*.core.v1.StaticFileHandlerSpec.HeaderEntryR\x06header\x1a9\n" +
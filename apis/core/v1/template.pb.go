@@ -334,7 +334,7 @@ var File_core_v1_template_proto protoreflect.FileDescriptor
 
 const file_core_v1_template_proto_rawDesc = "" +
 	"\n" +
-	"\x16core/v1/template.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x15kernel/resource.proto\"\xa5\x01\n" +
+	"\x16core/v1/template.proto\x12\acore.v1\x1a\x1bbuf/validate/validate.proto\x1a\x12core/v1/http.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\"\xa5\x01\n" +
 	"\x0fTemplateHandler\x12\x1e\n" +
 	"\n" +
 	"APIVersion\x18\x01 \x01(\tR\n" +
@@ -345,7 +345,7 @@ const file_core_v1_template_proto_rawDesc = "" +
 	"\x13TemplateHandlerSpec\x12$\n" +
 	"\bPatterns\x18\x01 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\bpatterns\x127\n" +
 	"\aMethods\x18\x02 \x03(\x0e2\x13.core.v1.HTTPMethodB\b\xbaH\x05\x92\x01\x02\x18\x01R\amethods\x12<\n" +
-	"\fMIMEContents\x18\x03 \x03(\v2\x18.core.v1.MIMEContentSpecR\fmimeContents\"\xee\x02\n" +
+	"\fMIMEContents\x18\x03 \x03(\v2\x18.core.v1.MIMEContentSpecR\fmimeContents\"\xf4\x02\n" +
 	"\x0fMIMEContentSpec\x12;\n" +
 	"\bMIMEType\x18\x01 \x01(\tB\x1f\xbaH\x1cr\x1a2\x18^[a-z]+/[0-9a-zA-Z.+-]+$R\bmimeType\x12*\n" +
 	"\n" +
@@ -354,8 +354,8 @@ const file_core_v1_template_proto_rawDesc = "" +
 	"statusCode\x12<\n" +
 	"\x06Header\x18\x03 \x03(\v2$.core.v1.MIMEContentSpec.HeaderEntryR\x06header\x129\n" +
 	"\fTemplateType\x18\x04 \x01(\x0e2\x15.core.v1.TemplateTypeR\ftemplateType\x12\x1a\n" +
-	"\bTemplate\x18\x05 \x01(\tR\btemplate\x12\"\n" +
-	"\fTemplateFile\x18\x06 \x01(\tR\ftemplateFile\x1a9\n" +
+	"\bTemplate\x18\x05 \x01(\tR\btemplate\x12(\n" +
+	"\fTemplateFile\x18\x06 \x01(\tB\x04\xc8\xf3\x18\x01R\ftemplateFile\x1a9\n" +
 	"\vHeaderEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*0\n" +
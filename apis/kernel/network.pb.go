@@ -449,7 +449,7 @@ func (x QuicConfig_Version) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use QuicConfig_Version.Descriptor instead.
 func (QuicConfig_Version) EnumDescriptor() ([]byte, []int) {
-	return file_kernel_network_proto_rawDescGZIP(), []int{8, 0}
+	return file_kernel_network_proto_rawDescGZIP(), []int{10, 0}
 }
 
 // + HTTPTransportConfig
@@ -1013,7 +1013,19 @@ type DialConfig struct {
 	// [OPTIONAL]
 	// SockOption is the socket options.
 	// Default is not set.
-	SockOption    *SockOption `protobuf:"bytes,6,opt,name=SockOption,json=sockOption,proto3" json:"SockOption,omitempty"`
+	SockOption *SockOption `protobuf:"bytes,6,opt,name=SockOption,json=sockOption,proto3" json:"SockOption,omitempty"`
+	// [OPTIONAL]
+	// ProxyProtocol is the flag to send PROXY protocol version 2 header
+	// to the upstream servers right after the connections are established.
+	// The header has the addresses of the client connection
+	// which triggered the dialing.
+	// LOCAL command is sent when the client addresses are not available.
+	// Keep-alive is disabled in HTTP transports when this is enabled
+	// so that connections are not reused for the requests of other clients.
+	// HTTP/2 transports cannot enable this because connections are shared.
+	// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+	// Default is [false].
+	ProxyProtocol bool `protobuf:"varint,7,opt,name=ProxyProtocol,json=proxyProtocol,proto3" json:"ProxyProtocol,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1090,6 +1102,13 @@ func (x *DialConfig) GetSockOption() *SockOption {
 	return nil
 }
 
+func (x *DialConfig) GetProxyProtocol() bool {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return false
+}
+
 // + HTTP3TransportConfig
 // HTTP3TransportConfig is the specifications for the HTTP3 TransportConfig object.
 // This is the configuration for the transport layer of HTTP3.
@@ -1222,15 +1241,39 @@ type ListenConfig struct {
 	// "@gateway" for abstract socket. Unix socket can be used from curl as follows.
 	// (curl --unix-socket "/var/run/gateway.sock" http://foo.com/bar)
 	// (curl --abstract-unix-socket "gateway" http://foo.com/bar)
+	// "systemd" network prefix takes the listener from systemd socket activation
+	// instead of binding a new socket. The socket is matched by the name
+	// given by FileDescriptorName in the socket unit, for example "systemd://aileron".
+	// See https://www.freedesktop.org/software/systemd/man/latest/sd_listen_fds.html
 	// Default is not set, or empty string [""].
 	Addr string `protobuf:"bytes,4,opt,name=Addr,json=addr,proto3" json:"Addr,omitempty"`
 	// [OPTIONAL]
 	// ConnectionLimit is the maximum number of TCP connections that the server can establish.
-	// Connections are dropped after ConnectionWaitTimeout when this limit was exceeded.
-	// Set -1 to disable limiting the number of TCP connections.
-	// Default is [1024].
+	// Connections wait or are dropped as configured by ConnectionWaitTimeout
+	// when this limit was exceeded.
+	// Set 0 or -1 to disable limiting the number of TCP connections.
+	// Default is [0].
 	ConnectionLimit int32 `protobuf:"varint,5,opt,name=ConnectionLimit,json=connectionLimit,proto3" json:"ConnectionLimit,omitempty"`
 	// [OPTIONAL]
+	// ConnectionLimitPerIP is the maximum number of TCP connections
+	// from a single source IP address.
+	// Connections exceeding this limit are closed immediately.
+	// When ProxyProtocol is configured, this limit is applied to the source addresses
+	// in the headers, rather than the addresses of the peers, typically load balancers.
+	// Those connections are closed after the headers were read.
+	// Set 0 or -1 to disable limiting the number of connections per IP.
+	// Default is [0].
+	ConnectionLimitPerIP int32 `protobuf:"varint,14,opt,name=ConnectionLimitPerIP,json=connectionLimitPerIP,proto3" json:"ConnectionLimitPerIP,omitempty"`
+	// [OPTIONAL]
+	// ConnectionWaitTimeout is the timeout in milliseconds that connections
+	// exceeding the ConnectionLimit wait for available slots.
+	// If 0, connections wait in the accept queue of the socket without timeout.
+	// If positive, connections are accepted and closed when no slot became available
+	// within the timeout. Note that subsequent connections are not accepted while waiting.
+	// If negative, connections are accepted and closed immediately.
+	// Default is [0].
+	ConnectionWaitTimeout int32 `protobuf:"varint,15,opt,name=ConnectionWaitTimeout,json=connectionWaitTimeout,proto3" json:"ConnectionWaitTimeout,omitempty"`
+	// [OPTIONAL]
 	// Networks is the allowed network address list.
 	// If set, listed network or ip addresses are considered as whitelist.
 	// If not set, all networks are allowed.
@@ -1261,7 +1304,17 @@ type ListenConfig struct {
 	// [OPTIONAL]
 	// SockOption is the socket options.
 	// Default is not set.
-	SockOption    *SockOption `protobuf:"bytes,12,opt,name=SockOption,json=sockOption,proto3" json:"SockOption,omitempty"`
+	SockOption *SockOption `protobuf:"bytes,12,opt,name=SockOption,json=sockOption,proto3" json:"SockOption,omitempty"`
+	// [OPTIONAL]
+	// ProxyProtocol is the configuration of PROXY protocol.
+	// PROXY protocol version 1 and 2 headers are accepted when set.
+	// Remote addresses of the connections are replaced with
+	// the addresses in the headers.
+	// Note that the Networks are checked with the addresses of the peers,
+	// typically load balancers, rather than the addresses in the headers.
+	// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+	// Default is not set.
+	ProxyProtocol *ProxyProtocolConfig `protobuf:"bytes,13,opt,name=ProxyProtocol,json=proxyProtocol,proto3" json:"ProxyProtocol,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1317,6 +1370,20 @@ func (x *ListenConfig) GetConnectionLimit() int32 {
 	return 0
 }
 
+func (x *ListenConfig) GetConnectionLimitPerIP() int32 {
+	if x != nil {
+		return x.ConnectionLimitPerIP
+	}
+	return 0
+}
+
+func (x *ListenConfig) GetConnectionWaitTimeout() int32 {
+	if x != nil {
+		return x.ConnectionWaitTimeout
+	}
+	return 0
+}
+
 func (x *ListenConfig) GetNetworks() []string {
 	if x != nil {
 		return x.Networks
@@ -1352,6 +1419,83 @@ func (x *ListenConfig) GetSockOption() *SockOption {
 	return nil
 }
 
+func (x *ListenConfig) GetProxyProtocol() *ProxyProtocolConfig {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return nil
+}
+
+// + ProxyProtocolConfig
+// ProxyProtocolConfig is the configuration of PROXY protocol
+// for listeners.
+type ProxyProtocolConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// [REQUIRED]
+	// TrustedNetworks is the list of networks in CIDR format
+	// that are allowed to send PROXY protocol headers.
+	// Connections from trusted networks must send a header.
+	// Headers are not read from connections of other networks
+	// and their remote addresses are used as-is.
+	// Set the addresses of load balancers.
+	// For example, "10.0.0.0/8" or "fd00::/8".
+	// At least 1 network must be set.
+	// Default is not set.
+	TrustedNetworks []string `protobuf:"bytes,1,rep,name=TrustedNetworks,json=trustedNetworks,proto3" json:"TrustedNetworks,omitempty"`
+	// [OPTIONAL]
+	// HeaderTimeout is the timeout in milliseconds
+	// to read a PROXY protocol header.
+	// Connections are closed when the header was not received within this timeout.
+	// Default is [10000] milliseconds.
+	HeaderTimeout int32 `protobuf:"varint,2,opt,name=HeaderTimeout,json=headerTimeout,proto3" json:"HeaderTimeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProxyProtocolConfig) Reset() {
+	*x = ProxyProtocolConfig{}
+	mi := &file_kernel_network_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProxyProtocolConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyProtocolConfig) ProtoMessage() {}
+
+func (x *ProxyProtocolConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_kernel_network_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyProtocolConfig.ProtoReflect.Descriptor instead.
+func (*ProxyProtocolConfig) Descriptor() ([]byte, []int) {
+	return file_kernel_network_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProxyProtocolConfig) GetTrustedNetworks() []string {
+	if x != nil {
+		return x.TrustedNetworks
+	}
+	return nil
+}
+
+func (x *ProxyProtocolConfig) GetHeaderTimeout() int32 {
+	if x != nil {
+		return x.HeaderTimeout
+	}
+	return 0
+}
+
 // + KeepAliveConfig
 // KeepAliveConfig is the configuration for listener keep-alive.
 // This configuration is bounded to net.KeepAliveConfig.
@@ -1391,7 +1535,7 @@ type KeepAliveConfig struct {
 
 func (x *KeepAliveConfig) Reset() {
 	*x = KeepAliveConfig{}
-	mi := &file_kernel_network_proto_msgTypes[5]
+	mi := &file_kernel_network_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1403,7 +1547,7 @@ func (x *KeepAliveConfig) String() string {
 func (*KeepAliveConfig) ProtoMessage() {}
 
 func (x *KeepAliveConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_kernel_network_proto_msgTypes[5]
+	mi := &file_kernel_network_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1416,7 +1560,7 @@ func (x *KeepAliveConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KeepAliveConfig.ProtoReflect.Descriptor instead.
 func (*KeepAliveConfig) Descriptor() ([]byte, []int) {
-	return file_kernel_network_proto_rawDescGZIP(), []int{5}
+	return file_kernel_network_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *KeepAliveConfig) GetDisable() bool {
@@ -1560,13 +1704,38 @@ type TLSConfig struct {
 	// This fieled will be used as https://pkg.go.dev/crypto/tls#Config.Renegotiation.
 	// Default is not set.
 	Renegotiation RenegotiationSupport `protobuf:"varint,14,opt,name=Renegotiation,json=renegotiation,proto3,enum=kernel.RenegotiationSupport" json:"Renegotiation,omitempty"`
+	// [OPTIONAL]
+	// CertManager is the reference to a certificate manager object
+	// which provides server certificates, for example, ACMEManager.
+	// Certificates provided by the manager are used in preference to CertKeyPairs.
+	// CertKeyPairs are used as fallback when clients did not send SNI
+	// or the manager failed to provide a certificate, for example, for unknown hosts.
+	// This field is used only for servers.
+	// Default is not set.
+	CertManager *Reference `protobuf:"bytes,15,opt,name=CertManager,json=certManager,proto3" json:"CertManager,omitempty"`
+	// [OPTIONAL]
+	// Revocation is the configuration of revocation checking
+	// of client certificates.
+	// Revocation is checked for the verified client certificates,
+	// that is, when ClientAuth is VerifyClientCertIfGiven or RequireAndVerifyClientCert.
+	// This field is used only for servers.
+	// Default is not set.
+	Revocation *RevocationConfig `protobuf:"bytes,16,opt,name=Revocation,json=revocation,proto3" json:"Revocation,omitempty"`
+	// [OPTIONAL]
+	// OCSPStapling enables OCSP stapling for the server certificates.
+	// OCSP responses are obtained from the OCSP responders written in
+	// the certificates and refreshed in background before they expire.
+	// Handshakes are not blocked by obtaining OCSP responses.
+	// This field is used only for servers.
+	// Default is [false].
+	OCSPStapling  bool `protobuf:"varint,17,opt,name=OCSPStapling,json=ocspStapling,proto3" json:"OCSPStapling,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TLSConfig) Reset() {
 	*x = TLSConfig{}
-	mi := &file_kernel_network_proto_msgTypes[6]
+	mi := &file_kernel_network_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1578,7 +1747,7 @@ func (x *TLSConfig) String() string {
 func (*TLSConfig) ProtoMessage() {}
 
 func (x *TLSConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_kernel_network_proto_msgTypes[6]
+	mi := &file_kernel_network_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1591,7 +1760,7 @@ func (x *TLSConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TLSConfig.ProtoReflect.Descriptor instead.
 func (*TLSConfig) Descriptor() ([]byte, []int) {
-	return file_kernel_network_proto_rawDescGZIP(), []int{6}
+	return file_kernel_network_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TLSConfig) GetCertKeyPairs() []*CertKeyPair {
@@ -1692,6 +1861,134 @@ func (x *TLSConfig) GetRenegotiation() RenegotiationSupport {
 	return RenegotiationSupport_RenegotiateNever
 }
 
+func (x *TLSConfig) GetCertManager() *Reference {
+	if x != nil {
+		return x.CertManager
+	}
+	return nil
+}
+
+func (x *TLSConfig) GetRevocation() *RevocationConfig {
+	if x != nil {
+		return x.Revocation
+	}
+	return nil
+}
+
+func (x *TLSConfig) GetOCSPStapling() bool {
+	if x != nil {
+		return x.OCSPStapling
+	}
+	return false
+}
+
+// + RevocationConfig
+// RevocationConfig is the configuration of revocation checking
+// for certificates.
+// Certificates are considered to be revoked when they are listed
+// in the CRLs or OCSP responders reported so.
+// When the revocation status could not be determined,
+// for example, no CRLs were found for the issuer and OCSP responders were not available,
+// certificates are rejected if SoftFail is false and are accepted if SoftFail is true.
+type RevocationConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// [OPTIONAL]
+	// CRLFiles is the list of CRL file paths.
+	// Both PEM and DER formats are accepted.
+	// Files are reloaded when they were modified.
+	// Default is not set.
+	CRLFiles []string `protobuf:"bytes,1,rep,name=CRLFiles,json=crlFiles,proto3" json:"CRLFiles,omitempty"`
+	// [OPTIONAL]
+	// CRLReloadInterval is the interval in seconds
+	// to check the modification of the CRL files.
+	// Default is [60] seconds.
+	CRLReloadInterval int32 `protobuf:"varint,2,opt,name=CRLReloadInterval,json=crlReloadInterval,proto3" json:"CRLReloadInterval,omitempty"`
+	// [OPTIONAL]
+	// OCSP enables revocation checking with OCSP responders
+	// written in the certificates.
+	// Responses are cached until their NextUpdate.
+	// Default is [false].
+	OCSP bool `protobuf:"varint,3,opt,name=OCSP,json=ocsp,proto3" json:"OCSP,omitempty"`
+	// [OPTIONAL]
+	// OCSPTimeout is the timeout in milliseconds
+	// of requests to OCSP responders.
+	// Default is [5000] milliseconds.
+	OCSPTimeout int32 `protobuf:"varint,4,opt,name=OCSPTimeout,json=ocspTimeout,proto3" json:"OCSPTimeout,omitempty"`
+	// [OPTIONAL]
+	// SoftFail accepts certificates when their revocation status
+	// could not be determined.
+	// Revoked certificates are rejected regardless of this field.
+	// Default is [false], or hard-fail.
+	SoftFail      bool `protobuf:"varint,5,opt,name=SoftFail,json=softFail,proto3" json:"SoftFail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevocationConfig) Reset() {
+	*x = RevocationConfig{}
+	mi := &file_kernel_network_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevocationConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationConfig) ProtoMessage() {}
+
+func (x *RevocationConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_kernel_network_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationConfig.ProtoReflect.Descriptor instead.
+func (*RevocationConfig) Descriptor() ([]byte, []int) {
+	return file_kernel_network_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RevocationConfig) GetCRLFiles() []string {
+	if x != nil {
+		return x.CRLFiles
+	}
+	return nil
+}
+
+func (x *RevocationConfig) GetCRLReloadInterval() int32 {
+	if x != nil {
+		return x.CRLReloadInterval
+	}
+	return 0
+}
+
+func (x *RevocationConfig) GetOCSP() bool {
+	if x != nil {
+		return x.OCSP
+	}
+	return false
+}
+
+func (x *RevocationConfig) GetOCSPTimeout() int32 {
+	if x != nil {
+		return x.OCSPTimeout
+	}
+	return 0
+}
+
+func (x *RevocationConfig) GetSoftFail() bool {
+	if x != nil {
+		return x.SoftFail
+	}
+	return false
+}
+
 // + CertKeyPair
 // CertKeyPair is the pair of TLS cert file path
 // and kery file path.
@@ -1711,7 +2008,7 @@ type CertKeyPair struct {
 
 func (x *CertKeyPair) Reset() {
 	*x = CertKeyPair{}
-	mi := &file_kernel_network_proto_msgTypes[7]
+	mi := &file_kernel_network_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1723,7 +2020,7 @@ func (x *CertKeyPair) String() string {
 func (*CertKeyPair) ProtoMessage() {}
 
 func (x *CertKeyPair) ProtoReflect() protoreflect.Message {
-	mi := &file_kernel_network_proto_msgTypes[7]
+	mi := &file_kernel_network_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1736,7 +2033,7 @@ func (x *CertKeyPair) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CertKeyPair.ProtoReflect.Descriptor instead.
 func (*CertKeyPair) Descriptor() ([]byte, []int) {
-	return file_kernel_network_proto_rawDescGZIP(), []int{7}
+	return file_kernel_network_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CertKeyPair) GetCertFile() string {
@@ -1838,7 +2135,7 @@ type QuicConfig struct {
 
 func (x *QuicConfig) Reset() {
 	*x = QuicConfig{}
-	mi := &file_kernel_network_proto_msgTypes[8]
+	mi := &file_kernel_network_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1850,7 +2147,7 @@ func (x *QuicConfig) String() string {
 func (*QuicConfig) ProtoMessage() {}
 
 func (x *QuicConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_kernel_network_proto_msgTypes[8]
+	mi := &file_kernel_network_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1863,7 +2160,7 @@ func (x *QuicConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QuicConfig.ProtoReflect.Descriptor instead.
 func (*QuicConfig) Descriptor() ([]byte, []int) {
-	return file_kernel_network_proto_rawDescGZIP(), []int{8}
+	return file_kernel_network_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *QuicConfig) GetVersions() []QuicConfig_Version {
@@ -1961,7 +2258,7 @@ var File_kernel_network_proto protoreflect.FileDescriptor
 
 const file_kernel_network_proto_rawDesc = "" +
 	"\n" +
-	"\x14kernel/network.proto\x12\x06kernel\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/sockopts.proto\"\xca\x05\n" +
+	"\x14kernel/network.proto\x12\x06kernel\x1a\x1bbuf/validate/validate.proto\x1a\x14kernel/options.proto\x1a\x15kernel/resource.proto\x1a\x15kernel/sockopts.proto\"\xca\x05\n" +
 	"\x13HTTPTransportConfig\x12/\n" +
 	"\tTLSConfig\x18\x01 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x120\n" +
 	"\x13TLSHandshakeTimeout\x18\x02 \x01(\x03R\x13tlsHandshakeTimeout\x12,\n" +
@@ -2001,7 +2298,7 @@ const file_kernel_network_proto_rawDesc = "" +
 	"DialConfig\x18\r \x01(\v2\x12.kernel.DialConfigR\n" +
 	"dialConfig\x12(\n" +
 	"\x0fMultiIPConnPool\x18\x0e \x01(\bR\x0fmultiIPConnPool\x12,\n" +
-	"\x11MinLookupInterval\x18\x0f \x01(\rR\x11minLookupInterval\"\xb2\x02\n" +
+	"\x11MinLookupInterval\x18\x0f \x01(\rR\x11minLookupInterval\"\xd8\x02\n" +
 	"\n" +
 	"DialConfig\x12/\n" +
 	"\tTLSConfig\x18\x01 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\"\n" +
@@ -2011,7 +2308,8 @@ const file_kernel_network_proto_rawDesc = "" +
 	"\rFallbackDelay\x18\x05 \x01(\x05R\rfallbackDelay\x122\n" +
 	"\n" +
 	"SockOption\x18\x06 \x01(\v2\x12.kernel.SockOptionR\n" +
-	"sockOption\"\x8d\x02\n" +
+	"sockOption\x12$\n" +
+	"\rProxyProtocol\x18\a \x01(\bR\rproxyProtocol\"\x8d\x02\n" +
 	"\x14HTTP3TransportConfig\x12/\n" +
 	"\tTLSConfig\x18\x01 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x122\n" +
 	"\n" +
@@ -2019,11 +2317,13 @@ const file_kernel_network_proto_rawDesc = "" +
 	"quicConfig\x12.\n" +
 	"\x12DisableCompression\x18\x03 \x01(\bR\x12disableCompression\x12(\n" +
 	"\x0fEnableDatagrams\x18\x04 \x01(\bR\x0fenableDatagrams\x126\n" +
-	"\x16MaxResponseHeaderBytes\x18\x05 \x01(\x03R\x16maxResponseHeaderBytes\"\xe4\x02\n" +
+	"\x16MaxResponseHeaderBytes\x18\x05 \x01(\x03R\x16maxResponseHeaderBytes\"\x91\x04\n" +
 	"\fListenConfig\x12/\n" +
 	"\tTLSConfig\x18\x01 \x01(\v2\x11.kernel.TLSConfigR\ttlsConfig\x12\x12\n" +
 	"\x04Addr\x18\x04 \x01(\tR\x04addr\x12(\n" +
-	"\x0fConnectionLimit\x18\x05 \x01(\x05R\x0fconnectionLimit\x12$\n" +
+	"\x0fConnectionLimit\x18\x05 \x01(\x05R\x0fconnectionLimit\x122\n" +
+	"\x14ConnectionLimitPerIP\x18\x0e \x01(\x05R\x14connectionLimitPerIP\x124\n" +
+	"\x15ConnectionWaitTimeout\x18\x0f \x01(\x05R\x15connectionWaitTimeout\x12$\n" +
 	"\bNetworks\x18\x06 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\bnetworks\x12\"\n" +
 	"\fReadDeadline\x18\b \x01(\x05R\freadDeadline\x12$\n" +
 	"\rWriteDeadline\x18\t \x01(\x05R\rwriteDeadline\x12A\n" +
@@ -2031,15 +2331,20 @@ const file_kernel_network_proto_rawDesc = "" +
 	" \x01(\v2\x17.kernel.KeepAliveConfigR\x0fkeepAliveConfig\x122\n" +
 	"\n" +
 	"SockOption\x18\f \x01(\v2\x12.kernel.SockOptionR\n" +
-	"sockOption\"q\n" +
+	"sockOption\x12A\n" +
+	"\rProxyProtocol\x18\r \x01(\v2\x1b.kernel.ProxyProtocolConfigR\rproxyProtocol\"z\n" +
+	"\x13ProxyProtocolConfig\x124\n" +
+	"\x0fTrustedNetworks\x18\x01 \x03(\tB\n" +
+	"\xbaH\a\x92\x01\x04\b\x01\x18\x01R\x0ftrustedNetworks\x12-\n" +
+	"\rHeaderTimeout\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\rheaderTimeout\"q\n" +
 	"\x0fKeepAliveConfig\x12\x18\n" +
 	"\aDisable\x18\x01 \x01(\bR\adisable\x12\x12\n" +
 	"\x04Idle\x18\x02 \x01(\x05R\x04idle\x12\x1a\n" +
 	"\bInterval\x18\x03 \x01(\x05R\binterval\x12\x14\n" +
-	"\x05Count\x18\x04 \x01(\x05R\x05count\"\xc5\x05\n" +
+	"\x05Count\x18\x04 \x01(\x05R\x05count\"\xfe\x06\n" +
 	"\tTLSConfig\x127\n" +
-	"\fCertKeyPairs\x18\x01 \x03(\v2\x13.kernel.CertKeyPairR\fcertKeyPairs\x12\"\n" +
-	"\aRootCAs\x18\x02 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\arootCAs\x12\x1e\n" +
+	"\fCertKeyPairs\x18\x01 \x03(\v2\x13.kernel.CertKeyPairR\fcertKeyPairs\x12&\n" +
+	"\aRootCAs\x18\x02 \x03(\tB\f\xbaH\x05\x92\x01\x02\x18\x01\xc8\xf3\x18\x01R\arootCAs\x12\x1e\n" +
 	"\n" +
 	"NextProtos\x18\x03 \x03(\tR\n" +
 	"nextProtos\x12\x1e\n" +
@@ -2048,8 +2353,8 @@ const file_kernel_network_proto_rawDesc = "" +
 	"serverName\x126\n" +
 	"\n" +
 	"ClientAuth\x18\x05 \x01(\x0e2\x16.kernel.ClientAuthTypeR\n" +
-	"clientAuth\x12&\n" +
-	"\tClientCAs\x18\x06 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\tclientCAs\x12.\n" +
+	"clientAuth\x12*\n" +
+	"\tClientCAs\x18\x06 \x03(\tB\f\xbaH\x05\x92\x01\x02\x18\x01\xc8\xf3\x18\x01R\tclientCAs\x12.\n" +
 	"\x12InsecureSkipVerify\x18\a \x01(\bR\x12insecureSkipVerify\x120\n" +
 	"\n" +
 	"TLSCiphers\x18\b \x03(\x0e2\x11.kernel.TLSCipherR\ttlsCipher\x126\n" +
@@ -2063,10 +2368,21 @@ const file_kernel_network_proto_rawDesc = "" +
 	"maxVersion\x12E\n" +
 	"\x10CurvePreferences\x18\f \x03(\x0e2\x0f.kernel.CurveIDB\b\xbaH\x05\x92\x01\x02\x18\x01R\x10curvePreferences\x12@\n" +
 	"\x1bDynamicRecordSizingDisabled\x18\r \x01(\bR\x1bdynamicRecordSizingDisabled\x12B\n" +
-	"\rRenegotiation\x18\x0e \x01(\x0e2\x1c.kernel.RenegotiationSupportR\rrenegotiation\"C\n" +
-	"\vCertKeyPair\x12\x1a\n" +
-	"\bCertFile\x18\x01 \x01(\tR\bcertFile\x12\x18\n" +
-	"\aKeyFile\x18\x02 \x01(\tR\akeyFile\"\xe3\x05\n" +
+	"\rRenegotiation\x18\x0e \x01(\x0e2\x1c.kernel.RenegotiationSupportR\rrenegotiation\x12Q\n" +
+	"\vCertManager\x18\x0f \x01(\v2\x11.kernel.ReferenceB\x1c\xd2\xf3\x18\x18core.CertificateProviderR\vcertManager\x128\n" +
+	"\n" +
+	"Revocation\x18\x10 \x01(\v2\x18.kernel.RevocationConfigR\n" +
+	"revocation\x12\"\n" +
+	"\fOCSPStapling\x18\x11 \x01(\bR\focspStapling\"\xce\x01\n" +
+	"\x10RevocationConfig\x12(\n" +
+	"\bCRLFiles\x18\x01 \x03(\tB\f\xbaH\x05\x92\x01\x02\x18\x01\xc8\xf3\x18\x01R\bcrlFiles\x125\n" +
+	"\x11CRLReloadInterval\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x11crlReloadInterval\x12\x12\n" +
+	"\x04OCSP\x18\x03 \x01(\bR\x04ocsp\x12)\n" +
+	"\vOCSPTimeout\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\vocspTimeout\x12\x1a\n" +
+	"\bSoftFail\x18\x05 \x01(\bR\bsoftFail\"O\n" +
+	"\vCertKeyPair\x12 \n" +
+	"\bCertFile\x18\x01 \x01(\tB\x04\xc8\xf3\x18\x01R\bcertFile\x12\x1e\n" +
+	"\aKeyFile\x18\x02 \x01(\tB\x04\xc8\xf3\x18\x01R\akeyFile\"\xe3\x05\n" +
 	"\n" +
 	"QuicConfig\x12@\n" +
 	"\bVersions\x18\x01 \x03(\x0e2\x1a.kernel.QuicConfig.VersionB\b\xbaH\x05\x92\x01\x02\x18\x01R\bversions\x122\n" +
@@ -2162,7 +2478,7 @@ func file_kernel_network_proto_rawDescGZIP() []byte {
 }
 
 var file_kernel_network_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_kernel_network_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_kernel_network_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_kernel_network_proto_goTypes = []any{
 	(NetworkType)(0),             // 0: kernel.NetworkType
 	(ClientAuthType)(0),          // 1: kernel.ClientAuthType
@@ -2175,35 +2491,41 @@ var file_kernel_network_proto_goTypes = []any{
 	(*DialConfig)(nil),           // 8: kernel.DialConfig
 	(*HTTP3TransportConfig)(nil), // 9: kernel.HTTP3TransportConfig
 	(*ListenConfig)(nil),         // 10: kernel.ListenConfig
-	(*KeepAliveConfig)(nil),      // 11: kernel.KeepAliveConfig
-	(*TLSConfig)(nil),            // 12: kernel.TLSConfig
-	(*CertKeyPair)(nil),          // 13: kernel.CertKeyPair
-	(*QuicConfig)(nil),           // 14: kernel.QuicConfig
-	(*SockOption)(nil),           // 15: kernel.SockOption
+	(*ProxyProtocolConfig)(nil),  // 11: kernel.ProxyProtocolConfig
+	(*KeepAliveConfig)(nil),      // 12: kernel.KeepAliveConfig
+	(*TLSConfig)(nil),            // 13: kernel.TLSConfig
+	(*RevocationConfig)(nil),     // 14: kernel.RevocationConfig
+	(*CertKeyPair)(nil),          // 15: kernel.CertKeyPair
+	(*QuicConfig)(nil),           // 16: kernel.QuicConfig
+	(*SockOption)(nil),           // 17: kernel.SockOption
+	(*Reference)(nil),            // 18: kernel.Reference
 }
 var file_kernel_network_proto_depIdxs = []int32{
-	12, // 0: kernel.HTTPTransportConfig.TLSConfig:type_name -> kernel.TLSConfig
+	13, // 0: kernel.HTTPTransportConfig.TLSConfig:type_name -> kernel.TLSConfig
 	8,  // 1: kernel.HTTPTransportConfig.DialConfig:type_name -> kernel.DialConfig
-	12, // 2: kernel.HTTP2TransportConfig.TLSConfig:type_name -> kernel.TLSConfig
+	13, // 2: kernel.HTTP2TransportConfig.TLSConfig:type_name -> kernel.TLSConfig
 	8,  // 3: kernel.HTTP2TransportConfig.DialConfig:type_name -> kernel.DialConfig
-	12, // 4: kernel.DialConfig.TLSConfig:type_name -> kernel.TLSConfig
-	15, // 5: kernel.DialConfig.SockOption:type_name -> kernel.SockOption
-	12, // 6: kernel.HTTP3TransportConfig.TLSConfig:type_name -> kernel.TLSConfig
-	14, // 7: kernel.HTTP3TransportConfig.QuicConfig:type_name -> kernel.QuicConfig
-	12, // 8: kernel.ListenConfig.TLSConfig:type_name -> kernel.TLSConfig
-	11, // 9: kernel.ListenConfig.KeepAliveConfig:type_name -> kernel.KeepAliveConfig
-	15, // 10: kernel.ListenConfig.SockOption:type_name -> kernel.SockOption
-	13, // 11: kernel.TLSConfig.CertKeyPairs:type_name -> kernel.CertKeyPair
-	1,  // 12: kernel.TLSConfig.ClientAuth:type_name -> kernel.ClientAuthType
-	4,  // 13: kernel.TLSConfig.TLSCiphers:type_name -> kernel.TLSCipher
-	3,  // 14: kernel.TLSConfig.CurvePreferences:type_name -> kernel.CurveID
-	2,  // 15: kernel.TLSConfig.Renegotiation:type_name -> kernel.RenegotiationSupport
-	5,  // 16: kernel.QuicConfig.Versions:type_name -> kernel.QuicConfig.Version
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	13, // 4: kernel.DialConfig.TLSConfig:type_name -> kernel.TLSConfig
+	17, // 5: kernel.DialConfig.SockOption:type_name -> kernel.SockOption
+	13, // 6: kernel.HTTP3TransportConfig.TLSConfig:type_name -> kernel.TLSConfig
+	16, // 7: kernel.HTTP3TransportConfig.QuicConfig:type_name -> kernel.QuicConfig
+	13, // 8: kernel.ListenConfig.TLSConfig:type_name -> kernel.TLSConfig
+	12, // 9: kernel.ListenConfig.KeepAliveConfig:type_name -> kernel.KeepAliveConfig
+	17, // 10: kernel.ListenConfig.SockOption:type_name -> kernel.SockOption
+	11, // 11: kernel.ListenConfig.ProxyProtocol:type_name -> kernel.ProxyProtocolConfig
+	15, // 12: kernel.TLSConfig.CertKeyPairs:type_name -> kernel.CertKeyPair
+	1,  // 13: kernel.TLSConfig.ClientAuth:type_name -> kernel.ClientAuthType
+	4,  // 14: kernel.TLSConfig.TLSCiphers:type_name -> kernel.TLSCipher
+	3,  // 15: kernel.TLSConfig.CurvePreferences:type_name -> kernel.CurveID
+	2,  // 16: kernel.TLSConfig.Renegotiation:type_name -> kernel.RenegotiationSupport
+	18, // 17: kernel.TLSConfig.CertManager:type_name -> kernel.Reference
+	14, // 18: kernel.TLSConfig.Revocation:type_name -> kernel.RevocationConfig
+	5,  // 19: kernel.QuicConfig.Versions:type_name -> kernel.QuicConfig.Version
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_kernel_network_proto_init() }
@@ -2211,6 +2533,8 @@ func file_kernel_network_proto_init() {
 	if File_kernel_network_proto != nil {
 		return
 	}
+	file_kernel_options_proto_init()
+	file_kernel_resource_proto_init()
 	file_kernel_sockopts_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -2218,7 +2542,7 @@ func file_kernel_network_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kernel_network_proto_rawDesc), len(file_kernel_network_proto_rawDesc)),
 			NumEnums:      6,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1388,7 +1388,7 @@ var File_kernel_replacer_proto protoreflect.FileDescriptor
 
 const file_kernel_replacer_proto_rawDesc = "" +
 	"\n" +
-	"\x15kernel/replacer.proto\x12\x06kernel\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/encoding.proto\x1a\x11kernel/hash.proto\x1a\x16kernel/commonkey.proto\"\x92\x06\n" +
+	"\x15kernel/replacer.proto\x12\x06kernel\x1a\x1bbuf/validate/validate.proto\x1a\x15kernel/encoding.proto\x1a\x11kernel/hash.proto\x1a\x16kernel/commonkey.proto\x1a\x14kernel/options.proto\"\x92\x06\n" +
 	"\fReplacerSpec\x12-\n" +
 	"\x05Fixed\x18\x01 \x01(\v2\x15.kernel.FixedReplacerH\x00R\x05fixed\x12-\n" +
 	"\x05Value\x18\x02 \x01(\v2\x15.kernel.ValueReplacerH\x00R\x05value\x12*\n" +
@@ -1451,19 +1451,21 @@ const file_kernel_replacer_proto_rawDesc = "" +
 	"\x0eExpandReplacer\x12!\n" +
 	"\aPattern\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\apattern\x12\x1a\n" +
 	"\bTemplate\x18\x02 \x01(\tR\btemplate\x12\x14\n" +
-	"\x05POSIX\x18\x03 \x01(\bR\x05posix\"\xd5\x01\n" +
+	"\x05POSIX\x18\x03 \x01(\bR\x05posix\"\xd9\x01\n" +
 	"\x0fEncryptReplacer\x12\x18\n" +
 	"\aPattern\x18\x01 \x01(\tR\apattern\x12\x14\n" +
 	"\x05POSIX\x18\x02 \x01(\bR\x05posix\x124\n" +
 	"\x03Alg\x18\x03 \x01(\x0e2\x1a.kernel.CommonKeyCryptTypeB\x06\xbaH\x03\xc8\x01\x01R\x03alg\x128\n" +
-	"\bEncoding\x18\x04 \x01(\x0e2\x14.kernel.EncodingTypeB\x06\xbaH\x03\xc8\x01\x01R\bencoding\x12\"\n" +
-	"\bPassword\x18\x05 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\bpassword\"\xbd\x01\n" +
+	"\bEncoding\x18\x04 \x01(\x0e2\x14.kernel.EncodingTypeB\x06\xbaH\x03\xc8\x01\x01R\bencoding\x12&\n" +
+	"\bPassword\x18\x05 \x01(\tB\n" +
+	"\xbaH\x03\xc8\x01\x01\xc0\xf3\x18\x01R\bpassword\"\xc1\x01\n" +
 	"\fHMACReplacer\x12\x18\n" +
 	"\aPattern\x18\x01 \x01(\tR\apattern\x12\x14\n" +
 	"\x05POSIX\x18\x02 \x01(\bR\x05posix\x12)\n" +
 	"\x03Alg\x18\x03 \x01(\x0e2\x0f.kernel.HashAlgB\x06\xbaH\x03\xc8\x01\x01R\x03alg\x128\n" +
-	"\bEncoding\x18\x04 \x01(\x0e2\x14.kernel.EncodingTypeB\x06\xbaH\x03\xc8\x01\x01R\bencoding\x12\x18\n" +
-	"\x03Key\x18\x05 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x03key*\xc4\x01\n" +
+	"\bEncoding\x18\x04 \x01(\x0e2\x14.kernel.EncodingTypeB\x06\xbaH\x03\xc8\x01\x01R\bencoding\x12\x1c\n" +
+	"\x03Key\x18\x05 \x01(\tB\n" +
+	"\xbaH\x03\xc8\x01\x01\xc0\xf3\x18\x01R\x03key*\xc4\x01\n" +
 	"\vReplaceType\x12\t\n" +
 	"\x05Fixed\x10\x00\x12\t\n" +
 	"\x05Value\x10\x01\x12\b\n" +
@@ -1563,6 +1565,7 @@ func file_kernel_replacer_proto_init() {
 	file_kernel_encoding_proto_init()
 	file_kernel_hash_proto_init()
 	file_kernel_commonkey_proto_init()
+	file_kernel_options_proto_init()
 	file_kernel_replacer_proto_msgTypes[0].OneofWrappers = []any{
 		(*ReplacerSpec_Fixed)(nil),
 		(*ReplacerSpec_Value)(nil),
@@ -12,6 +12,7 @@ import (
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"google.golang.org/protobuf/proto"
+	"reflect"
 )
 
 const (
@@ -31,6 +32,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.AuthenticationMiddlewareSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*authn]()},
 	},
 }
 
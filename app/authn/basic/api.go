@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -45,6 +46,7 @@ var Resource api.Resource = &API{
 				},
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*handler]()},
 	},
 }
 
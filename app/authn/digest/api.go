@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"reflect"
 	"strings"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -46,6 +47,7 @@ var Resource api.Resource = &API{
 				},
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*handler]()},
 	},
 }
 
@@ -7,6 +7,7 @@ import (
 	"cmp"
 	"net/http"
 	"os"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -44,6 +45,12 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.OAuthAuthenticationHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{
+			reflect.TypeFor[*authorizationCodeHandler](),
+			reflect.TypeFor[*clientCredentialsHandler](),
+			reflect.TypeFor[*ropcHandler](),
+			reflect.TypeFor[*resourceServerHandler](),
+		},
 	},
 }
 
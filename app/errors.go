@@ -42,6 +42,7 @@ var (
 	// app/authz: E3100 - E3149
 	ErrAppAuthzAuthorization = errorutil.NewKind("E3100", "AppAuthzAuthorization", "authorization failed")
 	ErrAppAuthzForbidden     = errorutil.NewKind("E3101", "ErrAppAuthzForbidden", "forbidden on authorization")
+	ErrAppAuthzExtAuthz      = errorutil.NewKind("E3102", "AppAuthzExtAuthz", "external authorization failed.")
 	// ---------------------------------------------------------
 
 	// ---------------------------------------------------------
@@ -71,6 +72,10 @@ var (
 	ErrAppMiddleSOAPRESTInvalidContentType = errorutil.NewKind("E3219", "AppMiddleSOAPRESTInvalidContentType", "invalid content type. expected:application/json got:{{type}}")
 	ErrAppMiddleSOAPRESTConvertJSONtoXML   = errorutil.NewKind("E3220", "AppMiddleSOAPRESTConvertJSONtoXML", "failed to convert json body to xml.")
 	ErrAppMiddleSOAPRESTWriteResponseBody  = errorutil.NewKind("E3221", "AppMiddleSOAPRESTWriteResponseBody", "failed to write response body.")
+	ErrAppMiddleMaintenance                = errorutil.NewKind("E3222", "AppMiddleMaintenance", "service unavailable due to maintenance.")
+	ErrAppMiddleWasm                       = errorutil.NewKind("E3223", "AppMiddleWasm", "failed to call wasm plugin function {{function}}.")
+	ErrAppMiddleExtProc                    = errorutil.NewKind("E3224", "AppMiddleExtProc", "external processing failed.")
+	ErrAppMiddleScript                     = errorutil.NewKind("E3225", "AppMiddleScript", "failed to run script hook {{hook}}.")
 	// ---------------------------------------------------------
 
 	// ---------------------------------------------------------
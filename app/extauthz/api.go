@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "ExtAuthzMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+const (
+	// defaultTimeout is the default timeout in milliseconds
+	// of authorization requests.
+	// This is the same as the Envoy's default.
+	defaultTimeout = 200
+	// defaultMaxCacheEntries is the default maximum number of cached decisions.
+	defaultMaxCacheEntries = 10_000
+)
+
+var errInvalidScheme = errors.New("extauthz: endpoint scheme must be http or https")
+
+// errCacheWithoutHeaders is returned when decisions are cached
+// without limiting the headers sent to the authorization service.
+// All headers are included in the cache key in that case
+// which makes the cache hit almost never.
+var errCacheWithoutHeaders = errors.New("extauthz: allowedHeaders must be set to cache decisions")
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.ExtAuthzMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.ExtAuthzMiddlewareSpec{
+				Timeout:         defaultTimeout,
+				ClaimsKey:       "AuthnClaims",
+				MaxCacheEntries: defaultMaxCacheEntries,
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*extAuthz]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.ExtAuthzMiddleware)
+	lg := log.DefaultOr(c.Metadata.Logger)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	u, err := url.Parse(c.Spec.Endpoint)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, core.ErrCoreGenCreateObject.WithStack(errInvalidScheme, map[string]any{"kind": kind})
+	}
+	endpoint := strings.TrimSuffix(c.Spec.Endpoint, "/")
+
+	var ck checker
+	switch c.Spec.Protocol {
+	case v1.ExtAuthzProtocol_ExtAuthzGRPC:
+		rt, err := network.HTTP2Transport(c.Spec.HTTP2TransportConfig)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		ck = &grpcChecker{rt: rt, url: endpoint + authv3.Authorization_Check_FullMethodName}
+	default:
+		var rt http.RoundTripper = network.DefaultHTTPTransport
+		if c.Spec.RoundTripper != nil {
+			rt, err = api.ReferTypedObject[http.RoundTripper](a, c.Spec.RoundTripper)
+			if err != nil {
+				return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+			}
+		}
+		ck = &httpChecker{
+			rt:              rt,
+			endpoint:        endpoint,
+			upstreamHeaders: canonicalHeaders(c.Spec.AllowedUpstreamHeaders),
+			clientHeaders:   canonicalHeaders(c.Spec.AllowedClientHeaders),
+		}
+	}
+
+	var ch *cache
+	if c.Spec.CacheTTL > 0 {
+		if len(c.Spec.AllowedHeaders) == 0 {
+			return nil, core.ErrCoreGenCreateObject.WithStack(errCacheWithoutHeaders, map[string]any{"kind": kind})
+		}
+		ttl := time.Millisecond * time.Duration(c.Spec.CacheTTL)
+		ch = newCache(ttl, int(cmp.Or(c.Spec.MaxCacheEntries, defaultMaxCacheEntries)))
+	}
+
+	return &extAuthz{
+		lg:             lg,
+		eh:             eh,
+		checker:        ck,
+		timeout:        time.Millisecond * time.Duration(cmp.Or(c.Spec.Timeout, defaultTimeout)),
+		failOpen:       c.Spec.FailOpen,
+		allowedHeaders: canonicalHeaders(c.Spec.AllowedHeaders),
+		claimsKey:      c.Spec.ClaimsKey,
+		includeClaims:  c.Spec.IncludeClaims,
+		includeCert:    c.Spec.IncludeClientCert,
+		cache:          ch,
+	}, nil
+}
+
+// canonicalHeaders returns the canonical header names.
+func canonicalHeaders(names []string) []string {
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		canonical = append(canonical, textproto.CanonicalMIMEHeaderKey(name))
+	}
+	return canonical
+}
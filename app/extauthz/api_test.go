@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		check      func(*testing.T, *extAuthz)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create http checker",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtAuthzMiddlewareSpec{
+						Endpoint:               "http://127.0.0.1:8080/auth/",
+						AllowedHeaders:         []string{"authorization"},
+						AllowedUpstreamHeaders: []string{"x-user"},
+						AllowedClientHeaders:   []string{"www-authenticate"},
+						ClaimsKey:              "AuthnClaims",
+						IncludeClaims:          true,
+						IncludeClientCert:      true,
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, m *extAuthz) {
+					t.Helper()
+					c := m.checker.(*httpChecker)
+					testutil.Diff(t, "http://127.0.0.1:8080/auth", c.endpoint)
+					testutil.Diff(t, []string{"X-User"}, c.upstreamHeaders)
+					testutil.Diff(t, []string{"Www-Authenticate"}, c.clientHeaders)
+					testutil.Diff(t, true, c.rt == http.RoundTripper(network.DefaultHTTPTransport))
+					testutil.Diff(t, []string{"Authorization"}, m.allowedHeaders)
+					testutil.Diff(t, 200*time.Millisecond, m.timeout)
+					testutil.Diff(t, "AuthnClaims", m.claimsKey)
+					testutil.Diff(t, true, m.includeClaims)
+					testutil.Diff(t, true, m.includeCert)
+					testutil.Diff(t, false, m.failOpen)
+					testutil.Diff(t, true, m.cache == nil)
+				},
+			},
+		),
+		gen(
+			"create grpc checker",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtAuthzMiddlewareSpec{
+						Endpoint:             "http://127.0.0.1:50051",
+						Protocol:             v1.ExtAuthzProtocol_ExtAuthzGRPC,
+						HTTP2TransportConfig: &k.HTTP2TransportConfig{AllowHTTP: true},
+						Timeout:              1000,
+						FailOpen:             true,
+						AllowedHeaders:       []string{"authorization"},
+						CacheTTL:             500,
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, m *extAuthz) {
+					t.Helper()
+					c := m.checker.(*grpcChecker)
+					testutil.Diff(t, "http://127.0.0.1:50051/envoy.service.auth.v3.Authorization/Check", c.url)
+					testutil.Diff(t, time.Second, m.timeout)
+					testutil.Diff(t, true, m.failOpen)
+					testutil.Diff(t, 500*time.Millisecond, m.cache.ttl)
+					testutil.Diff(t, defaultMaxCacheEntries, m.cache.max)
+				},
+			},
+		),
+		gen(
+			"invalid endpoint",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ExtAuthzMiddlewareSpec{Endpoint: "http://[::1"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ExtAuthzMiddleware`),
+			},
+		),
+		gen(
+			"cache without allowed headers",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ExtAuthzMiddlewareSpec{Endpoint: "http://127.0.0.1", CacheTTL: 500},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`allowedHeaders must be set to cache decisions`),
+			},
+		),
+		gen(
+			"invalid scheme",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ExtAuthzMiddlewareSpec{Endpoint: "ftp://127.0.0.1"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`endpoint scheme must be http or https`),
+			},
+		),
+		gen(
+			"fail to get round tripper",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtAuthzMiddlewareSpec{
+						Endpoint:     "http://127.0.0.1:8080",
+						RoundTripper: &k.Reference{APIVersion: "wrong"},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ExtAuthzMiddleware`),
+			},
+		),
+		gen(
+			"invalid tls config",
+			&condition{
+				manifest: &v1.ExtAuthzMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtAuthzMiddlewareSpec{
+						Endpoint: "https://127.0.0.1:50051",
+						Protocol: v1.ExtAuthzProtocol_ExtAuthzGRPC,
+						HTTP2TransportConfig: &k.HTTP2TransportConfig{
+							TLSConfig: &k.TLSConfig{RootCAs: []string{"not-exist.pem"}},
+						},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ExtAuthzMiddleware`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			tt.A.check(t, got.(*extAuthz))
+		})
+	}
+}
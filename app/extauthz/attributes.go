@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// excludedHeaders are the request headers
+// which are never sent to the authorization service.
+var excludedHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"Content-Length",
+}
+
+// attributes are the request attributes
+// sent to the authorization service.
+type attributes struct {
+	method string
+	scheme string
+	host   string
+	// path is the path and the query of the request.
+	path string
+	// remoteIP is the IP address of the client.
+	remoteIP   string
+	remotePort uint32
+	proto      string
+	header     http.Header
+	// claims is the JSON encoded claims.
+	// This is nil when claims are not sent.
+	claims []byte
+	// cert is the URL encoded PEM of the client certificate.
+	// This is empty when the certificate is not sent.
+	cert string
+}
+
+// newAttributes returns the attributes of the request.
+// allowed is the canonical header names sent to the authorization service.
+// All headers are sent when allowed is empty.
+func newAttributes(r *http.Request, allowed []string, claims []byte, includeCert bool) *attributes {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	a := &attributes{
+		method: r.Method,
+		scheme: scheme,
+		host:   r.Host,
+		path:   r.URL.RequestURI(),
+		proto:  r.Proto,
+		header: make(http.Header, len(r.Header)),
+		claims: claims,
+	}
+	if host, port, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		a.remoteIP = host
+		if p, err := strconv.ParseUint(port, 10, 16); err == nil {
+			a.remotePort = uint32(p)
+		}
+	}
+	for name, values := range r.Header {
+		if slices.Contains(excludedHeaders, name) {
+			continue
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, name) {
+			continue
+		}
+		a.header[name] = values
+	}
+	if includeCert && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.TLS.PeerCertificates[0].Raw})
+		a.cert = url.QueryEscape(string(b))
+	}
+	return a
+}
+
+// key returns the cache key of the attributes.
+// The remote port is not included so that
+// decisions are shared between connections of a client.
+func (a *attributes) key() [sha256.Size]byte {
+	h := sha256.New()
+	write := func(s string) {
+		// Write length-prefixed values to avoid ambiguity.
+		_ = binary.Write(h, binary.BigEndian, uint32(len(s)))
+		h.Write([]byte(s))
+	}
+	write(a.method)
+	write(a.scheme)
+	write(a.host)
+	write(a.path)
+	write(a.remoteIP)
+	write(a.proto)
+	names := make([]string, 0, len(a.header))
+	for name := range a.header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		write(name)
+		write(strings.Join(a.header[name], "\n"))
+	}
+	write(string(a.claims))
+	write(a.cert)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// marshalClaims returns the JSON encoded claims.
+// It returns nil when the claims is nil.
+func marshalClaims(claims any) ([]byte, error) {
+	if claims == nil {
+		return nil, nil
+	}
+	return json.Marshal(claims)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// cache caches decisions for the ttl.
+// Entries are evicted in the insertion order
+// when the number of entries exceeded the max.
+// Because all entries have the same ttl,
+// the oldest entry is the one that expires first.
+type cache struct {
+	ttl time.Duration
+	max int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	// order holds cacheEntry in the insertion order.
+	order *list.List
+}
+
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	d       *decision
+	expires time.Time
+}
+
+func newCache(ttl time.Duration, max int) *cache {
+	return &cache{
+		ttl:     ttl,
+		max:     max,
+		entries: map[[sha256.Size]byte]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// get returns the cached decision.
+// It returns nil when not found or expired.
+func (c *cache) get(key [sha256.Size]byte) *decision {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	ce := e.Value.(*cacheEntry)
+	if time.Now().After(ce.expires) {
+		c.order.Remove(e)
+		delete(c.entries, key)
+		return nil
+	}
+	return ce.d
+}
+
+// set caches the decision.
+func (c *cache) set(key [sha256.Size]byte, d *decision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+	for c.order.Len() >= c.max {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[key] = c.order.PushBack(&cacheEntry{key: key, d: d, expires: time.Now().Add(c.ttl)})
+}
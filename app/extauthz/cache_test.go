@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestCache(t *testing.T) {
+	key := func(s string) [sha256.Size]byte { return sha256.Sum256([]byte(s)) }
+	d1, d2, d3 := &decision{status: 1}, &decision{status: 2}, &decision{status: 3}
+
+	t.Run("evict oldest", func(t *testing.T) {
+		c := newCache(time.Minute, 2)
+		c.set(key("1"), d1)
+		c.set(key("2"), d2)
+		c.set(key("1"), d1) // Moves to the newest.
+		c.set(key("3"), d3)
+		testutil.Diff(t, true, c.get(key("1")) == d1)
+		testutil.Diff(t, true, c.get(key("2")) == nil)
+		testutil.Diff(t, true, c.get(key("3")) == d3)
+		testutil.Diff(t, 2, c.order.Len())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c := newCache(time.Millisecond, 2)
+		c.set(key("1"), d1)
+		time.Sleep(5 * time.Millisecond)
+		testutil.Diff(t, true, c.get(key("1")) == nil)
+		testutil.Diff(t, 0, c.order.Len())
+		testutil.Diff(t, 0, len(c.entries))
+	})
+}
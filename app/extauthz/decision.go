@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// checker checks the request attributes
+// with the authorization service.
+type checker interface {
+	check(ctx context.Context, a *attributes) (*decision, error)
+}
+
+// headerAction is the action to apply a header.
+type headerAction int
+
+const (
+	// headerSet overwrites the header.
+	headerSet headerAction = iota
+	// headerAdd appends the value to the header.
+	headerAdd
+	// headerAddIfAbsent sets the header only when not exists.
+	headerAddIfAbsent
+	// headerSetIfExists overwrites the header only when exists.
+	headerSetIfExists
+)
+
+// headerOp is an operation to a header.
+type headerOp struct {
+	name   string
+	value  string
+	action headerAction
+}
+
+// applyHeaders applies the header operations to the headers.
+func applyHeaders(h http.Header, ops []headerOp) {
+	for _, op := range ops {
+		switch op.action {
+		case headerAdd:
+			h.Add(op.name, op.value)
+		case headerAddIfAbsent:
+			if len(h.Values(op.name)) == 0 {
+				h.Set(op.name, op.value)
+			}
+		case headerSetIfExists:
+			if len(h.Values(op.name)) > 0 {
+				h.Set(op.name, op.value)
+			}
+		default:
+			h.Set(op.name, op.value)
+		}
+	}
+}
+
+// decision is the result of an authorization.
+// Decisions can be cached and shared between requests.
+// So decisions must not be modified once created.
+type decision struct {
+	allow bool
+
+	// upstream is the operations to the request headers
+	// applied when allowed.
+	upstream []headerOp
+	// removeUpstream is the request header names
+	// removed when allowed.
+	removeUpstream []string
+	// response is the operations to the response headers
+	// applied when allowed.
+	response []headerOp
+
+	// status is the status code responded when denied.
+	status int
+	// client is the operations to the response headers
+	// applied when denied.
+	client []headerOp
+	// body is the response body when denied.
+	body []byte
+}
+
+// apply applies the decision to the allowed request.
+func (d *decision) apply(w http.ResponseWriter, r *http.Request) {
+	for _, name := range d.removeUpstream {
+		r.Header.Del(name)
+	}
+	applyHeaders(r.Header, d.upstream)
+	applyHeaders(w.Header(), d.response)
+}
+
+// deny writes the denied response.
+func (d *decision) deny(w http.ResponseWriter) {
+	applyHeaders(w.Header(), d.client)
+	w.Header().Set("Content-Length", strconv.Itoa(len(d.body)))
+	w.WriteHeader(d.status)
+	_, _ = w.Write(d.body)
+}
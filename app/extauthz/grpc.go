@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aileron-gateway/aileron-gateway/internal/grpcutil"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// metadataNamespace is the namespace of the metadata_context
+	// which claims are sent in.
+	metadataNamespace = "aileron.authn"
+	// maxMessageSize is the maximum size of messages
+	// received from the authorization service.
+	maxMessageSize = 4 << 20
+)
+
+var errInvalidMessage = errors.New("extauthz: invalid gRPC message received")
+
+// grpcChecker checks requests with the Envoy's authorization gRPC service
+// envoy.service.auth.v3.Authorization/Check over HTTP/2.
+type grpcChecker struct {
+	rt  http.RoundTripper
+	url string
+}
+
+func (c *grpcChecker) check(ctx context.Context, a *attributes) (*decision, error) {
+	req, err := checkRequest(a)
+	if err != nil {
+		return nil, err
+	}
+	res := &authv3.CheckResponse{}
+	if err := invoke(ctx, c.rt, c.url, req, res); err != nil {
+		return nil, err
+	}
+
+	if res.GetStatus().GetCode() == 0 { // google.rpc.Code OK
+		ok := res.GetOkResponse()
+		return &decision{
+			allow:          true,
+			upstream:       headerOps(ok.GetHeaders()),
+			removeUpstream: ok.GetHeadersToRemove(),
+			response:       headerOps(ok.GetResponseHeadersToAdd()),
+		}, nil
+	}
+
+	denied := res.GetDeniedResponse()
+	status := int(denied.GetStatus().GetCode())
+	if status < 200 || status > 999 {
+		status = http.StatusForbidden
+	}
+	return &decision{
+		status: status,
+		client: headerOps(denied.GetHeaders()),
+		body:   []byte(denied.GetBody()),
+	}, nil
+}
+
+// checkRequest returns the check request of the attributes.
+func checkRequest(a *attributes) (*authv3.CheckRequest, error) {
+	headers := make(map[string]string, len(a.header))
+	for name, values := range a.header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	ac := &authv3.AttributeContext{
+		Source: &authv3.AttributeContext_Peer{
+			Address: &corev3.Address{
+				Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{
+						Address:       a.remoteIP,
+						PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: a.remotePort},
+					},
+				},
+			},
+			Certificate: a.cert,
+		},
+		Request: &authv3.AttributeContext_Request{
+			Time: timestamppb.Now(),
+			Http: &authv3.AttributeContext_HttpRequest{
+				Method:   a.method,
+				Headers:  headers,
+				Path:     a.path,
+				Host:     a.host,
+				Scheme:   a.scheme,
+				Protocol: a.proto,
+			},
+		},
+	}
+	if a.claims != nil {
+		var claims any
+		if err := json.Unmarshal(a.claims, &claims); err != nil {
+			return nil, err
+		}
+		v, err := structpb.NewValue(claims)
+		if err != nil {
+			return nil, err
+		}
+		ac.MetadataContext = &corev3.Metadata{
+			FilterMetadata: map[string]*structpb.Struct{
+				metadataNamespace: {Fields: map[string]*structpb.Value{"claims": v}},
+			},
+		}
+	}
+	return &authv3.CheckRequest{Attributes: ac}, nil
+}
+
+// headerOps converts the header value options into header operations.
+// The deprecated append field takes precedence when set.
+// Headers are overwritten by default as the Envoy's ext_authz filter does.
+func headerOps(opts []*corev3.HeaderValueOption) []headerOp {
+	ops := make([]headerOp, 0, len(opts))
+	for _, opt := range opts {
+		hv := opt.GetHeader()
+		if hv == nil || hv.Key == "" || strings.HasPrefix(hv.Key, ":") {
+			continue
+		}
+		op := headerOp{name: hv.Key, value: hv.Value, action: headerSet}
+		if len(hv.RawValue) > 0 {
+			op.value = string(hv.RawValue)
+		}
+		switch {
+		case opt.Append != nil:
+			if opt.Append.Value {
+				op.action = headerAdd
+			}
+		case opt.AppendAction == corev3.HeaderValueOption_ADD_IF_ABSENT:
+			op.action = headerAddIfAbsent
+		case opt.AppendAction == corev3.HeaderValueOption_OVERWRITE_IF_EXISTS:
+			op.action = headerSetIfExists
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// invoke calls the unary gRPC method of the url.
+func invoke(ctx context.Context, rt http.RoundTripper, url string, in, out proto.Message) error {
+	var body bytes.Buffer
+	if err := grpcutil.WriteMessage(&body, in); err != nil {
+		return err
+	}
+	req, err := grpcutil.NewRequest(ctx, url, &body)
+	if err != nil {
+		return err
+	}
+
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := grpcutil.CheckResponse(res); err != nil {
+		return err
+	}
+	if err := grpcutil.ReadMessage(res.Body, out, maxMessageSize); err != nil {
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		// No message was received.
+		if err := grpcutil.Status(res.Trailer); err != nil {
+			return err
+		}
+		return errInvalidMessage
+	}
+	// Unary methods must respond exactly one message.
+	// Read to the end so that the trailers are received.
+	var extra [1]byte
+	if n, _ := io.ReadFull(res.Body, extra[:]); n > 0 {
+		return errInvalidMessage
+	}
+	return grpcutil.Status(res.Trailer)
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+const (
+	// claimsHeader is the header name to send claims
+	// to the HTTP authorization service.
+	claimsHeader = "X-Authz-Claims"
+	// certHeader is the header name to send the client certificate
+	// to the HTTP authorization service.
+	certHeader = "X-Authz-Client-Cert"
+	// maxDeniedBodySize is the maximum size of the response body
+	// of denied authorization responses.
+	maxDeniedBodySize = 1 << 20
+)
+
+// httpChecker checks requests with plain HTTP requests.
+// The request method and path are the same as the original request
+// with the path of the endpoint prepended.
+// 2xx responses allow requests and 5xx responses are treated as errors.
+// Other responses deny requests and are returned to the client.
+// This follows the HTTP mode of the Envoy's ext_authz filter.
+type httpChecker struct {
+	rt http.RoundTripper
+	// endpoint is the endpoint URL without trailing slash.
+	endpoint string
+	// upstreamHeaders is the canonical header names
+	// copied to the upstream request when allowed.
+	upstreamHeaders []string
+	// clientHeaders is the canonical header names
+	// copied to the response to the client when denied.
+	// All headers except for excluded headers are copied when empty.
+	clientHeaders []string
+}
+
+func (c *httpChecker) check(ctx context.Context, a *attributes) (*decision, error) {
+	req, err := http.NewRequestWithContext(ctx, a.method, c.endpoint+a.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = a.header.Clone()
+	// Clients must not be able to forge the claims and the certificate.
+	req.Header.Del(claimsHeader)
+	req.Header.Del(certHeader)
+	req.Header.Set("X-Forwarded-Host", a.host)
+	req.Header.Set("X-Forwarded-Proto", a.scheme)
+	if a.remoteIP != "" {
+		req.Header.Set("X-Forwarded-For", a.remoteIP)
+	}
+	if a.claims != nil {
+		req.Header.Set(claimsHeader, base64.StdEncoding.EncodeToString(a.claims))
+	}
+	if a.cert != "" {
+		req.Header.Set(certHeader, a.cert)
+	}
+
+	res, err := c.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDeniedBodySize))
+		return &decision{allow: true, upstream: copyHeaders(res.Header, c.upstreamHeaders, false)}, nil
+	case res.StatusCode >= 500:
+		return nil, fmt.Errorf("extauthz: authorization service responded status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxDeniedBodySize))
+	if err != nil {
+		return nil, err
+	}
+	return &decision{
+		status: res.StatusCode,
+		client: copyHeaders(res.Header, c.clientHeaders, true),
+		body:   body,
+	}, nil
+}
+
+// copyHeaders returns the operations to set the headers of the names.
+// All headers except for excluded headers are returned
+// when the names is empty and the all is true.
+func copyHeaders(h http.Header, names []string, all bool) []headerOp {
+	var ops []headerOp
+	for name, values := range h {
+		if slices.Contains(excludedHeaders, name) {
+			continue
+		}
+		if !(all && len(names) == 0) && !slices.Contains(names, name) {
+			continue
+		}
+		for i, v := range values {
+			action := headerAdd
+			if i == 0 {
+				action = headerSet
+			}
+			ops = append(ops, headerOp{name: name, value: v, action: action})
+		}
+	}
+	return ops
+}
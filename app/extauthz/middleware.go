@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+)
+
+// extAuthz authorizes requests with an external authorization service.
+// This is modeled on the Envoy's ext_authz filter.
+// This implements core.Middleware interface.
+type extAuthz struct {
+	lg log.Logger
+	eh core.ErrorHandler
+
+	checker checker
+	// timeout is the timeout of an authorization request.
+	timeout time.Duration
+	// failOpen allows requests when
+	// the authorization service failed.
+	failOpen bool
+
+	// allowedHeaders is the canonical request header names
+	// sent to the authorization service.
+	// All headers are sent when empty.
+	allowedHeaders []string
+	// claimsKey is the context key to extract claims.
+	claimsKey string
+	// includeClaims sends claims when true.
+	includeClaims bool
+	// includeCert sends the client certificate when true.
+	includeCert bool
+
+	// cache caches decisions.
+	// Decisions are not cached when nil.
+	cache *cache
+}
+
+func (m *extAuthz) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var claims []byte
+		if m.includeClaims {
+			b, err := marshalClaims(r.Context().Value(m.claimsKey))
+			if err != nil {
+				err = app.ErrAppAuthzExtAuthz.WithStack(err, nil)
+				m.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusForbidden))
+				return
+			}
+			claims = b
+		}
+		a := newAttributes(r, m.allowedHeaders, claims, m.includeCert)
+
+		d, err := m.decide(r.Context(), a)
+		if err != nil {
+			if m.failOpen {
+				m.lg.Warn(r.Context(), "external authorization failed. request is allowed.", "error", err.Error())
+				next.ServeHTTP(w, r)
+				return
+			}
+			err = app.ErrAppAuthzExtAuthz.WithStack(err, nil)
+			m.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusForbidden))
+			return
+		}
+
+		if !d.allow {
+			if m.lg.Enabled(log.LvDebug) {
+				msg := "forbidden by external authorization for method=" + r.Method + " path=" + r.URL.Path
+				err := app.ErrAppAuthzAuthorization.WithoutStack(nil, nil)
+				m.lg.Debug(r.Context(), msg, err.Name(), err.Map())
+			}
+			d.deny(w)
+			return
+		}
+
+		d.apply(w, r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decide returns the decision for the attributes.
+// Cached decision is returned if exists.
+func (m *extAuthz) decide(ctx context.Context, a *attributes) (*decision, error) {
+	if m.cache == nil {
+		return m.check(ctx, a)
+	}
+	key := a.key()
+	if d := m.cache.get(key); d != nil {
+		return d, nil
+	}
+	d, err := m.check(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	m.cache.set(key, d)
+	return d, nil
+}
+
+// check checks the attributes with the authorization service.
+func (m *extAuthz) check(ctx context.Context, a *attributes) (*decision, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	return m.checker.check(ctx, a)
+}
@@ -0,0 +1,580 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extauthz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testHandler responds the X-User header of the request.
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-User", r.Header.Get("X-User"))
+	w.Header().Set("X-Remove", r.Header.Get("X-Remove"))
+	_, _ = w.Write([]byte("ok"))
+})
+
+// testAuthzServer is the HTTP authorization service for tests.
+// Decisions are made by the path of the request.
+type testAuthzServer struct {
+	calls atomic.Int32
+	mu    sync.Mutex
+	last  *http.Request
+}
+
+func (s *testAuthzServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.calls.Add(1)
+	s.mu.Lock()
+	s.last = r.Clone(context.Background())
+	s.mu.Unlock()
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/auth/allow"):
+		w.Header().Set("X-User", "alice")
+		w.Header().Set("X-Other", "other")
+		w.WriteHeader(http.StatusOK)
+	case strings.HasPrefix(r.URL.Path, "/auth/deny"):
+		w.Header().Set("Www-Authenticate", "Basic")
+		w.Header().Set("X-Other", "other")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("denied"))
+	case strings.HasPrefix(r.URL.Path, "/auth/slow"):
+		time.Sleep(200 * time.Millisecond)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (s *testAuthzServer) lastRequest() *http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+func newTestExtAuthz(t *testing.T, spec *v1.ExtAuthzMiddlewareSpec) *extAuthz {
+	t.Helper()
+	got, err := Resource.Create(api.NewContainerAPI(), &v1.ExtAuthzMiddleware{Metadata: &k.Metadata{}, Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got.(*extAuthz)
+}
+
+func TestMiddleware_http(t *testing.T) {
+	type condition struct {
+		spec *v1.ExtAuthzMiddlewareSpec
+		path string
+		// requests is the number of requests sent.
+		requests int
+	}
+
+	type action struct {
+		status int
+		header map[string]string
+		body   string
+		calls  int32
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"allowed",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{AllowedUpstreamHeaders: []string{"x-user"}},
+				path: "/allow",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-User": "alice", "X-Other": ""},
+				body:   "ok",
+				calls:  1,
+			},
+		),
+		gen(
+			"allowed without upstream headers",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/allow",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-User": ""},
+				body:   "ok",
+				calls:  1,
+			},
+		),
+		gen(
+			"denied with all headers",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/deny",
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				header: map[string]string{"Www-Authenticate": "Basic", "X-Other": "other", "Content-Length": "6"},
+				body:   "denied",
+				calls:  1,
+			},
+		),
+		gen(
+			"denied with allowed headers",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{AllowedClientHeaders: []string{"www-authenticate"}},
+				path: "/deny",
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				header: map[string]string{"Www-Authenticate": "Basic", "X-Other": ""},
+				body:   "denied",
+				calls:  1,
+			},
+		),
+		gen(
+			"server error fail closed",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/error",
+			},
+			&action{
+				status: http.StatusForbidden,
+				calls:  1,
+			},
+		),
+		gen(
+			"server error fail open",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{FailOpen: true},
+				path: "/error",
+			},
+			&action{
+				status: http.StatusOK,
+				body:   "ok",
+				calls:  1,
+			},
+		),
+		gen(
+			"timeout",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{Timeout: 20},
+				path: "/slow",
+			},
+			&action{
+				status: http.StatusForbidden,
+				calls:  1,
+			},
+		),
+		gen(
+			"cached allowed",
+			&condition{
+				spec:     &v1.ExtAuthzMiddlewareSpec{CacheTTL: 60_000, AllowedHeaders: []string{"authorization"}, AllowedUpstreamHeaders: []string{"x-user"}},
+				path:     "/allow",
+				requests: 3,
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-User": "alice"},
+				body:   "ok",
+				calls:  1,
+			},
+		),
+		gen(
+			"cached denied",
+			&condition{
+				spec:     &v1.ExtAuthzMiddlewareSpec{CacheTTL: 60_000, AllowedHeaders: []string{"authorization"}},
+				path:     "/deny",
+				requests: 3,
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				body:   "denied",
+				calls:  1,
+			},
+		),
+		gen(
+			"errors not cached",
+			&condition{
+				spec:     &v1.ExtAuthzMiddlewareSpec{CacheTTL: 60_000, AllowedHeaders: []string{"authorization"}},
+				path:     "/error",
+				requests: 3,
+			},
+			&action{
+				status: http.StatusForbidden,
+				calls:  3,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			as := &testAuthzServer{}
+			svr := httptest.NewServer(as)
+			defer svr.Close()
+
+			tt.C.spec.Endpoint = svr.URL + "/auth/"
+			m := newTestExtAuthz(t, tt.C.spec)
+			h := m.Middleware(testHandler)
+
+			var w *httptest.ResponseRecorder
+			for range max(tt.C.requests, 1) {
+				r := httptest.NewRequest(http.MethodGet, "http://test.com"+tt.C.path, nil)
+				w = httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+			}
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			for name, value := range tt.A.header {
+				testutil.Diff(t, value, w.Header().Get(name))
+			}
+			if tt.A.body != "" {
+				testutil.Diff(t, tt.A.body, w.Body.String())
+			}
+			testutil.Diff(t, tt.A.calls, as.calls.Load())
+		})
+	}
+}
+
+func TestMiddleware_httpAttributes(t *testing.T) {
+	as := &testAuthzServer{}
+	svr := httptest.NewServer(as)
+	defer svr.Close()
+
+	m := newTestExtAuthz(t, &v1.ExtAuthzMiddlewareSpec{
+		Endpoint:          svr.URL + "/auth",
+		AllowedHeaders:    []string{"authorization"},
+		ClaimsKey:         "AuthnClaims",
+		IncludeClaims:     true,
+		IncludeClientCert: true,
+	})
+	h := m.Middleware(testHandler)
+
+	r := httptest.NewRequest(http.MethodPost, "https://test.com/allow/foo?bar=baz", strings.NewReader("body"))
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("X-Not-Allowed", "test")
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("cert")}}}
+	r = r.WithContext(context.WithValue(r.Context(), m.claimsKey, map[string]any{"sub": "alice"}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusOK, w.Code)
+
+	last := as.lastRequest()
+	testutil.Diff(t, http.MethodPost, last.Method)
+	testutil.Diff(t, "/auth/allow/foo?bar=baz", last.URL.RequestURI())
+	testutil.Diff(t, int64(0), last.ContentLength)
+	testutil.Diff(t, "Bearer token", last.Header.Get("Authorization"))
+	testutil.Diff(t, "", last.Header.Get("X-Not-Allowed"))
+	testutil.Diff(t, "test.com", last.Header.Get("X-Forwarded-Host"))
+	testutil.Diff(t, "https", last.Header.Get("X-Forwarded-Proto"))
+	testutil.Diff(t, "192.0.2.1", last.Header.Get("X-Forwarded-For"))
+	testutil.Diff(t, base64.StdEncoding.EncodeToString([]byte(`{"sub":"alice"}`)), last.Header.Get(claimsHeader))
+	cert, _ := url.QueryUnescape(last.Header.Get(certHeader))
+	block, _ := pem.Decode([]byte(cert))
+	testutil.Diff(t, "cert", string(block.Bytes))
+}
+
+func TestMiddleware_httpForgedHeaders(t *testing.T) {
+	type condition struct {
+		spec *v1.ExtAuthzMiddlewareSpec
+	}
+
+	type action struct {
+		claims string
+		cert   string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"not included",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+			},
+			&action{},
+		),
+		gen(
+			"allowed explicitly",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{AllowedHeaders: []string{claimsHeader, certHeader}},
+			},
+			&action{},
+		),
+		gen(
+			"included but not found",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{ClaimsKey: "AuthnClaims", IncludeClaims: true, IncludeClientCert: true},
+			},
+			&action{},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			as := &testAuthzServer{}
+			svr := httptest.NewServer(as)
+			defer svr.Close()
+
+			tt.C.spec.Endpoint = svr.URL + "/auth"
+			m := newTestExtAuthz(t, tt.C.spec)
+			h := m.Middleware(testHandler)
+
+			r := httptest.NewRequest(http.MethodGet, "http://test.com/allow", nil)
+			r.Header.Set(claimsHeader, base64.StdEncoding.EncodeToString([]byte(`{"sub":"admin"}`)))
+			r.Header.Set(certHeader, "forged")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			testutil.Diff(t, http.StatusOK, w.Code)
+
+			last := as.lastRequest()
+			testutil.Diff(t, tt.A.claims, last.Header.Get(claimsHeader))
+			testutil.Diff(t, tt.A.cert, last.Header.Get(certHeader))
+		})
+	}
+}
+
+func TestMiddleware_invalidClaims(t *testing.T) {
+	m := newTestExtAuthz(t, &v1.ExtAuthzMiddlewareSpec{
+		Endpoint:      "http://127.0.0.1:1",
+		ClaimsKey:     "AuthnClaims",
+		IncludeClaims: true,
+	})
+	h := m.Middleware(testHandler)
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), m.claimsKey, func() {}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusForbidden, w.Code)
+}
+
+// testAuthorizationServer is the gRPC authorization service for tests.
+type testAuthorizationServer struct {
+	authv3.UnimplementedAuthorizationServer
+	mu   sync.Mutex
+	last *authv3.CheckRequest
+}
+
+func (s *testAuthorizationServer) Check(_ context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	s.mu.Lock()
+	s.last = req
+	s.mu.Unlock()
+	switch path := req.GetAttributes().GetRequest().GetHttp().GetPath(); {
+	case strings.HasPrefix(path, "/allow"):
+		return &authv3.CheckResponse{
+			Status: &rpcstatus.Status{Code: int32(codes.OK)},
+			HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{
+				Headers: []*corev3.HeaderValueOption{
+					{Header: &corev3.HeaderValue{Key: "x-user", Value: "alice"}},
+					{Header: &corev3.HeaderValue{Key: "x-user", RawValue: []byte("bob")}, Append: wrapperspb.Bool(true)},
+					{Header: &corev3.HeaderValue{Key: ":path", Value: "/ignored"}},
+				},
+				HeadersToRemove: []string{"x-remove"},
+				ResponseHeadersToAdd: []*corev3.HeaderValueOption{
+					{Header: &corev3.HeaderValue{Key: "x-response", Value: "added"}},
+				},
+			}},
+		}, nil
+	case strings.HasPrefix(path, "/deny"):
+		return &authv3.CheckResponse{
+			Status: &rpcstatus.Status{Code: int32(codes.PermissionDenied)},
+			HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+				Status: &typev3.HttpStatus{Code: typev3.StatusCode_Unauthorized},
+				Headers: []*corev3.HeaderValueOption{
+					{Header: &corev3.HeaderValue{Key: "www-authenticate", Value: "Bearer"}},
+				},
+				Body: "denied",
+			}},
+		}, nil
+	case strings.HasPrefix(path, "/forbidden"):
+		return &authv3.CheckResponse{Status: &rpcstatus.Status{Code: int32(codes.PermissionDenied)}}, nil
+	case strings.HasPrefix(path, "/slow"):
+		time.Sleep(200 * time.Millisecond)
+		return &authv3.CheckResponse{}, nil
+	}
+	return nil, grpcstatus.Error(codes.Internal, "internal error")
+}
+
+func (s *testAuthorizationServer) lastRequest() *authv3.CheckRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+func TestMiddleware_grpc(t *testing.T) {
+	type condition struct {
+		spec   *v1.ExtAuthzMiddlewareSpec
+		path   string
+		header map[string]string
+	}
+
+	type action struct {
+		status int
+		header map[string]string
+		body   string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"allowed",
+			&condition{
+				spec:   &v1.ExtAuthzMiddlewareSpec{},
+				path:   "/allow",
+				header: map[string]string{"X-Remove": "remove"},
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-User": "alice", "X-Remove": "", "X-Response": "added"},
+				body:   "ok",
+			},
+		),
+		gen(
+			"denied",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/deny",
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				header: map[string]string{"Www-Authenticate": "Bearer"},
+				body:   "denied",
+			},
+		),
+		gen(
+			"denied without status",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/forbidden",
+			},
+			&action{
+				status: http.StatusForbidden,
+			},
+		),
+		gen(
+			"grpc error fail closed",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{},
+				path: "/error",
+			},
+			&action{
+				status: http.StatusForbidden,
+			},
+		),
+		gen(
+			"grpc error fail open",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{FailOpen: true},
+				path: "/error",
+			},
+			&action{
+				status: http.StatusOK,
+				body:   "ok",
+			},
+		),
+		gen(
+			"timeout",
+			&condition{
+				spec: &v1.ExtAuthzMiddlewareSpec{Timeout: 20},
+				path: "/slow",
+			},
+			&action{
+				status: http.StatusForbidden,
+			},
+		),
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := grpc.NewServer()
+	as := &testAuthorizationServer{}
+	authv3.RegisterAuthorizationServer(svr, as)
+	go func() { _ = svr.Serve(ln) }()
+	defer svr.Stop()
+
+	t.Run("attributes", func(t *testing.T) {
+		m := newTestExtAuthz(t, &v1.ExtAuthzMiddlewareSpec{
+			Endpoint:             "http://" + ln.Addr().String(),
+			Protocol:             v1.ExtAuthzProtocol_ExtAuthzGRPC,
+			HTTP2TransportConfig: &k.HTTP2TransportConfig{AllowHTTP: true},
+			ClaimsKey:            "AuthnClaims",
+			IncludeClaims:        true,
+			IncludeClientCert:    true,
+		})
+		h := m.Middleware(testHandler)
+
+		r := httptest.NewRequest(http.MethodPost, "https://test.com/allow?foo=bar", nil)
+		r.Header.Add("X-Test", "1")
+		r.Header.Add("X-Test", "2")
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("cert")}}}
+		r = r.WithContext(context.WithValue(r.Context(), m.claimsKey, map[string]any{"sub": "alice"}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		testutil.Diff(t, http.StatusOK, w.Code)
+
+		attrs := as.lastRequest().GetAttributes()
+		req := attrs.GetRequest().GetHttp()
+		testutil.Diff(t, http.MethodPost, req.Method)
+		testutil.Diff(t, "/allow?foo=bar", req.Path)
+		testutil.Diff(t, "test.com", req.Host)
+		testutil.Diff(t, "https", req.Scheme)
+		testutil.Diff(t, "HTTP/1.1", req.Protocol)
+		testutil.Diff(t, map[string]string{"x-test": "1,2"}, req.Headers)
+		testutil.Diff(t, "192.0.2.1", attrs.GetSource().GetAddress().GetSocketAddress().GetAddress())
+		testutil.Diff(t, uint32(1234), attrs.GetSource().GetAddress().GetSocketAddress().GetPortValue())
+		cert, _ := url.QueryUnescape(attrs.GetSource().GetCertificate())
+		block, _ := pem.Decode([]byte(cert))
+		testutil.Diff(t, "cert", string(block.Bytes))
+		claims := attrs.GetMetadataContext().GetFilterMetadata()[metadataNamespace].GetFields()["claims"]
+		testutil.Diff(t, "alice", claims.GetStructValue().GetFields()["sub"].GetStringValue())
+	})
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.C.spec.Endpoint = "http://" + ln.Addr().String()
+			tt.C.spec.Protocol = v1.ExtAuthzProtocol_ExtAuthzGRPC
+			tt.C.spec.HTTP2TransportConfig = &k.HTTP2TransportConfig{AllowHTTP: true}
+			m := newTestExtAuthz(t, tt.C.spec)
+			h := m.Middleware(testHandler)
+
+			r := httptest.NewRequest(http.MethodGet, "http://test.com"+tt.C.path, nil)
+			for name, value := range tt.C.header {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			for name, value := range tt.A.header {
+				testutil.Diff(t, value, w.Header().Get(name))
+			}
+			if tt.A.body != "" {
+				testutil.Diff(t, tt.A.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/aileron-gateway/aileron-gateway/kernel/api"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"google.golang.org/protobuf/proto"
+	"reflect"
 )
 
 const (
@@ -28,6 +29,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.EchoHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*echo]()},
 	},
 }
 
@@ -4,6 +4,7 @@
 package healthcheck
 
 import (
+	"reflect"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -34,6 +35,7 @@ var Resource api.Resource = &API{
 				Timeout: 30,
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*healthCheck]()},
 	},
 }
 
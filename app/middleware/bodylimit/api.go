@@ -7,6 +7,7 @@ import (
 	"cmp"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -36,6 +37,7 @@ var Resource api.Resource = &API{
 				TempPath: os.TempDir(),
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*bodyLimit]()},
 	},
 }
 
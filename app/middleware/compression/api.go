@@ -4,6 +4,7 @@
 package compression
 
 import (
+	"reflect"
 	"slices"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 				MinimumSize: 1 << 10, // 1024 bytes.
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*compression]()},
 	},
 }
 
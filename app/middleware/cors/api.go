@@ -5,6 +5,7 @@ package cors
 
 import (
 	"cmp"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -36,6 +37,7 @@ var Resource api.Resource = &API{
 				CORSPolicy: &v1.CORSPolicySpec{},
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*cors]()},
 	},
 }
 
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"net/textproto"
 	"os"
+	"reflect"
 	"regexp"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -53,6 +54,7 @@ var Resource api.Resource = &API{
 				},
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*csrf]()},
 	},
 }
 
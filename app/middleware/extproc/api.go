@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"cmp"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "ExtProcMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+const (
+	// defaultTimeout is the default timeout in milliseconds
+	// to wait for responses from the external processor.
+	// This is the same as the Envoy's default.
+	defaultTimeout = 200
+	// defaultMaxBufferSize is the default maximum size of buffered bodies.
+	defaultMaxBufferSize = 4 << 20
+)
+
+var errInvalidScheme = errors.New("extproc: endpoint scheme must be http or https")
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.ExtProcMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.ExtProcMiddlewareSpec{
+				Timeout:        defaultTimeout,
+				MaxBufferSize:  defaultMaxBufferSize,
+				ProcessingMode: &v1.ExtProcProcessingMode{},
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*extProc]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.ExtProcMiddleware)
+	lg := log.DefaultOr(c.Metadata.Logger)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	u, err := url.Parse(c.Spec.Endpoint)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, core.ErrCoreGenCreateObject.WithStack(errInvalidScheme, map[string]any{"kind": kind})
+	}
+
+	rt, err := network.HTTP2Transport(c.Spec.HTTP2TransportConfig)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
+	pm := c.Spec.ProcessingMode
+	return &extProc{
+		lg: lg,
+		eh: eh,
+		client: &client{
+			rt:  rt,
+			url: strings.TrimSuffix(c.Spec.Endpoint, "/") + extprocv3.ExternalProcessor_Process_FullMethodName,
+		},
+		timeout:  time.Millisecond * time.Duration(cmp.Or(c.Spec.Timeout, defaultTimeout)),
+		failOpen: c.Spec.FailOpen,
+		mode: mode{
+			requestHeader:  pm.GetRequestHeaderMode() == v1.ExtProcHeaderMode_ExtProcHeaderSend,
+			responseHeader: pm.GetResponseHeaderMode() == v1.ExtProcHeaderMode_ExtProcHeaderSend,
+			requestBody:    pm.GetRequestBodyMode(),
+			responseBody:   pm.GetResponseBodyMode(),
+		},
+		allowModeOverride: c.Spec.AllowModeOverride,
+		maxBufferSize:     cmp.Or(c.Spec.MaxBufferSize, defaultMaxBufferSize),
+	}, nil
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		check      func(*testing.T, *extProc)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default values",
+			&condition{
+				manifest: &v1.ExtProcMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtProcMiddlewareSpec{
+						Endpoint: "http://127.0.0.1:50051/",
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, p *extProc) {
+					t.Helper()
+					testutil.Diff(t, "http://127.0.0.1:50051/envoy.service.ext_proc.v3.ExternalProcessor/Process", p.client.url)
+					testutil.Diff(t, 200*time.Millisecond, p.timeout)
+					testutil.Diff(t, int64(defaultMaxBufferSize), p.maxBufferSize)
+					testutil.Diff(t, false, p.failOpen)
+					testutil.Diff(t, false, p.allowModeOverride)
+					testutil.Diff(t, true, p.mode.requestHeader)
+					testutil.Diff(t, true, p.mode.responseHeader)
+					testutil.Diff(t, v1.ExtProcBodyMode_ExtProcBodyNone, p.mode.requestBody)
+					testutil.Diff(t, v1.ExtProcBodyMode_ExtProcBodyNone, p.mode.responseBody)
+				},
+			},
+		),
+		gen(
+			"create with options",
+			&condition{
+				manifest: &v1.ExtProcMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtProcMiddlewareSpec{
+						Endpoint:             "https://example.com",
+						HTTP2TransportConfig: &k.HTTP2TransportConfig{},
+						Timeout:              1000,
+						FailOpen:             true,
+						AllowModeOverride:    true,
+						MaxBufferSize:        10,
+						ProcessingMode: &v1.ExtProcProcessingMode{
+							RequestHeaderMode:  v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+							ResponseHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+							RequestBodyMode:    v1.ExtProcBodyMode_ExtProcBodyBuffered,
+							ResponseBodyMode:   v1.ExtProcBodyMode_ExtProcBodyStreamed,
+						},
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, p *extProc) {
+					t.Helper()
+					testutil.Diff(t, "https://example.com/envoy.service.ext_proc.v3.ExternalProcessor/Process", p.client.url)
+					testutil.Diff(t, time.Second, p.timeout)
+					testutil.Diff(t, int64(10), p.maxBufferSize)
+					testutil.Diff(t, true, p.failOpen)
+					testutil.Diff(t, true, p.allowModeOverride)
+					testutil.Diff(t, false, p.mode.requestHeader)
+					testutil.Diff(t, false, p.mode.responseHeader)
+					testutil.Diff(t, v1.ExtProcBodyMode_ExtProcBodyBuffered, p.mode.requestBody)
+					testutil.Diff(t, v1.ExtProcBodyMode_ExtProcBodyStreamed, p.mode.responseBody)
+				},
+			},
+		),
+		gen(
+			"invalid endpoint",
+			&condition{
+				manifest: &v1.ExtProcMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ExtProcMiddlewareSpec{Endpoint: "http://[::1"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ExtProcMiddleware`),
+			},
+		),
+		gen(
+			"invalid scheme",
+			&condition{
+				manifest: &v1.ExtProcMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ExtProcMiddlewareSpec{Endpoint: "grpc://127.0.0.1:50051"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`endpoint scheme must be http or https`),
+			},
+		),
+		gen(
+			"invalid tls config",
+			&condition{
+				manifest: &v1.ExtProcMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ExtProcMiddlewareSpec{
+						Endpoint: "https://127.0.0.1:50051",
+						HTTP2TransportConfig: &k.HTTP2TransportConfig{
+							TLSConfig: &k.TLSConfig{RootCAs: []string{"not-exist.pem"}},
+						},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ExtProcMiddleware`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			tt.A.check(t, got.(*extProc))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aileron-gateway/aileron-gateway/internal/grpcutil"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+)
+
+// maxMessageSize is the maximum size of messages
+// received from the external processor.
+const maxMessageSize = 64 << 20
+
+// client is the gRPC client of the external processor.
+// It calls the envoy.service.ext_proc.v3.ExternalProcessor/Process
+// bidirectional streaming method over the HTTP/2 transport.
+type client struct {
+	rt  http.RoundTripper
+	url string
+}
+
+// open opens a new stream.
+// The stream is not connected until the first message is sent.
+// Cancelling the context aborts the stream.
+func (c *client) open(ctx context.Context) (*stream, error) {
+	pr, pw := io.Pipe()
+	req, err := grpcutil.NewRequest(ctx, c.url, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stream{pw: pw, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		s.res, s.err = c.rt.RoundTrip(req)
+		if s.err == nil {
+			if s.err = grpcutil.CheckResponse(s.res); s.err != nil {
+				_ = s.res.Body.Close()
+			}
+		}
+		if s.err != nil {
+			_ = pr.CloseWithError(s.err) // Unblock the sender.
+		}
+	}()
+	return s, nil
+}
+
+// stream is a bidirectional stream to the external processor.
+// A stream must not be used concurrently.
+type stream struct {
+	pw *io.PipeWriter
+
+	// done is closed when the response headers were received
+	// or the round trip failed.
+	done chan struct{}
+	res  *http.Response
+	err  error
+}
+
+// send sends the message to the external processor.
+func (s *stream) send(msg *extprocv3.ProcessingRequest) error {
+	return grpcutil.WriteMessage(s.pw, msg)
+}
+
+// recv receives a message from the external processor.
+func (s *stream) recv() (*extprocv3.ProcessingResponse, error) {
+	<-s.done
+	if s.err != nil {
+		return nil, s.err
+	}
+	msg := &extprocv3.ProcessingResponse{}
+	if err := grpcutil.ReadMessage(s.res.Body, msg, maxMessageSize); err != nil {
+		if errors.Is(err, io.EOF) {
+			if err := grpcutil.Status(s.res.Trailer); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("extproc: stream closed by the external processor")
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// close closes the stream.
+func (s *stream) close() {
+	_ = s.pw.Close()
+	select {
+	case <-s.done:
+		if s.err == nil {
+			_ = s.res.Body.Close()
+		}
+	default:
+		// The round trip is still in progress.
+		// It will be aborted by the context.
+	}
+}
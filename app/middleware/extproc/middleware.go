@@ -0,0 +1,485 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+)
+
+// chunkSize is the size of body chunks sent to
+// the external processor in the streamed body mode.
+const chunkSize = 32 << 10
+
+var errResponseTooLarge = errors.New("extproc: response body exceeded the max buffer size")
+
+// extProc sends requests and responses to the external processor
+// and applies the mutations returned from the processor.
+// This is modeled on the Envoy's ext_proc filter.
+// This implements core.Middleware interface.
+type extProc struct {
+	lg     log.Logger
+	eh     core.ErrorHandler
+	client *client
+
+	// timeout is the timeout to wait for each response.
+	timeout time.Duration
+	// failOpen continues processing when
+	// communication with the external processor failed.
+	failOpen bool
+	// mode is the default processing mode.
+	mode mode
+	// allowModeOverride allows the external processor
+	// to override the processing mode.
+	allowModeOverride bool
+	// maxBufferSize is the maximum size of buffered bodies.
+	maxBufferSize int64
+}
+
+func (p *extProc) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		ss := &session{p: p, ctx: ctx, cancel: cancel, mode: p.mode}
+		defer ss.close()
+
+		bodyReplaced := false
+		if ss.mode.requestHeader {
+			res, err := ss.exchange(&extprocv3.ProcessingRequest{
+				Request: &extprocv3.ProcessingRequest_RequestHeaders{
+					RequestHeaders: &extprocv3.HttpHeaders{
+						Headers:     requestHeaderMap(r),
+						EndOfStream: r.Body == nil || r.Body == http.NoBody,
+					},
+				},
+			})
+			if err != nil {
+				p.serveError(w, r, err)
+				return
+			}
+			if ir := res.GetImmediateResponse(); ir != nil {
+				writeImmediate(w, ir)
+				return
+			}
+			if p.allowModeOverride && res.GetModeOverride() != nil {
+				ss.mode.override(res.GetModeOverride())
+			}
+			cr := res.GetRequestHeaders().GetResponse()
+			mutateRequestHeaders(r, cr.GetHeaderMutation())
+			if cr.GetStatus() == extprocv3.CommonResponse_CONTINUE_AND_REPLACE {
+				setRequestBody(r, mutateBody(nil, cr.GetBodyMutation()))
+				bodyReplaced = true
+			}
+		}
+
+		if !bodyReplaced && r.Body != nil && r.Body != http.NoBody {
+			switch ss.mode.requestBody {
+			case v1.ExtProcBodyMode_ExtProcBodyBuffered:
+				if !p.processBufferedRequest(w, r, ss) {
+					return
+				}
+			case v1.ExtProcBodyMode_ExtProcBodyStreamed:
+				r.Body = &streamReader{ReadCloser: r.Body, ss: ss, chunk: make([]byte, chunkSize)}
+				r.ContentLength = -1 // Length may be changed.
+				r.Header.Del("Content-Length")
+			}
+		}
+
+		ww := &wrappedWriter{ResponseWriter: w, p: p, ss: ss, r: r}
+		next.ServeHTTP(ww, r)
+		ww.finish()
+	})
+}
+
+// processBufferedRequest sends the buffered request body to the external processor.
+// It returns false when the response was written.
+func (p *extProc) processBufferedRequest(w http.ResponseWriter, r *http.Request, ss *session) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, p.maxBufferSize+1))
+	if err != nil {
+		p.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(app.ErrAppMiddleExtProc.WithStack(err, nil), http.StatusBadRequest))
+		return false
+	}
+	if int64(len(body)) > p.maxBufferSize {
+		err := app.ErrAppMiddleBodyTooLarge.WithoutStack(nil, nil)
+		p.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusRequestEntityTooLarge))
+		return false
+	}
+	res, err := ss.exchange(&extprocv3.ProcessingRequest{
+		Request: &extprocv3.ProcessingRequest_RequestBody{
+			RequestBody: &extprocv3.HttpBody{Body: body, EndOfStream: true},
+		},
+	})
+	if err != nil {
+		p.serveError(w, r, err)
+		return false
+	}
+	if ir := res.GetImmediateResponse(); ir != nil {
+		writeImmediate(w, ir)
+		return false
+	}
+	cr := res.GetRequestBody().GetResponse()
+	mutateRequestHeaders(r, cr.GetHeaderMutation())
+	setRequestBody(r, mutateBody(body, cr.GetBodyMutation()))
+	return true
+}
+
+// serveError responds the error occurred
+// while communicating with the external processor.
+func (p *extProc) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	p.eh.ServeHTTPError(w, r, app.ErrAppMiddleExtProc.WithStack(err, nil))
+}
+
+// requestHeaderMap returns the request headers sent to the external processor.
+// Pseudo headers of HTTP/2 are included as Envoy does.
+func requestHeaderMap(r *http.Request) *corev3.HeaderMap {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return headerMap(r.Header, ":method", r.Method, ":path", r.URL.RequestURI(), ":authority", r.Host, ":scheme", scheme)
+}
+
+// mutateRequestHeaders applies the header mutation to the request.
+// The ":method", ":path" and ":authority" pseudo headers
+// and the "Host" header update the request.
+func mutateRequestHeaders(r *http.Request, m *extprocv3.HeaderMutation) {
+	applyHeaderMutation(r.Header, m, func(name, value string) {
+		switch name {
+		case ":method":
+			r.Method = value
+		case ":path":
+			if u, err := url.ParseRequestURI(value); err == nil {
+				r.URL.Path, r.URL.RawPath, r.URL.RawQuery = u.Path, u.RawPath, u.RawQuery
+				r.RequestURI = value
+			}
+		case ":authority":
+			r.Host = value
+		}
+	})
+	if host := r.Header.Get("Host"); host != "" {
+		r.Host = host
+		r.Header.Del("Host")
+	}
+}
+
+// setRequestBody replaces the request body.
+func setRequestBody(r *http.Request, b []byte) {
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	r.ContentLength = int64(len(b))
+	r.TransferEncoding = nil
+	r.Header.Del("Transfer-Encoding")
+	if r.Header.Get("Content-Length") != "" {
+		r.Header.Set("Content-Length", strconv.Itoa(len(b)))
+	}
+}
+
+// writeImmediate writes the immediate response.
+// Status code is 200 when not set or invalid.
+func writeImmediate(w http.ResponseWriter, ir *extprocv3.ImmediateResponse) {
+	applyHeaderMutation(w.Header(), ir.GetHeaders(), nil)
+	code := int(ir.GetStatus().GetCode())
+	if code < 200 || code > 999 {
+		code = http.StatusOK
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(ir.GetBody())))
+	w.WriteHeader(code)
+	_, _ = w.Write(ir.GetBody())
+}
+
+// streamReader sends the request body to the external processor
+// in chunks and returns the mutated chunks.
+type streamReader struct {
+	io.ReadCloser
+	ss *session
+
+	chunk []byte
+	// buf is the mutated chunk not yet read.
+	buf []byte
+	eof bool
+	err error
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	for len(sr.buf) == 0 {
+		if sr.eof {
+			return 0, io.EOF
+		}
+		if sr.err != nil {
+			return 0, sr.err
+		}
+		n, err := sr.ReadCloser.Read(sr.chunk)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		eos := err == io.EOF
+		if n == 0 && !eos {
+			continue
+		}
+		data := bytes.Clone(sr.chunk[:n])
+		res, err := sr.ss.exchange(&extprocv3.ProcessingRequest{
+			Request: &extprocv3.ProcessingRequest_RequestBody{
+				RequestBody: &extprocv3.HttpBody{Body: data, EndOfStream: eos},
+			},
+		})
+		if err != nil {
+			sr.err = err
+			return 0, err
+		}
+		if ir := res.GetImmediateResponse(); ir != nil {
+			// The immediate response is written by the wrappedWriter
+			// instead of the response of the next handler.
+			sr.ss.setImmediate(ir)
+			sr.err = errImmediateResponse
+			return 0, sr.err
+		}
+		sr.buf = mutateBody(data, res.GetRequestBody().GetResponse().GetBodyMutation())
+		sr.eof = eos
+	}
+	n := copy(p, sr.buf)
+	sr.buf = sr.buf[n:]
+	return n, nil
+}
+
+// wrappedWriter wraps http.ResponseWriter and sends
+// the response headers and body to the external processor.
+type wrappedWriter struct {
+	http.ResponseWriter
+	p  *extProc
+	ss *session
+	r  *http.Request
+
+	code int
+	// started is true when the status code was written
+	// to the inner writer.
+	started bool
+	// discard is true when the response was written
+	// by this writer and the writes of the next handler are discarded.
+	discard bool
+	// buf is the buffered body in the buffered mode.
+	buf bytes.Buffer
+	// err is the error which occurred while writing the response.
+	err error
+}
+
+// Unwrap returns internal ResponseWriter.
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *wrappedWriter) WriteHeader(statusCode int) {
+	if w.code > 0 {
+		return
+	}
+	if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(statusCode) // Informational responses.
+		return
+	}
+	w.code = statusCode
+	if w.ss.mode.responseBody != v1.ExtProcBodyMode_ExtProcBodyBuffered {
+		w.processHeaders()
+	}
+}
+
+func (w *wrappedWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	switch w.ss.mode.responseBody {
+	case v1.ExtProcBodyMode_ExtProcBodyBuffered:
+		if int64(w.buf.Len()+len(b)) > w.p.maxBufferSize {
+			w.err = errResponseTooLarge
+			return 0, w.err
+		}
+		return w.buf.Write(b)
+	case v1.ExtProcBodyMode_ExtProcBodyStreamed:
+		if len(b) == 0 {
+			return 0, nil
+		}
+		body, err := w.processBody(bytes.Clone(b), false)
+		if err != nil {
+			w.err = err
+			return 0, err
+		}
+		if _, err := w.ResponseWriter.Write(body); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the inner writer
+// unless the response body is buffered.
+func (w *wrappedWriter) Flush() {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.started && !w.discard {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// processHeaders sends the response headers to the external processor
+// and writes the status code to the inner writer.
+// The status code is not written in the buffered mode.
+func (w *wrappedWriter) processHeaders() {
+	if ir := w.ss.getImmediate(); ir != nil {
+		w.writeImmediate(ir)
+		return
+	}
+	if w.ss.mode.responseHeader {
+		res, err := w.ss.exchange(&extprocv3.ProcessingRequest{
+			Request: &extprocv3.ProcessingRequest_ResponseHeaders{
+				ResponseHeaders: &extprocv3.HttpHeaders{
+					Headers: headerMap(w.Header(), ":status", strconv.Itoa(w.code)),
+				},
+			},
+		})
+		if err != nil {
+			w.serveError(err)
+			return
+		}
+		if ir := res.GetImmediateResponse(); ir != nil {
+			w.writeImmediate(ir)
+			return
+		}
+		cr := res.GetResponseHeaders().GetResponse()
+		w.mutateHeaders(cr.GetHeaderMutation())
+		if cr.GetStatus() == extprocv3.CommonResponse_CONTINUE_AND_REPLACE {
+			w.discard = true
+			writeResponse(w.ResponseWriter, w.code, mutateBody(nil, cr.GetBodyMutation()))
+			return
+		}
+	}
+	if w.ss.mode.responseBody == v1.ExtProcBodyMode_ExtProcBodyBuffered {
+		return
+	}
+	if w.ss.mode.responseBody == v1.ExtProcBodyMode_ExtProcBodyStreamed {
+		w.Header().Del("Content-Length") // Length may be changed.
+	}
+	w.started = true
+	w.ResponseWriter.WriteHeader(w.code)
+}
+
+// processBody sends the response body to the external processor
+// and returns the mutated body.
+func (w *wrappedWriter) processBody(body []byte, eos bool) ([]byte, error) {
+	res, err := w.ss.exchange(&extprocv3.ProcessingRequest{
+		Request: &extprocv3.ProcessingRequest_ResponseBody{
+			ResponseBody: &extprocv3.HttpBody{Body: body, EndOfStream: eos},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ir := res.GetImmediateResponse(); ir != nil {
+		if w.started {
+			return nil, errImmediateResponse
+		}
+		w.writeImmediate(ir)
+		return nil, nil
+	}
+	cr := res.GetResponseBody().GetResponse()
+	if !w.started {
+		w.mutateHeaders(cr.GetHeaderMutation())
+	}
+	return mutateBody(body, cr.GetBodyMutation()), nil
+}
+
+// mutateHeaders applies the header mutation to the response.
+// The ":status" pseudo header updates the status code.
+func (w *wrappedWriter) mutateHeaders(m *extprocv3.HeaderMutation) {
+	applyHeaderMutation(w.Header(), m, func(name, value string) {
+		if code, err := strconv.Atoi(value); name == ":status" && err == nil && code >= 200 && code <= 999 {
+			w.code = code
+		}
+	})
+}
+
+// finish finishes processing the response
+// after the next handler returned.
+func (w *wrappedWriter) finish() {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return
+	}
+
+	switch w.ss.mode.responseBody {
+	case v1.ExtProcBodyMode_ExtProcBodyBuffered:
+		if w.err != nil {
+			w.serveError(w.err)
+			return
+		}
+		w.processHeaders()
+		if w.discard {
+			return
+		}
+		body, err := w.processBody(w.buf.Bytes(), true)
+		if err != nil {
+			w.serveError(err)
+			return
+		}
+		if !w.discard {
+			writeResponse(w.ResponseWriter, w.code, body)
+		}
+	case v1.ExtProcBodyMode_ExtProcBodyStreamed:
+		if w.err != nil {
+			// The response was already started.
+			// Abort the response to notify the client of the failure.
+			panic(http.ErrAbortHandler)
+		}
+		body, err := w.processBody(nil, true)
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// writeImmediate discards the response of the next handler
+// and writes the immediate response instead.
+func (w *wrappedWriter) writeImmediate(ir *extprocv3.ImmediateResponse) {
+	w.discard = true
+	clear(w.Header())
+	writeImmediate(w.ResponseWriter, ir)
+}
+
+// serveError discards the response of the next handler
+// and responds the error.
+func (w *wrappedWriter) serveError(err error) {
+	w.discard = true
+	clear(w.Header())
+	w.p.serveError(w.ResponseWriter, w.r, err)
+}
+
+// writeResponse writes the response with the status and the body.
+func writeResponse(w http.ResponseWriter, code int, body []byte) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
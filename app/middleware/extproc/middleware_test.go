@@ -0,0 +1,747 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	filterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_proc/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testProcessor is the external processor for tests.
+// The handle function returns the response for each request.
+// Nil response closes the stream.
+type testProcessor struct {
+	extprocv3.UnimplementedExternalProcessorServer
+	handle func(*extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse
+
+	mu       sync.Mutex
+	received []*extprocv3.ProcessingRequest
+}
+
+func (p *testProcessor) Process(s extprocv3.ExternalProcessor_ProcessServer) error {
+	for {
+		req, err := s.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		p.mu.Lock()
+		p.received = append(p.received, req)
+		p.mu.Unlock()
+		res := p.handle(req)
+		if res == nil {
+			return errors.New("closed by processor")
+		}
+		if err := s.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *testProcessor) requests() []*extprocv3.ProcessingRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.received
+}
+
+// newTestExtProc starts the processor and returns the middleware connecting to it.
+func newTestExtProc(t *testing.T, p *testProcessor, spec *v1.ExtProcMiddlewareSpec) *extProc {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := grpc.NewServer()
+	extprocv3.RegisterExternalProcessorServer(svr, p)
+	go func() { _ = svr.Serve(ln) }()
+	t.Cleanup(svr.Stop)
+
+	spec.Endpoint = "http://" + ln.Addr().String()
+	spec.HTTP2TransportConfig = &k.HTTP2TransportConfig{AllowHTTP: true}
+	if spec.Timeout == 0 {
+		spec.Timeout = 5000
+	}
+	got, err := Resource.Create(api.NewContainerAPI(), &v1.ExtProcMiddleware{Metadata: &k.Metadata{}, Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got.(*extProc)
+}
+
+// testHandler echoes the request.
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("X-Method", r.Method)
+	w.Header().Set("X-URI", r.URL.RequestURI())
+	w.Header().Set("X-Host", r.Host)
+	w.Header().Set("X-Request", r.Header.Get("X-Request"))
+	w.Header().Set("Content-Length", "999") // Must be removed or updated.
+	if len(b) > 0 {
+		w.Header().Del("Content-Length")
+	} else {
+		w.Header().Set("Content-Length", "0")
+	}
+	_, _ = w.Write(b)
+})
+
+func setHeader(name, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{Header: &corev3.HeaderValue{Key: name, RawValue: []byte(value)}}
+}
+
+// continueResponse returns the response that continues
+// with the common response.
+func continueResponse(req *extprocv3.ProcessingRequest, cr *extprocv3.CommonResponse) *extprocv3.ProcessingResponse {
+	switch req.Request.(type) {
+	case *extprocv3.ProcessingRequest_RequestHeaders:
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestHeaders{
+			RequestHeaders: &extprocv3.HeadersResponse{Response: cr},
+		}}
+	case *extprocv3.ProcessingRequest_RequestBody:
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestBody{
+			RequestBody: &extprocv3.BodyResponse{Response: cr},
+		}}
+	case *extprocv3.ProcessingRequest_ResponseHeaders:
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseHeaders{
+			ResponseHeaders: &extprocv3.HeadersResponse{Response: cr},
+		}}
+	default:
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseBody{
+			ResponseBody: &extprocv3.BodyResponse{Response: cr},
+		}}
+	}
+}
+
+// upperBody returns the common response replacing the body with upper cased one.
+func upperBody(b []byte) *extprocv3.CommonResponse {
+	return &extprocv3.CommonResponse{
+		BodyMutation: &extprocv3.BodyMutation{
+			Mutation: &extprocv3.BodyMutation_Body{Body: []byte(strings.ToUpper(string(b)))},
+		},
+	}
+}
+
+func immediate(code int, body string) *extprocv3.ProcessingResponse {
+	return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ImmediateResponse{
+		ImmediateResponse: &extprocv3.ImmediateResponse{
+			Status:  &typev3.HttpStatus{Code: typev3.StatusCode(code)},
+			Headers: &extprocv3.HeaderMutation{SetHeaders: []*corev3.HeaderValueOption{setHeader("X-Immediate", "true")}},
+			Body:    []byte(body),
+		},
+	}}
+}
+
+func TestMiddleware(t *testing.T) {
+	type condition struct {
+		spec    *v1.ExtProcMiddlewareSpec
+		handle  func(*extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse
+		body    string
+		handler http.Handler
+	}
+
+	type action struct {
+		status int
+		header map[string]string
+		body   string
+		// types is the types of requests received by the processor.
+		types []string
+	}
+
+	typeOf := func(req *extprocv3.ProcessingRequest) string {
+		switch v := req.Request.(type) {
+		case *extprocv3.ProcessingRequest_RequestHeaders:
+			return "reqHeaders"
+		case *extprocv3.ProcessingRequest_RequestBody:
+			if v.RequestBody.EndOfStream {
+				return "reqBody(eos)"
+			}
+			return "reqBody"
+		case *extprocv3.ProcessingRequest_ResponseHeaders:
+			return "resHeaders"
+		case *extprocv3.ProcessingRequest_ResponseBody:
+			if v.ResponseBody.EndOfStream {
+				return "resBody(eos)"
+			}
+			return "resBody"
+		}
+		return ""
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"mutate headers",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if req.GetRequestHeaders() != nil {
+						return continueResponse(req, &extprocv3.CommonResponse{
+							HeaderMutation: &extprocv3.HeaderMutation{
+								SetHeaders: []*corev3.HeaderValueOption{
+									setHeader(":method", "PUT"),
+									setHeader(":path", "/new?foo=bar"),
+									setHeader(":authority", "new.example.com"),
+									setHeader("X-Request", "mutated"),
+								},
+							},
+						})
+					}
+					return continueResponse(req, &extprocv3.CommonResponse{
+						HeaderMutation: &extprocv3.HeaderMutation{
+							SetHeaders:    []*corev3.HeaderValueOption{setHeader(":status", "201"), setHeader("X-Response", "mutated")},
+							RemoveHeaders: []string{"X-Host"},
+						},
+					})
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusCreated,
+				header: map[string]string{
+					"X-Method":   "PUT",
+					"X-URI":      "/new?foo=bar",
+					"X-Host":     "",
+					"X-Request":  "mutated",
+					"X-Response": "mutated",
+				},
+				body:  "hello",
+				types: []string{"reqHeaders", "resHeaders"},
+			},
+		),
+		gen(
+			"buffered bodies",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode:  v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						ResponseHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						RequestBodyMode:    v1.ExtProcBodyMode_ExtProcBodyBuffered,
+						ResponseBodyMode:   v1.ExtProcBodyMode_ExtProcBodyBuffered,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if b := req.GetRequestBody(); b != nil {
+						return continueResponse(req, upperBody(b.Body))
+					}
+					return continueResponse(req, &extprocv3.CommonResponse{
+						BodyMutation: &extprocv3.BodyMutation{
+							Mutation: &extprocv3.BodyMutation_Body{Body: append(req.GetResponseBody().Body, "!"...)},
+						},
+					})
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"Content-Length": "6"},
+				body:   "HELLO!",
+				types:  []string{"reqBody(eos)", "resBody(eos)"},
+			},
+		),
+		gen(
+			"streamed bodies",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode:  v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						ResponseHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						RequestBodyMode:    v1.ExtProcBodyMode_ExtProcBodyStreamed,
+						ResponseBodyMode:   v1.ExtProcBodyMode_ExtProcBodyStreamed,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if b := req.GetRequestBody(); b != nil {
+						return continueResponse(req, upperBody(b.Body))
+					}
+					b := req.GetResponseBody()
+					if b.EndOfStream {
+						return continueResponse(req, &extprocv3.CommonResponse{
+							BodyMutation: &extprocv3.BodyMutation{Mutation: &extprocv3.BodyMutation_Body{Body: []byte("!")}},
+						})
+					}
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"Content-Length": ""},
+				body:   "HELLO!",
+				types:  []string{"reqBody", "reqBody(eos)", "resBody", "resBody(eos)"},
+			},
+		),
+		gen(
+			"immediate response to request headers",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return immediate(http.StatusForbidden, "forbidden")
+				},
+			},
+			&action{
+				status: http.StatusForbidden,
+				header: map[string]string{"X-Immediate": "true", "X-Method": ""},
+				body:   "forbidden",
+				types:  []string{"reqHeaders"},
+			},
+		),
+		gen(
+			"immediate response to buffered request body",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestBodyMode: v1.ExtProcBodyMode_ExtProcBodyBuffered,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if req.GetRequestBody() != nil {
+						return immediate(http.StatusBadRequest, "bad")
+					}
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusBadRequest,
+				header: map[string]string{"X-Immediate": "true"},
+				body:   "bad",
+				types:  []string{"reqHeaders", "reqBody(eos)"},
+			},
+		),
+		gen(
+			"immediate response to streamed request body",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						RequestBodyMode:   v1.ExtProcBodyMode_ExtProcBodyStreamed,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return immediate(http.StatusBadRequest, "bad")
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusBadRequest,
+				header: map[string]string{"X-Immediate": "true", "X-Method": ""},
+				body:   "bad",
+				types:  []string{"reqBody"},
+			},
+		),
+		gen(
+			"immediate response to response headers",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if req.GetResponseHeaders() != nil {
+						return immediate(http.StatusBadGateway, "bad gateway")
+					}
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusBadGateway,
+				header: map[string]string{"X-Immediate": "true", "X-Method": ""},
+				body:   "bad gateway",
+				types:  []string{"reqHeaders", "resHeaders"},
+			},
+		),
+		gen(
+			"immediate response to buffered response body",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						ResponseBodyMode:  v1.ExtProcBodyMode_ExtProcBodyBuffered,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					if req.GetResponseBody() != nil {
+						return immediate(http.StatusBadGateway, "bad gateway")
+					}
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusBadGateway,
+				header: map[string]string{"X-Immediate": "true"},
+				body:   "bad gateway",
+				types:  []string{"resHeaders", "resBody(eos)"},
+			},
+		),
+		gen(
+			"replace request and response",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					b := "replaced request"
+					if req.GetResponseHeaders() != nil {
+						b = "replaced response"
+					}
+					return continueResponse(req, &extprocv3.CommonResponse{
+						Status: extprocv3.CommonResponse_CONTINUE_AND_REPLACE,
+						BodyMutation: &extprocv3.BodyMutation{
+							Mutation: &extprocv3.BodyMutation_Body{Body: []byte(b)},
+						},
+					})
+				},
+				body: "hello",
+				handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					b, _ := io.ReadAll(r.Body)
+					w.Header().Set("X-Body", string(b))
+					_, _ = w.Write([]byte("original"))
+				}),
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Body": "replaced request", "Content-Length": "17"},
+				body:   "replaced response",
+				types:  []string{"reqHeaders", "resHeaders"},
+			},
+		),
+		gen(
+			"mode override",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{AllowModeOverride: true},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					res := continueResponse(req, nil)
+					if req.GetRequestHeaders() != nil {
+						res.ModeOverride = &filterv3.ProcessingMode{
+							ResponseHeaderMode: filterv3.ProcessingMode_SKIP,
+							RequestBodyMode:    filterv3.ProcessingMode_BUFFERED,
+							ResponseBodyMode:   filterv3.ProcessingMode_BUFFERED,
+						}
+					}
+					return res
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				body:   "hello",
+				types:  []string{"reqHeaders", "reqBody(eos)", "resBody(eos)"},
+			},
+		),
+		gen(
+			"mode override not allowed",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					res := continueResponse(req, nil)
+					res.ModeOverride = &filterv3.ProcessingMode{ResponseHeaderMode: filterv3.ProcessingMode_SKIP}
+					return res
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				body:   "hello",
+				types:  []string{"reqHeaders", "resHeaders"},
+			},
+		),
+		gen(
+			"request body too large",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					MaxBufferSize: 3,
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						RequestBodyMode:   v1.ExtProcBodyMode_ExtProcBodyBuffered,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusRequestEntityTooLarge,
+				types:  []string{},
+			},
+		),
+		gen(
+			"response body too large",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{
+					MaxBufferSize: 3,
+					ProcessingMode: &v1.ExtProcProcessingMode{
+						RequestHeaderMode:  v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						ResponseHeaderMode: v1.ExtProcHeaderMode_ExtProcHeaderSkip,
+						ResponseBodyMode:   v1.ExtProcBodyMode_ExtProcBodyBuffered,
+					},
+				},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				types:  []string{},
+			},
+		),
+		gen(
+			"fail closed",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return nil
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				types:  []string{"reqHeaders"},
+			},
+		),
+		gen(
+			"fail open",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{FailOpen: true},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return nil
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Method": "POST"},
+				body:   "hello",
+				types:  []string{"reqHeaders"},
+			},
+		),
+		gen(
+			"timeout fail closed",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{Timeout: 50},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					time.Sleep(200 * time.Millisecond)
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				types:  []string{"reqHeaders"},
+			},
+		),
+		gen(
+			"timeout fail open",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{Timeout: 50, FailOpen: true},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					time.Sleep(200 * time.Millisecond)
+					return continueResponse(req, nil)
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Method": "POST"},
+				body:   "hello",
+				types:  []string{"reqHeaders"},
+			},
+		),
+		gen(
+			"unexpected response type",
+			&condition{
+				spec: &v1.ExtProcMiddlewareSpec{},
+				handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+					return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseBody{
+						ResponseBody: &extprocv3.BodyResponse{},
+					}}
+				},
+				body: "hello",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				types:  []string{"reqHeaders"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tp := &testProcessor{handle: tt.C.handle}
+			p := newTestExtProc(t, tp, tt.C.spec)
+			var next http.Handler = testHandler
+			if tt.C.handler != nil {
+				next = tt.C.handler
+			}
+			h := p.Middleware(next)
+
+			var body io.Reader = http.NoBody
+			if tt.C.body != "" {
+				body = strings.NewReader(tt.C.body)
+			}
+			r := httptest.NewRequest(http.MethodPost, "http://test.com/test", body)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			for name, value := range tt.A.header {
+				testutil.Diff(t, value, w.Header().Get(name))
+			}
+			if tt.A.body != "" {
+				testutil.Diff(t, tt.A.body, w.Body.String())
+			}
+			types := []string{}
+			for _, req := range tp.requests() {
+				types = append(types, typeOf(req))
+			}
+			testutil.Diff(t, tt.A.types, types)
+		})
+	}
+}
+
+func TestMiddleware_requestHeaders(t *testing.T) {
+	tp := &testProcessor{handle: func(req *extprocv3.ProcessingRequest) *extprocv3.ProcessingResponse {
+		return continueResponse(req, nil)
+	}}
+	p := newTestExtProc(t, tp, &v1.ExtProcMiddlewareSpec{})
+	h := p.Middleware(testHandler)
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/test?foo=bar", nil)
+	r.Header.Set("X-Test", "test")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusOK, w.Code)
+
+	reqs := tp.requests()
+	testutil.Diff(t, 2, len(reqs))
+
+	headers := map[string]string{}
+	for _, hv := range reqs[0].GetRequestHeaders().GetHeaders().GetHeaders() {
+		headers[hv.Key] = string(hv.RawValue)
+	}
+	testutil.Diff(t, map[string]string{
+		":method":    "GET",
+		":path":      "/test?foo=bar",
+		":authority": "test.com",
+		":scheme":    "http",
+		"x-test":     "test",
+	}, headers)
+	testutil.Diff(t, true, reqs[0].GetRequestHeaders().GetEndOfStream())
+	testutil.Diff(t, filterv3.ProcessingMode_NONE, reqs[0].GetProtocolConfig().GetRequestBodyMode())
+	testutil.Diff(t, true, reqs[1].GetProtocolConfig() == nil)
+
+	headers = map[string]string{}
+	for _, hv := range reqs[1].GetResponseHeaders().GetHeaders().GetHeaders() {
+		headers[hv.Key] = string(hv.RawValue)
+	}
+	testutil.Diff(t, "200", headers[":status"])
+	testutil.Diff(t, "GET", headers["x-method"])
+}
+
+func TestApplyHeaderMutation(t *testing.T) {
+	type condition struct {
+		header http.Header
+		m      *extprocv3.HeaderMutation
+	}
+
+	type action struct {
+		header http.Header
+		pseudo map[string]string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil mutation",
+			&condition{header: http.Header{"Foo": {"bar"}}},
+			&action{header: http.Header{"Foo": {"bar"}}, pseudo: map[string]string{}},
+		),
+		gen(
+			"remove headers",
+			&condition{
+				header: http.Header{"Foo": {"bar"}, "Alice": {"bob"}},
+				m:      &extprocv3.HeaderMutation{RemoveHeaders: []string{"foo", ":path"}},
+			},
+			&action{header: http.Header{"Alice": {"bob"}}, pseudo: map[string]string{}},
+		),
+		gen(
+			"append actions",
+			&condition{
+				header: http.Header{"A": {"1"}, "B": {"1"}, "C": {"1"}, "D": {"1"}},
+				m: &extprocv3.HeaderMutation{
+					SetHeaders: []*corev3.HeaderValueOption{
+						{Header: &corev3.HeaderValue{Key: "a", Value: "2"}},
+						{Header: &corev3.HeaderValue{Key: "b", RawValue: []byte("2")}, AppendAction: corev3.HeaderValueOption_ADD_IF_ABSENT},
+						{Header: &corev3.HeaderValue{Key: "e", RawValue: []byte("2")}, AppendAction: corev3.HeaderValueOption_ADD_IF_ABSENT},
+						{Header: &corev3.HeaderValue{Key: "c", RawValue: []byte("2")}, AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS},
+						{Header: &corev3.HeaderValue{Key: "f", RawValue: []byte("2")}, AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS},
+						{Header: &corev3.HeaderValue{Key: "d", RawValue: []byte("2")}, Append: wrapperspb.Bool(true)},
+						{Header: &corev3.HeaderValue{Key: "g", RawValue: []byte("2")}, Append: wrapperspb.Bool(false)},
+					},
+				},
+			},
+			&action{
+				header: http.Header{"A": {"2"}, "B": {"1"}, "C": {"2"}, "D": {"1", "2"}, "E": {"2"}, "G": {"2"}},
+				pseudo: map[string]string{},
+			},
+		),
+		gen(
+			"empty values",
+			&condition{
+				header: http.Header{},
+				m: &extprocv3.HeaderMutation{
+					SetHeaders: []*corev3.HeaderValueOption{
+						{Header: &corev3.HeaderValue{Key: "a"}},
+						{Header: &corev3.HeaderValue{Key: "b"}, KeepEmptyValue: true},
+						{Header: &corev3.HeaderValue{Key: ""}},
+						{},
+					},
+				},
+			},
+			&action{header: http.Header{"B": {""}}, pseudo: map[string]string{}},
+		),
+		gen(
+			"pseudo headers",
+			&condition{
+				header: http.Header{},
+				m: &extprocv3.HeaderMutation{
+					SetHeaders: []*corev3.HeaderValueOption{
+						{Header: &corev3.HeaderValue{Key: ":path", Value: "/test"}},
+					},
+				},
+			},
+			&action{header: http.Header{}, pseudo: map[string]string{":path": "/test"}},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			pseudo := map[string]string{}
+			applyHeaderMutation(tt.C.header, tt.C.m, func(name, value string) { pseudo[name] = value })
+			testutil.Diff(t, tt.A.header, tt.C.header)
+			testutil.Diff(t, tt.A.pseudo, pseudo)
+		})
+	}
+}
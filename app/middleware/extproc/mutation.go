@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"net/http"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+)
+
+// headerMap converts the pseudo headers and the headers
+// into the header map sent to the external processor.
+// Header names are lower cased as HTTP/2.
+// Pseudo headers are given as pairs of names and values.
+func headerMap(h http.Header, pseudo ...string) *corev3.HeaderMap {
+	hm := &corev3.HeaderMap{Headers: make([]*corev3.HeaderValue, 0, len(pseudo)/2+len(h))}
+	for i := 0; i+1 < len(pseudo); i += 2 {
+		hm.Headers = append(hm.Headers, &corev3.HeaderValue{Key: pseudo[i], RawValue: []byte(pseudo[i+1])})
+	}
+	for name, values := range h {
+		key := strings.ToLower(name)
+		for _, v := range values {
+			hm.Headers = append(hm.Headers, &corev3.HeaderValue{Key: key, RawValue: []byte(v)})
+		}
+	}
+	return hm
+}
+
+// headerValue returns the value of the header value.
+// RawValue is used when set as Envoy does.
+func headerValue(hv *corev3.HeaderValue) string {
+	if len(hv.RawValue) > 0 {
+		return string(hv.RawValue)
+	}
+	return hv.Value
+}
+
+// applyHeaderMutation applies the header mutation to the headers.
+// Pseudo headers which start with ":" are passed to the pseudo function
+// and are not set to the headers.
+// The pseudo function can be nil.
+//
+// How set headers are applied follows the append field
+// and the append_action field of the header value options.
+// The deprecated append field takes precedence when set.
+// Headers are overwritten by default as Envoy's ext_proc filter does.
+func applyHeaderMutation(h http.Header, m *extprocv3.HeaderMutation, pseudo func(name, value string)) {
+	if m == nil {
+		return
+	}
+	for _, name := range m.RemoveHeaders {
+		if !strings.HasPrefix(name, ":") {
+			h.Del(name)
+		}
+	}
+	for _, opt := range m.SetHeaders {
+		hv := opt.GetHeader()
+		if hv == nil || hv.Key == "" {
+			continue
+		}
+		name, value := hv.Key, headerValue(hv)
+		if strings.HasPrefix(name, ":") {
+			if pseudo != nil {
+				pseudo(name, value)
+			}
+			continue
+		}
+		if value == "" && !opt.KeepEmptyValue {
+			continue // Empty values are dropped.
+		}
+		if opt.Append != nil {
+			if opt.Append.Value {
+				h.Add(name, value)
+			} else {
+				h.Set(name, value)
+			}
+			continue
+		}
+		switch opt.AppendAction {
+		case corev3.HeaderValueOption_ADD_IF_ABSENT:
+			if len(h.Values(name)) == 0 {
+				h.Set(name, value)
+			}
+		case corev3.HeaderValueOption_OVERWRITE_IF_EXISTS:
+			if len(h.Values(name)) > 0 {
+				h.Set(name, value)
+			}
+		default:
+			h.Set(name, value)
+		}
+	}
+}
+
+// mutateBody returns the body mutated by the body mutation.
+func mutateBody(body []byte, m *extprocv3.BodyMutation) []byte {
+	switch v := m.GetMutation().(type) {
+	case *extprocv3.BodyMutation_Body:
+		return v.Body
+	case *extprocv3.BodyMutation_ClearBody:
+		if v.ClearBody {
+			return nil
+		}
+	}
+	return body
+}
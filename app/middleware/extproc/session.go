@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package extproc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	filterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_proc/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+)
+
+var (
+	errTimeout           = errors.New("extproc: timeout waiting for the external processor")
+	errUnexpectedType    = errors.New("extproc: unexpected response type from the external processor")
+	errImmediateResponse = errors.New("extproc: immediate response received after the request or response was started")
+)
+
+// mode is the processing mode.
+type mode struct {
+	// requestHeader and responseHeader are true
+	// when the headers are sent to the external processor.
+	requestHeader  bool
+	responseHeader bool
+	requestBody    v1.ExtProcBodyMode
+	responseBody   v1.ExtProcBodyMode
+}
+
+// override overrides the mode with the mode given by the external processor.
+// Unsupported body modes are ignored.
+func (m *mode) override(o *filterv3.ProcessingMode) {
+	switch o.ResponseHeaderMode {
+	case filterv3.ProcessingMode_SEND:
+		m.responseHeader = true
+	case filterv3.ProcessingMode_SKIP:
+		m.responseHeader = false
+	}
+	bodyModes := map[filterv3.ProcessingMode_BodySendMode]v1.ExtProcBodyMode{
+		filterv3.ProcessingMode_NONE:     v1.ExtProcBodyMode_ExtProcBodyNone,
+		filterv3.ProcessingMode_STREAMED: v1.ExtProcBodyMode_ExtProcBodyStreamed,
+		filterv3.ProcessingMode_BUFFERED: v1.ExtProcBodyMode_ExtProcBodyBuffered,
+	}
+	if bm, ok := bodyModes[o.RequestBodyMode]; ok {
+		m.requestBody = bm
+	}
+	if bm, ok := bodyModes[o.ResponseBodyMode]; ok {
+		m.responseBody = bm
+	}
+}
+
+// protocolConfig returns the protocol configuration
+// sent to the external processor with the first message.
+func (m *mode) protocolConfig() *extprocv3.ProtocolConfiguration {
+	bodyModes := map[v1.ExtProcBodyMode]filterv3.ProcessingMode_BodySendMode{
+		v1.ExtProcBodyMode_ExtProcBodyNone:     filterv3.ProcessingMode_NONE,
+		v1.ExtProcBodyMode_ExtProcBodyStreamed: filterv3.ProcessingMode_STREAMED,
+		v1.ExtProcBodyMode_ExtProcBodyBuffered: filterv3.ProcessingMode_BUFFERED,
+	}
+	return &extprocv3.ProtocolConfiguration{
+		RequestBodyMode:  bodyModes[m.requestBody],
+		ResponseBodyMode: bodyModes[m.responseBody],
+	}
+}
+
+// session is the processing session of a request.
+// A session uses a stream to the external processor.
+// The stream is opened when the first message is sent.
+type session struct {
+	p      *extProc
+	ctx    context.Context
+	cancel context.CancelFunc
+	mode   mode
+
+	// mu protects the fields below.
+	// The request body can be read in another goroutine
+	// than the one handling the response.
+	mu sync.Mutex
+	s  *stream
+	// err is the error occurred while communicating with the external processor.
+	// Messages are not sent once an error occurred.
+	err error
+	// immediate is the immediate response received
+	// while streaming the request body.
+	immediate *extprocv3.ImmediateResponse
+}
+
+// exchange sends the message to the external processor
+// and returns the received response.
+// It returns nil response and nil error when failed in the fail-open mode.
+func (ss *session) exchange(req *extprocv3.ProcessingRequest) (*extprocv3.ProcessingResponse, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.err != nil {
+		return ss.failed(nil)
+	}
+
+	if ss.s == nil {
+		s, err := ss.p.client.open(ss.ctx)
+		if err != nil {
+			return ss.failed(err)
+		}
+		ss.s = s
+		req.ProtocolConfig = ss.mode.protocolConfig()
+	}
+
+	type result struct {
+		res *extprocv3.ProcessingResponse
+		err error
+	}
+	ch := make(chan *result, 1)
+	go func() {
+		if err := ss.s.send(req); err != nil {
+			ch <- &result{err: err}
+			return
+		}
+		res, err := ss.s.recv()
+		ch <- &result{res: res, err: err}
+	}()
+
+	timer := time.NewTimer(ss.p.timeout)
+	defer timer.Stop()
+	var r *result
+	select {
+	case r = <-ch:
+	case <-timer.C:
+		ss.cancel() // Abort the stream.
+		<-ch
+		r = &result{err: errTimeout}
+	}
+	if r.err != nil {
+		return ss.failed(r.err)
+	}
+	if !expectedType(req, r.res) {
+		return ss.failed(errUnexpectedType)
+	}
+	return r.res, nil
+}
+
+// failed records the error and aborts the stream.
+// The error is returned in the fail-closed mode.
+// Nil error is returned in the fail-open mode.
+// Callers must hold the lock.
+func (ss *session) failed(err error) (*extprocv3.ProcessingResponse, error) {
+	if ss.err == nil {
+		ss.err = err
+		ss.cancel()
+		if ss.p.failOpen {
+			ss.p.lg.Warn(ss.ctx, "external processing failed. continue without processing.", "error", err.Error())
+		}
+	}
+	if ss.p.failOpen {
+		return nil, nil
+	}
+	return nil, ss.err
+}
+
+// setImmediate records the immediate response
+// received while streaming the request body.
+func (ss *session) setImmediate(ir *extprocv3.ImmediateResponse) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.immediate = ir
+}
+
+// getImmediate returns the immediate response
+// received while streaming the request body.
+func (ss *session) getImmediate() *extprocv3.ImmediateResponse {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.immediate
+}
+
+// close closes the stream.
+func (ss *session) close() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.s != nil {
+		ss.s.close()
+	}
+	ss.cancel()
+}
+
+// expectedType returns true when the response
+// is the one expected for the request.
+// Immediate responses are always expected.
+func expectedType(req *extprocv3.ProcessingRequest, res *extprocv3.ProcessingResponse) bool {
+	if res.GetImmediateResponse() != nil {
+		return true
+	}
+	switch req.Request.(type) {
+	case *extprocv3.ProcessingRequest_RequestHeaders:
+		return res.GetRequestHeaders() != nil
+	case *extprocv3.ProcessingRequest_RequestBody:
+		return res.GetRequestBody() != nil
+	case *extprocv3.ProcessingRequest_ResponseHeaders:
+		return res.GetResponseHeaders() != nil
+	case *extprocv3.ProcessingRequest_ResponseBody:
+		return res.GetResponseBody() != nil
+	}
+	return false
+}
@@ -6,6 +6,7 @@ package header
 import (
 	"cmp"
 	"net/textproto"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.HeaderPolicyMiddlewareSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*headerPolicy]()},
 	},
 }
 
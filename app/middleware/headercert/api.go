@@ -8,10 +8,12 @@ import (
 	"crypto/x509"
 	"errors"
 	"os"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	"github.com/aileron-gateway/aileron-gateway/kernel/api"
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
@@ -41,6 +43,7 @@ var Resource api.Resource = &API{
 				FingerprintpHeader: "",
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*headerCert]()},
 	},
 }
 
@@ -58,6 +61,11 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
 	}
 
+	rc, err := network.NewRevocationChecker(c.Spec.Revocation)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
 	return &headerCert{
 		eh: eh,
 		opts: x509.VerifyOptions{
@@ -65,6 +73,7 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 		},
 		certHeader: c.Spec.CertHeader,
 		fpHeader:   c.Spec.FingerprintpHeader,
+		revocation: rc,
 	}, nil
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/aileron-gateway/aileron-gateway/app"
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 )
 
@@ -22,6 +23,9 @@ type headerCert struct {
 	opts       x509.VerifyOptions
 	certHeader string
 	fpHeader   string
+	// revocation checks the revocation status of the client certificates.
+	// Revocation is not checked when nil.
+	revocation *network.RevocationChecker
 }
 
 func (m *headerCert) Middleware(next http.Handler) http.Handler {
@@ -38,10 +42,18 @@ func (m *headerCert) Middleware(next http.Handler) http.Handler {
 			return
 		}
 		// Verify the client certificate
-		if _, err := cert.Verify(m.opts); err != nil {
+		chains, err := cert.Verify(m.opts)
+		if err != nil {
 			m.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusUnauthorized))
 			return
 		}
+		if m.revocation != nil {
+			if err := m.revocation.Check(r.Context(), chains[0]); err != nil {
+				err = app.ErrAppMiddleInvalidCert.WithoutStack(err, map[string]any{"reason": "revocation check failed"})
+				m.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusUnauthorized))
+				return
+			}
+		}
 
 		if m.fpHeader != "" {
 			fh := r.Header.Get(m.fpHeader)
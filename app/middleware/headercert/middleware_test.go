@@ -11,7 +11,9 @@ import (
 	"os"
 	"testing"
 
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 )
@@ -34,6 +36,7 @@ func TestMiddleware(t *testing.T) {
 		certHeader string
 		fpHeader   string
 		headers    map[string]string
+		revocation *k.RevocationConfig
 	}
 
 	type action struct {
@@ -167,6 +170,38 @@ func TestMiddleware(t *testing.T) {
 				status: http.StatusUnauthorized,
 			},
 		),
+		gen(
+			"revocation status unknown",
+			&condition{
+				method:     http.MethodGet,
+				certHeader: "X-SSL-Client-Cert",
+				fpHeader:   "X-SSL-Client-Fingerprint",
+				headers: map[string]string{
+					"X-SSL-Client-Cert":        base64.URLEncoding.EncodeToString(cert),
+					"X-SSL-Client-Fingerprint": string(fp),
+				},
+				revocation: &k.RevocationConfig{},
+			},
+			&action{
+				status: http.StatusUnauthorized,
+			},
+		),
+		gen(
+			"revocation status unknown with soft-fail",
+			&condition{
+				method:     http.MethodGet,
+				certHeader: "X-SSL-Client-Cert",
+				fpHeader:   "X-SSL-Client-Fingerprint",
+				headers: map[string]string{
+					"X-SSL-Client-Cert":        base64.URLEncoding.EncodeToString(cert),
+					"X-SSL-Client-Fingerprint": string(fp),
+				},
+				revocation: &k.RevocationConfig{SoftFail: true},
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
 	}
 
 	rootCAs := []string{rootCAPath}
@@ -187,8 +222,12 @@ func TestMiddleware(t *testing.T) {
 		tt := tt
 		t.Run(tt.Name, func(t *testing.T) {
 
+			rc, err := network.NewRevocationChecker(tt.C.revocation)
+			testutil.Diff(t, nil, err)
+
 			// Prepare the headercert middleware
 			headerCertMiddleware := &headerCert{
+				revocation: rc,
 				eh:         utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName),
 				opts:       opts,
 				certHeader: tt.C.certHeader,
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package maintenance
+
+import (
+	"cmp"
+	"net/http"
+	"net/netip"
+	"net/textproto"
+	"reflect"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/txtutil"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"github.com/aileron-projects/go/ztime/zcron"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "MaintenanceMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.MaintenanceMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.MaintenanceMiddlewareSpec{},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*maintenance]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.MaintenanceMiddleware)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	m := &maintenance{
+		eh:         eh,
+		enabled:    c.Spec.Enabled,
+		retryAfter: int(c.Spec.RetryAfter),
+		timeNow:    time.Now,
+	}
+
+	if c.Spec.FlagFile != "" {
+		m.flag = &flagFile{path: c.Spec.FlagFile}
+	}
+
+	for _, spec := range c.Spec.Windows {
+		ct, err := zcron.Parse(spec.Cron)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		m.windows = append(m.windows, &window{
+			cron:     ct,
+			loc:      ct.Now().Location(),
+			duration: time.Duration(spec.Duration) * time.Second,
+		})
+	}
+
+	if c.Spec.Matcher != nil {
+		paths, err := txtutil.NewStringMatcher(txtutil.MatchTypes[c.Spec.Matcher.MatchType], c.Spec.Matcher.Patterns...)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		m.paths = paths
+	}
+
+	for _, spec := range c.Spec.MIMEContents {
+		content, err := utilhttp.NewMIMEContent(spec)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		content.StatusCode = cmp.Or(content.StatusCode, http.StatusServiceUnavailable)
+		m.contents = append(m.contents, content)
+	}
+
+	for _, n := range c.Spec.BypassNetworks {
+		prefix, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		m.bypassNetworks = append(m.bypassNetworks, prefix.Masked())
+	}
+
+	for _, spec := range c.Spec.BypassHeaders {
+		bh := &bypassHeader{name: textproto.CanonicalMIMEHeaderKey(spec.Name)}
+		for _, v := range spec.Values {
+			bh.values = append(bh.values, []byte(v))
+		}
+		m.bypassHeaders = append(m.bypassHeaders, bh)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package maintenance
+
+import (
+	"net/http"
+	"net/netip"
+	"regexp"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	corev1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		check      func(*testing.T, *maintenance)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default manifest",
+			&condition{
+				manifest: Resource.Default(),
+			},
+			&action{
+				check: func(t *testing.T, m *maintenance) {
+					t.Helper()
+					testutil.Diff(t, false, m.enabled)
+					testutil.Diff(t, true, m.flag == nil)
+					testutil.Diff(t, true, m.paths == nil)
+					testutil.Diff(t, 0, len(m.windows))
+					testutil.Diff(t, 0, len(m.contents))
+				},
+			},
+		),
+		gen(
+			"create with full manifest",
+			&condition{
+				manifest: &v1.MaintenanceMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.MaintenanceMiddlewareSpec{
+						Enabled:    true,
+						FlagFile:   "maintenance.flag",
+						Windows:    []*v1.MaintenanceWindowSpec{{Cron: "TZ=UTC 0 2 * * SUN", Duration: 3600}},
+						Matcher:    &k.MatcherSpec{Patterns: []string{"/api/"}, MatchType: k.MatchType_Prefix},
+						RetryAfter: 60,
+						MIMEContents: []*corev1.MIMEContentSpec{
+							{MIMEType: "text/plain", Template: "maintenance"},
+							{MIMEType: "application/json", StatusCode: 500, Template: "{}"},
+						},
+						BypassNetworks: []string{"10.0.0.1/8"},
+						BypassHeaders:  []*v1.MaintenanceBypassHeaderSpec{{Name: "x-bypass", Values: []string{"secret"}}},
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, m *maintenance) {
+					t.Helper()
+					testutil.Diff(t, true, m.enabled)
+					testutil.Diff(t, "maintenance.flag", m.flag.path)
+					testutil.Diff(t, 1, len(m.windows))
+					testutil.Diff(t, "UTC", m.windows[0].loc.String())
+					testutil.Diff(t, time.Hour, m.windows[0].duration)
+					testutil.Diff(t, true, m.paths.Match("/api/foo"))
+					testutil.Diff(t, 60, m.retryAfter)
+					testutil.Diff(t, http.StatusServiceUnavailable, m.contents[0].StatusCode)
+					testutil.Diff(t, http.StatusInternalServerError, m.contents[1].StatusCode)
+					testutil.Diff(t, true, m.bypassNetworks[0] == netip.MustParsePrefix("10.0.0.0/8"))
+					testutil.Diff(t, "X-Bypass", m.bypassHeaders[0].name)
+					testutil.Diff(t, [][]byte{[]byte("secret")}, m.bypassHeaders[0].values)
+				},
+			},
+		),
+		gen(
+			"invalid cron",
+			&condition{
+				manifest: &v1.MaintenanceMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.MaintenanceMiddlewareSpec{
+						Windows: []*v1.MaintenanceWindowSpec{{Cron: "invalid", Duration: 10}},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create MaintenanceMiddleware`),
+			},
+		),
+		gen(
+			"invalid matcher",
+			&condition{
+				manifest: &v1.MaintenanceMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.MaintenanceMiddlewareSpec{
+						Matcher: &k.MatcherSpec{Patterns: []string{"[0-9"}, MatchType: k.MatchType_Regex},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create MaintenanceMiddleware`),
+			},
+		),
+		gen(
+			"invalid content",
+			&condition{
+				manifest: &v1.MaintenanceMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.MaintenanceMiddlewareSpec{
+						MIMEContents: []*corev1.MIMEContentSpec{{MIMEType: "text/plain", TemplateFile: "not-exist.txt"}},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create MaintenanceMiddleware`),
+			},
+		),
+		gen(
+			"invalid network",
+			&condition{
+				manifest: &v1.MaintenanceMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.MaintenanceMiddlewareSpec{
+						BypassNetworks: []string{"10.0.0.1"},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create MaintenanceMiddleware`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			tt.A.check(t, got.(*maintenance))
+		})
+	}
+}
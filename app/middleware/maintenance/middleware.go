@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package maintenance
+
+import (
+	"crypto/subtle"
+	"math"
+	"mime"
+	"net/http"
+	"net/netip"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/txtutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"github.com/aileron-projects/go/ztime/zcron"
+)
+
+// flagCheckInterval is the minimum interval
+// to check the existence of the flag file.
+const flagCheckInterval = time.Second
+
+// maintenance responds 503 Service Unavailable
+// while the maintenance mode is enabled.
+// This implements core.Middleware interface.
+type maintenance struct {
+	eh core.ErrorHandler
+
+	// enabled enables the maintenance mode statically.
+	enabled bool
+	// flag enables the maintenance mode while the file exists.
+	// Flag file is not checked when nil.
+	flag *flagFile
+	// windows are the scheduled maintenance windows.
+	windows []*window
+	// paths is the path matcher to apply the maintenance mode.
+	// All paths are matched when nil.
+	paths txtutil.Matcher[string]
+
+	// retryAfter is the value of Retry-After header in seconds.
+	// The remaining time of the active window is used when 0.
+	retryAfter int
+	// contents is the list of contents to respond.
+	// The error handler is used when no contents are acceptable.
+	contents []*utilhttp.MIMEContent
+
+	// bypassNetworks is the list of networks
+	// allowed to access during maintenance.
+	bypassNetworks []netip.Prefix
+	// bypassHeaders is the list of headers
+	// allowed to access during maintenance.
+	bypassHeaders []*bypassHeader
+
+	// timeNow returns the current time.
+	timeNow func() time.Time
+}
+
+func (m *maintenance) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.paths != nil && !m.paths.Match(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		active, remaining := m.active(m.timeNow())
+		if !active || m.bypass(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(m.retryAfter))
+		} else if remaining > 0 {
+			sec := int(math.Ceil(remaining.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(sec))
+		}
+
+		c := m.findContent(r.Header.Get("Accept"))
+		if c == nil {
+			err := app.ErrAppMiddleMaintenance.WithoutStack(nil, nil)
+			m.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusServiceUnavailable))
+			return
+		}
+
+		// info is the information that is
+		// given to the template.
+		info := map[string]any{
+			"proto":  r.Proto,
+			"host":   r.Host,
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"remote": r.RemoteAddr,
+			"header": r.Header,
+			"query":  r.URL.Query(),
+		}
+
+		header := w.Header()
+		for k, v := range c.Header {
+			header[k] = append(header[k], v...)
+		}
+		header.Set("Content-Type", c.MIMEType+"; charset=utf-8")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Add("Vary", "Accept")
+
+		w.WriteHeader(c.StatusCode)
+		_, _ = w.Write(c.Content(info))
+	})
+}
+
+// active reports whether the maintenance mode is enabled at the given time.
+// The remaining time of the active windows is returned together.
+// The remaining time is 0 when the maintenance mode is enabled
+// statically or by the flag file.
+func (m *maintenance) active(now time.Time) (bool, time.Duration) {
+	if m.enabled || (m.flag != nil && m.flag.exists(now)) {
+		return true, 0
+	}
+	var remaining time.Duration
+	for _, w := range m.windows {
+		if d, ok := w.remaining(now); ok {
+			remaining = max(remaining, d)
+		}
+	}
+	return remaining > 0, remaining
+}
+
+// bypass reports whether the request is allowed
+// to access during maintenance.
+func (m *maintenance) bypass(r *http.Request) bool {
+	if len(m.bypassNetworks) > 0 {
+		if ap, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+			addr := ap.Addr().Unmap()
+			for _, p := range m.bypassNetworks {
+				if p.Contains(addr) {
+					return true
+				}
+			}
+		}
+	}
+	for _, h := range m.bypassHeaders {
+		if h.match(r.Header) {
+			return true
+		}
+	}
+	return false
+}
+
+// findContent returns content depending on the Accept header.
+// Empty Accept header is considered as "*/*".
+// findContent returns nil when an appropriate type of content
+// was not found.
+func (m *maintenance) findContent(accept string) *utilhttp.MIMEContent {
+	if accept == "" {
+		accept = "*/*"
+	}
+	for _, a := range strings.Split(accept, ",") {
+		mimeType, _, _ := mime.ParseMediaType(a)
+		for _, c := range m.contents {
+			if matched, _ := path.Match(mimeType, c.MIMEType); matched {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// window is a scheduled maintenance window.
+type window struct {
+	// cron is the schedule of the start time of the window.
+	cron *zcron.Crontab
+	// loc is the location of the cron schedule.
+	loc *time.Location
+	// duration is the length of the window.
+	duration time.Duration
+}
+
+// remaining returns the remaining time of the window
+// if the window is active at the given time.
+// Occurrences of the window overlap when the schedule is more frequent
+// than the duration. The remaining time of the latest one is returned then.
+func (w *window) remaining(now time.Time) (time.Duration, bool) {
+	// The window is active when it started in (now-duration, now].
+	lo := now.In(w.loc).Add(-w.duration)
+	if w.cron.NextAfter(lo).After(now) {
+		return 0, false
+	}
+	// Search the latest start in (now-duration, now] because
+	// NextAfter only looks forward. Schedules are in seconds.
+	// NextAfter(lo) <= now < NextAfter(hi) holds while searching.
+	hi := now.In(w.loc)
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if w.cron.NextAfter(mid).After(now) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	start := w.cron.NextAfter(lo)
+	return start.Add(w.duration).Sub(now), true
+}
+
+// flagFile checks the existence of the flag file.
+// The result is cached for flagCheckInterval.
+type flagFile struct {
+	path    string
+	checked atomic.Int64 // Unix nano time of the last check.
+	found   atomic.Bool
+}
+
+func (f *flagFile) exists(now time.Time) bool {
+	last := f.checked.Load()
+	if last != 0 && now.UnixNano()-last < int64(flagCheckInterval) {
+		return f.found.Load()
+	}
+	if f.checked.CompareAndSwap(last, now.UnixNano()) {
+		_, err := os.Stat(f.path)
+		f.found.Store(err == nil)
+	}
+	return f.found.Load()
+}
+
+// bypassHeader matches request headers
+// that are allowed to access during maintenance.
+type bypassHeader struct {
+	name   string
+	values [][]byte
+}
+
+func (h *bypassHeader) match(header http.Header) bool {
+	for _, v := range header.Values(h.name) {
+		for _, allowed := range h.values {
+			if subtle.ConstantTimeCompare([]byte(v), allowed) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
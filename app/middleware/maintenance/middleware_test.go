@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package maintenance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	"github.com/aileron-gateway/aileron-gateway/internal/txtutil"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"github.com/aileron-projects/go/ztime/zcron"
+)
+
+func testWindow(t *testing.T, crontab string, d time.Duration) *window {
+	t.Helper()
+	ct, err := zcron.Parse(crontab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &window{cron: ct, loc: ct.Now().Location(), duration: d}
+}
+
+func testContent(t *testing.T, spec *v1.MIMEContentSpec) *utilhttp.MIMEContent {
+	t.Helper()
+	c, err := utilhttp.NewMIMEContent(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.StatusCode == 0 {
+		c.StatusCode = http.StatusServiceUnavailable
+	}
+	return c
+}
+
+func TestMiddleware(t *testing.T) {
+	type condition struct {
+		m          *maintenance
+		path       string
+		accept     string
+		remoteAddr string
+		header     http.Header
+	}
+
+	type action struct {
+		status     int
+		retryAfter string
+		body       string
+	}
+
+	// 2025-01-05 is a sunday.
+	now := time.Date(2025, 1, 5, 2, 30, 0, 0, time.UTC)
+	eh := utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName)
+	paths, _ := txtutil.NewStringMatcher(txtutil.MatchTypePrefix, "/api/")
+	contents := []*utilhttp.MIMEContent{
+		testContent(t, &v1.MIMEContentSpec{MIMEType: "text/plain", Template: "under maintenance"}),
+		testContent(t, &v1.MIMEContentSpec{
+			MIMEType:     "application/json",
+			TemplateType: v1.TemplateType_GoText,
+			Template:     `{"path":"{{.path}}"}`,
+			Header:       map[string]string{"Cache-Control": "no-store"},
+		}),
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"disabled",
+			&condition{
+				m: &maintenance{},
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
+		gen(
+			"enabled without contents",
+			&condition{
+				m: &maintenance{enabled: true},
+			},
+			&action{
+				status: http.StatusServiceUnavailable,
+				body:   `{"status":503,"statusText":"Service Unavailable"}`,
+			},
+		),
+		gen(
+			"enabled with retry after",
+			&condition{
+				m:      &maintenance{enabled: true, retryAfter: 120, contents: contents},
+				accept: "text/html,text/plain",
+			},
+			&action{
+				status:     http.StatusServiceUnavailable,
+				retryAfter: "120",
+				body:       "under maintenance",
+			},
+		),
+		gen(
+			"render template",
+			&condition{
+				m:      &maintenance{enabled: true, contents: contents},
+				path:   "/foo",
+				accept: "application/json",
+			},
+			&action{
+				status: http.StatusServiceUnavailable,
+				body:   `{"path":"/foo"}`,
+			},
+		),
+		gen(
+			"path not matched",
+			&condition{
+				m:    &maintenance{enabled: true, paths: paths},
+				path: "/foo",
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
+		gen(
+			"path matched",
+			&condition{
+				m:    &maintenance{enabled: true, paths: paths, contents: contents},
+				path: "/api/foo",
+			},
+			&action{
+				status: http.StatusServiceUnavailable,
+				body:   "under maintenance",
+			},
+		),
+		gen(
+			"active window",
+			&condition{
+				m: &maintenance{
+					windows:  []*window{testWindow(t, "TZ=UTC 0 2 * * SUN", time.Hour)},
+					contents: contents,
+				},
+			},
+			&action{
+				status:     http.StatusServiceUnavailable,
+				retryAfter: "1800",
+				body:       "under maintenance",
+			},
+		),
+		gen(
+			"inactive window",
+			&condition{
+				m: &maintenance{
+					windows: []*window{testWindow(t, "TZ=UTC 0 2 * * SAT", time.Hour)},
+				},
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
+		gen(
+			"bypass network",
+			&condition{
+				m: &maintenance{
+					enabled:        true,
+					bypassNetworks: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				},
+				remoteAddr: "10.0.0.1:12345",
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
+		gen(
+			"network not bypassed",
+			&condition{
+				m: &maintenance{
+					enabled:        true,
+					bypassNetworks: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+					contents:       contents,
+				},
+				remoteAddr: "192.168.0.1:12345",
+			},
+			&action{
+				status: http.StatusServiceUnavailable,
+				body:   "under maintenance",
+			},
+		),
+		gen(
+			"bypass header",
+			&condition{
+				m: &maintenance{
+					enabled:       true,
+					bypassHeaders: []*bypassHeader{{name: "X-Bypass", values: [][]byte{[]byte("secret")}}},
+				},
+				header: http.Header{"X-Bypass": {"wrong", "secret"}},
+			},
+			&action{
+				status: http.StatusOK,
+			},
+		),
+		gen(
+			"header not bypassed",
+			&condition{
+				m: &maintenance{
+					enabled:       true,
+					bypassHeaders: []*bypassHeader{{name: "X-Bypass", values: [][]byte{[]byte("secret")}}},
+					contents:      contents,
+				},
+				header: http.Header{"X-Bypass": {"wrong"}},
+			},
+			&action{
+				status: http.StatusServiceUnavailable,
+				body:   "under maintenance",
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.C.m.eh = eh
+			tt.C.m.timeNow = func() time.Time { return now }
+			h := tt.C.m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "http://test.com"+tt.C.path, nil)
+			r.RemoteAddr = tt.C.remoteAddr
+			for k, v := range tt.C.header {
+				r.Header[k] = v
+			}
+			r.Header.Set("Accept", tt.C.accept)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			testutil.Diff(t, tt.A.retryAfter, w.Header().Get("Retry-After"))
+			testutil.Diff(t, tt.A.body, w.Body.String())
+		})
+	}
+}
+
+func TestWindow_remaining(t *testing.T) {
+	w := testWindow(t, "TZ=UTC 0 2 * * *", time.Hour)
+	day := func(h, m, s int) time.Time { return time.Date(2025, 1, 5, h, m, s, 0, time.UTC) }
+
+	_, ok := w.remaining(day(1, 59, 59))
+	testutil.Diff(t, false, ok)
+	d, ok := w.remaining(day(2, 0, 0))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, time.Hour, d)
+	d, ok = w.remaining(day(2, 59, 59))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, time.Second, d)
+	_, ok = w.remaining(day(3, 0, 0))
+	testutil.Diff(t, false, ok)
+
+	// Windows across the day.
+	w = testWindow(t, "TZ=UTC 0 23 * * *", 2*time.Hour)
+	d, ok = w.remaining(day(0, 30, 0))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, 30*time.Minute, d)
+
+	// Overlapping occurrences end at the latest one.
+	w = testWindow(t, "TZ=UTC */10 * * * *", 30*time.Minute)
+	d, ok = w.remaining(day(2, 25, 0))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, 25*time.Minute, d)
+	d, ok = w.remaining(day(2, 20, 0))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, 30*time.Minute, d)
+	d, ok = w.remaining(day(2, 19, 59))
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, 20*time.Minute+time.Second, d)
+}
+
+func TestMaintenance_active(t *testing.T) {
+	now := time.Date(2025, 1, 5, 2, 30, 0, 0, time.UTC)
+	m := &maintenance{
+		windows: []*window{
+			testWindow(t, "TZ=UTC 0 2 * * *", time.Hour),
+			testWindow(t, "TZ=UTC 0 2 * * *", 2*time.Hour),
+		},
+	}
+	active, remaining := m.active(now)
+	testutil.Diff(t, true, active)
+	testutil.Diff(t, 90*time.Minute, remaining)
+
+	m.enabled = true
+	active, remaining = m.active(now)
+	testutil.Diff(t, true, active)
+	testutil.Diff(t, time.Duration(0), remaining)
+}
+
+func TestFlagFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "maintenance.flag")
+	f := &flagFile{path: p}
+	now := time.Now()
+
+	testutil.Diff(t, false, f.exists(now))
+
+	if err := os.WriteFile(p, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Cached result is returned within the interval.
+	testutil.Diff(t, false, f.exists(now.Add(flagCheckInterval/2)))
+	testutil.Diff(t, true, f.exists(now.Add(flagCheckInterval)))
+
+	if err := os.Remove(p); err != nil {
+		t.Fatal(err)
+	}
+	testutil.Diff(t, false, f.exists(now.Add(2*flagCheckInterval)))
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"cmp"
+	"reflect"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "ScriptMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+const (
+	// defaultMaxSteps is the default maximum number
+	// of execution steps of a hook call.
+	defaultMaxSteps = 1_000_000
+	// defaultTimeout is the default timeout
+	// in milliseconds of a hook call.
+	defaultTimeout = 100
+)
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.ScriptMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.ScriptMiddlewareSpec{
+				MaxSteps:  defaultMaxSteps,
+				Timeout:   defaultTimeout,
+				ClaimsKey: "AuthnClaims",
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*script]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.ScriptMiddleware)
+	lg := log.DefaultOr(c.Metadata.Logger)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	interval := time.Millisecond * time.Duration(c.Spec.ReloadInterval)
+	l, err := newLoader(lg, c.Spec.ScriptFile, predeclared(lg, c.Spec.Vars), interval)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
+	return &script{
+		lg:        lg,
+		eh:        eh,
+		loader:    l,
+		claimsKey: c.Spec.ClaimsKey,
+		maxSteps:  uint64(cmp.Or(c.Spec.MaxSteps, defaultMaxSteps)),
+		timeout:   time.Millisecond * time.Duration(cmp.Or(c.Spec.Timeout, defaultTimeout)),
+	}, nil
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+const testDataDir = "../../../test/ut/app/script/"
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		check      func(*testing.T, *script)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default values",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ScriptMiddlewareSpec{
+						ScriptFile: testDataDir + "hooks.star",
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, s *script) {
+					t.Helper()
+					testutil.Diff(t, uint64(defaultMaxSteps), s.maxSteps)
+					testutil.Diff(t, 100*time.Millisecond, s.timeout)
+					testutil.Diff(t, time.Duration(0), s.loader.interval)
+					p := s.loader.current.Load()
+					testutil.Diff(t, onRequest, p.onRequest.Name())
+					testutil.Diff(t, onResponse, p.onResponse.Name())
+				},
+			},
+		),
+		gen(
+			"create with options",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ScriptMiddlewareSpec{
+						ScriptFile:     testDataDir + "request-only.star",
+						ReloadInterval: 1000,
+						MaxSteps:       10,
+						Timeout:        20,
+						ClaimsKey:      "Claims",
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, s *script) {
+					t.Helper()
+					testutil.Diff(t, uint64(10), s.maxSteps)
+					testutil.Diff(t, 20*time.Millisecond, s.timeout)
+					testutil.Diff(t, time.Second, s.loader.interval)
+					testutil.Diff(t, "Claims", s.claimsKey)
+					p := s.loader.current.Load()
+					testutil.Diff(t, true, p.onResponse == nil)
+				},
+			},
+		),
+		gen(
+			"file not found",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "not-exist.star"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ScriptMiddleware`),
+			},
+		),
+		gen(
+			"syntax error",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "syntax-error.star"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`syntax-error.star:[0-9]+:[0-9]+: got newline, want ':'`),
+			},
+		),
+		gen(
+			"no hooks",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "no-hooks.star"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`neither on_request nor on_response is defined`),
+			},
+		),
+		gen(
+			"invalid params",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "invalid-params.star"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`on_response must take 2 parameters but takes 1`),
+			},
+		),
+		gen(
+			"not function",
+			&condition{
+				manifest: &v1.ScriptMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "not-function.star"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`on_request must be a function but got string`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			tt.A.check(t, got.(*script))
+		})
+	}
+}
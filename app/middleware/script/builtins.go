@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// localContext is the thread local key of the request context.
+const localContext = "context"
+
+// threadContext returns the request context of the thread.
+func threadContext(thread *starlark.Thread) context.Context {
+	if ctx, ok := thread.Local(localContext).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// predeclared returns the predeclared values available from scripts.
+//
+//   - respond(status, body="", headers={}) returns an immediate response.
+//   - log.debug, log.info, log.warn and log.error output logs.
+//   - vars is the dict of the configured variables.
+func predeclared(lg log.Logger, vars map[string]string) starlark.StringDict {
+	d := starlark.NewDict(len(vars))
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		_ = d.SetKey(starlark.String(k), starlark.String(vars[k]))
+	}
+	d.Freeze()
+
+	logFunc := func(name string, f func(context.Context, string, ...any)) *starlark.Builtin {
+		return starlark.NewBuiltin("log."+name, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var msg string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, nil, 1, &msg); err != nil {
+				return nil, err
+			}
+			kvs := make([]any, 0, 2*len(kwargs))
+			for _, kv := range kwargs {
+				key, _ := starlark.AsString(kv[0])
+				value, ok := starlark.AsString(kv[1])
+				if !ok {
+					value = kv[1].String()
+				}
+				kvs = append(kvs, key, value)
+			}
+			f(threadContext(thread), msg, kvs...)
+			return starlark.None, nil
+		})
+	}
+
+	return starlark.StringDict{
+		"respond": starlark.NewBuiltin("respond", respond),
+		"vars":    d,
+		"log": &starlarkstruct.Module{
+			Name: "log",
+			Members: starlark.StringDict{
+				"debug": logFunc("debug", lg.Debug),
+				"info":  logFunc("info", lg.Info),
+				"warn":  logFunc("warn", lg.Warn),
+				"error": logFunc("error", lg.Error),
+			},
+		},
+	}
+}
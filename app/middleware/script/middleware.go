@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"go.starlark.net/starlark"
+)
+
+// script runs Starlark scripts for requests and responses.
+// This implements core.Middleware interface.
+type script struct {
+	lg     log.Logger
+	eh     core.ErrorHandler
+	loader *loader
+
+	// claimsKey is the context key to extract claims.
+	claimsKey string
+	// maxSteps is the maximum number of execution steps of a hook call.
+	maxSteps uint64
+	// timeout is the timeout of a hook call.
+	timeout time.Duration
+}
+
+func (m *script) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := m.loader.get(r.Context())
+		req := newRequest(r, r.Context().Value(m.claimsKey))
+
+		if p.onRequest != nil {
+			v, err := m.call(r.Context(), p.onRequest, req)
+			if err != nil {
+				m.serveError(w, r, onRequest, err)
+				return
+			}
+			req.apply()
+			if ir, ok := v.(*immediateResponse); ok {
+				ir.write(w)
+				return
+			}
+		}
+
+		if p.onResponse == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ww := &wrappedWriter{ResponseWriter: w, m: m, p: p, req: req, r: r}
+		next.ServeHTTP(ww, r)
+		ww.finish()
+	})
+}
+
+// call calls the hook function with the limits of execution steps and time.
+// The hook must return None or an immediate response.
+func (m *script) call(ctx context.Context, fn *starlark.Function, args ...starlark.Value) (starlark.Value, error) {
+	thread := &starlark.Thread{
+		Name: fn.Name(),
+		Print: func(_ *starlark.Thread, msg string) {
+			m.lg.Debug(ctx, msg, "path", m.loader.path)
+		},
+	}
+	thread.SetLocal(localContext, ctx)
+	thread.SetMaxExecutionSteps(m.maxSteps)
+	timer := time.AfterFunc(m.timeout, func() { thread.Cancel("timeout") })
+	defer timer.Stop()
+	stop := context.AfterFunc(ctx, func() { thread.Cancel("request canceled") })
+	defer stop()
+
+	v, err := starlark.Call(thread, fn, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch v.(type) {
+	case starlark.NoneType, *immediateResponse:
+		return v, nil
+	}
+	return nil, fmt.Errorf("script: %s must return None or respond() but returned %s", fn.Name(), v.Type())
+}
+
+// serveError responds the error occurred while running the hook.
+func (m *script) serveError(w http.ResponseWriter, r *http.Request, hook string, err error) {
+	m.eh.ServeHTTPError(w, r, app.ErrAppMiddleScript.WithStack(err, map[string]any{"hook": hook}))
+}
+
+// wrappedWriter wraps http.ResponseWriter
+// and runs the on_response hook before the status code is written.
+type wrappedWriter struct {
+	http.ResponseWriter
+	m   *script
+	p   *program
+	req *request
+	r   *http.Request
+
+	// written is true when the status code was written.
+	written bool
+	// discard is true when the response was written by this writer
+	// and the writes of the next handler are discarded.
+	discard bool
+}
+
+// Unwrap returns internal ResponseWriter.
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *wrappedWriter) WriteHeader(statusCode int) {
+	if w.written {
+		return
+	}
+	if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(statusCode) // Informational responses.
+		return
+	}
+	w.written = true
+
+	res := &response{status: statusCode, headers: newHeaders(w.Header())}
+	v, err := w.m.call(w.r.Context(), w.p.onResponse, w.req, res)
+	if err != nil {
+		w.discard = true
+		clear(w.Header())
+		w.m.serveError(w.ResponseWriter, w.r, onResponse, err)
+		return
+	}
+	if ir, ok := v.(*immediateResponse); ok {
+		w.discard = true
+		clear(w.Header())
+		ir.write(w.ResponseWriter)
+		return
+	}
+	w.ResponseWriter.WriteHeader(res.status)
+}
+
+func (w *wrappedWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the inner writer
+// unless the response was written by this writer.
+func (w *wrappedWriter) Flush() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.discard {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// finish runs the on_response hook
+// if the next handler did not write anything.
+func (w *wrappedWriter) finish() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+}
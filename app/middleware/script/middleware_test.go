@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func newTestScript(t *testing.T, spec *v1.ScriptMiddlewareSpec) *script {
+	t.Helper()
+	got, err := Resource.Create(api.NewContainerAPI(), &v1.ScriptMiddleware{Metadata: &k.Metadata{}, Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got.(*script)
+}
+
+// testHandler echoes the request.
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Path", r.URL.Path)
+	w.Header().Set("X-Query", r.URL.RawQuery)
+	w.Header().Set("X-Added", strings.Join(r.Header.Values("X-Added"), ","))
+	w.Header().Set("X-Var", r.Header.Get("X-Var"))
+	w.Header().Set("X-Sub", r.Header.Get("X-Sub"))
+	w.Header().Set("X-Remove", r.Header.Get("X-Remove"))
+	w.Header().Set("X-Script", r.Header.Get("X-Script"))
+	_, _ = w.Write([]byte("ok"))
+})
+
+func TestMiddleware(t *testing.T) {
+	type condition struct {
+		spec   *v1.ScriptMiddlewareSpec
+		path   string
+		claims any
+	}
+
+	type action struct {
+		status int
+		header map[string]string
+		body   string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"modify request",
+			&condition{
+				spec:   &v1.ScriptMiddlewareSpec{Vars: map[string]string{"name": "value"}},
+				path:   "/test?q=foo",
+				claims: map[string]any{"sub": "alice"},
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{
+					"X-Path":   "/test",
+					"X-Query":  "added=true&q=FOO",
+					"X-Added":  "1,2",
+					"X-Var":    "value",
+					"X-Sub":    "alice",
+					"X-Remove": "",
+					"X-Status": "200",
+					"X-Host":   "test.com",
+				},
+				body: "ok",
+			},
+		),
+		gen(
+			"rewrite path",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/rewrite",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Path": "/rewritten", "X-Query": "", "X-Sub": ""},
+				body:   "ok",
+			},
+		),
+		gen(
+			"respond from on_request",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/deny",
+			},
+			&action{
+				status: http.StatusForbidden,
+				header: map[string]string{"X-Script": "deny", "X-Path": "", "X-Status": ""},
+				body:   "denied",
+			},
+		),
+		gen(
+			"respond from on_response",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/replace",
+			},
+			&action{
+				status: http.StatusCreated,
+				header: map[string]string{"X-Path": "", "Content-Length": "8"},
+				body:   "replaced",
+			},
+		),
+		gen(
+			"change status",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/teapot",
+			},
+			&action{
+				status: http.StatusTeapot,
+				header: map[string]string{"X-Path": "/teapot", "X-Status": "200"},
+				body:   "ok",
+			},
+		),
+		gen(
+			"error in on_request",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/error",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				header: map[string]string{"X-Path": ""},
+			},
+		),
+		gen(
+			"error in on_response",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/response-error",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				header: map[string]string{"X-Path": "", "X-Status": ""},
+			},
+		),
+		gen(
+			"invalid return value",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{},
+				path: "/invalid-return",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+			},
+		),
+		gen(
+			"max steps exceeded",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{MaxSteps: 1000},
+				path: "/loop",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+			},
+		),
+		gen(
+			"timeout",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{MaxSteps: 1 << 62, Timeout: 10},
+				path: "/loop",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+			},
+		),
+		gen(
+			"request hook only",
+			&condition{
+				spec: &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "request-only.star"},
+				path: "/test",
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Script": "request-only", "X-Status": ""},
+				body:   "ok",
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			if tt.C.spec.ScriptFile == "" {
+				tt.C.spec.ScriptFile = testDataDir + "hooks.star"
+			}
+			tt.C.spec.ClaimsKey = "AuthnClaims"
+			s := newTestScript(t, tt.C.spec)
+			h := s.Middleware(testHandler)
+
+			r := httptest.NewRequest(http.MethodGet, "http://test.com"+tt.C.path, nil)
+			r.Header.Set("X-Remove", "remove")
+			if tt.C.claims != nil {
+				r = r.WithContext(context.WithValue(r.Context(), s.claimsKey, tt.C.claims))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			for name, value := range tt.A.header {
+				testutil.Diff(t, value, w.Header().Get(name))
+			}
+			if tt.A.body != "" {
+				testutil.Diff(t, tt.A.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddleware_noWrite(t *testing.T) {
+	s := newTestScript(t, &v1.ScriptMiddlewareSpec{ScriptFile: testDataDir + "hooks.star"})
+	h := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusOK, w.Code)
+	testutil.Diff(t, "200", w.Header().Get("X-Status"))
+	testutil.Diff(t, true, w.Flushed)
+}
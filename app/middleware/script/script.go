@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const (
+	onRequest  = "on_request"
+	onResponse = "on_response"
+)
+
+const (
+	// loadMaxSteps and loadTimeout limit the execution
+	// of the top level statements of scripts.
+	loadMaxSteps = 10_000_000
+	loadTimeout  = 5 * time.Second
+)
+
+var errNoHooks = errors.New("script: neither " + onRequest + " nor " + onResponse + " is defined")
+
+// fileStamp is the state of a file used to detect modification.
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// stampOf returns the current state of the file.
+// Zero stamp is returned when the file cannot be stat.
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
+
+// program is a loaded script.
+type program struct {
+	// onRequest and onResponse are the hook functions.
+	// They are nil when not defined in the script.
+	onRequest  *starlark.Function
+	onResponse *starlark.Function
+	// stamp is the state of the file when loaded.
+	stamp fileStamp
+}
+
+// loader loads the script from the file
+// and reloads it when the file was modified.
+// The file is checked on requests at most once in the interval
+// so that no background goroutine is required.
+// The currently loaded script is kept when failed to reload
+// and reloading is retried after the interval.
+type loader struct {
+	lg          log.Logger
+	path        string
+	predeclared starlark.StringDict
+
+	// interval is the minimum interval to check the file.
+	// Reloading is disabled when zero.
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	next    time.Time
+	current atomic.Pointer[program]
+}
+
+// newLoader returns a new loader with initially loaded script.
+func newLoader(lg log.Logger, path string, predeclared starlark.StringDict, interval time.Duration) (*loader, error) {
+	l := &loader{
+		lg:          lg,
+		path:        path,
+		predeclared: predeclared,
+		interval:    interval,
+		now:         time.Now,
+	}
+	p, err := l.load()
+	if err != nil {
+		return nil, err // Return err as-is.
+	}
+	l.current.Store(p)
+	l.next = l.now().Add(interval)
+	return l, nil
+}
+
+// load loads the script from the file.
+func (l *loader) load() (*program, error) {
+	// Take the stamp before reading the file so that
+	// the file modified while loading is reloaded next time.
+	stamp := stampOf(l.path)
+	src, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &starlark.Thread{Name: "load " + l.path}
+	thread.SetMaxExecutionSteps(loadMaxSteps)
+	timer := time.AfterFunc(loadTimeout, func() { thread.Cancel("script load timeout") })
+	defer timer.Stop()
+
+	// Allow while loops and sets.
+	// Infinite loops are stopped by the step limit and the timeout.
+	opts := &syntax.FileOptions{Set: true, While: true}
+	globals, err := starlark.ExecFileOptions(opts, thread, l.path, src, l.predeclared)
+	if err != nil {
+		return nil, err
+	}
+	// Hooks are called concurrently by requests.
+	// Starlark values are not thread-safe
+	// so the globals must not be modified by the hooks.
+	globals.Freeze()
+
+	p := &program{stamp: stamp}
+	if p.onRequest, err = hook(globals, onRequest, 1); err != nil {
+		return nil, err
+	}
+	if p.onResponse, err = hook(globals, onResponse, 2); err != nil {
+		return nil, err
+	}
+	if p.onRequest == nil && p.onResponse == nil {
+		return nil, errNoHooks
+	}
+	return p, nil
+}
+
+// hook returns the hook function of the name defined in the globals.
+// It returns nil when not defined.
+func hook(globals starlark.StringDict, name string, params int) (*starlark.Function, error) {
+	v, ok := globals[name]
+	if !ok {
+		return nil, nil
+	}
+	fn, ok := v.(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("script: %s must be a function but got %s", name, v.Type())
+	}
+	if fn.NumParams() != params {
+		return nil, fmt.Errorf("script: %s must take %d parameters but takes %d", name, params, fn.NumParams())
+	}
+	return fn, nil
+}
+
+// get returns the currently loaded script.
+// The script is reloaded if the file was modified after loaded.
+func (l *loader) get(ctx context.Context) *program {
+	current := l.current.Load()
+	if l.interval <= 0 {
+		return current
+	}
+	if !l.mu.TryLock() {
+		return current // Other request is checking the file.
+	}
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.next) {
+		return current
+	}
+	l.next = now.Add(l.interval)
+
+	if current.stamp == stampOf(l.path) {
+		return current
+	}
+	p, err := l.load()
+	if err != nil {
+		// The file may be being rewritten. Retry next time.
+		l.lg.Error(ctx, "failed to reload script. continue with the current script.", "path", l.path, "error", err.Error())
+		return current
+	}
+	l.lg.Info(ctx, "script reloaded.", "path", l.path)
+	l.current.Store(p)
+	return p
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"go.starlark.net/starlark"
+)
+
+func TestLoader_get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.star")
+	write := func(src string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("def on_request(req):\n    pass\n")
+	lg := log.GlobalLogger(log.DefaultLoggerName)
+	l, err := newLoader(lg, path, predeclared(lg, nil), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	p1 := l.get(ctx)
+	testutil.Diff(t, true, p1.onRequest != nil)
+	testutil.Diff(t, true, p1.onResponse == nil)
+
+	// Not reloaded within the interval.
+	write("def on_response(req, res):\n    pass\n")
+	testutil.Diff(t, true, l.get(ctx) == p1)
+
+	// Reloaded after the interval.
+	now = now.Add(2 * time.Second)
+	p2 := l.get(ctx)
+	testutil.Diff(t, true, p2 != p1)
+	testutil.Diff(t, true, p2.onRequest == nil)
+	testutil.Diff(t, true, p2.onResponse != nil)
+
+	// Not reloaded when not modified.
+	now = now.Add(2 * time.Second)
+	testutil.Diff(t, true, l.get(ctx) == p2)
+
+	// Current script is kept when failed to reload.
+	write("invalid script\n")
+	now = now.Add(2 * time.Second)
+	testutil.Diff(t, true, l.get(ctx) == p2)
+
+	// Reloading is disabled.
+	write("def on_request(req):\n    return None\n")
+	now = now.Add(2 * time.Second)
+	l.interval = 0
+	testutil.Diff(t, true, l.get(ctx) == p2)
+}
+
+func TestLoader_frozenGlobals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.star")
+	src := "seen = {}\ndef on_request(req):\n    seen[req] = 1\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lg := log.GlobalLogger(log.DefaultLoggerName)
+	l, err := newLoader(lg, path, predeclared(lg, nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := l.get(context.Background())
+
+	// Hooks are called concurrently.
+	// Modifying globals must fail rather than race.
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			thread := &starlark.Thread{Name: "test"}
+			_, errs[i] = starlark.Call(thread, p.onRequest, starlark.Tuple{starlark.MakeInt(i)}, nil)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		testutil.Diff(t, true, err != nil && strings.Contains(err.Error(), "frozen"))
+	}
+}
@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+// valuesMap exposes http.Header or url.Values to scripts.
+// It implements starlark.HasAttrs interface.
+type valuesMap struct {
+	typ string
+	m   map[string][]string
+	// canonical converts keys into canonical form.
+	// Nil for case-sensitive keys.
+	canonical func(string) string
+	frozen    bool
+	// modified is true when the values were modified.
+	modified bool
+}
+
+func newHeaders(h http.Header) *valuesMap {
+	return &valuesMap{typ: "headers", m: h, canonical: textproto.CanonicalMIMEHeaderKey}
+}
+
+func newQuery(q map[string][]string) *valuesMap {
+	return &valuesMap{typ: "query", m: q}
+}
+
+func (v *valuesMap) String() string        { return v.typ }
+func (v *valuesMap) Type() string          { return v.typ }
+func (v *valuesMap) Freeze()               { v.frozen = true }
+func (v *valuesMap) Truth() starlark.Bool  { return len(v.m) > 0 }
+func (v *valuesMap) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: %s", v.typ) }
+func (v *valuesMap) AttrNames() []string   { return slices.Sorted(maps.Keys(valuesMapMethods)) }
+
+func (v *valuesMap) Attr(name string) (starlark.Value, error) {
+	m, ok := valuesMapMethods[name]
+	if !ok {
+		return nil, nil
+	}
+	return starlark.NewBuiltin(v.typ+"."+name, m).BindReceiver(v), nil
+}
+
+func (v *valuesMap) key(name string) string {
+	if v.canonical != nil {
+		return v.canonical(name)
+	}
+	return name
+}
+
+// mutate checks if the values can be modified
+// and marks the values as modified.
+func (v *valuesMap) mutate() error {
+	if v.frozen {
+		return fmt.Errorf("cannot modify frozen %s", v.typ)
+	}
+	v.modified = true
+	return nil
+}
+
+var valuesMapMethods = map[string]func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error){
+	// get(name) returns the first value or None.
+	"get": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		var name string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+		if vs := v.m[v.key(name)]; len(vs) > 0 {
+			return starlark.String(vs[0]), nil
+		}
+		return starlark.None, nil
+	},
+	// values(name) returns the list of all values.
+	"values": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		var name string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+		return stringList(v.m[v.key(name)]), nil
+	},
+	// keys() returns the sorted list of names.
+	"keys": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+			return nil, err
+		}
+		return stringList(slices.Sorted(maps.Keys(v.m))), nil
+	},
+	// set(name, value) replaces the values.
+	"set": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		var name, value string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &name, &value); err != nil {
+			return nil, err
+		}
+		if err := v.mutate(); err != nil {
+			return nil, err
+		}
+		v.m[v.key(name)] = []string{value}
+		return starlark.None, nil
+	},
+	// add(name, value) appends the value.
+	"add": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		var name, value string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &name, &value); err != nil {
+			return nil, err
+		}
+		if err := v.mutate(); err != nil {
+			return nil, err
+		}
+		key := v.key(name)
+		v.m[key] = append(v.m[key], value)
+		return starlark.None, nil
+	},
+	// delete(name) deletes the values.
+	"delete": func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		v := b.Receiver().(*valuesMap)
+		var name string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+		if err := v.mutate(); err != nil {
+			return nil, err
+		}
+		delete(v.m, v.key(name))
+		return starlark.None, nil
+	},
+}
+
+// request exposes http.Request to scripts.
+// It implements starlark.HasAttrs and starlark.HasSetField interfaces.
+type request struct {
+	r       *http.Request
+	headers *valuesMap
+	query   *valuesMap
+	// claims is the claims obtained from the request context.
+	// It is converted into starlark value when first accessed.
+	claims  any
+	sclaims starlark.Value
+	frozen  bool
+}
+
+func newRequest(r *http.Request, claims any) *request {
+	return &request{
+		r:       r,
+		headers: newHeaders(r.Header),
+		query:   newQuery(r.URL.Query()),
+		claims:  claims,
+	}
+}
+
+func (r *request) String() string        { return "request" }
+func (r *request) Type() string          { return "request" }
+func (r *request) Truth() starlark.Bool  { return true }
+func (r *request) Hash() (uint32, error) { return 0, errors.New("unhashable type: request") }
+
+func (r *request) Freeze() {
+	r.frozen = true
+	r.headers.Freeze()
+	r.query.Freeze()
+}
+
+func (r *request) AttrNames() []string {
+	return []string{"claims", "headers", "host", "method", "path", "proto", "query", "remote_addr"}
+}
+
+func (r *request) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "method":
+		return starlark.String(r.r.Method), nil
+	case "path":
+		return starlark.String(r.r.URL.Path), nil
+	case "host":
+		return starlark.String(r.r.Host), nil
+	case "proto":
+		return starlark.String(r.r.Proto), nil
+	case "remote_addr":
+		return starlark.String(r.r.RemoteAddr), nil
+	case "headers":
+		return r.headers, nil
+	case "query":
+		return r.query, nil
+	case "claims":
+		return r.claimsValue()
+	}
+	return nil, nil
+}
+
+func (r *request) SetField(name string, val starlark.Value) error {
+	if r.frozen {
+		return errors.New("cannot modify frozen request")
+	}
+	switch name {
+	case "path":
+		path, ok := starlark.AsString(val)
+		if !ok || len(path) == 0 || path[0] != '/' {
+			return fmt.Errorf("request.path must be a string starting with '/' but got %s", val.String())
+		}
+		r.r.URL.Path = path
+		r.r.URL.RawPath = ""
+		return nil
+	}
+	return starlark.NoSuchAttrError(fmt.Sprintf("request has no settable field .%s", name))
+}
+
+// claimsValue returns the claims converted into starlark value
+// through JSON encoding. None is returned when no claims found.
+func (r *request) claimsValue() (starlark.Value, error) {
+	if r.sclaims != nil {
+		return r.sclaims, nil
+	}
+	if r.claims == nil {
+		r.sclaims = starlark.None
+		return r.sclaims, nil
+	}
+	b, err := json.Marshal(r.claims)
+	if err != nil {
+		return nil, err
+	}
+	decode := starlarkjson.Module.Members["decode"].(*starlark.Builtin)
+	v, err := starlark.Call(&starlark.Thread{}, decode, starlark.Tuple{starlark.String(b)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.sclaims = v
+	return v, nil
+}
+
+// apply applies the modification of the query to the request.
+func (r *request) apply() {
+	if r.query.modified {
+		r.r.URL.RawQuery = url.Values(r.query.m).Encode()
+	}
+}
+
+// response exposes the response status and headers to scripts.
+// It implements starlark.HasAttrs and starlark.HasSetField interfaces.
+type response struct {
+	status  int
+	headers *valuesMap
+	frozen  bool
+}
+
+func (r *response) String() string        { return "response" }
+func (r *response) Type() string          { return "response" }
+func (r *response) Truth() starlark.Bool  { return true }
+func (r *response) Hash() (uint32, error) { return 0, errors.New("unhashable type: response") }
+func (r *response) AttrNames() []string   { return []string{"headers", "status"} }
+
+func (r *response) Freeze() {
+	r.frozen = true
+	r.headers.Freeze()
+}
+
+func (r *response) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "status":
+		return starlark.MakeInt(r.status), nil
+	case "headers":
+		return r.headers, nil
+	}
+	return nil, nil
+}
+
+func (r *response) SetField(name string, val starlark.Value) error {
+	if r.frozen {
+		return errors.New("cannot modify frozen response")
+	}
+	switch name {
+	case "status":
+		status, err := statusCode(val)
+		if err != nil {
+			return err
+		}
+		r.status = status
+		return nil
+	}
+	return starlark.NoSuchAttrError(fmt.Sprintf("response has no settable field .%s", name))
+}
+
+// immediateResponse is the response returned from hooks
+// to respond without calling upstream handlers.
+// This is created by the respond builtin function.
+type immediateResponse struct {
+	status int
+	body   string
+	// headers are the pairs of header names and values.
+	headers [][2]string
+}
+
+func (r *immediateResponse) String() string        { return fmt.Sprintf("immediate_response(%d)", r.status) }
+func (r *immediateResponse) Type() string          { return "immediate_response" }
+func (r *immediateResponse) Freeze()               {}
+func (r *immediateResponse) Truth() starlark.Bool  { return true }
+func (r *immediateResponse) Hash() (uint32, error) { return 0, errors.New("unhashable type: immediate_response") }
+
+// write writes the response.
+func (r *immediateResponse) write(w http.ResponseWriter) {
+	for _, h := range r.headers {
+		w.Header().Add(h[0], h[1])
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(r.body)))
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte(r.body))
+}
+
+// respond is the builtin function
+//
+//	respond(status, body="", headers={})
+//
+// that returns an immediate response.
+func respond(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var status starlark.Value
+	var body string
+	var headers *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "status", &status, "body?", &body, "headers?", &headers); err != nil {
+		return nil, err
+	}
+	code, err := statusCode(status)
+	if err != nil {
+		return nil, err
+	}
+	ir := &immediateResponse{status: code, body: body}
+	if headers != nil {
+		for _, item := range headers.Items() {
+			name, ok1 := starlark.AsString(item[0])
+			value, ok2 := starlark.AsString(item[1])
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("%s: headers must be a dict of strings", b.Name())
+			}
+			ir.headers = append(ir.headers, [2]string{name, value})
+		}
+	}
+	return ir, nil
+}
+
+// statusCode returns the HTTP status code of the value.
+func statusCode(v starlark.Value) (int, error) {
+	var code int
+	if err := starlark.AsInt(v, &code); err != nil || code < 200 || code > 999 {
+		return 0, fmt.Errorf("status must be an integer between 200 and 999 but got %s", v.String())
+	}
+	return code, nil
+}
+
+func stringList(ss []string) *starlark.List {
+	vs := make([]starlark.Value, len(ss))
+	for i, s := range ss {
+		vs[i] = starlark.String(s)
+	}
+	return starlark.NewList(vs)
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package script
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"go.starlark.net/starlark"
+)
+
+func TestBuiltins(t *testing.T) {
+	type condition struct {
+		src    string
+		claims any
+	}
+
+	type action struct {
+		result     string
+		errPattern *regexp.Regexp
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"headers",
+			&condition{src: `
+req.headers.set("x-a", "1")
+req.headers.add("X-A", "2")
+req.headers.add("X-B", "3")
+req.headers.delete("x-b")
+result = str([req.headers.get("X-A"), req.headers.values("x-a"), req.headers.keys(), req.headers.get("X-B"), bool(req.headers)])
+`},
+			&action{result: `["1", ["1", "2"], ["X-A"], None, True]`},
+		),
+		gen(
+			"query is case sensitive",
+			&condition{src: `
+req.query.set("A", "1")
+result = str([req.query.get("a"), req.query.get("A"), req.query.keys(), dir(req.query)])
+`},
+			&action{result: `[None, "1", ["A", "foo"], ["add", "delete", "get", "keys", "set", "values"]]`},
+		),
+		gen(
+			"request attributes",
+			&condition{src: `
+result = str([req.method, req.path, req.host, req.proto, req.remote_addr, req.claims, dir(req), type(req), type(req.headers)])
+`},
+			&action{result: `["GET", "/test", "test.com", "HTTP/1.1", "192.0.2.1:1234", None, ["claims", "headers", "host", "method", "path", "proto", "query", "remote_addr"], "request", "headers"]`},
+		),
+		gen(
+			"claims",
+			&condition{
+				src:    `result = str(req.claims["roles"])`,
+				claims: map[string]any{"roles": []string{"admin"}},
+			},
+			&action{result: `["admin"]`},
+		),
+		gen(
+			"invalid claims",
+			&condition{
+				src:    `result = req.claims`,
+				claims: func() {},
+			},
+			&action{errPattern: regexp.MustCompile(`unsupported type: func\(\)`)},
+		),
+		gen(
+			"invalid path",
+			&condition{src: `req.path = "test"`},
+			&action{errPattern: regexp.MustCompile(`request.path must be a string starting with '/'`)},
+		),
+		gen(
+			"not settable field",
+			&condition{src: `req.method = "POST"`},
+			&action{errPattern: regexp.MustCompile(`request has no settable field .method`)},
+		),
+		gen(
+			"respond",
+			&condition{src: `result = str(respond(404, body="not found", headers={"X-A": "a"}))`},
+			&action{result: `immediate_response(404)`},
+		),
+		gen(
+			"respond invalid status",
+			&condition{src: `respond(99)`},
+			&action{errPattern: regexp.MustCompile(`status must be an integer between 200 and 999`)},
+		),
+		gen(
+			"respond invalid headers",
+			&condition{src: `respond(200, headers={"X-A": 1})`},
+			&action{errPattern: regexp.MustCompile(`respond: headers must be a dict of strings`)},
+		),
+		gen(
+			"log",
+			&condition{src: `log.debug("debug", key=1); log.info("info"); log.warn("warn", key="value"); log.error("error")`},
+			&action{},
+		),
+		gen(
+			"log without message",
+			&condition{src: `log.info()`},
+			&action{errPattern: regexp.MustCompile(`log.info: got 0 arguments, want 1`)},
+		),
+		gen(
+			"vars are frozen",
+			&condition{src: `vars["name"] = "new"`},
+			&action{errPattern: regexp.MustCompile(`cannot insert into frozen hash table`)},
+		),
+		gen(
+			"headers method args",
+			&condition{src: `req.headers.get()`},
+			&action{errPattern: regexp.MustCompile(`headers.get: got 0 arguments, want 1`)},
+		),
+	}
+
+	lg := log.GlobalLogger(log.DefaultLoggerName)
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://test.com/test?foo=bar", nil)
+			globals := predeclared(lg, map[string]string{"name": "value"})
+			globals["req"] = newRequest(r, tt.C.claims)
+			thread := &starlark.Thread{}
+			got, err := starlark.ExecFile(thread, "test.star", tt.C.src, globals)
+			if tt.A.errPattern != nil {
+				if err == nil || !tt.A.errPattern.MatchString(err.Error()) {
+					t.Errorf("error mismatch: want %s got %v", tt.A.errPattern, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.A.result != "" {
+				result, _ := starlark.AsString(got["result"])
+				testutil.Diff(t, tt.A.result, result)
+			}
+		})
+	}
+}
+
+func TestFrozen(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/test", nil)
+	req := newRequest(r, nil)
+	res := &response{status: 200, headers: newHeaders(http.Header{})}
+	req.Freeze()
+	res.Freeze()
+
+	testutil.Diff(t, "cannot modify frozen request", req.SetField("path", starlark.String("/")).Error())
+	testutil.Diff(t, "cannot modify frozen response", res.SetField("status", starlark.MakeInt(200)).Error())
+	set, _ := req.headers.Attr("set")
+	_, err := starlark.Call(&starlark.Thread{}, set, starlark.Tuple{starlark.String("a"), starlark.String("b")}, nil)
+	testutil.Diff(t, "cannot modify frozen headers", err.Error())
+}
@@ -7,6 +7,7 @@ import (
 	"cmp"
 	"encoding/json"
 	"encoding/xml"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -24,7 +25,9 @@ const (
 )
 
 var Resource api.Resource = &API{
-	BaseResource: &api.BaseResource{},
+	BaseResource: &api.BaseResource{
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*soapREST]()},
+	},
 }
 
 type API struct {
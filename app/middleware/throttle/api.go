@@ -5,6 +5,7 @@ package throttle
 
 import (
 	"cmp"
+	"reflect"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -35,6 +36,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.ThrottleMiddlewareSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*throttle]()},
 	},
 }
 
@@ -5,6 +5,7 @@ package timeout
 
 import (
 	"cmp"
+	"reflect"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.TimeoutMiddlewareSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*timeout]()},
 	},
 }
 
@@ -6,6 +6,7 @@ package tracking
 import (
 	"cmp"
 	"net/textproto"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -31,6 +32,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.TrackingMiddlewareSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*tracker]()},
 	},
 }
 
@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	wapi "github.com/tetratelabs/wazero/api"
+)
+
+// hostModuleName is the name of the module
+// that provides host functions to plugins.
+const hostModuleName = "aileron"
+
+// Kinds of headers and bodies.
+const (
+	kindRequest  = 0
+	kindResponse = 1
+)
+
+// Fields of requests read by the request_get.
+const (
+	fieldMethod     = 0
+	fieldPath       = 1
+	fieldQuery      = 2
+	fieldHost       = 3
+	fieldRemoteAddr = 4
+	fieldProto      = 5
+)
+
+// Negative values returned by host functions.
+const (
+	// resultNotFound means the value was not found.
+	resultNotFound = -1
+	// resultTooLarge means the body exceeds the max body size.
+	resultTooLarge = -2
+)
+
+var errOutOfRange = errors.New("wasm: memory access out of range")
+
+// stateKey is the context key of the state.
+type stateKey struct{}
+
+// withState returns a new context with the state.
+func withState(ctx context.Context, s *state) context.Context {
+	return context.WithValue(ctx, stateKey{}, s)
+}
+
+// state is the state of a call of a plugin function.
+// Host functions read and modify the request and response through it.
+type state struct {
+	pool *pool
+
+	// r is the request.
+	// It is nil while calling aileron_on_configure.
+	r *http.Request
+	// header is the response header.
+	header http.Header
+	// maxBodySize is the maximum body size that plugins can read.
+	maxBodySize int64
+
+	// reqBody is the request body read by the plugin.
+	reqBody []byte
+	// reqBodyRead is true once the request body was read.
+	reqBodyRead bool
+	// reqBodyTooLarge is true when the request body
+	// is larger than the max body size.
+	reqBodyTooLarge bool
+
+	// status is the response status code.
+	status int
+	// body is the response body.
+	body []byte
+}
+
+// readRequestBody reads the request body up to the max body size.
+// Read body is restored so that the next handlers can read it again.
+func (s *state) readRequestBody() ([]byte, bool) {
+	if s.reqBodyRead {
+		return s.reqBody, !s.reqBodyTooLarge
+	}
+	s.reqBodyRead = true
+	if s.r.Body == nil || s.r.Body == http.NoBody {
+		return nil, true
+	}
+	b, err := io.ReadAll(io.LimitReader(s.r.Body, s.maxBodySize+1))
+	if err != nil {
+		panic(err) // Abort the function call.
+	}
+	if int64(len(b)) > s.maxBodySize {
+		s.reqBodyTooLarge = true
+		s.r.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(b), s.r.Body), Closer: s.r.Body}
+		return nil, false
+	}
+	s.reqBody = b
+	s.r.Body = io.NopCloser(bytes.NewReader(b))
+	return b, true
+}
+
+// setRequestBody replaces the request body.
+func (s *state) setRequestBody(b []byte) {
+	s.reqBodyRead, s.reqBodyTooLarge = true, false
+	s.reqBody = b
+	s.r.Body = io.NopCloser(bytes.NewReader(b))
+	s.r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	s.r.ContentLength = int64(len(b))
+	s.r.TransferEncoding = nil
+	s.r.Header.Del("Transfer-Encoding")
+	if s.r.Header.Get("Content-Length") != "" {
+		s.r.Header.Set("Content-Length", strconv.Itoa(len(b)))
+	}
+}
+
+// headers returns the headers of the kind.
+// It returns nil when the headers are not available.
+func (s *state) headers(kind int32) http.Header {
+	switch kind {
+	case kindRequest:
+		if s.r != nil {
+			return s.r.Header
+		}
+	case kindResponse:
+		return s.header
+	}
+	return nil
+}
+
+// readCloser combines a reader and a closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// read reads the memory of the module.
+// The returned bytes are copied from the memory.
+func read(m wapi.Module, ptr, size uint32) []byte {
+	b, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return bytes.Clone(b)
+}
+
+// write writes the value to the buffer in the memory of the module
+// and returns the length of the value.
+// The value is not written when the buffer is smaller than the value
+// so that plugins can retry with a buffer of the returned length.
+func write(m wapi.Module, ptr, size uint32, value []byte) int32 {
+	if len(value) > int(size) {
+		return int32(len(value))
+	}
+	if !m.Memory().Write(ptr, value) {
+		panic(errOutOfRange)
+	}
+	return int32(len(value))
+}
+
+// hostModule returns the builder of the host module.
+// Plugins import the host functions from the "aileron" module.
+// All pointers and lengths are i32.
+// Functions that return values write them to the given buffer
+// and return the length of the values.
+// Values are not written when the buffer is too small.
+// Plugins should retry with a buffer of the returned length in that case.
+//
+//	config_get(buf, buf_len) -> len
+//	log(level, msg, msg_len)
+//	request_get(field, buf, buf_len) -> len
+//	header_get(kind, name, name_len, buf, buf_len) -> len
+//	header_names(kind, buf, buf_len) -> len
+//	header_set(kind, name, name_len, value, value_len)
+//	header_add(kind, name, name_len, value, value_len)
+//	header_del(kind, name, name_len)
+//	body_get(kind, buf, buf_len) -> len
+//	body_set(kind, body, body_len)
+//	status_get() -> status
+//	status_set(status)
+//
+// See docs/app/middleware/wasm.md for details.
+func hostModule(rt wazero.Runtime) wazero.HostModuleBuilder {
+	b := rt.NewHostModuleBuilder(hostModuleName)
+	b.NewFunctionBuilder().WithFunc(configGet).Export("config_get")
+	b.NewFunctionBuilder().WithFunc(logMessage).Export("log")
+	b.NewFunctionBuilder().WithFunc(requestGet).Export("request_get")
+	b.NewFunctionBuilder().WithFunc(headerGet).Export("header_get")
+	b.NewFunctionBuilder().WithFunc(headerNames).Export("header_names")
+	b.NewFunctionBuilder().WithFunc(headerSet).Export("header_set")
+	b.NewFunctionBuilder().WithFunc(headerAdd).Export("header_add")
+	b.NewFunctionBuilder().WithFunc(headerDel).Export("header_del")
+	b.NewFunctionBuilder().WithFunc(bodyGet).Export("body_get")
+	b.NewFunctionBuilder().WithFunc(bodySet).Export("body_set")
+	b.NewFunctionBuilder().WithFunc(statusGet).Export("status_get")
+	b.NewFunctionBuilder().WithFunc(statusSet).Export("status_set")
+	return b
+}
+
+func stateFrom(ctx context.Context) *state {
+	return ctx.Value(stateKey{}).(*state)
+}
+
+func configGet(ctx context.Context, m wapi.Module, buf, bufLen uint32) int32 {
+	return write(m, buf, bufLen, stateFrom(ctx).pool.config)
+}
+
+func logMessage(ctx context.Context, m wapi.Module, level int32, msg, msgLen uint32) {
+	lg := stateFrom(ctx).pool.lg
+	text := string(read(m, msg, msgLen))
+	switch level {
+	case 0:
+		lg.Debug(ctx, text)
+	case 1:
+		lg.Info(ctx, text)
+	case 2:
+		lg.Warn(ctx, text)
+	default:
+		lg.Error(ctx, text)
+	}
+}
+
+func requestGet(ctx context.Context, m wapi.Module, field int32, buf, bufLen uint32) int32 {
+	r := stateFrom(ctx).r
+	if r == nil {
+		return resultNotFound
+	}
+	var v string
+	switch field {
+	case fieldMethod:
+		v = r.Method
+	case fieldPath:
+		v = r.URL.Path
+	case fieldQuery:
+		v = r.URL.RawQuery
+	case fieldHost:
+		v = r.Host
+	case fieldRemoteAddr:
+		v = r.RemoteAddr
+	case fieldProto:
+		v = r.Proto
+	default:
+		return resultNotFound
+	}
+	return write(m, buf, bufLen, []byte(v))
+}
+
+func headerGet(ctx context.Context, m wapi.Module, kind int32, name, nameLen, buf, bufLen uint32) int32 {
+	h := stateFrom(ctx).headers(kind)
+	values := h.Values(string(read(m, name, nameLen)))
+	if len(values) == 0 {
+		return resultNotFound
+	}
+	return write(m, buf, bufLen, []byte(strings.Join(values, ", ")))
+}
+
+func headerNames(ctx context.Context, m wapi.Module, kind int32, buf, bufLen uint32) int32 {
+	h := stateFrom(ctx).headers(kind)
+	if h == nil {
+		return resultNotFound
+	}
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	return write(m, buf, bufLen, []byte(strings.Join(names, "\n")))
+}
+
+func headerSet(ctx context.Context, m wapi.Module, kind int32, name, nameLen, value, valueLen uint32) {
+	if h := stateFrom(ctx).headers(kind); h != nil {
+		h.Set(string(read(m, name, nameLen)), string(read(m, value, valueLen)))
+	}
+}
+
+func headerAdd(ctx context.Context, m wapi.Module, kind int32, name, nameLen, value, valueLen uint32) {
+	if h := stateFrom(ctx).headers(kind); h != nil {
+		h.Add(string(read(m, name, nameLen)), string(read(m, value, valueLen)))
+	}
+}
+
+func headerDel(ctx context.Context, m wapi.Module, kind int32, name, nameLen uint32) {
+	if h := stateFrom(ctx).headers(kind); h != nil {
+		h.Del(string(read(m, name, nameLen)))
+	}
+}
+
+func bodyGet(ctx context.Context, m wapi.Module, kind int32, buf, bufLen uint32) int32 {
+	s := stateFrom(ctx)
+	switch {
+	case kind == kindRequest && s.r != nil:
+		b, ok := s.readRequestBody()
+		if !ok {
+			return resultTooLarge
+		}
+		return write(m, buf, bufLen, b)
+	case kind == kindResponse && s.header != nil:
+		return write(m, buf, bufLen, s.body)
+	}
+	return resultNotFound
+}
+
+func bodySet(ctx context.Context, m wapi.Module, kind int32, body, bodyLen uint32) {
+	s := stateFrom(ctx)
+	switch {
+	case kind == kindRequest && s.r != nil:
+		s.setRequestBody(read(m, body, bodyLen))
+	case kind == kindResponse && s.header != nil:
+		s.body = read(m, body, bodyLen)
+	}
+}
+
+func statusGet(ctx context.Context) int32 {
+	return int32(stateFrom(ctx).status)
+}
+
+func statusSet(ctx context.Context, status int32) {
+	if status >= 100 && status <= 999 {
+		stateFrom(ctx).status = int(status)
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"runtime"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "WasmMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+// defaultMaxBodySize is the default maximum body size
+// that plugins can read.
+const defaultMaxBodySize = 4 << 20
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.WasmMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.WasmMiddlewareSpec{
+				MaxBodySize: defaultMaxBodySize,
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*wasm]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.WasmMiddleware)
+	lg := log.DefaultOr(c.Metadata.Logger)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	size := int(c.Spec.PoolSize)
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	p, err := newPool(context.Background(), &poolConfig{
+		path:     c.Spec.WasmPath,
+		config:   []byte(c.Spec.Config),
+		size:     size,
+		maxPages: c.Spec.MaxMemoryPages,
+		lg:       lg,
+	})
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
+	return &wasm{
+		eh:          eh,
+		pool:        p,
+		maxBodySize: cmp.Or(c.Spec.MaxBodySize, defaultMaxBodySize),
+	}, nil
+}
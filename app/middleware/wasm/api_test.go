@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+const testDataDir = "../../../test/ut/app/wasm/"
+
+// testPlugin is the path to the plugin built from
+// the test/ut/app/wasm/plugin/ for tests.
+var testPlugin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "wasm")
+	if err != nil {
+		panic(err)
+	}
+	testPlugin = filepath.Join(dir, "plugin.wasm")
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", testPlugin, ".")
+	cmd.Dir = testDataDir + "plugin/"
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		fmt.Println(string(out))
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		check      func(*testing.T, *wasm)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.wasm")
+	if err := os.WriteFile(empty, []byte("\x00asm\x01\x00\x00\x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with plugin",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.WasmMiddlewareSpec{
+						WasmPath: testPlugin,
+						Config:   "test",
+						PoolSize: 2,
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, w *wasm) {
+					t.Helper()
+					testutil.Diff(t, int64(defaultMaxBodySize), w.maxBodySize)
+					testutil.Diff(t, 2, cap(w.pool.sem))
+					testutil.Diff(t, 1, len(w.pool.idle))
+					testutil.Diff(t, true, w.pool.onRequest)
+					testutil.Diff(t, true, w.pool.onResponse)
+					testutil.Diff(t, "test", string(w.pool.config))
+				},
+			},
+		),
+		gen(
+			"default pool size",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.WasmMiddlewareSpec{
+						WasmPath:       testPlugin,
+						MaxBodySize:    10,
+						MaxMemoryPages: 1000,
+					},
+				},
+			},
+			&action{
+				check: func(t *testing.T, w *wasm) {
+					t.Helper()
+					testutil.Diff(t, int64(10), w.maxBodySize)
+					testutil.Diff(t, runtime.GOMAXPROCS(0), cap(w.pool.sem))
+				},
+			},
+		),
+		gen(
+			"file not found",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.WasmMiddlewareSpec{WasmPath: testDataDir + "not-exist.wasm"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create WasmMiddleware`),
+			},
+		),
+		gen(
+			"invalid module",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.WasmMiddlewareSpec{WasmPath: testDataDir + "plugin/main.go"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create WasmMiddleware`),
+			},
+		),
+		gen(
+			"no handlers exported",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.WasmMiddlewareSpec{WasmPath: empty},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`exports neither aileron_on_request nor aileron_on_response`),
+			},
+		),
+		gen(
+			"configure failed",
+			&condition{
+				manifest: &v1.WasmMiddleware{
+					Metadata: &k.Metadata{},
+					Spec:     &v1.WasmMiddlewareSpec{WasmPath: testPlugin, Config: "fail"},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(`aileron_on_configure returned non-zero value`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			w := got.(*wasm)
+			defer w.Finalize()
+			tt.A.check(t, w)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+)
+
+// Actions returned by aileron_on_request.
+const (
+	// actionContinue passes the request to the next handler.
+	actionContinue = 0
+	// actionRespond responds the response built by the plugin
+	// without calling the next handler.
+	actionRespond = 1
+)
+
+// wasm calls the WebAssembly plugin for requests and responses.
+// This implements core.Middleware and core.Finalizer interface.
+type wasm struct {
+	eh   core.ErrorHandler
+	pool *pool
+
+	// maxBodySize is the maximum body size that plugins can read.
+	maxBodySize int64
+}
+
+func (m *wasm) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.pool.onRequest {
+			s := &state{r: r, header: w.Header(), maxBodySize: m.maxBodySize}
+			action, err := m.pool.call(r.Context(), exportOnRequest, s)
+			if err != nil || (action != actionContinue && action != actionRespond) {
+				if err == nil {
+					err = errorAction(action)
+				}
+				err = app.ErrAppMiddleWasm.WithStack(err, map[string]any{"function": exportOnRequest})
+				m.eh.ServeHTTPError(w, r, err)
+				return
+			}
+			if action == actionRespond {
+				writeResponse(w, s.status, s.body)
+				return
+			}
+		}
+
+		if !m.pool.onResponse {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := &wrappedWriter{ResponseWriter: w, maxBodySize: m.maxBodySize}
+		next.ServeHTTP(ww, r)
+		if ww.streaming {
+			return // Body was too large for the plugin.
+		}
+
+		s := &state{r: r, header: w.Header(), maxBodySize: m.maxBodySize, status: ww.StatusCode(), body: ww.buf.Bytes()}
+		action, err := m.pool.call(r.Context(), exportOnResponse, s)
+		if err != nil || action != actionContinue {
+			if err == nil {
+				err = errorAction(action)
+			}
+			err = app.ErrAppMiddleWasm.WithStack(err, map[string]any{"function": exportOnResponse})
+			clear(w.Header()) // Headers such as Content-Length of the upstream response.
+			m.eh.ServeHTTPError(w, r, err)
+			return
+		}
+		writeResponse(w, s.status, s.body)
+	})
+}
+
+// Finalize closes the runtime of the plugin.
+func (m *wasm) Finalize() error {
+	return m.pool.close()
+}
+
+// errorAction is the error of invalid action returned by plugins.
+type errorAction int32
+
+func (e errorAction) Error() string {
+	return "wasm: plugin returned unknown action " + strconv.Itoa(int(e))
+}
+
+// writeResponse writes the response with the status and the body.
+// Status is 200 when not set.
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// wrappedWriter wraps http.ResponseWriter and buffers
+// the response body so that plugins can read and modify it.
+// When the body exceeds the max body size or the response is flushed,
+// the buffered body and the rest are written to the inner writer.
+type wrappedWriter struct {
+	http.ResponseWriter
+
+	code        int
+	buf         bytes.Buffer
+	maxBodySize int64
+	// streaming is true when the body is written
+	// to the inner writer without buffering.
+	streaming bool
+}
+
+// Unwrap returns internal ResponseWriter.
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *wrappedWriter) WriteHeader(statusCode int) {
+	if w.code > 0 {
+		return
+	}
+	w.code = statusCode
+}
+
+func (w *wrappedWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	if int64(w.buf.Len()+len(b)) <= w.maxBodySize {
+		return w.buf.Write(b)
+	}
+	if err := w.stream(); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush writes the buffered body to the inner writer and flushes it.
+// The rest of the body is streamed without calling the plugin
+// so that responses such as server-sent events are not held back.
+func (w *wrappedWriter) Flush() {
+	if !w.streaming {
+		if err := w.stream(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// stream switches to streaming and writes
+// the status code and the buffered body to the inner writer.
+func (w *wrappedWriter) stream() error {
+	w.streaming = true
+	w.code = w.StatusCode()
+	w.ResponseWriter.WriteHeader(w.code)
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	return err
+}
+
+// StatusCode returns the written status code.
+// It returns 200 when nothing was written.
+func (w *wrappedWriter) StatusCode() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newTestWasm(t *testing.T, spec *v1.WasmMiddlewareSpec) *wasm {
+	t.Helper()
+	spec.WasmPath = testPlugin
+	got, err := Resource.Create(api.NewContainerAPI(), &v1.WasmMiddleware{Metadata: &k.Metadata{}, Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := got.(*wasm)
+	t.Cleanup(func() { _ = w.Finalize() })
+	return w
+}
+
+// testHandler echoes the request headers and body.
+// Status code is given by the X-Status header.
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+	w.Header().Set("X-Request", r.Header.Get("X-Request"))
+	w.Header()["X-Added"] = r.Header.Values("X-Added")
+	w.Header().Set("X-Remove", r.Header.Get("X-Remove"))
+	w.Header().Set("X-Body-Too-Large", r.Header.Get("X-Body-Too-Large"))
+	switch r.Header.Get("X-Status") {
+	case "404":
+		w.WriteHeader(http.StatusNotFound)
+	case "500":
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_, _ = w.Write(b)
+})
+
+func TestMiddleware(t *testing.T) {
+	type condition struct {
+		method string
+		body   string
+		header map[string]string
+	}
+
+	type action struct {
+		status int
+		header map[string]string
+		body   string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"modify request and response",
+			&condition{
+				method: http.MethodPost,
+				body:   "hello",
+				header: map[string]string{"X-Remove": "remove"},
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{
+					"X-Request": "POST /test?foo=bar",
+					"X-Added":   "1",
+					"X-Remove":  "",
+					"X-Config":  "test",
+					"X-Status":  "200",
+				},
+				body: "HELLO!",
+			},
+		),
+		gen(
+			"request body too large",
+			&condition{
+				method: http.MethodPost,
+				body:   strings.Repeat("a", 20),
+			},
+			&action{
+				status: http.StatusOK,
+				header: map[string]string{"X-Body-Too-Large": "true"},
+				body:   strings.Repeat("a", 20),
+			},
+		),
+		gen(
+			"respond from plugin",
+			&condition{
+				method: http.MethodGet,
+				header: map[string]string{"X-Deny": "true"},
+			},
+			&action{
+				status: http.StatusForbidden,
+				header: map[string]string{"X-Plugin": "denied", "X-Request": ""},
+				body:   "denied: test",
+			},
+		),
+		gen(
+			"replace response",
+			&condition{
+				method: http.MethodGet,
+				header: map[string]string{"X-Status": "404"},
+			},
+			&action{
+				status: http.StatusOK,
+				body:   "replaced",
+			},
+		),
+		gen(
+			"on_request trapped",
+			&condition{
+				method: http.MethodGet,
+				header: map[string]string{"X-Trap": "true"},
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				header: map[string]string{"X-Request": ""},
+				body:   `{"status":500,"statusText":"Internal Server Error"}`,
+			},
+		),
+		gen(
+			"on_response returned error",
+			&condition{
+				method: http.MethodGet,
+				header: map[string]string{"X-Status": "500"},
+			},
+			&action{
+				status: http.StatusInternalServerError,
+				body:   `{"status":500,"statusText":"Internal Server Error"}`,
+			},
+		),
+	}
+
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{Config: "test", MaxBodySize: 10, PoolSize: 1})
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.C.method, "http://test.com/test?foo=bar", strings.NewReader(tt.C.body))
+			r.Header.Set("Accept", "application/json")
+			for k, v := range tt.C.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			m.Middleware(testHandler).ServeHTTP(w, r)
+
+			testutil.Diff(t, tt.A.status, w.Code)
+			for k, v := range tt.A.header {
+				testutil.Diff(t, v, w.Header().Get(k))
+			}
+			testutil.Diff(t, tt.A.body, w.Body.String())
+		})
+	}
+
+	// Instances failed are discarded and re-created.
+	testutil.Diff(t, 1, len(m.pool.sem))
+}
+
+func TestMiddleware_streaming(t *testing.T) {
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{MaxBodySize: 10})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("12345"))
+		_, _ = w.Write([]byte("67890"))
+		_, _ = w.Write([]byte("abc"))
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	w := httptest.NewRecorder()
+	m.Middleware(next).ServeHTTP(w, r)
+
+	// Response larger than the max body size is not passed to the plugin.
+	testutil.Diff(t, http.StatusAccepted, w.Code)
+	testutil.Diff(t, "", w.Header().Get("X-Status"))
+	testutil.Diff(t, "1234567890abc", w.Body.String())
+}
+
+func TestMiddleware_flush(t *testing.T) {
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{MaxBodySize: 10})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("event: 1\n"))
+		_ = http.NewResponseController(w).Flush()
+		_, _ = w.Write([]byte("X"))
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	w := httptest.NewRecorder()
+	m.Middleware(next).ServeHTTP(w, r)
+
+	// Flushed response is streamed without passing to the plugin.
+	testutil.Diff(t, true, w.Flushed)
+	testutil.Diff(t, http.StatusAccepted, w.Code)
+	testutil.Diff(t, "", w.Header().Get("X-Status"))
+	testutil.Diff(t, "event: 1\nX", w.Body.String())
+}
+
+func TestMiddleware_errorHeaders(t *testing.T) {
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{MaxBodySize: 10})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", "3")
+		w.WriteHeader(http.StatusInternalServerError) // on_response returns an error.
+		_, _ = w.Write([]byte("abc"))
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	m.Middleware(next).ServeHTTP(w, r)
+
+	// Headers of the upstream response are not written with the error.
+	body := `{"status":500,"statusText":"Internal Server Error"}`
+	testutil.Diff(t, http.StatusInternalServerError, w.Code)
+	testutil.Diff(t, "", w.Header().Get("Content-Encoding"))
+	testutil.Diff(t, false, w.Header().Get("Content-Length") == "3")
+	testutil.Diff(t, body, w.Body.String())
+}
+
+func TestMiddleware_concurrent(t *testing.T) {
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{PoolSize: 2})
+	h := m.Middleware(testHandler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, "http://test.com/", strings.NewReader("body"))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			testutil.Diff(t, "BODY!", w.Body.String())
+		}()
+	}
+	wg.Wait()
+	testutil.Diff(t, true, len(m.pool.sem) <= 2)
+}
+
+func TestPool_get(t *testing.T) {
+	m := newTestWasm(t, &v1.WasmMiddlewareSpec{PoolSize: 1})
+	inst, err := m.pool.get(context.Background())
+	testutil.Diff(t, nil, err)
+
+	// All instances are in use.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = m.pool.get(ctx)
+	testutil.Diff(t, context.Canceled, err, cmpopts.EquateErrors())
+
+	m.pool.put(inst, true)
+	inst, err = m.pool.get(context.Background())
+	testutil.Diff(t, nil, err)
+	m.pool.put(inst, false)
+	testutil.Diff(t, 0, len(m.pool.sem))
+	testutil.Diff(t, true, inst.IsClosed())
+}
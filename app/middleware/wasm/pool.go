@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package wasm
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/tetratelabs/wazero"
+	wapi "github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Names of the functions exported by plugins.
+const (
+	exportOnConfigure = "aileron_on_configure"
+	exportOnRequest   = "aileron_on_request"
+	exportOnResponse  = "aileron_on_response"
+)
+
+var (
+	errNoHandlers = errors.New("wasm: module exports neither " + exportOnRequest + " nor " + exportOnResponse)
+	errConfigure  = errors.New("wasm: " + exportOnConfigure + " returned non-zero value")
+)
+
+// poolConfig is the configuration of a pool.
+type poolConfig struct {
+	// path is the path to the wasm module.
+	path string
+	// config is the plugin config read by the config_get.
+	config []byte
+	// size is the maximum number of instances.
+	size int
+	// maxPages is the maximum memory pages of an instance.
+	// The runtime default is used when 0.
+	maxPages uint32
+	// lg is the logger used by the log host function.
+	lg log.Logger
+}
+
+// pool is the pool of module instances.
+// Module instances are not safe for concurrent use.
+// Each request uses an instance exclusively while calling a plugin function.
+type pool struct {
+	lg     log.Logger
+	config []byte
+
+	rt       wazero.Runtime
+	compiled wazero.CompiledModule
+
+	// idle holds the instances that are not in use.
+	idle chan wapi.Module
+	// sem limits the number of instances.
+	sem chan struct{}
+
+	// onRequest and onResponse reports if
+	// the module exports the functions.
+	onRequest  bool
+	onResponse bool
+}
+
+// newPool compiles the wasm module and returns a new pool.
+// An instance is created to check that the module
+// can be instantiated and configured.
+func newPool(ctx context.Context, c *poolConfig) (*pool, error) {
+	bin, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if c.maxPages > 0 {
+		rc = rc.WithMemoryLimitPages(c.maxPages)
+	}
+	p := &pool{
+		lg:     c.lg,
+		config: c.config,
+		rt:     wazero.NewRuntimeWithConfig(ctx, rc),
+		idle:   make(chan wapi.Module, c.size),
+		sem:    make(chan struct{}, c.size),
+	}
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, p.rt); err != nil {
+		_ = p.rt.Close(ctx)
+		return nil, err
+	}
+	if _, err := hostModule(p.rt).Instantiate(ctx); err != nil {
+		_ = p.rt.Close(ctx)
+		return nil, err
+	}
+	if p.compiled, err = p.rt.CompileModule(ctx, bin); err != nil {
+		_ = p.rt.Close(ctx)
+		return nil, err
+	}
+
+	exports := p.compiled.ExportedFunctions()
+	for _, name := range []string{exportOnConfigure, exportOnRequest, exportOnResponse} {
+		fn, ok := exports[name]
+		if !ok {
+			continue
+		}
+		if len(fn.ParamTypes()) != 0 || len(fn.ResultTypes()) != 1 || fn.ResultTypes()[0] != wapi.ValueTypeI32 {
+			_ = p.rt.Close(ctx)
+			return nil, fmt.Errorf("wasm: %s must have the signature of () -> i32", name)
+		}
+	}
+	_, p.onRequest = exports[exportOnRequest]
+	_, p.onResponse = exports[exportOnResponse]
+	if !p.onRequest && !p.onResponse {
+		_ = p.rt.Close(ctx)
+		return nil, errNoHandlers
+	}
+
+	m, err := p.get(ctx)
+	if err != nil {
+		_ = p.rt.Close(ctx)
+		return nil, err
+	}
+	p.put(m, true)
+	return p, nil
+}
+
+// instantiate creates a new module instance.
+// The "_initialize" function of WASI reactors is called if exported.
+// The aileron_on_configure function is called if exported.
+func (p *pool) instantiate(ctx context.Context) (wapi.Module, error) {
+	mc := wazero.NewModuleConfig().
+		WithName(""). // Anonymous modules can be instantiated multiple times.
+		WithStartFunctions("_initialize").
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(crand.Reader)
+	m, err := p.rt.InstantiateModule(ctx, p.compiled, mc)
+	if err != nil {
+		return nil, err
+	}
+	if fn := m.ExportedFunction(exportOnConfigure); fn != nil {
+		ret, err := fn.Call(withState(ctx, &state{pool: p}))
+		if err != nil {
+			_ = m.Close(ctx)
+			return nil, err
+		}
+		if int32(ret[0]) != 0 {
+			_ = m.Close(ctx)
+			return nil, errConfigure
+		}
+	}
+	return m, nil
+}
+
+// get returns an idle instance.
+// A new instance is created when no instances are idle
+// and the number of instances does not reach the limit.
+// Otherwise, get waits for an instance to be released.
+func (p *pool) get(ctx context.Context) (wapi.Module, error) {
+	select {
+	case m := <-p.idle:
+		return m, nil
+	default:
+	}
+	select {
+	case m := <-p.idle:
+		return m, nil
+	case p.sem <- struct{}{}:
+		m, err := p.instantiate(ctx)
+		if err != nil {
+			<-p.sem
+			return nil, err
+		}
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// put releases the instance.
+// Instances which failed calling functions should be put with ok=false
+// so that they are discarded because their state may be broken.
+func (p *pool) put(m wapi.Module, ok bool) {
+	if !ok || m.IsClosed() {
+		_ = m.Close(context.Background())
+		<-p.sem
+		return
+	}
+	p.idle <- m
+}
+
+// call calls the exported function with an instance in the pool.
+// The state can be accessed from the host functions while the call.
+func (p *pool) call(ctx context.Context, name string, s *state) (int32, error) {
+	m, err := p.get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.pool = p
+	ret, err := m.ExportedFunction(name).Call(withState(ctx, s))
+	p.put(m, err == nil)
+	if err != nil {
+		return 0, err
+	}
+	return int32(ret[0]), nil
+}
+
+// close closes the runtime and all instances.
+func (p *pool) close() error {
+	return p.rt.Close(context.Background())
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package xfcc
+
+import (
+	"net/netip"
+	"net/textproto"
+	"reflect"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "app/v1"
+	kind       = "XFCCMiddleware"
+	Key        = apiVersion + "/" + kind
+)
+
+// defaultFields is the certificate details forwarded
+// in the Envoy format when no fields are configured.
+var defaultFields = []v1.XFCCField{
+	v1.XFCCField_XFCCHash,
+	v1.XFCCField_XFCCSubject,
+	v1.XFCCField_XFCCURI,
+	v1.XFCCField_XFCCDNS,
+}
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.XFCCMiddleware{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.XFCCMiddlewareSpec{
+				HeaderName: "X-Forwarded-Client-Cert",
+				Format:     v1.XFCCFormat_XFCCEnvoy,
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*xfcc]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(_ api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.XFCCMiddleware)
+
+	trusted := make([]netip.Prefix, 0, len(c.Spec.TrustedNetworks))
+	for _, n := range c.Spec.TrustedNetworks {
+		prefix, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+
+	fields := c.Spec.Fields
+	if len(fields) == 0 {
+		fields = defaultFields
+	}
+
+	return &xfcc{
+		header:  textproto.CanonicalMIMEHeaderKey(c.Spec.HeaderName),
+		format:  c.Spec.Format,
+		fields:  fields,
+		trusted: trusted,
+	}, nil
+}
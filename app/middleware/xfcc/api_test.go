@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package xfcc
+
+import (
+	"net/netip"
+	"regexp"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		expect     any
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default manifest",
+			&condition{
+				manifest: Resource.Default(),
+			},
+			&action{
+				expect: &xfcc{
+					header:  "X-Forwarded-Client-Cert",
+					format:  v1.XFCCFormat_XFCCEnvoy,
+					fields:  defaultFields,
+					trusted: []netip.Prefix{},
+				},
+			},
+		),
+		gen(
+			"create with fields and trusted networks",
+			&condition{
+				manifest: &v1.XFCCMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.XFCCMiddlewareSpec{
+						HeaderName:      "x-client-cert",
+						Format:          v1.XFCCFormat_XFCCPEM,
+						Fields:          []v1.XFCCField{v1.XFCCField_XFCCCert},
+						TrustedNetworks: []string{"10.0.0.1/8", "fd00::/8"},
+					},
+				},
+			},
+			&action{
+				expect: &xfcc{
+					header: "X-Client-Cert",
+					format: v1.XFCCFormat_XFCCPEM,
+					fields: []v1.XFCCField{v1.XFCCField_XFCCCert},
+					trusted: []netip.Prefix{
+						netip.MustParsePrefix("10.0.0.0/8"),
+						netip.MustParsePrefix("fd00::/8"),
+					},
+				},
+			},
+		),
+		gen(
+			"invalid trusted network",
+			&condition{
+				manifest: &v1.XFCCMiddleware{
+					Metadata: &k.Metadata{},
+					Spec: &v1.XFCCMiddlewareSpec{
+						TrustedNetworks: []string{"10.0.0.1"},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create XFCCMiddleware`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			server := api.NewContainerAPI()
+			got, err := Resource.Create(server, tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			opts := []cmp.Option{
+				cmp.AllowUnexported(xfcc{}),
+				cmp.Comparer(func(x, y netip.Prefix) bool { return x == y }),
+			}
+			testutil.Diff(t, tt.A.expect, got, opts...)
+		})
+	}
+}
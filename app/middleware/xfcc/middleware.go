@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package xfcc
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+)
+
+// xfcc forwards the details of verified client certificates
+// to upstream servers with the X-Forwarded-Client-Cert header.
+// This implements core.Middleware interface.
+type xfcc struct {
+	// header is the HTTP header name to forward the client certificate.
+	// Value must be formatted with textproto.CanonicalMIMEHeaderKey.
+	header string
+	// format is the format of the header value.
+	format v1.XFCCFormat
+	// fields are the certificate details to be forwarded
+	// in the Envoy format.
+	fields []v1.XFCCField
+	// trusted is the list of networks of the peers
+	// that are allowed to send the header.
+	trusted []netip.Prefix
+}
+
+func (m *xfcc) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.trustedPeer(r.RemoteAddr) {
+			r.Header.Del(m.header) // Strip values sent from untrusted peers.
+		}
+
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		h := utilhttp.ProxyHeaderFromContext(ctx)
+		if h == nil {
+			h = make(http.Header)
+			ctx = utilhttp.ContextWithProxyHeader(ctx, h)
+		}
+		v := m.value(r.TLS.VerifiedChains[0])
+		if prev := r.Header.Values(m.header); len(prev) > 0 {
+			// Append to the elements sent from the trusted peer
+			// in the same way as the APPEND_FORWARD of Envoy.
+			// Incoming values are moved to the proxy headers so that
+			// the header is sent as a single comma separated value.
+			v = strings.Join(prev, ",") + "," + v
+			r.Header.Del(m.header)
+		}
+		h.Set(m.header, v)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// trustedPeer reports whether the peer of the given
+// remote address is allowed to send the header.
+func (m *xfcc) trustedPeer(remoteAddr string) bool {
+	if len(m.trusted) == 0 {
+		return false
+	}
+	var addr netip.Addr
+	if ap, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		addr = ap.Addr()
+	} else if a, err := netip.ParseAddr(remoteAddr); err == nil {
+		addr = a
+	} else {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range m.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the header value of the given verified chain.
+// The chain must have at least 1 certificate.
+func (m *xfcc) value(chain []*x509.Certificate) string {
+	cert := chain[0]
+	if m.format == v1.XFCCFormat_XFCCPEM {
+		return escapePEM(cert)
+	}
+
+	elems := make([]string, 0, len(m.fields))
+	for _, f := range m.fields {
+		switch f {
+		case v1.XFCCField_XFCCHash:
+			sum := sha256.Sum256(cert.Raw)
+			elems = append(elems, "Hash="+hex.EncodeToString(sum[:]))
+		case v1.XFCCField_XFCCSubject:
+			elems = append(elems, "Subject="+quote(cert.Subject.String()))
+		case v1.XFCCField_XFCCURI:
+			for _, u := range cert.URIs {
+				elems = append(elems, "URI="+quote(u.String()))
+			}
+		case v1.XFCCField_XFCCDNS:
+			for _, d := range cert.DNSNames {
+				elems = append(elems, "DNS="+quote(d))
+			}
+		case v1.XFCCField_XFCCCert:
+			elems = append(elems, "Cert="+quote(escapePEM(cert)))
+		case v1.XFCCField_XFCCChain:
+			elems = append(elems, "Chain="+quote(escapePEM(chain...)))
+		case v1.XFCCField_XFCCSerial:
+			elems = append(elems, "Serial="+hex.EncodeToString(cert.SerialNumber.Bytes()))
+		}
+	}
+	return strings.Join(elems, ";")
+}
+
+// escapePEM returns the URL encoded PEM of the certificates.
+// Spaces are encoded into "%20" rather than "+".
+func escapePEM(certs ...*x509.Certificate) string {
+	var b strings.Builder
+	for _, c := range certs {
+		_ = pem.Encode(&b, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+	return strings.ReplaceAll(url.QueryEscape(b.String()), "+", "%20")
+}
+
+// quote returns the value quoted with double quotes
+// when it contains the characters that have special meaning
+// in the Envoy format which are `,`, `;`, `=` and `"`.
+// Double quotes in the value are escaped with a back slash.
+func quote(s string) string {
+	if !strings.ContainsAny(s, `,;="`) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package xfcc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+)
+
+// testCert returns a self-signed certificate for testing.
+func testCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("spiffe://example.com/foo")
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x1234),
+		Subject:      pkix.Name{CommonName: "foo", Organization: []string{"Example, Inc."}},
+		DNSNames:     []string{"foo.example.com", "bar.example.com"},
+		URIs:         []*url.URL{u},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestMiddleware(t *testing.T) {
+	cert := testCert(t)
+	sum := sha256.Sum256(cert.Raw)
+	hash := hex.EncodeToString(sum[:])
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	type condition struct {
+		m          *xfcc
+		remoteAddr string
+		header     string
+		tls        *tls.ConnectionState
+	}
+
+	type action struct {
+		header string   // Header value left in the request.
+		proxy  []string // Header value added to the proxy headers.
+	}
+
+	defaultXFCC := &xfcc{
+		header: "X-Forwarded-Client-Cert",
+		fields: defaultFields,
+	}
+	trustedXFCC := &xfcc{
+		header:  "X-Forwarded-Client-Cert",
+		fields:  []v1.XFCCField{v1.XFCCField_XFCCHash},
+		trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no tls",
+			&condition{
+				m:          defaultXFCC,
+				remoteAddr: "192.168.0.1:12345",
+			},
+			&action{},
+		),
+		gen(
+			"strip untrusted without tls",
+			&condition{
+				m:          defaultXFCC,
+				remoteAddr: "10.0.0.1:12345",
+				header:     "Hash=dummy",
+			},
+			&action{},
+		),
+		gen(
+			"no verified chains",
+			&condition{
+				m:          defaultXFCC,
+				remoteAddr: "192.168.0.1:12345",
+				tls:        &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			},
+			&action{},
+		),
+		gen(
+			"envoy format with default fields",
+			&condition{
+				m:          defaultXFCC,
+				remoteAddr: "192.168.0.1:12345",
+				header:     "Hash=dummy",
+				tls:        verified,
+			},
+			&action{
+				proxy: []string{"Hash=" + hash + `;Subject="CN=foo,O=Example\, Inc.";URI=spiffe://example.com/foo;DNS=foo.example.com;DNS=bar.example.com`},
+			},
+		),
+		gen(
+			"append to header from trusted peer",
+			&condition{
+				m:          trustedXFCC,
+				remoteAddr: "10.0.0.1:12345",
+				header:     "Hash=dummy",
+				tls:        verified,
+			},
+			&action{
+				proxy: []string{"Hash=dummy,Hash=" + hash},
+			},
+		),
+		gen(
+			"keep header from trusted peer without tls",
+			&condition{
+				m:          trustedXFCC,
+				remoteAddr: "10.0.0.1:12345",
+				header:     "Hash=dummy",
+			},
+			&action{
+				header: "Hash=dummy",
+			},
+		),
+		gen(
+			"strip header from untrusted peer",
+			&condition{
+				m:          trustedXFCC,
+				remoteAddr: "192.168.0.1:12345",
+				header:     "Hash=dummy",
+				tls:        verified,
+			},
+			&action{
+				proxy: []string{"Hash=" + hash},
+			},
+		),
+		gen(
+			"strip header from invalid address",
+			&condition{
+				m:          trustedXFCC,
+				remoteAddr: "invalid",
+				header:     "Hash=dummy",
+				tls:        verified,
+			},
+			&action{
+				proxy: []string{"Hash=" + hash},
+			},
+		),
+		gen(
+			"pem format",
+			&condition{
+				m: &xfcc{
+					header: "X-Client-Cert",
+					format: v1.XFCCFormat_XFCCPEM,
+				},
+				remoteAddr: "192.168.0.1:12345",
+				tls:        verified,
+			},
+			&action{
+				proxy: []string{escapePEM(cert)},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var proxy http.Header
+			var header string
+			h := tt.C.m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxy = utilhttp.ProxyHeaderFromContext(r.Context())
+				header = r.Header.Get(tt.C.m.header)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+			r.RemoteAddr = tt.C.remoteAddr
+			r.TLS = tt.C.tls
+			if tt.C.header != "" {
+				r.Header.Set(tt.C.m.header, tt.C.header)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			testutil.Diff(t, tt.A.header, header)
+			testutil.Diff(t, tt.A.proxy, proxy.Values(tt.C.m.header))
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	cert := testCert(t)
+	m := &xfcc{
+		fields: []v1.XFCCField{
+			v1.XFCCField_XFCCCert,
+			v1.XFCCField_XFCCChain,
+			v1.XFCCField_XFCCSerial,
+		},
+	}
+	got := m.value([]*x509.Certificate{cert, cert})
+	elems := strings.Split(got, ";")
+	testutil.Diff(t, 3, len(elems))
+	testutil.Diff(t, "Cert="+escapePEM(cert), elems[0])
+	testutil.Diff(t, "Chain="+escapePEM(cert, cert), elems[1])
+	testutil.Diff(t, "Serial=1234", elems[2])
+
+	pem, err := url.QueryUnescape(strings.TrimPrefix(elems[0], "Cert="))
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, true, strings.HasPrefix(pem, "-----BEGIN CERTIFICATE-----\n"))
+	testutil.Diff(t, false, strings.Contains(elems[0], "+"))
+}
+
+func TestQuote(t *testing.T) {
+	testutil.Diff(t, "foo", quote("foo"))
+	testutil.Diff(t, `"CN=foo,O=bar"`, quote("CN=foo,O=bar"))
+	testutil.Diff(t, `"foo;bar"`, quote("foo;bar"))
+	testutil.Diff(t, `"foo\"bar"`, quote(`foo"bar`))
+}
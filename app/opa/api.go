@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"reflect"
 	"strings"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -46,6 +47,7 @@ var Resource api.Resource = &API{
 				ClaimsKey: "AuthnClaims",
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*authz]()},
 	},
 }
 
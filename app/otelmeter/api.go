@@ -38,7 +38,9 @@ var (
 )
 
 var Resource api.Resource = &API{
-	BaseResource: &api.BaseResource{},
+	BaseResource: &api.BaseResource{
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*otelMeter]()},
+	},
 }
 
 type API struct {
@@ -120,6 +122,10 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 		metric.WithDescription("Total number of sent http requests"),
 	)
 
+	if err := registerListenerMetrics(meter); err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
 	return &otelMeter{
 		mp:        mp,
 		mAPICalls: mAPICall,
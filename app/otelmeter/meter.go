@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -77,3 +78,40 @@ func (m *otelMeter) Tripperware(next http.RoundTripper) http.RoundTripper {
 func (m *otelMeter) Finalize() error {
 	return m.mp.Shutdown(context.Background())
 }
+
+// registerListenerMetrics registers the instruments that observe
+// the connection statistics of the listeners.
+func registerListenerMetrics(meter metric.Meter) error {
+	accepted, err := meter.Int64ObservableCounter(
+		"listener_connections_accepted_total",
+		metric.WithDescription("Total number of accepted connections"),
+	)
+	if err != nil {
+		return err
+	}
+	rejected, err := meter.Int64ObservableCounter(
+		"listener_connections_rejected_total",
+		metric.WithDescription("Total number of rejected connections by connection limits"),
+	)
+	if err != nil {
+		return err
+	}
+	active, err := meter.Int64ObservableGauge(
+		"listener_connections_active",
+		metric.WithDescription("Number of currently active connections"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, s := range network.ListenerStats() {
+			addr := attribute.String("addr", s.Addr)
+			o.ObserveInt64(accepted, s.Accepted, metric.WithAttributes(addr))
+			o.ObserveInt64(rejected, s.RejectedLimit, metric.WithAttributes(addr, attribute.String("reason", "limit")))
+			o.ObserveInt64(rejected, s.RejectedPerIP, metric.WithAttributes(addr, attribute.String("reason", "per_ip")))
+			o.ObserveInt64(active, s.Active, metric.WithAttributes(addr))
+		}
+		return nil
+	}, accepted, rejected, active)
+	return err
+}
@@ -5,12 +5,14 @@ package otelmeter
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"testing"
 
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -309,3 +311,52 @@ func TestFinalize(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterListenerMetrics(t *testing.T) {
+	ln, err := network.NewListener(&network.ListenConfig{Address: "127.0.0.1:0"})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	go func() {
+		c, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			defer c.Close()
+			c.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := ln.Accept()
+	testutil.Diff(t, nil, err)
+	defer conn.Close()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	testutil.Diff(t, nil, registerListenerMetrics(mp.Meter("test-meter")))
+
+	var rm metricdata.ResourceMetrics
+	testutil.Diff(t, nil, reader.Collect(context.Background(), &rm))
+
+	addr := attribute.String("addr", ln.Addr().String())
+	values := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		var points []metricdata.DataPoint[int64]
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			points = data.DataPoints
+		case metricdata.Gauge[int64]:
+			points = data.DataPoints
+		}
+		for _, p := range points {
+			if v, ok := p.Attributes.Value(addr.Key); !ok || v != addr.Value {
+				continue
+			}
+			reason, _ := p.Attributes.Value("reason")
+			values[m.Name+reason.AsString()] = p.Value
+		}
+	}
+	testutil.Diff(t, map[string]int64{
+		"listener_connections_accepted_total":       1,
+		"listener_connections_rejected_totallimit":  0,
+		"listener_connections_rejected_totalper_ip": 0,
+		"listener_connections_active":               1,
+	}, values)
+}
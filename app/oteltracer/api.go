@@ -40,7 +40,9 @@ const (
 )
 
 var Resource api.Resource = &API{
-	BaseResource: &api.BaseResource{},
+	BaseResource: &api.BaseResource{
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*otelTracer]()},
+	},
 }
 
 type API struct {
@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/protobuf/proto"
+	"reflect"
 )
 
 const (
@@ -31,6 +32,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.PrometheusMeterSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*metrics]()},
 	},
 }
 
@@ -59,6 +61,7 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	reg.MustRegister(collectors.NewGoCollector())
+	reg.MustRegister(listenerCollector{})
 
 	opts := promhttp.HandlerOpts{}
 	handler := promhttp.InstrumentMetricHandler(reg, promhttp.HandlerFor(reg, opts))
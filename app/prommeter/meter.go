@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -74,3 +75,40 @@ func (m *metrics) Tripperware(next http.RoundTripper) http.RoundTripper {
 		return resp, err
 	})
 }
+
+var (
+	listenerAcceptedDesc = prometheus.NewDesc(
+		"listener_connections_accepted_total",
+		"Total number of accepted connections",
+		[]string{"addr"}, nil,
+	)
+	listenerRejectedDesc = prometheus.NewDesc(
+		"listener_connections_rejected_total",
+		"Total number of rejected connections by connection limits",
+		[]string{"addr", "reason"}, nil,
+	)
+	listenerActiveDesc = prometheus.NewDesc(
+		"listener_connections_active",
+		"Number of currently active connections",
+		[]string{"addr"}, nil,
+	)
+)
+
+// listenerCollector collects the connection statistics
+// of the listeners on every scrape.
+type listenerCollector struct{}
+
+func (listenerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- listenerAcceptedDesc
+	ch <- listenerRejectedDesc
+	ch <- listenerActiveDesc
+}
+
+func (listenerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range network.ListenerStats() {
+		ch <- prometheus.MustNewConstMetric(listenerAcceptedDesc, prometheus.CounterValue, float64(s.Accepted), s.Addr)
+		ch <- prometheus.MustNewConstMetric(listenerRejectedDesc, prometheus.CounterValue, float64(s.RejectedLimit), s.Addr, "limit")
+		ch <- prometheus.MustNewConstMetric(listenerRejectedDesc, prometheus.CounterValue, float64(s.RejectedPerIP), s.Addr, "per_ip")
+		ch <- prometheus.MustNewConstMetric(listenerActiveDesc, prometheus.GaugeValue, float64(s.Active), s.Addr)
+	}
+}
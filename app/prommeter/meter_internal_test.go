@@ -4,11 +4,13 @@
 package prommeter
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
 	"github.com/google/go-cmp/cmp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -229,3 +231,105 @@ func TestTripperware(t *testing.T) {
 		})
 	}
 }
+
+// gatherListener gathers the listener metrics of the addr.
+// Keys of the returned map are the metric names followed by the reason labels.
+func gatherListener(t *testing.T, addr string) map[string]float64 {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(listenerCollector{})
+	mfs, err := reg.Gather()
+	testutil.Diff(t, nil, err)
+
+	values := map[string]float64{}
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["addr"] != addr {
+				continue
+			}
+			key := mf.GetName() + labels["reason"]
+			if m.GetCounter() != nil {
+				values[key] = m.GetCounter().GetValue()
+			} else {
+				values[key] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	return values
+}
+
+// acceptOne connects to the ln and returns the accepted connection.
+func acceptOne(t *testing.T, ln net.Listener) net.Conn {
+	t.Helper()
+	go func() {
+		c, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			defer c.Close()
+			c.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := ln.Accept()
+	testutil.Diff(t, nil, err)
+	return conn
+}
+
+func TestListenerCollector(t *testing.T) {
+	ln, err := network.NewListener(&network.ListenConfig{Address: "127.0.0.1:0"})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	conn := acceptOne(t, ln)
+	defer conn.Close()
+
+	testutil.Diff(t, map[string]float64{
+		"listener_connections_accepted_total":       1,
+		"listener_connections_rejected_totallimit":  0,
+		"listener_connections_rejected_totalper_ip": 0,
+		"listener_connections_active":               1,
+	}, gatherListener(t, ln.Addr().String()))
+}
+
+func TestListenerCollector_sameAddress(t *testing.T) {
+	// Get available address for testing.
+	tmp, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	addr := tmp.Addr().String()
+	tmp.Close()
+
+	ln1, err := network.NewListener(&network.ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+	defer ln1.Close()
+
+	// Listeners on the same address coexist while reloading configs.
+	done := network.InheritSockets()
+	ln2, err := network.NewListener(&network.ListenConfig{Address: addr})
+	done(false)
+	testutil.Diff(t, nil, err)
+	defer ln2.Close()
+
+	conn1 := acceptOne(t, ln1)
+	defer conn1.Close()
+	conn2 := acceptOne(t, ln2)
+	defer conn2.Close()
+
+	testutil.Diff(t, map[string]float64{
+		"listener_connections_accepted_total":       2,
+		"listener_connections_rejected_totallimit":  0,
+		"listener_connections_rejected_totalper_ip": 0,
+		"listener_connections_active":               2,
+	}, gatherListener(t, addr))
+
+	// Totals do not decrease when the old listener is closed.
+	conn1.Close()
+	ln1.Close()
+	testutil.Diff(t, map[string]float64{
+		"listener_connections_accepted_total":       2,
+		"listener_connections_rejected_totallimit":  0,
+		"listener_connections_rejected_totalper_ip": 0,
+		"listener_connections_active":               1,
+	}, gatherListener(t, addr))
+}
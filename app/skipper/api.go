@@ -4,6 +4,7 @@
 package skipper
 
 import (
+	"reflect"
 	"strings"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.SkipperSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*skippable]()},
 	},
 }
 
@@ -5,6 +5,7 @@ package redis
 
 import (
 	"crypto/tls"
+	"reflect"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
@@ -45,6 +46,7 @@ var Resource api.Resource = &API{
 				Expiration:      0,
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*client]()},
 	},
 }
 
@@ -31,11 +31,10 @@ func main() {
 
 	a := app.New()
 	a.ParseArgs(os.Args[1:])
+	a.SetServerFunc(newServer)  // Enable reloading configs.
+	a.SetKinds(register.Keys()) // Enable showing JSON schema of all kinds.
 
-	f := api.NewFactoryAPI()
-	register.RegisterAll(f)    // Register all APIs.
-	_ = svr.Handle("core/", f) // Handle "core/*" APIs.
-	_ = svr.Handle("app/", f)  // Handle "app/*" APIs.
+	registerAPIs(svr)
 
 	if err := a.Run(svr); err != nil {
 		e := app.ErrAppMain.WithStack(err, nil)
@@ -43,3 +42,19 @@ func main() {
 		app.Exit(1)
 	}
 }
+
+// newServer returns a new API server with all APIs registered.
+// This is used to rebuild resources when reloading configs.
+func newServer() api.API[*api.Request, *api.Response] {
+	svr := api.NewDefaultServeMux()
+	registerAPIs(svr)
+	return svr
+}
+
+// registerAPIs registers all APIs to the server.
+func registerAPIs(svr *api.DefaultServeMux) {
+	f := api.NewFactoryAPI()
+	register.RegisterAll(f)    // Register all APIs.
+	_ = svr.Handle("core/", f) // Handle "core/*" APIs.
+	_ = svr.Handle("app/", f)  // Handle "app/*" APIs.
+}
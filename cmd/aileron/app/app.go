@@ -6,16 +6,23 @@ package app
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 
 	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-gateway/aileron-gateway/core"
-	"github.com/aileron-gateway/aileron-gateway/internal/encoder"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
 	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/secret"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"github.com/aileron-projects/go/zos"
 	"github.com/spf13/pflag"
 )
@@ -30,12 +37,41 @@ func New() *App {
 	return &App{}
 }
 
+// ServerFunc is the function that returns a new API server
+// which resources are registered to.
+type ServerFunc func() api.API[*api.Request, *api.Response]
+
 // App is the application.
 // Use app.New() to get a new instance of this struct.
 // This implements core.Runner interface.
 type App struct {
 	args []string
 	opts *Options
+	// newServer returns a new API server.
+	// This is used to rebuild resources when reloading configs.
+	// Configs are not reloaded when nil.
+	newServer ServerFunc
+	// kinds are the keys of the resources registered to the server
+	// in the format of "APIGroup/APIVersion/Kind".
+	// This is used to show the JSON schema of all kinds.
+	kinds []string
+	// reader reads the config files.
+	// This is configured by the command line options in Run.
+	reader *ConfigReader
+}
+
+// SetServerFunc sets the function that returns a new API server.
+// When set, the app reloads the configs on SIGHUP signal
+// by building the all resources in a new server returned by the function.
+func (a *App) SetServerFunc(f ServerFunc) {
+	a.newServer = f
+}
+
+// SetKinds sets the keys of the resources registered to the server
+// in the format of "APIGroup/APIVersion/Kind".
+// When set, the JSON schema of all the kinds can be shown.
+func (a *App) SetKinds(kinds []string) {
+	a.kinds = kinds
 }
 
 // ParseArgs parse arguments.
@@ -56,21 +92,73 @@ func (a *App) Run(server api.API[*api.Request, *api.Response]) error {
 		syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
 	defer cancel()
 
+	// Inherit listening sockets from the parent process
+	// when this process was started for upgrading the binary.
+	upgraded := inheritUpgradeSockets()
+	defer upgraded(true) // Abort when failed before getting ready.
+
 	// Load env files before loading config files.
 	if err := zos.LoadEnv(a.opts.Basic.Envs...); err != nil {
 		return ErrAppMainLoadEnv.WithStack(err, nil) // Return err as-is.
 	}
+	// Configure the loader of remote config files.
+	remote, err := NewRemoteLoader(a.opts.Remote)
+	if err != nil {
+		return ErrAppMainLoadConfigs.WithStack(err, nil)
+	}
+	a.reader = &ConfigReader{Remote: remote, Jsonnet: a.opts.Jsonnet}
+	// Validate config files and exit.
+	if a.opts.Command == CommandValidate {
+		a.reader.ShowValidation(server, a.opts.Basic.Configs, a.opts.Basic.Patches...)
+		return nil
+	}
+	// Show configs with patches applied and exit.
+	if a.opts.Command == CommandDump {
+		a.reader.ShowDump(a.opts.Basic.Configs, a.opts.Basic.Patches, a.opts.Basic.Out)
+		return nil
+	}
 	// Load config files.
 	// Environmental variables in the configs will be resolved.
-	if err := LoadConfigFiles(server, a.opts.Basic.Configs); err != nil {
+	if err := a.reader.LoadConfigFiles(server, a.opts.Basic.Configs, a.opts.Basic.Patches...); err != nil {
 		return err // Return err as-is.
 	}
 
+	// Show resource dependency graph and exit.
+	if a.opts.Command == CommandGraph {
+		ShowGraph(server, a.opts.Basic.Out)
+		return nil
+	}
+
 	// Show resource template and exit.
 	ShowTemplate(server, a.opts.Basic.Template, a.opts.Basic.Out)
 
+	// Show JSON schema and exit.
+	ShowSchema(server, a.opts.Basic.Schema, a.kinds)
+
 	// Get the entrypoint resource and run it.
-	// Entrypoint must implement core.Runner interface.
+	entrypoint, err := getEntrypoint(ctx, server)
+	if err != nil {
+		return err // Return err as-is.
+	}
+	// Notify the parent process that this process is ready
+	// after the entrypoint got ready to serve.
+	ctx = core.ContextWithReady(ctx, func() { upgraded(false) })
+
+	if a.newServer != nil {
+		return a.runWithReload(ctx, entrypoint)
+	}
+
+	// Run the entrypoint runner.
+	if err := entrypoint.Run(ctx); err != nil {
+		return ErrAppMainRun.WithStack(err, nil)
+	}
+
+	return nil
+}
+
+// getEntrypoint returns the entrypoint resource from the server.
+// Entrypoint must implement core.Runner interface.
+func getEntrypoint(ctx context.Context, server api.API[*api.Request, *api.Response]) (core.Runner, error) {
 	req := &api.Request{
 		Method: api.MethodGet,
 		Key:    "core/v1/Entrypoint",
@@ -85,71 +173,236 @@ func (a *App) Run(server api.API[*api.Request, *api.Response]) error {
 
 	res, err := server.Serve(ctx, req)
 	if err != nil {
-		return ErrAppMainGetEntrypoint.WithStack(err, nil)
+		return nil, ErrAppMainGetEntrypoint.WithStack(err, nil)
 	}
 	entrypoint, ok := res.Content.(core.Runner)
 	if !ok {
 		err := fmt.Errorf("entrypoint type %T is not runnable because it lacks core.Runner interface", res.Content)
-		return ErrAppMainGetEntrypoint.WithStack(err, nil)
+		return nil, ErrAppMainGetEntrypoint.WithStack(err, nil)
 	}
+	return entrypoint, nil
+}
 
-	// Run the entrypoint runner.
-	if err := entrypoint.Run(ctx); err != nil {
-		return ErrAppMainRun.WithStack(err, nil)
+// runWithReload runs the entrypoint and reloads configs on SIGHUP signal.
+// Configs are also reloaded when the remote configs were changed
+// if the polling interval of the remote configs is configured.
+// The running entrypoint is replaced only when all resources
+// of the new configs were successfully created.
+// Global loggers and error handlers are restored when the reload failed.
+// The replaced entrypoint is stopped after the new one got ready
+// so that the in-flight requests are completed by the old resources
+// within the graceful shutdown period.
+// The old entrypoint keeps running when the new one failed before getting ready.
+// On SIGUSR2 signal, a new process of the same binary is started
+// with the listening sockets passed.
+// This process stops gracefully after the new process got ready.
+func (a *App) runWithReload(ctx context.Context, entrypoint core.Runner) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	usr2 := make(chan os.Signal, 1)
+	notifyUpgrade(usr2)
+	defer signal.Stop(usr2)
+
+	var wg sync.WaitGroup
+	defer wg.Wait() // Wait for replaced entrypoints to be stopped.
+
+	// start runs the entrypoint in a new goroutine.
+	// The returned ready channel is closed when the entrypoint got ready.
+	start := func(r core.Runner) (context.CancelFunc, <-chan error, <-chan struct{}) {
+		ready := make(chan struct{})
+		ctx, cancel := context.WithCancel(core.ContextWithReady(ctx, sync.OnceFunc(func() { close(ready) })))
+		done := make(chan error, 1)
+		wg.Go(func() {
+			done <- r.Run(ctx)
+		})
+		return cancel, done, ready
 	}
 
-	return nil
+	// Poll remote configs and reload when changed.
+	changed := make(chan struct{}, 1)
+	var remotes []string
+	for _, p := range slices.Concat(a.opts.Basic.Configs, a.opts.Basic.Patches) {
+		if isRemote(p) {
+			remotes = append(remotes, p)
+		}
+	}
+	if a.opts.Remote != nil && a.opts.Remote.Poll > 0 && len(remotes) > 0 {
+		pollCtx, stopPoll := context.WithCancel(ctx)
+		defer stopPoll()
+		go a.reader.remote().Poll(pollCtx, remotes, a.opts.Remote.Poll, changed)
+	}
+
+	cancel, done, _ := start(entrypoint)
+	defer func() { cancel() }()
+	reload := func() {
+		lg := log.GlobalLogger(log.DefaultLoggerName)
+		lg.Info(ctx, "reloading configs.")
+		// Global loggers and error handlers are replaced while creating the new resources.
+		// Restore them so that the running resources keep using the current ones.
+		restoreLoggers := log.SnapshotGlobalLoggers()
+		restoreHandlers := utilhttp.SnapshotGlobalErrorHandlers()
+		next, err := a.reload(ctx)
+		if err != nil {
+			restoreLoggers()
+			restoreHandlers()
+			err := ErrAppMainReload.WithStack(err, nil)
+			lg.Error(ctx, "failed to reload configs. keep running with the current configs.", err.Name(), err.Map())
+			return
+		}
+		// Stop the old entrypoint only after the new one got ready
+		// so that the old one keeps serving when the new one failed to start.
+		nextCancel, nextDone, ready := start(next)
+		select {
+		case <-ready:
+		case err := <-nextDone:
+			nextCancel()
+			restoreLoggers()
+			restoreHandlers()
+			if err == nil {
+				err = errors.New("entrypoint exited before getting ready")
+			}
+			e := ErrAppMainReload.WithStack(err, nil)
+			lg.Error(ctx, "failed to start reloaded configs. keep running with the current configs.", e.Name(), e.Map())
+			return
+		}
+		stop := cancel
+		cancel, done = nextCancel, nextDone
+		stop() // Stop the old entrypoint gracefully.
+		lg.Info(ctx, "configs reloaded.")
+	}
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return ErrAppMainRun.WithStack(err, nil)
+			}
+			return nil
+		case <-hup:
+			reload()
+		case <-changed:
+			reload()
+		case <-usr2:
+			lg := log.GlobalLogger(log.DefaultLoggerName)
+			lg.Info(ctx, "starting new process for upgrade.")
+			if err := upgrade(ctx); err != nil {
+				err := ErrAppMainUpgrade.WithStack(err, nil)
+				lg.Error(ctx, "failed to upgrade. keep running the current process.", err.Name(), err.Map())
+				continue
+			}
+			lg.Info(ctx, "new process is ready. shutting down the current process.")
+			cancel()
+			if err := <-done; err != nil {
+				return ErrAppMainRun.WithStack(err, nil)
+			}
+			return nil
+		}
+	}
+}
+
+// reload loads the configs again and returns a new entrypoint.
+// All resources are created in a new server returned by the newServer.
+// Listening sockets of the running servers are inherited by the new servers
+// so that no connections are refused while reloading.
+func (a *App) reload(ctx context.Context) (entrypoint core.Runner, err error) {
+	done := network.InheritSockets()
+	defer func() {
+		done(err != nil) // Close the created listeners on failure.
+	}()
+
+	server := a.newServer()
+	if err := zos.LoadEnv(a.opts.Basic.Envs...); err != nil {
+		return nil, ErrAppMainLoadEnv.WithStack(err, nil)
+	}
+	if err := a.reader.LoadConfigFiles(server, a.opts.Basic.Configs, a.opts.Basic.Patches...); err != nil {
+		return nil, err // Return err as-is.
+	}
+	return getEntrypoint(ctx, server)
 }
 
-// LoadConfigFiles load config files in json or yaml format from given file paths.
-// Currently only ".json", ".yaml" and ".yml" file extensions are supported.
-// Others will be ignored.
+// ConfigReader reads config files from local paths and remote URLs.
+// The zero value is ready to use with the default options.
+// A nil *ConfigReader is treated as the zero value.
+type ConfigReader struct {
+	// Remote loads the config files at http(s) URLs.
+	// A loader that allows only https URLs is used when nil.
+	Remote *RemoteLoader
+	// Jsonnet is the options to evaluate jsonnet files.
+	// No variables or library paths are given when nil.
+	Jsonnet *JsonnetOptions
+}
+
+// remote returns the loader of the remote config files.
+func (r *ConfigReader) remote() *RemoteLoader {
+	if r != nil && r.Remote != nil {
+		return r.Remote
+	}
+	l, _ := NewRemoteLoader(nil) // No errors without options.
+	return l
+}
+
+// jsonnet returns the options to evaluate jsonnet files.
+func (r *ConfigReader) jsonnet() *JsonnetOptions {
+	if r != nil && r.Jsonnet != nil {
+		return r.Jsonnet
+	}
+	return &JsonnetOptions{}
+}
+
+// LoadConfigFiles load config files in json, yaml or jsonnet format from given file paths.
+// Configs are read by a ConfigReader with the default options.
+// See ConfigReader.LoadConfigFiles for the details.
+// This function panics when the given server is nil.
+func LoadConfigFiles(server api.API[*api.Request, *api.Response], paths []string, patches ...string) error {
+	return (&ConfigReader{}).LoadConfigFiles(server, paths, patches...)
+}
+
+// LoadConfigFiles load config files in json, yaml or jsonnet format from given file paths.
+// Currently only ".json", ".yaml", ".yml" and ".jsonnet" file extensions are supported.
+// Others will be ignored. Jsonnet files are evaluated with the Jsonnet options of the reader.
+// Paths can be http(s) URLs of config files or tarballs loaded by the Remote of the reader.
+// Patches are the paths of the patch documents applied to the configs.
+// See applyPatches for the format of the patch documents.
+// Secret references such as "${file:/run/secrets/password}" are resolved
+// before the manifests are posted to the server. See the kernel/secret package.
 // This function panics when the given server is nil.
-func LoadConfigFiles(server api.API[*api.Request, *api.Response], paths []string) error {
-	configs, err := zos.ReadFiles(false, paths...)
+func (r *ConfigReader) LoadConfigFiles(server api.API[*api.Request, *api.Response], paths []string, patches ...string) error {
+	docs, err := r.readDocuments(paths)
 	if err != nil {
 		return ErrAppMainLoadConfigs.WithStack(err, nil)
 	}
+	docs, errs := r.applyPatches(docs, patches)
+	if len(errs) > 0 {
+		return ErrAppMainPatchConfigs.WithoutStack(nil, map[string]any{"reason": errs[0].String()})
+	}
 
-	for path, manifest := range configs {
-		var format api.Format
-		var unmarshalFunc func([]byte, any) error
-		if strings.HasSuffix(path, ".json") {
-			format = api.FormatJSON
-			unmarshalFunc = encoder.UnmarshalJSON
-		} else if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
-			format = api.FormatYAML
-			unmarshalFunc = encoder.UnmarshalYAML
-		} else {
-			continue // Ignore other formats.
-		}
-
-		manifest = bytes.ReplaceAll(manifest, []byte("\r\n"), []byte("\n"))
-		bs := SplitMultiDoc(manifest, "---\n")
-		for _, b := range bs {
-			into := &struct {
-				APIVersion string `json:"apiVersion" yaml:"apiVersion"`
-				Kind       string `json:"kind" yaml:"kind"`
-			}{}
-
-			if err := unmarshalFunc(b, into); err != nil {
-				return ErrAppMainLoadConfigs.WithStack(err, map[string]any{"path": path})
-			}
-
-			if into.APIVersion == "" && into.Kind == "" {
-				continue //Skip
-			}
-
-			req := &api.Request{
-				Method:  api.MethodPost,
-				Key:     into.APIVersion + "/" + into.Kind,
-				Format:  format,
-				Content: b,
-			}
+	for _, doc := range docs {
+		path, format, b := doc.file, doc.format, doc.content
+		into := &header{}
+		if err := unmarshal(format, b, into); err != nil {
+			return ErrAppMainLoadConfigs.WithStack(err, map[string]any{"path": path})
+		}
+		if into.APIVersion == "" && into.Kind == "" {
+			continue //Skip
+		}
 
-			if _, err := server.Serve(context.Background(), req); err != nil {
+		req := &api.Request{
+			Method:  api.MethodPost,
+			Key:     into.APIVersion + "/" + into.Kind,
+			Format:  format,
+			Content: b,
+		}
+		if refs := secret.FindReferences(b); len(refs) > 0 {
+			msg, err := resolveSecrets(server, format, into.APIVersion, into.Kind, b, refs, resolveSecret)
+			if err != nil {
 				return ErrAppMainLoadConfigs.WithStack(err, map[string]any{"path": path})
 			}
+			req.Format = api.FormatProtoMessage
+			req.Content = msg
+		}
+
+		if _, err := server.Serve(context.Background(), req); err != nil {
+			return ErrAppMainLoadConfigs.WithStack(err, map[string]any{"path": path})
 		}
 	}
 
@@ -215,6 +468,67 @@ func ShowTemplate(server api.API[*api.Request, *api.Response], tpl, out string)
 	Exit(0)
 }
 
+// ShowSchema shows the JSON schema of the resource.
+// The schema is "apiGroup/apiVersion/kind" or "all".
+// When "all" is given, the schemas of the given kinds
+// are merged into a single schema which accepts any of the kinds.
+// This function panics when the given server is nil.
+func ShowSchema(server api.API[*api.Request, *api.Response], schema string, kinds []string) {
+	if schema == "" {
+		return
+	}
+
+	schema = strings.Trim(schema, " ")
+	if schema != "all" {
+		kinds = []string{schema}
+	}
+
+	schemas := make([]map[string]any, 0, len(kinds))
+	for _, kind := range kinds {
+		arr := strings.Split(kind, "/")
+		if len(arr) != 3 {
+			fmt.Println("invalid schema format: " + kind)
+			fmt.Println("Should be \"apiGroup/apiVersion/kind\" or \"all\"")
+			Exit(2)
+			return
+		}
+		req := &api.Request{
+			Method: api.MethodGet,
+			Key:    kind,
+			Format: api.FormatProtoReference,
+			Params: map[string]string{api.KeyAccept: string(api.FormatJSONSchema)},
+			Content: &k.Reference{
+				APIVersion: arr[0] + "/" + arr[1],
+				Kind:       arr[2],
+				Namespace:  "template",
+				Name:       "template",
+			},
+		}
+		res, err := server.Serve(context.Background(), req)
+		if err != nil {
+			fmt.Println(err.Error())
+			Exit(2)
+			return
+		}
+		if schema != "all" {
+			fmt.Println(string(res.Content.([]byte)))
+			Exit(0)
+			return
+		}
+		var s map[string]any
+		if err := json.Unmarshal(res.Content.([]byte), &s); err != nil {
+			fmt.Println(err.Error())
+			Exit(2)
+			return
+		}
+		schemas = append(schemas, s)
+	}
+
+	b, _ := json.MarshalIndent(api.MergeJSONSchemas(schemas), "", "  ")
+	fmt.Println(string(b))
+	Exit(0)
+}
+
 // SplitMultiDoc splits a documents in []byte format with a given separator.
 // If an empty separator is given, the default separator "---\n" is used.
 // Empty contents are ignored.
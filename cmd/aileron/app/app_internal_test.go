@@ -6,13 +6,19 @@ package app
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"regexp"
+	"runtime"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/aileron-gateway/aileron-gateway/core"
 	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/pflag"
 )
@@ -243,3 +249,192 @@ func TestApp_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_Run_validate(t *testing.T) {
+	exitCode := -1
+	Exit = func(code int) { exitCode = code }
+	defer func() {
+		Exit = os.Exit
+	}()
+
+	a := &App{opts: ParseArgs([]string{"validate"})}
+	testutil.Diff(t, CommandValidate, a.opts.Command)
+	entrypoint := &testEntrypoint{}
+	err := a.Run(&runTestServer{res: &api.Response{Content: entrypoint}})
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, 0, exitCode)
+	testutil.Diff(t, false, entrypoint.called)
+}
+
+// reloadTestEntrypoint is an entrypoint that
+// gets ready and blocks until the given context is done.
+// It fails before getting ready when err is not nil.
+type reloadTestEntrypoint struct {
+	started chan struct{}
+	stopped chan struct{}
+	err     error
+}
+
+func (e *reloadTestEntrypoint) Run(ctx context.Context) error {
+	close(e.started)
+	if e.err != nil {
+		return e.err // Failed before getting ready.
+	}
+	core.Ready(ctx)
+	<-ctx.Done()
+	close(e.stopped)
+	return nil
+}
+
+func newReloadTestEntrypoint() *reloadTestEntrypoint {
+	return &reloadTestEntrypoint{
+		started: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func TestApp_runWithReload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not available on windows")
+	}
+
+	type condition struct {
+		server *runTestServer
+	}
+
+	type action struct {
+		reloaded bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"reload succeeded",
+			&condition{
+				server: &runTestServer{
+					res: &api.Response{Content: newReloadTestEntrypoint()},
+				},
+			},
+			&action{
+				reloaded: true,
+			},
+		),
+		gen(
+			"reload failed",
+			&condition{
+				server: &runTestServer{
+					err: errors.New("test server error"),
+				},
+			},
+			&action{
+				reloaded: false,
+			},
+		),
+		gen(
+			"reloaded entrypoint failed before getting ready",
+			&condition{
+				server: &runTestServer{
+					res: &api.Response{Content: &reloadTestEntrypoint{
+						started: make(chan struct{}),
+						stopped: make(chan struct{}),
+						err:     errors.New("test initializer error"),
+					}},
+				},
+			},
+			&action{
+				reloaded: false,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			called := make(chan struct{})
+			a := &App{
+				opts: &Options{
+					Metadata: &MetadataOptions{},
+					Basic:    &BasicOptions{},
+				},
+				newServer: func() api.API[*api.Request, *api.Response] {
+					defer close(called)
+					return tt.C.server
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			first := newReloadTestEntrypoint()
+			errChan := make(chan error)
+			go func() { errChan <- a.runWithReload(ctx, first) }()
+
+			<-first.started
+			p, _ := os.FindProcess(os.Getpid())
+			testutil.Diff(t, nil, p.Signal(syscall.SIGHUP))
+			<-called
+
+			if tt.A.reloaded {
+				next := tt.C.server.res.Content.(*reloadTestEntrypoint)
+				<-next.started
+				<-first.stopped // Old entrypoint must be stopped.
+			} else {
+				select {
+				case <-first.stopped:
+					t.Error("entrypoint stopped by failed reload")
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
+			cancel()
+			testutil.Diff(t, nil, <-errChan)
+		})
+	}
+}
+
+// globalsTestServer is a server that replaces the global
+// logger and error handler and fails as entrypoints do on failed reloads.
+type globalsTestServer struct{}
+
+func (s *globalsTestServer) Serve(ctx context.Context, req *api.Request) (*api.Response, error) {
+	log.SetGlobalLogger(log.DefaultLoggerName, log.NewJSONSLogger(io.Discard, nil))
+	log.SetGlobalLogger("reload", log.NewJSONSLogger(io.Discard, nil))
+	utilhttp.SetGlobalErrorHandler(utilhttp.DefaultErrorHandlerName, &utilhttp.DefaultErrorHandler{})
+	return nil, errors.New("test server error")
+}
+
+func TestApp_runWithReload_restoreGlobals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not available on windows")
+	}
+
+	lg := log.GlobalLogger(log.DefaultLoggerName)
+	eh := utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName)
+
+	called := make(chan struct{})
+	a := &App{
+		opts: &Options{
+			Metadata: &MetadataOptions{},
+			Basic:    &BasicOptions{},
+		},
+		newServer: func() api.API[*api.Request, *api.Response] {
+			defer close(called)
+			return &globalsTestServer{}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := newReloadTestEntrypoint()
+	errChan := make(chan error)
+	go func() { errChan <- a.runWithReload(ctx, first) }()
+
+	<-first.started
+	p, _ := os.FindProcess(os.Getpid())
+	testutil.Diff(t, nil, p.Signal(syscall.SIGHUP))
+	<-called
+
+	cancel()
+	testutil.Diff(t, nil, <-errChan)
+	testutil.Diff(t, true, log.GlobalLogger(log.DefaultLoggerName) == lg)
+	testutil.Diff(t, nil, log.GlobalLogger("reload"))
+	testutil.Diff(t, true, utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName) == eh)
+}
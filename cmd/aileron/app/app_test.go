@@ -430,6 +430,167 @@ func TestShowTemplate(t *testing.T) {
 	}
 }
 
+func TestShowSchema(t *testing.T) {
+	type condition struct {
+		server *testServer
+		schema string
+		kinds  []string
+	}
+
+	type action struct {
+		shouldExit bool
+		exitCode   int
+		contains   string
+		reqs       []*api.Request
+	}
+
+	schemaReq := func(apiVersion, kind string) *api.Request {
+		return &api.Request{
+			Method: api.MethodGet,
+			Key:    apiVersion + "/" + kind,
+			Params: map[string]string{api.KeyAccept: string(api.FormatJSONSchema)},
+			Format: api.FormatProtoReference,
+			Content: &k.Reference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+				Namespace:  "template",
+				Name:       "template",
+			},
+		}
+	}
+	kindSchema := []byte(`{"title":"test","properties":{"apiVersion":{"const":"group/v1"},"kind":{"const":"kind"}}}`)
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"input nil",
+			&condition{
+				server: &testServer{
+					res: &api.Response{Content: kindSchema},
+				},
+				schema: "",
+			},
+			&action{
+				shouldExit: false,
+				reqs:       nil,
+			},
+		),
+		gen(
+			"apiGroup/apiVersion/kind",
+			&condition{
+				server: &testServer{
+					res: &api.Response{Content: kindSchema},
+				},
+				schema: "group/v1/kind",
+				kinds:  []string{"group/v1/other"},
+			},
+			&action{
+				shouldExit: true,
+				exitCode:   0,
+				contains:   string(kindSchema),
+				reqs:       []*api.Request{schemaReq("group/v1", "kind")},
+			},
+		),
+		gen(
+			"all kinds",
+			&condition{
+				server: &testServer{
+					res: &api.Response{Content: kindSchema},
+				},
+				schema: "all",
+				kinds:  []string{"group/v1/kind", "group/v2/other"},
+			},
+			&action{
+				shouldExit: true,
+				exitCode:   0,
+				contains:   `"allOf"`,
+				reqs: []*api.Request{
+					schemaReq("group/v1", "kind"),
+					schemaReq("group/v2", "other"),
+				},
+			},
+		),
+		gen(
+			"invalid schema",
+			&condition{
+				server: &testServer{
+					res: &api.Response{Content: kindSchema},
+				},
+				schema: "test1",
+			},
+			&action{
+				shouldExit: true,
+				exitCode:   2,
+				contains:   "invalid schema",
+				reqs:       nil,
+			},
+		),
+		gen(
+			"invalid schema returned",
+			&condition{
+				server: &testServer{
+					res: &api.Response{Content: []byte("not a json")},
+				},
+				schema: "all",
+				kinds:  []string{"group/v1/kind"},
+			},
+			&action{
+				shouldExit: true,
+				exitCode:   2,
+				contains:   "invalid character",
+				reqs:       []*api.Request{schemaReq("group/v1", "kind")},
+			},
+		),
+		gen(
+			"server error",
+			&condition{
+				server: &testServer{
+					err: errors.New("test server error"),
+				},
+				schema: "group/v1/kind",
+			},
+			&action{
+				shouldExit: true,
+				exitCode:   2,
+				contains:   "test server error",
+				reqs:       []*api.Request{schemaReq("group/v1", "kind")},
+			},
+		),
+	}
+
+	// Set default exit function at the end of this test.
+	defer func() {
+		app.Exit = os.Exit
+	}()
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tmp := os.Stdout
+			defer func() {
+				os.Stdout = tmp
+			}()
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			exited := false
+			app.Exit = func(code int) {
+				exited = true
+				testutil.Diff(t, tt.A.exitCode, code)
+			}
+
+			app.ShowSchema(tt.C.server, tt.C.schema, tt.C.kinds)
+			testutil.Diff(t, tt.A.shouldExit, exited)
+			testutil.Diff(t, tt.A.reqs, tt.C.server.reqs, cmpopts.IgnoreUnexported(k.Reference{}))
+
+			w.Close()
+
+			out, err := io.ReadAll(r)
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, true, strings.Contains(string(out), tt.A.contains))
+		})
+	}
+}
+
 func TestSplitMultiDoc(t *testing.T) {
 	type condition struct {
 		docs []byte
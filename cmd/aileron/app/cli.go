@@ -6,6 +6,8 @@ package app
 import (
 	"fmt"
 	"runtime/debug"
+	"slices"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -17,11 +19,21 @@ func ParseArgs(args []string, custom ...*pflag.FlagSet) *Options {
 	opts := &Options{
 		Metadata: &MetadataOptions{},
 		Basic:    &BasicOptions{},
+		Remote:   &RemoteOptions{},
+		Jsonnet:  &JsonnetOptions{},
+	}
+
+	// The first argument can be a command.
+	if len(args) > 0 && slices.Contains([]string{CommandValidate, CommandGraph, CommandDump}, args[0]) {
+		opts.Command = args[0]
+		args = args[1:]
 	}
 
 	root := pflag.NewFlagSet("root", pflag.ContinueOnError)
 	root.AddFlagSet(opts.Metadata.FlagSet())
 	root.AddFlagSet(opts.Basic.FlagSet())
+	root.AddFlagSet(opts.Remote.FlagSet())
+	root.AddFlagSet(opts.Jsonnet.FlagSet())
 	for _, c := range custom {
 		root.AddFlagSet(c) // Add custom flags.
 	}
@@ -61,6 +73,11 @@ func ParseArgs(args []string, custom ...*pflag.FlagSet) *Options {
 	}
 
 	if opts.Metadata.Help {
+		fmt.Println("Commands :")
+		fmt.Println("      " + CommandValidate + "   validate config files given by --file and exit")
+		fmt.Println("      " + CommandGraph + "      show resource dependency graph in dot or json given by --out and exit")
+		fmt.Println("      " + CommandDump + "       show configs merged with --patch in yaml or json given by --out and exit")
+		fmt.Println("")
 		fmt.Println("Options :")
 		fmt.Println(root.FlagUsages())
 		Exit(0)
@@ -70,9 +87,29 @@ func ParseArgs(args []string, custom ...*pflag.FlagSet) *Options {
 	return opts
 }
 
+const (
+	// CommandValidate is the command that validates
+	// config files without running the application.
+	// Use it like "aileron validate -f config.yaml".
+	CommandValidate = "validate"
+	// CommandGraph is the command that shows the dependency graph
+	// of the resources without running the application.
+	// Use it like "aileron graph -f config.yaml -o dot".
+	CommandGraph = "graph"
+	// CommandDump is the command that shows the configs
+	// with the patches applied without running the application.
+	// Use it like "aileron dump -f base/ -p prod/ -o yaml".
+	CommandDump = "dump"
+)
+
 type Options struct {
 	Metadata *MetadataOptions
 	Basic    *BasicOptions
+	Remote   *RemoteOptions
+	Jsonnet  *JsonnetOptions
+	// Command is the command given as the first argument
+	// such as "validate". Empty when no command was given.
+	Command string
 }
 
 type MetadataOptions struct {
@@ -91,16 +128,81 @@ func (o *MetadataOptions) FlagSet() *pflag.FlagSet {
 
 type BasicOptions struct {
 	Configs  []string
+	Patches  []string
 	Envs     []string
 	Template string
+	Schema   string
 	Out      string
 }
 
 func (o *BasicOptions) FlagSet() *pflag.FlagSet {
 	fs := pflag.NewFlagSet("basic", pflag.ContinueOnError)
-	fs.StringArrayVarP(&o.Configs, "file", "f", []string{}, "config file or directory path. absolute or relative")
+	fs.StringArrayVarP(&o.Configs, "file", "f", []string{}, "config file or directory path. absolute or relative. or http(s) URL of a config file or a tarball. .json, .yaml, .yml and .jsonnet are loaded")
+	fs.StringArrayVarP(&o.Patches, "patch", "p", []string{}, "patch file or directory path, or http(s) URL. patches are applied to the configs given by --file")
 	fs.StringArrayVarP(&o.Envs, "env", "e", []string{}, "env file path. each line be 'KEY=VALUE'")
 	fs.StringVarP(&o.Template, "template", "t", "", "show template config. value format be 'Group/Version/Kind(/Namespace/Name)'")
-	fs.StringVarP(&o.Out, "out", "o", "yaml", "template output format. yaml or json")
+	fs.StringVarP(&o.Schema, "schema", "s", "", "show JSON schema. value format be 'Group/Version/Kind' or 'all' for all kinds")
+	fs.StringVarP(&o.Out, "out", "o", "yaml", "output format. yaml or json for templates and dumps. dot or json for graphs")
+	return fs
+}
+
+// RemoteOptions is the options to load config files from remote URLs.
+type RemoteOptions struct {
+	// Headers are the HTTP headers in the format of "Name: Value".
+	// Environmental variables and secret references in the values are resolved.
+	Headers []string
+	// RootCAs are the paths of root CA certificates in PEM format.
+	// System root CAs are used when not set.
+	RootCAs []string
+	// CertFile and KeyFile are the paths of the client certificate and key in PEM format.
+	CertFile string
+	KeyFile  string
+	// VerifyKey is the path of the public key in PEM format.
+	// When set, detached signatures at "<URL>.sig" are verified.
+	VerifyKey string
+	// Insecure allows plain HTTP URLs without verifying signatures.
+	// Plain HTTP URLs are allowed only when VerifyKey is set by default.
+	Insecure bool
+	// Poll is the interval to poll the remote configs.
+	// Configs are reloaded when changed. Polling is disabled when 0.
+	Poll time.Duration
+}
+
+func (o *RemoteOptions) FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("remote", pflag.ContinueOnError)
+	fs.StringArrayVar(&o.Headers, "remote-header", []string{}, "HTTP header sent to get remote configs. each be 'Name: Value'")
+	fs.StringArrayVar(&o.RootCAs, "remote-ca", []string{}, "root CA file to verify servers of remote configs")
+	fs.StringVar(&o.CertFile, "remote-cert", "", "client certificate file to get remote configs")
+	fs.StringVar(&o.KeyFile, "remote-key", "", "client key file to get remote configs")
+	fs.StringVar(&o.VerifyKey, "remote-verify-key", "", "public key file to verify detached signatures of remote configs at '<URL>.sig'")
+	fs.BoolVar(&o.Insecure, "remote-insecure", false, "allow http URLs of remote configs without --remote-verify-key")
+	fs.DurationVar(&o.Poll, "remote-poll", 0, "interval to poll remote configs. configs are reloaded on change. 0 disables polling")
+	return fs
+}
+
+// JsonnetOptions is the options to evaluate jsonnet config files.
+// Variables are given in the format of "key=value".
+// The value is read from the environmental variable
+// of the same name when given only the key.
+type JsonnetOptions struct {
+	// ExtStrs and ExtCodes are the external variables
+	// referred by std.extVar in string and in jsonnet code.
+	ExtStrs  []string
+	ExtCodes []string
+	// TLAStrs and TLACodes are the top-level arguments
+	// in string and in jsonnet code.
+	TLAStrs  []string
+	TLACodes []string
+	// JPaths are the library search paths.
+	JPaths []string
+}
+
+func (o *JsonnetOptions) FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("jsonnet", pflag.ContinueOnError)
+	fs.StringArrayVar(&o.ExtStrs, "ext-str", []string{}, "jsonnet external variable in string. each be 'key=value' or 'key' to read env")
+	fs.StringArrayVar(&o.ExtCodes, "ext-code", []string{}, "jsonnet external variable in code. each be 'key=code' or 'key' to read env")
+	fs.StringArrayVar(&o.TLAStrs, "tla-str", []string{}, "jsonnet top-level argument in string. each be 'key=value' or 'key' to read env")
+	fs.StringArrayVar(&o.TLACodes, "tla-code", []string{}, "jsonnet top-level argument in code. each be 'key=code' or 'key' to read env")
+	fs.StringArrayVar(&o.JPaths, "jpath", []string{}, "jsonnet library search path")
 	return fs
 }
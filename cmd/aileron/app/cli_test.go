@@ -43,6 +43,46 @@ func TestParseArgs(t *testing.T) {
 				checkOutput: []string{},
 			},
 		),
+		gen(
+			"validate command",
+			&condition{
+				args: []string{"validate", "-f", "config.yaml"},
+			},
+			&action{
+				shouldExit:  false,
+				checkOutput: []string{},
+			},
+		),
+		gen(
+			"schema flag",
+			&condition{
+				args: []string{"-s", "core/v1/HTTPServer"},
+			},
+			&action{
+				shouldExit:  false,
+				checkOutput: []string{},
+			},
+		),
+		gen(
+			"graph command",
+			&condition{
+				args: []string{"graph", "-f", "config.yaml", "-o", "json"},
+			},
+			&action{
+				shouldExit:  false,
+				checkOutput: []string{},
+			},
+		),
+		gen(
+			"dump command",
+			&condition{
+				args: []string{"dump", "-f", "config.yaml", "-p", "patch.yaml"},
+			},
+			&action{
+				shouldExit:  false,
+				checkOutput: []string{},
+			},
+		),
 		gen(
 			"version flag",
 			&condition{
@@ -78,6 +118,10 @@ func TestParseArgs(t *testing.T) {
 				shouldExit: true,
 				exitCode:   0,
 				checkOutput: []string{
+					"Commands :",
+					"validate",
+					"graph",
+					"dump",
 					"Options :",
 				},
 			},
@@ -211,7 +255,9 @@ func TestBasicOptions(t *testing.T) {
 			&action{
 				flags: []string{
 					"-f, --file stringArray",
+					"-p, --patch stringArray",
 					"-e, --env stringArray",
+					"-s, --schema string",
 				},
 			},
 		),
@@ -232,3 +278,87 @@ func TestBasicOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteOptions(t *testing.T) {
+	type condition struct {
+	}
+
+	type action struct {
+		flags []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"check registered flags",
+			&condition{},
+			&action{
+				flags: []string{
+					"--remote-header stringArray",
+					"--remote-ca stringArray",
+					"--remote-cert string",
+					"--remote-key string",
+					"--remote-verify-key string",
+					"--remote-insecure",
+					"--remote-poll duration",
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			opt := &app.RemoteOptions{}
+			flg := opt.FlagSet()
+			usage := flg.FlagUsages()
+
+			for _, s := range tt.A.flags {
+				t.Log("expect contains", s)
+				t.Log("but got", usage)
+				testutil.Diff(t, true, strings.Contains(usage, s))
+			}
+		})
+	}
+}
+
+func TestJsonnetOptions(t *testing.T) {
+	type condition struct {
+	}
+
+	type action struct {
+		flags []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"check registered flags",
+			&condition{},
+			&action{
+				flags: []string{
+					"--ext-str stringArray",
+					"--ext-code stringArray",
+					"--tla-str stringArray",
+					"--tla-code stringArray",
+					"--jpath stringArray",
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			opt := &app.JsonnetOptions{}
+			flg := opt.FlagSet()
+			usage := flg.FlagUsages()
+
+			for _, s := range tt.A.flags {
+				t.Log("expect contains", s)
+				t.Log("but got", usage)
+				testutil.Diff(t, true, strings.Contains(usage, s))
+			}
+		})
+	}
+}
@@ -13,4 +13,8 @@ var (
 	ErrAppMainLoadEnv       = errorutil.NewKind("E1004", "AppMainLoadEnv", "failed to load environmental variables. {{path}}")
 	ErrAppMainLoadConfigs   = errorutil.NewKind("E1005", "AppMainLoadConfigs", "failed to load configs. {{path}} {{content}}")
 	ErrAppMainGetEntrypoint = errorutil.NewKind("E1006", "AppMainGetEntrypoint", "failed to get entrypoint resource")
+	ErrAppMainReload        = errorutil.NewKind("E1007", "AppMainReload", "failed to reload configs")
+	ErrAppMainUpgrade       = errorutil.NewKind("E1008", "AppMainUpgrade", "failed to upgrade process")
+	ErrAppMainResolveSecret = errorutil.NewKind("E1009", "AppMainResolveSecret", "failed to resolve secret. {{path}} {{reason}}")
+	ErrAppMainPatchConfigs  = errorutil.NewKind("E1010", "AppMainPatchConfigs", "failed to apply patch. {{reason}}")
 )
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+)
+
+// entrypointKind is the "APIGroup/APIVersion/Kind" of the entrypoint.
+// Entrypoints are the roots of the graph and never be orphans.
+const entrypointKind = "core/v1/Entrypoint"
+
+// Graph is the dependency graph of resources.
+// Nodes are the resources and edges are the kernel.Reference
+// from the referring resources to the referred resources.
+type Graph struct {
+	// Nodes are the resources sorted by their IDs.
+	Nodes []*GraphNode `json:"nodes"`
+	// Edges are the references sorted by the referring resources
+	// and by the order of the fields in the manifests.
+	Edges []*GraphEdge `json:"edges"`
+	// Orphans are the IDs of the resources
+	// that are not referred from any other resources.
+	// Entrypoints are not considered as orphans.
+	Orphans []string `json:"orphans"`
+	// Cycles are the IDs of the resources which refer each other.
+	// Each cycle is a strongly connected component of the graph
+	// and the IDs are sorted.
+	Cycles [][]string `json:"cycles"`
+}
+
+// GraphNode is a resource in the graph.
+type GraphNode struct {
+	// ID is the resource ID in the format of
+	// "APIGroup/APIVersion/Kind/Namespace/Name".
+	ID         string `json:"id"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	// Missing is true when the resource is referred
+	// but its manifest was not found.
+	Missing bool `json:"missing,omitempty"`
+	// Orphan is true when the resource is not referred from any resources.
+	Orphan bool `json:"orphan,omitempty"`
+	// Cyclic is true when the resource is in a cycle.
+	Cyclic bool `json:"cyclic,omitempty"`
+}
+
+// GraphEdge is a reference in the graph.
+type GraphEdge struct {
+	// From is the ID of the referring resource.
+	From string `json:"from"`
+	// To is the ID of the referred resource.
+	To string `json:"to"`
+	// Path is the JSON path of the reference
+	// in the manifest of the referring resource.
+	Path string `json:"path"`
+}
+
+// ShowGraph shows the dependency graph of the resources
+// loaded to the server and exit.
+// The graph is shown in JSON when the out is "json",
+// otherwise in Graphviz DOT.
+// This function panics when the given server is nil.
+func ShowGraph(server api.API[*api.Request, *api.Response], out string) {
+	g, err := BuildGraph(server)
+	if err != nil {
+		fmt.Println(err.Error())
+		Exit(1)
+		return
+	}
+	if out == "json" {
+		b, _ := json.MarshalIndent(g, "", "  ")
+		fmt.Println(string(b))
+	} else {
+		fmt.Print(g.DOT())
+	}
+	Exit(0)
+}
+
+// BuildGraph builds the dependency graph of the resources
+// stored in the server. No resources are created by building the graph.
+// This function panics when the given server is nil.
+func BuildGraph(server api.API[*api.Request, *api.Response]) (*Graph, error) {
+	manifests, err := listManifests(server)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*GraphNode{}
+	for id := range manifests {
+		nodes[id] = newGraphNode(id)
+	}
+	g := &Graph{
+		Nodes:   []*GraphNode{},
+		Edges:   []*GraphEdge{},
+		Orphans: []string{},
+		Cycles:  [][]string{},
+	}
+	referred := map[string]struct{}{}
+	adjacent := map[string][]string{}
+	for id, msg := range manifests {
+		api.WalkReferences(msg, func(path string, ref *k.Reference) {
+			to := api.ReferenceID(ref)
+			if _, ok := nodes[to]; !ok {
+				n := newGraphNode(to)
+				n.Missing = true
+				nodes[to] = n
+			}
+			g.Edges = append(g.Edges, &GraphEdge{From: id, To: to, Path: path})
+			adjacent[id] = append(adjacent[id], to)
+			if to != id {
+				referred[to] = struct{}{}
+			}
+		})
+	}
+	slices.SortStableFunc(g.Edges, func(x, y *GraphEdge) int {
+		return strings.Compare(x.From, y.From) // Keep the order of the paths.
+	})
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		n := nodes[id]
+		g.Nodes = append(g.Nodes, n)
+		if _, ok := referred[id]; !ok && !n.Missing && !strings.HasPrefix(id, entrypointKind+"/") {
+			n.Orphan = true
+			g.Orphans = append(g.Orphans, id)
+		}
+	}
+	for _, cycle := range findCycles(ids, adjacent) {
+		for _, id := range cycle {
+			nodes[id].Cyclic = true
+		}
+		g.Cycles = append(g.Cycles, cycle)
+	}
+	return g, nil
+}
+
+// newGraphNode returns a new node of the resource ID.
+// The ID must be in the format of "APIGroup/APIVersion/Kind/Namespace/Name".
+func newGraphNode(id string) *GraphNode {
+	n := &GraphNode{ID: id}
+	if arr := strings.Split(id, "/"); len(arr) == 5 {
+		n.APIVersion = arr[0] + "/" + arr[1]
+		n.Kind = arr[2]
+		n.Namespace = arr[3]
+		n.Name = arr[4]
+	}
+	return n
+}
+
+// findCycles returns the cycles in the graph
+// using the Tarjan's strongly connected components algorithm.
+// Components which consist of multiple nodes
+// or a single node referring itself are returned as cycles.
+// Nodes in each cycle are sorted and cycles are sorted by their first node.
+func findCycles(ids []string, adjacent map[string][]string) [][]string {
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+
+	var connect func(v string)
+	connect = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range adjacent[v] {
+			if _, ok := index[w]; !ok {
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+		if lowlink[v] != index[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(adjacent[v], v) {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, id := range ids {
+		if _, ok := index[id]; !ok {
+			connect(id)
+		}
+	}
+	slices.SortFunc(cycles, func(x, y []string) int {
+		return strings.Compare(x[0], y[0])
+	})
+	return cycles
+}
+
+// DOT returns the graph in the Graphviz DOT language.
+// Missing resources are drawn with dashed red lines,
+// orphan resources with dashed gray lines
+// and references in cycles with red lines.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph aileron {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	cyclic := map[string]int{}
+	for i, cycle := range g.Cycles {
+		for _, id := range cycle {
+			cyclic[id] = i
+		}
+	}
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + strconv.Quote(n.Kind+"\n"+n.Namespace+"/"+n.Name)}
+		switch {
+		case n.Missing:
+			attrs = append(attrs, `style=dashed`, `color=red`, `xlabel="missing"`)
+		case n.Orphan:
+			attrs = append(attrs, `style=dashed`, `color=gray`, `xlabel="orphan"`)
+		}
+		if n.Cyclic {
+			attrs = append(attrs, `color=red`)
+		}
+		b.WriteString("  " + strconv.Quote(n.ID) + " [" + strings.Join(attrs, ", ") + "];\n")
+	}
+	for _, e := range g.Edges {
+		attrs := []string{"label=" + strconv.Quote(e.Path)}
+		i, ok1 := cyclic[e.From]
+		j, ok2 := cyclic[e.To]
+		if ok1 && ok2 && i == j {
+			attrs = append(attrs, `color=red`)
+		}
+		b.WriteString("  " + strconv.Quote(e.From) + " -> " + strconv.Quote(e.To) + " [" + strings.Join(attrs, ", ") + "];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/proto"
+)
+
+func ref(kind, name string) *k.Reference {
+	return &k.Reference{APIVersion: "core/v1", Kind: kind, Name: name}
+}
+
+func manifest(kind, name string, msg proto.Message) *api.Manifest {
+	return &api.Manifest{ID: "core/v1/" + kind + "/default/" + name, Message: msg}
+}
+
+func TestBuildGraph(t *testing.T) {
+	type condition struct {
+		server *testServer
+	}
+
+	type action struct {
+		graph      *app.Graph
+		err        error
+		errPattern *regexp.Regexp
+	}
+
+	testErr := errors.New("test error")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no manifests",
+			&condition{
+				server: &testServer{res: &api.Response{Content: []*api.Manifest{}}},
+			},
+			&action{
+				graph: &app.Graph{
+					Nodes:   []*app.GraphNode{},
+					Edges:   []*app.GraphEdge{},
+					Orphans: []string{},
+					Cycles:  [][]string{},
+				},
+			},
+		),
+		gen(
+			"list error",
+			&condition{
+				server: &testServer{err: testErr},
+			},
+			&action{
+				err:        testErr,
+				errPattern: regexp.MustCompile(`test error`),
+			},
+		),
+		gen(
+			"orphans, missing and cycles",
+			&condition{
+				server: &testServer{res: &api.Response{Content: []*api.Manifest{
+					manifest("Entrypoint", "default", &v1.Entrypoint{Spec: &v1.EntrypointSpec{
+						Runners: []*k.Reference{ref("HTTPServer", "a")},
+					}}),
+					manifest("HTTPServer", "a", &v1.HTTPServer{Spec: &v1.HTTPServerSpec{
+						Middleware: []*k.Reference{ref("HTTPHandler", "b"), ref("HTTPHandler", "missing")},
+					}}),
+					manifest("HTTPHandler", "b", &v1.HTTPHandler{Spec: &v1.HTTPHandlerSpec{
+						Middleware: []*k.Reference{ref("HTTPHandler", "c")},
+					}}),
+					manifest("HTTPHandler", "c", &v1.HTTPHandler{Spec: &v1.HTTPHandlerSpec{
+						Middleware: []*k.Reference{ref("HTTPHandler", "b")},
+					}}),
+					manifest("HTTPHandler", "self", &v1.HTTPHandler{Spec: &v1.HTTPHandlerSpec{
+						Middleware: []*k.Reference{ref("HTTPHandler", "self")},
+					}}),
+					manifest("HTTPServer", "orphan", &v1.HTTPServer{}),
+				}}},
+			},
+			&action{
+				graph: &app.Graph{
+					Nodes: []*app.GraphNode{
+						{ID: "core/v1/Entrypoint/default/default", APIVersion: "core/v1", Kind: "Entrypoint", Namespace: "default", Name: "default"},
+						{ID: "core/v1/HTTPHandler/default/b", APIVersion: "core/v1", Kind: "HTTPHandler", Namespace: "default", Name: "b", Cyclic: true},
+						{ID: "core/v1/HTTPHandler/default/c", APIVersion: "core/v1", Kind: "HTTPHandler", Namespace: "default", Name: "c", Cyclic: true},
+						{ID: "core/v1/HTTPHandler/default/missing", APIVersion: "core/v1", Kind: "HTTPHandler", Namespace: "default", Name: "missing", Missing: true},
+						{ID: "core/v1/HTTPHandler/default/self", APIVersion: "core/v1", Kind: "HTTPHandler", Namespace: "default", Name: "self", Orphan: true, Cyclic: true},
+						{ID: "core/v1/HTTPServer/default/a", APIVersion: "core/v1", Kind: "HTTPServer", Namespace: "default", Name: "a"},
+						{ID: "core/v1/HTTPServer/default/orphan", APIVersion: "core/v1", Kind: "HTTPServer", Namespace: "default", Name: "orphan", Orphan: true},
+					},
+					Edges: []*app.GraphEdge{
+						{From: "core/v1/Entrypoint/default/default", To: "core/v1/HTTPServer/default/a", Path: "spec.runners[0]"},
+						{From: "core/v1/HTTPHandler/default/b", To: "core/v1/HTTPHandler/default/c", Path: "spec.middleware[0]"},
+						{From: "core/v1/HTTPHandler/default/c", To: "core/v1/HTTPHandler/default/b", Path: "spec.middleware[0]"},
+						{From: "core/v1/HTTPHandler/default/self", To: "core/v1/HTTPHandler/default/self", Path: "spec.middleware[0]"},
+						{From: "core/v1/HTTPServer/default/a", To: "core/v1/HTTPHandler/default/b", Path: "spec.middleware[0]"},
+						{From: "core/v1/HTTPServer/default/a", To: "core/v1/HTTPHandler/default/missing", Path: "spec.middleware[1]"},
+					},
+					Orphans: []string{"core/v1/HTTPHandler/default/self", "core/v1/HTTPServer/default/orphan"},
+					Cycles: [][]string{
+						{"core/v1/HTTPHandler/default/b", "core/v1/HTTPHandler/default/c"},
+						{"core/v1/HTTPHandler/default/self"},
+					},
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			g, err := app.BuildGraph(tt.C.server)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err, cmpopts.EquateErrors())
+			testutil.Diff(t, tt.A.graph, g)
+		})
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	g := &app.Graph{
+		Nodes: []*app.GraphNode{
+			{ID: "core/v1/HTTPServer/default/a", Kind: "HTTPServer", Namespace: "default", Name: "a", Orphan: true},
+			{ID: "core/v1/HTTPHandler/default/b", Kind: "HTTPHandler", Namespace: "default", Name: "b", Cyclic: true},
+			{ID: "core/v1/HTTPHandler/default/c", Kind: "HTTPHandler", Namespace: "default", Name: "c", Missing: true},
+		},
+		Edges: []*app.GraphEdge{
+			{From: "core/v1/HTTPServer/default/a", To: "core/v1/HTTPHandler/default/b", Path: "spec.middleware[0]"},
+			{From: "core/v1/HTTPHandler/default/b", To: "core/v1/HTTPHandler/default/b", Path: "spec.middleware[0]"},
+			{From: "core/v1/HTTPHandler/default/b", To: "core/v1/HTTPHandler/default/c", Path: "spec.middleware[1]"},
+		},
+		Cycles: [][]string{{"core/v1/HTTPHandler/default/b"}},
+	}
+	want := `digraph aileron {
+  rankdir=LR;
+  node [shape=box];
+  "core/v1/HTTPServer/default/a" [label="HTTPServer\ndefault/a", style=dashed, color=gray, xlabel="orphan"];
+  "core/v1/HTTPHandler/default/b" [label="HTTPHandler\ndefault/b", color=red];
+  "core/v1/HTTPHandler/default/c" [label="HTTPHandler\ndefault/c", style=dashed, color=red, xlabel="missing"];
+  "core/v1/HTTPServer/default/a" -> "core/v1/HTTPHandler/default/b" [label="spec.middleware[0]"];
+  "core/v1/HTTPHandler/default/b" -> "core/v1/HTTPHandler/default/b" [label="spec.middleware[0]", color=red];
+  "core/v1/HTTPHandler/default/b" -> "core/v1/HTTPHandler/default/c" [label="spec.middleware[1]"];
+}
+`
+	testutil.Diff(t, want, g.DOT())
+}
+
+func TestShowGraph(t *testing.T) {
+	type condition struct {
+		server *testServer
+		out    string
+	}
+
+	type action struct {
+		exitCode int
+		contains string
+	}
+
+	manifests := []*api.Manifest{
+		manifest("Entrypoint", "default", &v1.Entrypoint{Spec: &v1.EntrypointSpec{
+			Runners: []*k.Reference{ref("HTTPServer", "a")},
+		}}),
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"dot",
+			&condition{
+				server: &testServer{res: &api.Response{Content: manifests}},
+				out:    "yaml",
+			},
+			&action{
+				exitCode: 0,
+				contains: `"core/v1/Entrypoint/default/default" -> "core/v1/HTTPServer/default/a"`,
+			},
+		),
+		gen(
+			"json",
+			&condition{
+				server: &testServer{res: &api.Response{Content: manifests}},
+				out:    "json",
+			},
+			&action{
+				exitCode: 0,
+				contains: `"from": "core/v1/Entrypoint/default/default"`,
+			},
+		),
+		gen(
+			"error",
+			&condition{
+				server: &testServer{err: errors.New("test error")},
+			},
+			&action{
+				exitCode: 1,
+				contains: "test error",
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tmp := os.Stdout
+			defer func() {
+				os.Stdout = tmp
+			}()
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			exitCode := -1
+			app.Exit = func(code int) { exitCode = code }
+			defer func() { app.Exit = os.Exit }()
+
+			app.ShowGraph(tt.C.server, tt.C.out)
+			w.Close()
+
+			out, err := io.ReadAll(r)
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, tt.A.exitCode, exitCode)
+			t.Log(string(out))
+			testutil.Diff(t, true, strings.Contains(string(out), tt.A.contains))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aileron-gateway/aileron-gateway/internal/encoder"
+	"github.com/google/go-jsonnet"
+)
+
+// isJsonnet reports if the path is a jsonnet file
+// which is evaluated into config documents.
+// Libraries with ".libsonnet" extension are not evaluated
+// but can be imported from jsonnet files.
+func isJsonnet(p string) bool {
+	return strings.HasSuffix(p, ".jsonnet")
+}
+
+// jsonnetImporter imports files from the configs read by readConfigs
+// so that remote jsonnet files can import the files
+// in the same tarball or at the relative URLs already read.
+// Other files are imported from the local file system.
+type jsonnetImporter struct {
+	configs map[string][]byte
+	file    *jsonnet.FileImporter
+}
+
+func (i *jsonnetImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	if scheme, rest, ok := strings.Cut(importedFrom, "://"); ok {
+		p := scheme + "://" + path.Join(path.Dir(rest), importedPath)
+		if b, ok := i.configs[p]; ok {
+			return jsonnet.MakeContentsRaw(b), p, nil
+		}
+		return jsonnet.Contents{}, "", fmt.Errorf("%s not found in the remote configs", p)
+	}
+	return i.file.Import(importedFrom, importedPath)
+}
+
+// vm returns a new jsonnet VM configured by the options.
+// The configs are the files read by readConfigs.
+func (o *JsonnetOptions) vm(configs map[string][]byte) (*jsonnet.VM, error) {
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnetImporter{configs: configs, file: &jsonnet.FileImporter{JPaths: o.JPaths}})
+	vars := []struct {
+		values []string
+		set    func(string, string)
+	}{
+		{o.ExtStrs, vm.ExtVar},
+		{o.ExtCodes, vm.ExtCode},
+		{o.TLAStrs, vm.TLAVar},
+		{o.TLACodes, vm.TLACode},
+	}
+	for _, v := range vars {
+		for _, kv := range v.values {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				// Read from the environmental variable of the same name
+				// as the jsonnet command does.
+				value, ok = os.LookupEnv(key)
+				if !ok {
+					return nil, fmt.Errorf("jsonnet variable %s has no value and the environmental variable not set", key)
+				}
+			}
+			v.set(key, value)
+		}
+	}
+	return vm, nil
+}
+
+// evaluateJsonnet evaluates the jsonnet file and returns
+// the config documents in JSON.
+// An array is evaluated into multiple documents
+// and any other value is evaluated into a single document.
+func evaluateJsonnet(vm *jsonnet.VM, file string, b []byte) ([][]byte, error) {
+	out, err := vm.EvaluateSnippet(file, string(b))
+	if err != nil {
+		return nil, fmt.Errorf("evaluating jsonnet %s failed: %w", file, err)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		return nil, err
+	}
+	values, ok := v.([]any)
+	if !ok {
+		values = []any{v}
+	}
+	docs := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b, err := encoder.MarshalJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	return docs, nil
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestLoadConfigFiles_jsonnet(t *testing.T) {
+	type condition struct {
+		paths []string
+		opts  *app.JsonnetOptions
+	}
+
+	type action struct {
+		name       string
+		addr       string
+		hosts      []string
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	dir := testDir + "ut/cmd/aileron/app/jsonnet/"
+	t.Setenv("TEST_JSONNET_ADDR", ":8082")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"external variables and library path",
+			&condition{
+				paths: []string{dir + "config/"},
+				opts: &app.JsonnetOptions{
+					ExtStrs: []string{"addr=:8081"},
+					JPaths:  []string{dir + "lib/"},
+				},
+			},
+			&action{
+				name:  "public",
+				addr:  ":8081",
+				hosts: []string{"dev.example.com"},
+			},
+		),
+		gen(
+			"top-level arguments",
+			&condition{
+				paths: []string{dir + "config/config.jsonnet"},
+				opts: &app.JsonnetOptions{
+					ExtCodes: []string{"addr=':' + std.toString(8000 + 443)"},
+					TLAStrs:  []string{"env=prod"},
+					JPaths:   []string{dir + "lib/"},
+				},
+			},
+			&action{
+				name:  "public",
+				addr:  ":8443",
+				hosts: []string{"prod.example.com"},
+			},
+		),
+		gen(
+			"variables from environmental variables",
+			&condition{
+				paths: []string{dir + "config/config.jsonnet"},
+				opts: &app.JsonnetOptions{
+					ExtCodes: []string{"addr=std.extVar('TEST_JSONNET_ADDR')"},
+					ExtStrs:  []string{"TEST_JSONNET_ADDR"},
+					TLACodes: []string{"env='stg'"},
+					JPaths:   []string{dir + "lib/"},
+				},
+			},
+			&action{
+				name:  "public",
+				addr:  ":8082",
+				hosts: []string{"stg.example.com"},
+			},
+		),
+		gen(
+			"single document with relative import",
+			&condition{
+				paths: []string{dir + "single.jsonnet"},
+				opts:  &app.JsonnetOptions{},
+			},
+			&action{
+				name:  "single",
+				addr:  ":8080",
+				hosts: []string{"example.com"},
+			},
+		),
+		gen(
+			"external variable not given",
+			&condition{
+				paths: []string{dir + "config/"},
+				opts:  &app.JsonnetOptions{JPaths: []string{dir + "lib/"}},
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`evaluating jsonnet .*config.jsonnet failed: .*addr`),
+			},
+		),
+		gen(
+			"library not found",
+			&condition{
+				paths: []string{dir + "config/"},
+				opts:  &app.JsonnetOptions{ExtStrs: []string{"addr=:8081"}},
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`server.libsonnet`),
+			},
+		),
+		gen(
+			"evaluation error",
+			&condition{
+				paths: []string{dir + "error.jsonnet"},
+				opts:  &app.JsonnetOptions{},
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`kind is required`),
+			},
+		),
+		gen(
+			"variable without value",
+			&condition{
+				paths: []string{dir + "single.jsonnet"},
+				opts:  &app.JsonnetOptions{ExtStrs: []string{"TEST_JSONNET_NOT_SET"}},
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`jsonnet variable TEST_JSONNET_NOT_SET has no value`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := &app.ConfigReader{Jsonnet: tt.C.opts}
+			server := newValidateServer()
+			err := r.LoadConfigFiles(server, tt.C.paths)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+
+			res, err := server.Serve(context.Background(), &api.Request{
+				Method:  api.MethodGet,
+				Key:     "core/v1/HTTPServer",
+				Format:  api.FormatProtoReference,
+				Params:  map[string]string{api.KeyAccept: string(api.FormatProtoMessage)},
+				Content: &k.Reference{APIVersion: "core/v1", Kind: "HTTPServer", Namespace: "default", Name: tt.A.name},
+			})
+			testutil.Diff(t, nil, err)
+			spec := res.Content.(*v1.HTTPServer).Spec
+			testutil.Diff(t, tt.A.addr, spec.Addr)
+			testutil.Diff(t, tt.A.hosts, spec.VirtualHosts[0].Hosts)
+		})
+	}
+}
+
+func TestValidateConfigFiles_jsonnet(t *testing.T) {
+	dir := testDir + "ut/cmd/aileron/app/jsonnet/"
+	r := &app.ConfigReader{Jsonnet: &app.JsonnetOptions{ExtStrs: []string{"addr=:8081"}, JPaths: []string{dir + "lib/"}}}
+
+	errs := r.ValidateConfigFiles(newValidateServer(), []string{dir + "config/"})
+	testutil.Diff(t, 0, len(errs))
+
+	// Variables are not given without the options.
+	errs = app.ValidateConfigFiles(newValidateServer(), []string{dir + "config/"})
+	testutil.Diff(t, 1, len(errs))
+
+	// Patches are applied to the evaluated documents.
+	b, errs := r.Dump([]string{dir + "config/"}, []string{testDir + "ut/cmd/aileron/app/patch/prod/"}, "yaml")
+	testutil.Diff(t, 0, len(errs))
+	testutil.Diff(t, true, strings.Contains(string(b), "# "+dir+"config/config.jsonnet#1\n"))
+	testutil.Diff(t, true, strings.Contains(string(b), "addr: :8443"))
+}
+
+func TestLoadConfigFiles_remoteJsonnet(t *testing.T) {
+	dir := testDir + "ut/cmd/aileron/app/jsonnet/"
+	single, _ := os.ReadFile(dir + "single.jsonnet")
+	lib, _ := os.ReadFile(dir + "lib/server.libsonnet")
+	svr := httptest.NewServer(newRemoteServer(map[string][]byte{
+		"/configs.tgz": tarball(t, map[string]string{
+			"single.jsonnet":       string(single),
+			"lib/server.libsonnet": string(lib),
+			"lib/README.md":        "ignored",
+		}),
+		"/single.jsonnet": single,
+	}))
+	defer svr.Close()
+
+	l, _ := app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	r := &app.ConfigReader{Remote: l}
+
+	// Libraries in the same tarball are imported.
+	err := r.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/configs.tgz"})
+	testutil.Diff(t, nil, err)
+
+	// Libraries that were not read are not imported.
+	err = r.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/single.jsonnet"})
+	testutil.DiffError(t, app.ErrAppMainLoadConfigs, regexp.MustCompile(`lib/server.libsonnet not found in the remote configs`), err)
+}
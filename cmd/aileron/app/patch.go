@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aileron-gateway/aileron-gateway/internal/encoder"
+	"github.com/aileron-gateway/aileron-gateway/internal/jsonpatch"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/google/go-jsonnet"
+)
+
+// document is a config document in a config file.
+type document struct {
+	// file is the path of the config file.
+	file string
+	// index is the index of the document in the file starting from 0.
+	index int
+	// format is the format of the content.
+	// api.FormatJSON or api.FormatYAML.
+	format  api.Format
+	content []byte
+}
+
+// header is the header of manifests.
+type header struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Metadata   *struct {
+		Namespace string `json:"namespace" yaml:"namespace"`
+		Name      string `json:"name" yaml:"name"`
+	} `json:"metadata" yaml:"metadata"`
+}
+
+// id returns the ID of the manifest in the format of
+// "APIGroup/APIVersion/Kind/Namespace/Name".
+// Namespace and name are "default" when not set
+// as the same as api.ParseID.
+func (h *header) id() string {
+	ns, name := "default", "default"
+	if h.Metadata != nil {
+		ns, name = cmp.Or(h.Metadata.Namespace, ns), cmp.Or(h.Metadata.Name, name)
+	}
+	return h.APIVersion + "/" + h.Kind + "/" + ns + "/" + name
+}
+
+// unmarshal unmarshals the content in the format into the given value.
+func unmarshal(format api.Format, b []byte, into any) error {
+	if format == api.FormatJSON {
+		return encoder.UnmarshalJSON(b, into)
+	}
+	return encoder.UnmarshalYAML(b, into)
+}
+
+// toJSONValue returns the content as a value decoded from JSON.
+// YAML contents are converted into JSON so that
+// numbers are float64 regardless of the format.
+func toJSONValue(format api.Format, b []byte) (any, error) {
+	var v any
+	if err := unmarshal(format, b, &v); err != nil {
+		return nil, err
+	}
+	if format == api.FormatJSON {
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	v = nil
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+// readDocuments reads the config documents from the paths.
+// Currently only ".json", ".yaml", ".yml" and ".jsonnet" file extensions are supported.
+// Others will be ignored.
+// Jsonnet files are evaluated into JSON documents. See evaluateJsonnet.
+// Documents are sorted by the file paths and the indexes.
+func (r *ConfigReader) readDocuments(paths []string) ([]*document, error) {
+	configs, err := r.readConfigs(paths)
+	if err != nil {
+		return nil, err
+	}
+	var vm *jsonnet.VM
+	files := make([]string, 0, len(configs))
+	for path := range configs {
+		files = append(files, path)
+	}
+	slices.Sort(files)
+
+	var docs []*document
+	for _, path := range files {
+		if isJsonnet(path) {
+			if vm == nil {
+				if vm, err = r.jsonnet().vm(configs); err != nil {
+					return nil, err
+				}
+			}
+			bs, err := evaluateJsonnet(vm, path, configs[path])
+			if err != nil {
+				return nil, err
+			}
+			for i, b := range bs {
+				docs = append(docs, &document{file: path, index: i, format: api.FormatJSON, content: b})
+			}
+			continue
+		}
+		var format api.Format
+		if strings.HasSuffix(path, ".json") {
+			format = api.FormatJSON
+		} else if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+			format = api.FormatYAML
+		} else {
+			continue // Ignore other formats.
+		}
+		manifest := bytes.ReplaceAll(configs[path], []byte("\r\n"), []byte("\n"))
+		for i, b := range SplitMultiDoc(manifest, "---\n") {
+			docs = append(docs, &document{file: path, index: i, format: format, content: b})
+		}
+	}
+	return docs, nil
+}
+
+// patchTarget is the target resources of a patch.
+type patchTarget struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace and Name of the target resources.
+	// All resources of the kind are targeted when empty.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// patch is a patch document.
+// MergePatch is applied before JSONPatch when both are set.
+type patch struct {
+	Target     *patchTarget           `json:"target"`
+	MergePatch any                    `json:"mergePatch"`
+	JSONPatch  []*jsonpatch.Operation `json:"jsonPatch"`
+}
+
+// matches returns true when the manifest
+// which has the given ID is the target of this patch.
+func (p *patch) matches(id string) bool {
+	t := p.Target
+	arr := strings.Split(id, "/")
+	return len(arr) == 5 && arr[0]+"/"+arr[1] == t.APIVersion && arr[2] == t.Kind &&
+		(t.Namespace == "" || arr[3] == t.Namespace) && (t.Name == "" || arr[4] == t.Name)
+}
+
+// applyPatches applies the patch documents read from the patches paths
+// to the config documents and returns the patched documents.
+// Patches are applied in the order of the file paths and the indexes.
+// A patch document looks like below.
+//
+//	target:
+//	  apiVersion: core/v1
+//	  kind: HTTPServer
+//	  namespace: default # Optional. All namespaces when empty.
+//	  name: default      # Optional. All names when empty.
+//	mergePatch:          # JSON Merge Patch (RFC 7386).
+//	  spec:
+//	    addr: ":8443"
+//	jsonPatch:           # JSON Patch (RFC 6902).
+//	  - op: add
+//	    path: /spec/virtualHosts/0/hosts/-
+//	    value: example.com
+//
+// Patched documents are converted into JSON.
+// Documents that could not be parsed are not patched and returned as-is
+// so that the errors are reported when they are loaded.
+// All found errors are returned.
+func (r *ConfigReader) applyPatches(docs []*document, patches []string) ([]*document, []*ConfigError) {
+	if len(patches) == 0 {
+		return docs, nil
+	}
+	patchDocs, err := r.readDocuments(patches)
+	if err != nil {
+		return nil, []*ConfigError{{Message: err.Error()}}
+	}
+
+	ids := make([]string, len(docs))
+	values := make([]any, len(docs))
+	for i, doc := range docs {
+		h := &header{}
+		if err := unmarshal(doc.format, doc.content, h); err != nil || (h.APIVersion == "" && h.Kind == "") {
+			continue
+		}
+		ids[i] = h.id()
+	}
+
+	var errs []*ConfigError
+	for _, pd := range patchDocs {
+		v, err := toJSONValue(pd.format, pd.content)
+		if err != nil {
+			errs = append(errs, &ConfigError{File: pd.file, Index: pd.index, Message: err.Error()})
+			continue
+		}
+		b, _ := json.Marshal(v)
+		p := &patch{}
+		if err := json.Unmarshal(b, p); err != nil {
+			errs = append(errs, &ConfigError{File: pd.file, Index: pd.index, Message: err.Error()})
+			continue
+		}
+		if p.Target == nil || p.Target.APIVersion == "" || p.Target.Kind == "" {
+			errs = append(errs, &ConfigError{File: pd.file, Index: pd.index, Message: "patch target must have apiVersion and kind."})
+			continue
+		}
+
+		matched := false
+		for i, doc := range docs {
+			if ids[i] == "" || !p.matches(ids[i]) {
+				continue
+			}
+			matched = true
+			if values[i] == nil {
+				if values[i], err = toJSONValue(doc.format, doc.content); err != nil {
+					errs = append(errs, &ConfigError{File: doc.file, Index: doc.index, Message: err.Error()})
+					continue
+				}
+			}
+			if p.MergePatch != nil {
+				values[i] = jsonpatch.MergePatch(values[i], p.MergePatch)
+			}
+			if values[i], err = jsonpatch.Apply(values[i], p.JSONPatch); err != nil {
+				errs = append(errs, &ConfigError{File: pd.file, Index: pd.index, Message: err.Error() + " target=" + ids[i]})
+			}
+		}
+		if !matched {
+			t := p.Target
+			target := t.APIVersion + "/" + t.Kind + "/" + cmp.Or(t.Namespace, "*") + "/" + cmp.Or(t.Name, "*")
+			errs = append(errs, &ConfigError{File: pd.file, Index: pd.index, Message: "patch target " + target + " not found."})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	patched := make([]*document, len(docs))
+	for i, doc := range docs {
+		patched[i] = doc
+		if values[i] == nil {
+			continue
+		}
+		b, err := encoder.MarshalJSON(values[i])
+		if err != nil {
+			return nil, []*ConfigError{{File: doc.file, Index: doc.index, Message: err.Error()}}
+		}
+		patched[i] = &document{file: doc.file, index: doc.index, format: api.FormatJSON, content: b}
+	}
+	return patched, nil
+}
+
+// ShowDump shows the config documents read from the paths
+// with the patches applied and exit.
+// Documents are shown in JSON array when the out is "json",
+// otherwise in multi-document YAML.
+// Environmental variables and secret references are shown as-is.
+// Configs are read by a ConfigReader with the default options.
+func ShowDump(paths, patches []string, out string) {
+	(&ConfigReader{}).ShowDump(paths, patches, out)
+}
+
+// ShowDump shows the config documents read from the paths
+// with the patches applied and exit.
+// See the ShowDump function.
+func (r *ConfigReader) ShowDump(paths, patches []string, out string) {
+	b, errs := r.Dump(paths, patches, out)
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e.String())
+		}
+		fmt.Printf("%d error(s) found.\n", len(errs))
+		Exit(1)
+		return
+	}
+	fmt.Print(string(b))
+	Exit(0)
+}
+
+// Dump returns the config documents read from the paths
+// with the patches applied.
+// Documents are returned in JSON array when the out is "json",
+// otherwise in multi-document YAML with the comments of their sources.
+// See ShowDump.
+// Configs are read by a ConfigReader with the default options.
+func Dump(paths, patches []string, out string) ([]byte, []*ConfigError) {
+	return (&ConfigReader{}).Dump(paths, patches, out)
+}
+
+// Dump returns the config documents read from the paths
+// with the patches applied.
+// See the Dump function.
+func (r *ConfigReader) Dump(paths, patches []string, out string) ([]byte, []*ConfigError) {
+	docs, err := r.readDocuments(paths)
+	if err != nil {
+		return nil, []*ConfigError{{Message: err.Error()}}
+	}
+	docs, errs := r.applyPatches(docs, patches)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	values := make([]any, 0, len(docs))
+	for _, doc := range docs {
+		v, err := toJSONValue(doc.format, doc.content)
+		if err != nil {
+			errs = append(errs, &ConfigError{File: doc.file, Index: doc.index, Message: err.Error()})
+			continue
+		}
+		values = append(values, v)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if out == "json" {
+		b, err := encoder.MarshalJSON(values)
+		if err != nil {
+			return nil, []*ConfigError{{Message: err.Error()}}
+		}
+		return b, nil
+	}
+	var buf bytes.Buffer
+	for i, v := range values {
+		b, err := encoder.MarshalYAML(v)
+		if err != nil {
+			return nil, []*ConfigError{{File: docs[i].file, Index: docs[i].index, Message: err.Error()}}
+		}
+		buf.WriteString("---\n# " + docs[i].file + "#" + strconv.Itoa(docs[i].index) + "\n")
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
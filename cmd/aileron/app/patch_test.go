@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestLoadConfigFiles_patches(t *testing.T) {
+	type condition struct {
+		patches []string
+	}
+
+	type action struct {
+		addr       string
+		hosts      []string
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	base := testDir + "ut/cmd/aileron/app/patch/base/"
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no patches",
+			&condition{},
+			&action{
+				addr:  ":8080",
+				hosts: []string{"dev.example.com"},
+			},
+		),
+		gen(
+			"merge patch and json patch",
+			&condition{
+				patches: []string{testDir + "ut/cmd/aileron/app/patch/prod/"},
+			},
+			&action{
+				addr:  ":8443",
+				hosts: []string{"example.com", "www.example.com"},
+			},
+		),
+		gen(
+			"target not found",
+			&condition{
+				patches: []string{testDir + "ut/cmd/aileron/app/patch/not-found.yaml"},
+			},
+			&action{
+				err:        app.ErrAppMainPatchConfigs,
+				errPattern: regexp.MustCompile(`patch target core/v1/HTTPServer/\*/private not found`),
+			},
+		),
+		gen(
+			"invalid operation",
+			&condition{
+				patches: []string{testDir + "ut/cmd/aileron/app/patch/invalid-op.yaml"},
+			},
+			&action{
+				err:        app.ErrAppMainPatchConfigs,
+				errPattern: regexp.MustCompile(`invalid-op.yaml#0: .*member=notExist.* target=core/v1/HTTPServer/default/public`),
+			},
+		),
+		gen(
+			"no target",
+			&condition{
+				patches: []string{testDir + "ut/cmd/aileron/app/patch/no-target.yaml"},
+			},
+			&action{
+				err:        app.ErrAppMainPatchConfigs,
+				errPattern: regexp.MustCompile(`patch target must have apiVersion and kind`),
+			},
+		),
+		gen(
+			"invalid patched value",
+			&condition{
+				patches: []string{testDir + "ut/cmd/aileron/app/patch/invalid-value.yaml"},
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`addr`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			server := newValidateServer()
+			err := app.LoadConfigFiles(server, []string{base}, tt.C.patches...)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+
+			res, err := server.Serve(context.Background(), &api.Request{
+				Method:  api.MethodGet,
+				Key:     "core/v1/HTTPServer",
+				Format:  api.FormatProtoReference,
+				Params:  map[string]string{api.KeyAccept: string(api.FormatProtoMessage)},
+				Content: &k.Reference{APIVersion: "core/v1", Kind: "HTTPServer", Namespace: "default", Name: "public"},
+			})
+			testutil.Diff(t, nil, err)
+			spec := res.Content.(*v1.HTTPServer).Spec
+			testutil.Diff(t, tt.A.addr, spec.Addr)
+			testutil.Diff(t, tt.A.hosts, spec.VirtualHosts[0].Hosts)
+		})
+	}
+}
+
+func TestValidateConfigFiles_patches(t *testing.T) {
+	base := testDir + "ut/cmd/aileron/app/patch/base/"
+
+	errs := app.ValidateConfigFiles(newValidateServer(), []string{base}, testDir+"ut/cmd/aileron/app/patch/prod/")
+	testutil.Diff(t, 0, len(errs))
+
+	errs = app.ValidateConfigFiles(newValidateServer(), []string{base},
+		testDir+"ut/cmd/aileron/app/patch/not-found.yaml", testDir+"ut/cmd/aileron/app/patch/no-target.yaml")
+	testutil.Diff(t, 2, len(errs))
+	testutil.Diff(t, testDir+"ut/cmd/aileron/app/patch/no-target.yaml", errs[0].File)
+	testutil.Diff(t, testDir+"ut/cmd/aileron/app/patch/not-found.yaml", errs[1].File)
+
+	// Errors in the patched configs are reported at the base documents.
+	// The entrypoint refers the HTTPServer that failed to be loaded.
+	errs = app.ValidateConfigFiles(newValidateServer(), []string{base}, testDir+"ut/cmd/aileron/app/patch/invalid-value.yaml")
+	testutil.Diff(t, 2, len(errs))
+	testutil.Diff(t, base+"config.yaml", errs[1].File)
+	testutil.Diff(t, 1, errs[1].Index)
+}
+
+func TestDump(t *testing.T) {
+	base := testDir + "ut/cmd/aileron/app/patch/base/"
+	prod := testDir + "ut/cmd/aileron/app/patch/prod/"
+
+	b, errs := app.Dump([]string{base}, []string{prod}, "yaml")
+	testutil.Diff(t, 0, len(errs))
+	out := string(b)
+	testutil.Diff(t, 3, strings.Count(out, "---\n"))
+	testutil.Diff(t, true, strings.Contains(out, "# "+base+"config.yaml#1\n"))
+	testutil.Diff(t, true, strings.Contains(out, `addr: :8443`))
+	testutil.Diff(t, true, strings.Contains(out, "- www.example.com"))
+	testutil.Diff(t, true, strings.Contains(out, "template: prod"))
+
+	b, errs = app.Dump([]string{base}, []string{prod}, "json")
+	testutil.Diff(t, 0, len(errs))
+	var docs []map[string]any
+	testutil.Diff(t, nil, json.Unmarshal(b, &docs))
+	testutil.Diff(t, 3, len(docs))
+	testutil.Diff(t, ":8443", docs[1]["spec"].(map[string]any)["addr"])
+
+	_, errs = app.Dump([]string{base}, []string{testDir + "ut/cmd/aileron/app/patch/not-found.yaml"}, "yaml")
+	testutil.Diff(t, 1, len(errs))
+}
+
+func TestShowDump(t *testing.T) {
+	base := testDir + "ut/cmd/aileron/app/patch/base/"
+
+	tmp := app.Exit
+	defer func() { app.Exit = tmp }()
+	var code int
+	app.Exit = func(c int) { code = c }
+
+	app.ShowDump([]string{base}, nil, "yaml")
+	testutil.Diff(t, 0, code)
+	app.ShowDump([]string{base}, []string{testDir + "ut/cmd/aileron/app/patch/no-target.yaml"}, "yaml")
+	testutil.Diff(t, 1, code)
+}
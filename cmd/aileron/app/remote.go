@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/secret"
+	"github.com/aileron-projects/go/zos"
+)
+
+// maxRemoteSize is the max size of remote config files and tarballs.
+// It is also the max total size of the config files extracted from a tarball.
+const maxRemoteSize = 32 << 20 // 32 MiB
+
+// maxTarEntries is the max number of entries in a tarball
+// including the entries which are not config files.
+const maxTarEntries = 1024
+
+// isRemote reports if the path is a URL of a remote config.
+func isRemote(p string) bool {
+	return strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://")
+}
+
+// isTarball reports if the path is a tarball.
+func isTarball(p string) bool {
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz") || strings.HasSuffix(p, ".tar")
+}
+
+// readConfigs reads the config files from the given paths.
+// Paths are local files, local directories or URLs of remote configs.
+// The keys of the returned map are the paths of the files.
+// See RemoteLoader.Load for the keys of remote configs.
+func (r *ConfigReader) readConfigs(paths []string) (map[string][]byte, error) {
+	var local []string
+	configs := map[string][]byte{}
+	for _, p := range paths {
+		if !isRemote(p) {
+			local = append(local, p)
+			continue
+		}
+		files, _, err := r.remote().Load(context.Background(), p)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(configs, files)
+	}
+	files, err := zos.ReadFiles(false, local...)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(configs, files)
+	return configs, nil
+}
+
+// remoteEntry is the cache of a remote config.
+type remoteEntry struct {
+	etag   string
+	digest [sha256.Size]byte
+	files  map[string][]byte
+}
+
+// RemoteLoader loads config files from remote URLs.
+// A URL refers to a config file or a tarball of config files
+// which name ends with ".tar.gz", ".tgz" or ".tar".
+// Loaded configs are cached with their ETag
+// and requested again with the If-None-Match header.
+// RemoteLoader is safe for concurrent use.
+type RemoteLoader struct {
+	client *http.Client
+	header http.Header
+	// verifyKey is the public key to verify detached signatures.
+	// Signatures are not verified when nil.
+	verifyKey crypto.PublicKey
+	// insecure allows plain HTTP URLs without verifying signatures.
+	insecure bool
+
+	mu sync.Mutex
+	// cache is the loaded configs keyed by their URLs.
+	cache map[string]*remoteEntry
+}
+
+// NewRemoteLoader returns a new RemoteLoader configured by the options.
+// Environmental variables and secret references in the header values are resolved.
+// A loader that uses the default HTTP client is returned when the opts is nil.
+func NewRemoteLoader(opts *RemoteOptions) (*RemoteLoader, error) {
+	l := &RemoteLoader{client: http.DefaultClient, header: http.Header{}, cache: map[string]*remoteEntry{}}
+	if opts == nil {
+		return l, nil
+	}
+	l.insecure = opts.Insecure
+
+	for _, h := range opts.Headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid remote header `%s`. must be 'Name: Value'", name)
+		}
+		v, err := resolveHeaderValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("resolving remote header %s failed: %w", name, err)
+		}
+		l.header.Add(strings.TrimSpace(name), v)
+	}
+
+	if len(opts.RootCAs) > 0 || opts.CertFile != "" || opts.KeyFile != "" {
+		spec := &k.TLSConfig{RootCAs: opts.RootCAs}
+		if opts.CertFile != "" || opts.KeyFile != "" {
+			spec.CertKeyPairs = []*k.CertKeyPair{{CertFile: opts.CertFile, KeyFile: opts.KeyFile}}
+		}
+		tlsConfig, err := network.TLSConfig(spec)
+		if err != nil {
+			return nil, err
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		l.client = &http.Client{Transport: t}
+	}
+
+	if opts.VerifyKey != "" {
+		b, err := os.ReadFile(opts.VerifyKey)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, errors.New("no PEM block found in " + opts.VerifyKey)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		l.verifyKey = key
+	}
+	return l, nil
+}
+
+// resolveHeaderValue resolves the secret references
+// and environmental variables in the header value.
+func resolveHeaderValue(v string) (string, error) {
+	for _, ref := range secret.FindReferences([]byte(v)) {
+		s, err := secret.Resolve(context.Background(), ref)
+		if err != nil {
+			return "", err
+		}
+		v = strings.Replace(v, ref.Raw, s, 1)
+	}
+	b, err := zos.EnvSubst2([]byte(v))
+	return string(b), err
+}
+
+// Load loads the config files from the URL.
+// The keys of the returned map are the URL without query and fragment
+// for a config file, and the URL joined with the file paths in the tarball
+// such as "https://example.com/configs.tar.gz/foo/bar.yaml" for a tarball.
+// The changed is false when the configs were the same as the cached ones.
+// Plain HTTP URLs are rejected unless signatures are verified
+// or the loader was configured to be insecure.
+func (l *RemoteLoader) Load(ctx context.Context, rawURL string) (files map[string][]byte, changed bool, err error) {
+	if strings.HasPrefix(rawURL, "http://") && l.verifyKey == nil && !l.insecure {
+		return nil, false, fmt.Errorf("http URL %s is not allowed. use https, --remote-verify-key or --remote-insecure", redactURL(rawURL))
+	}
+
+	l.mu.Lock()
+	entry := l.cache[rawURL]
+	l.mu.Unlock()
+
+	body, etag, err := l.get(ctx, rawURL, entry)
+	if err != nil {
+		return nil, false, err
+	}
+	if body == nil {
+		return entry.files, false, nil // Not modified.
+	}
+
+	digest := sha256.Sum256(body)
+	if entry != nil && entry.digest == digest {
+		l.mu.Lock()
+		entry.etag = etag
+		l.mu.Unlock()
+		return entry.files, false, nil
+	}
+
+	if l.verifyKey != nil {
+		sig, _, err := l.get(ctx, signatureURL(rawURL), nil)
+		if err != nil {
+			return nil, false, fmt.Errorf("getting signature of %s failed: %w", redactURL(rawURL), err)
+		}
+		if err := verifySignature(l.verifyKey, body, sig); err != nil {
+			return nil, false, fmt.Errorf("verifying signature of %s failed: %w", redactURL(rawURL), err)
+		}
+	}
+
+	u, _ := url.Parse(rawURL)
+	name := u.Scheme + "://" + u.Host + u.Path
+	if isTarball(u.Path) {
+		files, err = untar(name, body, strings.HasSuffix(u.Path, ".tar"))
+		if err != nil {
+			return nil, false, fmt.Errorf("reading tarball %s failed: %w", name, err)
+		}
+	} else {
+		files = map[string][]byte{name: body}
+	}
+
+	l.mu.Lock()
+	l.cache[rawURL] = &remoteEntry{etag: etag, digest: digest, files: files}
+	l.mu.Unlock()
+	return files, true, nil
+}
+
+// get sends a GET request to the URL and returns the body and the ETag.
+// Nil body is returned when the server responded 304 Not Modified
+// to the ETag of the cached entry.
+func (l *RemoteLoader) get(ctx context.Context, rawURL string, entry *remoteEntry) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, values := range l.header {
+		req.Header[name] = values
+	}
+	if entry != nil && entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	res, err := l.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && entry != nil:
+		return nil, entry.etag, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("unexpected status %d from %s", res.StatusCode, redactURL(rawURL))
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > maxRemoteSize {
+		return nil, "", fmt.Errorf("%s exceeds the max size %d bytes", redactURL(rawURL), maxRemoteSize)
+	}
+	return body, res.Header.Get("ETag"), nil
+}
+
+// Poll polls the URLs at the interval until the ctx is done.
+// The notify is sent when any of the configs were changed.
+// Errors are logged and polling is continued.
+func (l *RemoteLoader) Poll(ctx context.Context, urls []string, interval time.Duration, notify chan<- struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lg := log.GlobalLogger(log.DefaultLoggerName)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, u := range urls {
+			_, changed, err := l.Load(ctx, u)
+			if err != nil {
+				lg.Warn(ctx, "failed to poll remote configs.", "url", redactURL(u), "error", err.Error())
+				continue
+			}
+			if changed {
+				lg.Info(ctx, "remote configs changed.", "url", redactURL(u))
+				select {
+				case notify <- struct{}{}:
+				default: // Reload is already notified.
+				}
+			}
+		}
+	}
+}
+
+// redactURL returns the URL without the query and the user info
+// which may contain credentials.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// signatureURL returns the URL of the detached signature.
+// ".sig" is appended to the path so that the query
+// such as tokens of presigned URLs is kept.
+func signatureURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL + ".sig"
+	}
+	u.Path += ".sig"
+	if u.RawPath != "" {
+		u.RawPath += ".sig"
+	}
+	return u.String()
+}
+
+// verifySignature verifies the detached signature of the body.
+// The sig can be the raw signature or the base64 encoded one.
+// Ed25519, ECDSA with SHA-256 and RSA PKCS #1 v1.5 with SHA-256 are supported.
+func verifySignature(key crypto.PublicKey, body, sig []byte) error {
+	if b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+		sig = b
+	}
+	digest := sha256.Sum256(body)
+	switch pub := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, body, sig) {
+			return errors.New("invalid ed25519 signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return errors.New("invalid ecdsa signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig)
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// untar returns the config files in the tarball.
+// Files with the extension other than ".yaml", ".yml", ".json",
+// ".jsonnet" and ".libsonnet" are ignored.
+// The keys of the returned map are the name joined with the file paths.
+// An error is returned when the tarball has more than maxTarEntries entries
+// or the total size of the config files exceeds maxRemoteSize
+// so that compressed tarballs cannot exhaust memory.
+func untar(name string, b []byte, uncompressed bool) (map[string][]byte, error) {
+	var r io.Reader = bytes.NewReader(b)
+	if !uncompressed {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	files := map[string][]byte{}
+	total := 0
+	tr := tar.NewReader(r)
+	for entries := 1; ; entries++ {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entries > maxTarEntries {
+			return nil, fmt.Errorf("more than %d entries found", maxTarEntries)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		switch path.Ext(h.Name) {
+		case ".yaml", ".yml", ".json", ".jsonnet", ".libsonnet":
+		default:
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, int64(maxRemoteSize-total)+1))
+		if err != nil {
+			return nil, err
+		}
+		total += len(content)
+		if total > maxRemoteSize {
+			return nil, fmt.Errorf("config files exceed the max total size %d bytes", maxRemoteSize)
+		}
+		files[name+"/"+path.Clean(strings.TrimPrefix(h.Name, "./"))] = content
+	}
+}
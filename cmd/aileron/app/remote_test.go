@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+// remoteServer serves the files with ETag.
+// Requests are recorded.
+type remoteServer struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	requests []*http.Request
+}
+
+func (s *remoteServer) set(path string, b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = b
+}
+
+func (s *remoteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	b, ok := s.files[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	etag := `"` + base64.RawURLEncoding.EncodeToString(b[:min(len(b), 16)]) + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(b)
+}
+
+func newRemoteServer(files map[string][]byte) *remoteServer {
+	return &remoteServer{files: files}
+}
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gw.Close()
+	return buf.Bytes()
+}
+
+func TestRemoteLoader_Load(t *testing.T) {
+	rs := newRemoteServer(map[string][]byte{
+		"/config.yaml": []byte("foo: bar\n"),
+		"/configs.tar.gz": tarball(t, map[string]string{
+			"./a.yaml":     "a: 1\n",
+			"dir/b.json":   `{"b":2}`,
+			"dir/c.txt":    "ignored",
+			"dir/../d.yml": "d: 4\n",
+		}),
+	})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	l, err := app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	testutil.Diff(t, nil, err)
+
+	// Config file.
+	files, changed, err := l.Load(context.Background(), svr.URL+"/config.yaml?token=foo")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, true, changed)
+	testutil.Diff(t, map[string][]byte{svr.URL + "/config.yaml": []byte("foo: bar\n")}, files)
+
+	// Not modified.
+	files, changed, err = l.Load(context.Background(), svr.URL+"/config.yaml?token=foo")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, false, changed)
+	testutil.Diff(t, map[string][]byte{svr.URL + "/config.yaml": []byte("foo: bar\n")}, files)
+	testutil.Diff(t, `"Zm9vOiBiYXIK"`, rs.requests[1].Header.Get("If-None-Match"))
+
+	// Modified.
+	rs.set("/config.yaml", []byte("foo: baz\n"))
+	files, changed, err = l.Load(context.Background(), svr.URL+"/config.yaml?token=foo")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, true, changed)
+	testutil.Diff(t, map[string][]byte{svr.URL + "/config.yaml": []byte("foo: baz\n")}, files)
+
+	// Tarball.
+	files, changed, err = l.Load(context.Background(), svr.URL+"/configs.tar.gz")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, true, changed)
+	testutil.Diff(t, map[string][]byte{
+		svr.URL + "/configs.tar.gz/a.yaml":     []byte("a: 1\n"),
+		svr.URL + "/configs.tar.gz/dir/b.json": []byte(`{"b":2}`),
+		svr.URL + "/configs.tar.gz/d.yml":      []byte("d: 4\n"),
+	}, files)
+
+	// Not found.
+	_, _, err = l.Load(context.Background(), svr.URL+"/not-found.yaml")
+	testutil.Diff(t, true, err != nil)
+}
+
+func TestRemoteLoader_tarballLimits(t *testing.T) {
+	manyFiles := map[string]string{}
+	for i := range 1025 {
+		manyFiles["dir/"+strconv.Itoa(i)+".txt"] = ""
+	}
+	largeFiles := map[string]string{}
+	for i := range 3 {
+		largeFiles[strconv.Itoa(i)+".yaml"] = strings.Repeat("a", 12<<20) // 36 MiB in total.
+	}
+	rs := newRemoteServer(map[string][]byte{
+		"/many.tar.gz":  tarball(t, manyFiles),
+		"/large.tar.gz": tarball(t, largeFiles),
+	})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	l, err := app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	testutil.Diff(t, nil, err)
+
+	_, _, err = l.Load(context.Background(), svr.URL+"/many.tar.gz")
+	testutil.Diff(t, true, err != nil && strings.Contains(err.Error(), "more than 1024 entries"))
+	_, _, err = l.Load(context.Background(), svr.URL+"/large.tar.gz")
+	testutil.Diff(t, true, err != nil && strings.Contains(err.Error(), "max total size"))
+}
+
+func TestRemoteLoader_insecure(t *testing.T) {
+	rs := newRemoteServer(map[string][]byte{"/config.yaml": []byte("foo: bar\n")})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	// Plain HTTP is rejected by default.
+	l, err := app.NewRemoteLoader(nil)
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), svr.URL+"/config.yaml?token=foo")
+	testutil.Diff(t, true, err != nil)
+	testutil.Diff(t, true, strings.Contains(err.Error(), "http URL "+svr.URL+"/config.yaml is not allowed"))
+	testutil.Diff(t, 0, len(rs.requests))
+
+	l, err = app.NewRemoteLoader(&app.RemoteOptions{})
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), svr.URL+"/config.yaml")
+	testutil.Diff(t, true, err != nil)
+	testutil.Diff(t, 0, len(rs.requests))
+
+	// Allowed when insecure.
+	l, err = app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), svr.URL+"/config.yaml")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, 1, len(rs.requests))
+}
+
+func TestRemoteLoader_headers(t *testing.T) {
+	t.Setenv("TEST_REMOTE_TOKEN", "secret-token")
+	rs := newRemoteServer(map[string][]byte{"/config.yaml": []byte("foo: bar\n")})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	l, err := app.NewRemoteLoader(&app.RemoteOptions{
+		Headers: []string{
+			"Authorization: Bearer ${env:TEST_REMOTE_TOKEN}",
+			"X-Test: ${TEST_REMOTE_TOKEN}",
+		},
+		Insecure: true,
+	})
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), svr.URL+"/config.yaml")
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "Bearer secret-token", rs.requests[0].Header.Get("Authorization"))
+	testutil.Diff(t, "secret-token", rs.requests[0].Header.Get("X-Test"))
+
+	_, err = app.NewRemoteLoader(&app.RemoteOptions{Headers: []string{"invalid"}})
+	testutil.Diff(t, true, err != nil)
+	_, err = app.NewRemoteLoader(&app.RemoteOptions{Headers: []string{"Authorization: ${env:TEST_REMOTE_NOT_SET}"}})
+	testutil.Diff(t, true, err != nil)
+}
+
+func TestRemoteLoader_TLS(t *testing.T) {
+	// Server name is verified by the client.
+	// Use a certificate for "localhost" instead of the default one for IP addresses.
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	_ = os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+
+	svr := httptest.NewUnstartedServer(newRemoteServer(map[string][]byte{"/config.yaml": []byte("foo: bar\n")}))
+	svr.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	svr.StartTLS()
+	defer svr.Close()
+	u := strings.Replace(svr.URL, "127.0.0.1", "localhost", 1) + "/config.yaml"
+
+	// Server certificate is not trusted.
+	l, err := app.NewRemoteLoader(&app.RemoteOptions{})
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), u)
+	testutil.Diff(t, true, err != nil)
+
+	// Trusted by the root CA.
+	l, err = app.NewRemoteLoader(&app.RemoteOptions{RootCAs: []string{ca}})
+	testutil.Diff(t, nil, err)
+	files, _, err := l.Load(context.Background(), u)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, []byte("foo: bar\n"), files[u])
+
+	_, err = app.NewRemoteLoader(&app.RemoteOptions{RootCAs: []string{"not-exist.pem"}})
+	testutil.Diff(t, true, err != nil)
+}
+
+func TestRemoteLoader_signature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	key := filepath.Join(t.TempDir(), "key.pem")
+	_ = os.WriteFile(key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+
+	body := []byte("foo: bar\n")
+	rs := newRemoteServer(map[string][]byte{
+		"/signed.yaml":       body,
+		"/signed.yaml.sig":   []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, body)) + "\n"),
+		"/raw.yaml":          body,
+		"/raw.yaml.sig":      ed25519.Sign(priv, body),
+		"/tampered.yaml":     []byte("foo: baz\n"),
+		"/tampered.yaml.sig": ed25519.Sign(priv, body),
+		"/unsigned.yaml":     body,
+	})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	l, err := app.NewRemoteLoader(&app.RemoteOptions{VerifyKey: key})
+	testutil.Diff(t, nil, err)
+
+	_, _, err = l.Load(context.Background(), svr.URL+"/signed.yaml")
+	testutil.Diff(t, nil, err)
+	_, _, err = l.Load(context.Background(), svr.URL+"/raw.yaml")
+	testutil.Diff(t, nil, err)
+	// Signature is got with the same query.
+	_, _, err = l.Load(context.Background(), svr.URL+"/signed.yaml?token=secret")
+	testutil.Diff(t, nil, err)
+	last := rs.requests[len(rs.requests)-1]
+	testutil.Diff(t, "/signed.yaml.sig", last.URL.Path)
+	testutil.Diff(t, "token=secret", last.URL.RawQuery)
+	_, _, err = l.Load(context.Background(), svr.URL+"/tampered.yaml")
+	testutil.Diff(t, true, err != nil)
+	_, _, err = l.Load(context.Background(), svr.URL+"/unsigned.yaml")
+	testutil.Diff(t, true, err != nil)
+
+	_, err = app.NewRemoteLoader(&app.RemoteOptions{VerifyKey: "not-exist.pem"})
+	testutil.Diff(t, true, err != nil)
+	_, err = app.NewRemoteLoader(&app.RemoteOptions{VerifyKey: testDir + "ut/cmd/aileron/app/env1.txt"})
+	testutil.Diff(t, true, err != nil)
+}
+
+func TestRemoteLoader_Poll(t *testing.T) {
+	rs := newRemoteServer(map[string][]byte{"/config.yaml": []byte("foo: bar\n")})
+	svr := httptest.NewServer(rs)
+	defer svr.Close()
+
+	l, _ := app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	_, _, err := l.Load(context.Background(), svr.URL+"/config.yaml")
+	testutil.Diff(t, nil, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notify := make(chan struct{}, 1)
+	go l.Poll(ctx, []string{svr.URL + "/config.yaml"}, 10*time.Millisecond, notify)
+
+	select {
+	case <-notify:
+		t.Error("notified without changes")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	rs.set("/config.yaml", []byte("foo: baz\n"))
+	select {
+	case <-notify:
+	case <-time.After(time.Second):
+		t.Error("not notified after changes")
+	}
+}
+
+func TestLoadConfigFiles_remote(t *testing.T) {
+	valid, _ := os.ReadFile(testDir + "ut/cmd/aileron/app/validate/valid.yaml")
+	invalid, _ := os.ReadFile(testDir + "ut/cmd/aileron/app/validate/invalid.yaml")
+	svr := httptest.NewServer(newRemoteServer(map[string][]byte{
+		"/valid.yaml":    valid,
+		"/configs.tgz":   tarball(t, map[string]string{"valid.yaml": string(valid)}),
+		"/invalid.yaml":  invalid,
+		"/not-found.txt": []byte("ignored"),
+	}))
+	defer svr.Close()
+
+	l, _ := app.NewRemoteLoader(&app.RemoteOptions{Insecure: true})
+	r := &app.ConfigReader{Remote: l}
+
+	err := r.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/valid.yaml"})
+	testutil.Diff(t, nil, err)
+	err = r.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/configs.tgz"})
+	testutil.Diff(t, nil, err)
+	err = r.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/not-found.yaml"})
+	testutil.DiffError(t, app.ErrAppMainLoadConfigs, nil, err)
+	// The default reader does not allow plain http.
+	err = app.LoadConfigFiles(newValidateServer(), []string{svr.URL + "/valid.yaml"})
+	testutil.DiffError(t, app.ErrAppMainLoadConfigs, nil, err)
+
+	errs := r.ValidateConfigFiles(newValidateServer(), []string{svr.URL + "/valid.yaml"})
+	testutil.Diff(t, 0, len(errs))
+	errs = r.ValidateConfigFiles(newValidateServer(), []string{svr.URL + "/invalid.yaml"})
+	testutil.Diff(t, 5, len(errs))
+	testutil.Diff(t, svr.URL+"/invalid.yaml", errs[0].File)
+}
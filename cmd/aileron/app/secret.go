@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/secret"
+	"google.golang.org/protobuf/proto"
+)
+
+// secretPlaceholder is the prefix of the placeholders
+// which temporarily replace secret references in manifests.
+// Secret references are replaced before parsing manifests
+// not to be substituted as environmental variables.
+const secretPlaceholder = "__AILERON_SECRET_"
+
+// resolveSecrets parses the manifest and resolves the secret references in it.
+// The refs are the references found in the manifest by secret.FindReferences.
+// References must be the entire values of the fields marked
+// with the (kernel.sensitive) option so that the resolved values
+// are redacted when the manifests are shown.
+// The resolve returns the value of a reference.
+// Use resolveSecret to read the secret values and checkSecret to only check the references.
+// The returned message is the manifest merged into the template of the kind.
+// Errors never contain the resolved values.
+func resolveSecrets(server api.API[*api.Request, *api.Response], format api.Format, apiVersion, kind string, b []byte,
+	refs []*secret.Reference, resolve func(*secret.Reference) (string, error),
+) (proto.Message, error) {
+	placeholders := make(map[string]*secret.Reference, len(refs))
+	for i, ref := range refs {
+		p := secretPlaceholder + strconv.Itoa(i) + "__"
+		b = bytes.Replace(b, []byte(ref.Raw), []byte(p), 1)
+		placeholders[p] = ref
+	}
+
+	tpl, err := templateOf(server, apiVersion, kind)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := api.ProtoMessage(format, b, tpl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.ReplaceStrings(msg, func(path string, sensitive bool, value string) (string, error) {
+		if !strings.Contains(value, secretPlaceholder) {
+			return value, nil
+		}
+		ref, ok := placeholders[value]
+		if !ok {
+			reason := "secret reference must be the entire value"
+			return "", ErrAppMainResolveSecret.WithoutStack(nil, map[string]any{"path": path, "reason": reason})
+		}
+		if !sensitive {
+			reason := "secret reference " + ref.Raw + " is not allowed in non-sensitive field"
+			return "", ErrAppMainResolveSecret.WithoutStack(nil, map[string]any{"path": path, "reason": reason})
+		}
+		v, err := resolve(ref)
+		if err != nil {
+			return "", ErrAppMainResolveSecret.WithoutStack(err, map[string]any{"path": path, "reason": "cannot resolve " + ref.Raw})
+		}
+		return v, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// resolveSecret returns the secret value referred by the ref.
+func resolveSecret(ref *secret.Reference) (string, error) {
+	return secret.Resolve(context.Background(), ref)
+}
+
+// checkSecret checks the ref without reading the secret value
+// and returns the raw reference as the value.
+// This is used to validate config files offline.
+func checkSecret(ref *secret.Reference) (string, error) {
+	if err := secret.Check(ref); err != nil {
+		return "", err
+	}
+	return ref.Raw, nil
+}
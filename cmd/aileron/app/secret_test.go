@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestLoadConfigFiles_secrets(t *testing.T) {
+	type condition struct {
+		path string
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	t.Setenv("TEST_CRYPT_SECRET", "MTIzNDU2Nzg5MDEyMzQ1Ng==")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"resolve secrets",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/config.yaml",
+			},
+			&action{},
+		),
+		gen(
+			"reference in non-sensitive field",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/non-sensitive.yaml",
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`spec.cookieName secret reference \${env:TEST_CRYPT_SECRET} is not allowed in non-sensitive field`),
+			},
+		),
+		gen(
+			"reference in a part of value",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/partial.yaml",
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`spec.secureEncoder.hmacSecret secret reference must be the entire value`),
+			},
+		),
+		gen(
+			"secret not found",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/not-found.yaml",
+			},
+			&action{
+				err:        app.ErrAppMainLoadConfigs,
+				errPattern: regexp.MustCompile(`spec.secureEncoder.hmacSecret cannot resolve \${file:.*not-found.txt}`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			server := newValidateServer()
+			err := app.LoadConfigFiles(server, []string{tt.C.path})
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				testutil.Diff(t, false, strings.Contains(err.Error(), "MTIzNDU2Nzg5MDEyMzQ1Ng=="))
+				return
+			}
+
+			ref := &k.Reference{APIVersion: "app/v1", Kind: "SessionMiddleware", Namespace: "default", Name: "secret"}
+			res, err := server.Serve(context.Background(), &api.Request{
+				Method:  api.MethodGet,
+				Key:     "app/v1/SessionMiddleware",
+				Format:  api.FormatProtoReference,
+				Params:  map[string]string{api.KeyAccept: string(api.FormatProtoMessage)},
+				Content: ref,
+			})
+			testutil.Diff(t, nil, err)
+			spec := res.Content.(*v1.SessionMiddleware).Spec
+			testutil.Diff(t, "c2VjcmV0", spec.SecureEncoder.HMACSecret)
+			testutil.Diff(t, "MTIzNDU2Nzg5MDEyMzQ1Ng==", spec.SecureEncoder.CryptSecret)
+
+			// Resolved values are not shown.
+			res, err = server.Serve(context.Background(), &api.Request{
+				Method:  api.MethodGet,
+				Key:     "app/v1/SessionMiddleware",
+				Format:  api.FormatProtoReference,
+				Params:  map[string]string{api.KeyAccept: string(api.FormatYAML)},
+				Content: ref,
+			})
+			testutil.Diff(t, nil, err)
+			out := string(res.Content.([]byte))
+			testutil.Diff(t, false, strings.Contains(out, "c2VjcmV0"))
+			testutil.Diff(t, false, strings.Contains(out, "MTIzNDU2Nzg5MDEyMzQ1Ng=="))
+			testutil.Diff(t, true, strings.Contains(out, api.Redacted))
+		})
+	}
+}
+
+func TestValidateConfigFiles_secrets(t *testing.T) {
+	type condition struct {
+		path string
+	}
+
+	type action struct {
+		errPattern *regexp.Regexp
+	}
+
+	// TEST_CRYPT_SECRET is not set.
+	// Secrets must not be resolved in validation.
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"valid references",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/config.yaml",
+			},
+			&action{},
+		),
+		gen(
+			"secret not found",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/not-found.yaml",
+			},
+			&action{},
+		),
+		gen(
+			"non-sensitive field",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/non-sensitive.yaml",
+			},
+			&action{
+				errPattern: regexp.MustCompile(`spec.cookieName secret reference \${env:TEST_CRYPT_SECRET} is not allowed in non-sensitive field`),
+			},
+		),
+		gen(
+			"partial reference",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/partial.yaml",
+			},
+			&action{
+				errPattern: regexp.MustCompile(`spec.secureEncoder.hmacSecret secret reference must be the entire value`),
+			},
+		),
+		gen(
+			"unknown option",
+			&condition{
+				path: testDir + "ut/cmd/aileron/app/secret/unknown-option.yaml",
+			},
+			&action{
+				errPattern: regexp.MustCompile(`spec.secureEncoder.hmacSecret cannot resolve \${env:TEST_CRYPT_SECRET\|upper}`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			errs := app.ValidateConfigFiles(newValidateServer(), []string{tt.C.path})
+			if tt.A.errPattern == nil {
+				testutil.Diff(t, 0, len(errs))
+				return
+			}
+			testutil.Diff(t, 1, len(errs))
+			testutil.Diff(t, true, tt.A.errPattern.MatchString(errs[0].Message))
+		})
+	}
+}
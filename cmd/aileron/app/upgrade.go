@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+//go:build unix
+
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+)
+
+const (
+	// envUpgradeSockets is the environmental variable name
+	// that holds the listening sockets passed from the parent process.
+	// The value is comma separated list of "<FD>=<Network>://<Address>"
+	// such as "3=tcp://:8080,4=unix:///var/run/aileron.sock".
+	envUpgradeSockets = "AILERON_UPGRADE_SOCKETS"
+	// envUpgradeReady is the environmental variable name
+	// that holds the file descriptor number of the pipe
+	// which the child process notifies its readiness through.
+	envUpgradeReady = "AILERON_UPGRADE_READY"
+)
+
+// upgradeTimeout is the maximum duration to wait for
+// the new process to be ready.
+// The new process is killed when it did not get ready within this duration.
+var upgradeTimeout = time.Minute
+
+// notifyUpgrade relays the upgrade signal, SIGUSR2, to the channel.
+func notifyUpgrade(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR2)
+}
+
+// inheritUpgradeSockets adds the listening sockets
+// passed from the parent process to the socket store.
+// Call the returned function when the entrypoint got ready to serve.
+// The parent process is notified that this process is ready
+// when the function is called with abort=false.
+// Only the first call of the returned function takes effect.
+// The returned function is no-op when this process was not started for upgrade.
+func inheritUpgradeSockets() (done func(abort bool)) {
+	sockets, ok := os.LookupEnv(envUpgradeSockets)
+	if !ok {
+		return func(bool) {}
+	}
+	ready := os.Getenv(envUpgradeReady)
+	// Unset the variables so that they are not passed to the next upgrade.
+	os.Unsetenv(envUpgradeSockets)
+	os.Unsetenv(envUpgradeReady)
+
+	var keys []string
+	var files []*os.File
+	for s := range strings.SplitSeq(sockets, ",") {
+		fd, key, found := strings.Cut(s, "=")
+		n, err := strconv.Atoi(fd)
+		if !found || err != nil {
+			continue
+		}
+		keys = append(keys, key)
+		files = append(files, os.NewFile(uintptr(n), key))
+	}
+	network.AddSocketFiles(keys, files)
+	release := network.InheritSockets()
+
+	var w *os.File
+	if n, err := strconv.Atoi(ready); err == nil {
+		w = os.NewFile(uintptr(n), "ready")
+	}
+	var once sync.Once
+	return func(abort bool) {
+		once.Do(func() {
+			release(abort)
+			if w == nil {
+				return
+			}
+			defer w.Close()
+			if !abort {
+				_, _ = w.Write([]byte{1}) // Notify the parent process.
+			}
+		})
+	}
+}
+
+// upgrade starts a new process of the same executable with the same arguments
+// and passes all listening sockets to it.
+// It returns nil when the new process became ready to serve.
+// The caller should stop serving gracefully after that.
+// An error is returned when the new process exited or did not get ready.
+func upgrade(ctx context.Context) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	keys, files := network.SocketFiles()
+	sockets := make([]string, 0, len(keys))
+	for i, key := range keys {
+		sockets = append(sockets, strconv.Itoa(3+i)+"="+key) // ExtraFiles start at FD 3.
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, w)
+	cmd.Env = append(os.Environ(),
+		envUpgradeSockets+"="+strings.Join(sockets, ","),
+		envUpgradeReady+"="+strconv.Itoa(3+len(files)),
+	)
+	err = cmd.Start()
+	w.Close() // Close the write end so that the read returns EOF when the child exited.
+	if err != nil {
+		return err
+	}
+
+	readyCh := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) {
+			err = errors.New("new process exited before getting ready")
+		}
+		readyCh <- err
+	}()
+
+	timer := time.NewTimer(upgradeTimeout)
+	defer timer.Stop()
+	select {
+	case err = <-readyCh:
+	case <-timer.C:
+		err = errors.New("new process did not get ready within " + upgradeTimeout.String())
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	// The new process continues to use the sockets
+	// after this process exited.
+	network.DetachSockets()
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
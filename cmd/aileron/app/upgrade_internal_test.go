@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+//go:build unix
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestInheritUpgradeSockets(t *testing.T) {
+	type condition struct {
+		abort bool
+	}
+
+	type action struct {
+		ready bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen("ready", &condition{abort: false}, &action{ready: true}),
+		gen("abort", &condition{abort: true}, &action{ready: false}),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			// Emulate the socket passed from the parent process.
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			testutil.Diff(t, nil, err)
+			addr := ln.Addr().String()
+			f, err := ln.(*net.TCPListener).File()
+			testutil.Diff(t, nil, err)
+			fd, err := syscall.Dup(int(f.Fd()))
+			testutil.Diff(t, nil, err)
+			f.Close()
+			ln.Close()
+
+			r, w, err := os.Pipe()
+			testutil.Diff(t, nil, err)
+			defer r.Close()
+			wfd, err := syscall.Dup(int(w.Fd()))
+			testutil.Diff(t, nil, err)
+			w.Close()
+
+			t.Setenv(envUpgradeSockets, strconv.Itoa(fd)+"=tcp://"+addr)
+			t.Setenv(envUpgradeReady, strconv.Itoa(wfd))
+
+			done := inheritUpgradeSockets()
+			_, ok := os.LookupEnv(envUpgradeSockets)
+			testutil.Diff(t, false, ok)
+
+			ln1, err := network.NewListener(&network.ListenConfig{Address: addr})
+			testutil.Diff(t, nil, err)
+			defer ln1.Close()
+			done(tt.C.abort)
+			done(false) // Second call is ignored.
+
+			b, err := io.ReadAll(r)
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, tt.A.ready, len(b) == 1)
+		})
+	}
+}
+
+func TestInheritUpgradeSockets_noEnv(t *testing.T) {
+	t.Setenv(envUpgradeSockets, "")
+	os.Unsetenv(envUpgradeSockets)
+	done := inheritUpgradeSockets()
+	done(false) // Must not panic.
+}
+
+// readyTestEntrypoint is an entrypoint that gets ready and returns.
+// It fails before getting ready as initializers do when err is not nil.
+type readyTestEntrypoint struct {
+	err error
+}
+
+func (e *readyTestEntrypoint) Run(ctx context.Context) error {
+	if e.err != nil {
+		return e.err
+	}
+	core.Ready(ctx)
+	return nil
+}
+
+func TestApp_Run_upgradeReady(t *testing.T) {
+	type condition struct {
+		entrypoint *readyTestEntrypoint
+	}
+
+	type action struct {
+		ready bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"entrypoint got ready",
+			&condition{
+				entrypoint: &readyTestEntrypoint{},
+			},
+			&action{
+				ready: true,
+			},
+		),
+		gen(
+			"initializer failed",
+			&condition{
+				entrypoint: &readyTestEntrypoint{err: errors.New("test initializer error")},
+			},
+			&action{
+				ready: false,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			testutil.Diff(t, nil, err)
+			defer r.Close()
+			wfd, err := syscall.Dup(int(w.Fd()))
+			testutil.Diff(t, nil, err)
+			w.Close()
+
+			t.Setenv(envUpgradeSockets, "")
+			t.Setenv(envUpgradeReady, strconv.Itoa(wfd))
+
+			a := &App{
+				opts: &Options{
+					Metadata: &MetadataOptions{},
+					Basic:    &BasicOptions{},
+				},
+			}
+			_ = a.Run(&runTestServer{res: &api.Response{Content: tt.C.entrypoint}})
+
+			b, err := io.ReadAll(r)
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, tt.A.ready, len(b) == 1)
+		})
+	}
+}
+
+// TestUpgrade_initializerFailed checks that the parent process keeps serving
+// when an initializer of the new process failed.
+// This test binary is started again as the new process.
+func TestUpgrade_initializerFailed(t *testing.T) {
+	if _, ok := os.LookupEnv(envUpgradeReady); ok {
+		// Run as the new process.
+		a := &App{
+			opts: &Options{
+				Metadata: &MetadataOptions{},
+				Basic:    &BasicOptions{},
+			},
+		}
+		err := a.Run(&runTestServer{res: &api.Response{
+			Content: &readyTestEntrypoint{err: errors.New("test initializer error")},
+		}})
+		if err == nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	args := os.Args
+	os.Args = []string{args[0], "-test.run=^TestUpgrade_initializerFailed$"}
+	defer func() { os.Args = args }()
+
+	// Watch the logs to know that the upgrade finished.
+	defer log.SnapshotGlobalLoggers()()
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	log.SetGlobalLogger(log.DefaultLoggerName, log.NewJSONSLogger(pw, nil))
+
+	a := &App{
+		opts: &Options{
+			Metadata: &MetadataOptions{},
+			Basic:    &BasicOptions{},
+		},
+		newServer: func() api.API[*api.Request, *api.Response] { return nil },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := newReloadTestEntrypoint()
+	errChan := make(chan error, 1)
+	go func() { errChan <- a.runWithReload(ctx, first) }()
+	<-first.started
+
+	p, _ := os.FindProcess(os.Getpid())
+	testutil.Diff(t, nil, p.Signal(syscall.SIGUSR2))
+	sc := bufio.NewScanner(pr)
+	for sc.Scan() {
+		if strings.Contains(sc.Text(), "failed to upgrade") || strings.Contains(sc.Text(), "new process is ready") {
+			break
+		}
+	}
+	go func() { _, _ = io.Copy(io.Discard, pr) }()
+	testutil.Diff(t, true, strings.Contains(sc.Text(), "new process exited before getting ready"))
+
+	select {
+	case <-first.stopped:
+		t.Error("parent stopped by failed upgrade")
+	default:
+	}
+	cancel()
+	testutil.Diff(t, nil, <-errChan)
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+//go:build !unix
+
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// notifyUpgrade does nothing because
+// passing listening sockets to a new process is not supported.
+func notifyUpgrade(_ chan<- os.Signal) {}
+
+// inheritUpgradeSockets returns no-op function because
+// passing listening sockets to a new process is not supported.
+func inheritUpgradeSockets() (done func(abort bool)) {
+	return func(bool) {}
+}
+
+// upgrade always returns an error because
+// passing listening sockets to a new process is not supported.
+func upgrade(_ context.Context) error {
+	return errors.New("upgrade is not supported on this platform")
+}
@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"buf.build/go/protovalidate"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	apps "github.com/aileron-gateway/aileron-gateway/app"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/kvs"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/secret"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ConfigError is an error found in a config document.
+type ConfigError struct {
+	// File is the path of the config file.
+	// Empty when the error is not specific to a file.
+	File string
+	// Index is the index of the document in the file starting from 0.
+	// Documents are separated by "---".
+	Index int
+	// Path is the JSON path of the field such as "spec.addr".
+	// Empty when the error is not specific to a field.
+	Path string
+	// Message is the error message.
+	Message string
+}
+
+// String returns the error in the format of
+// "<File>#<Index> <Path>: <Message>".
+func (e *ConfigError) String() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File + "#" + strconv.Itoa(e.Index))
+		if e.Path != "" {
+			b.WriteString(" " + e.Path)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// ShowValidation validates the config files, shows the found errors and exit.
+// The exit code is 0 when no errors were found and 1 otherwise.
+// See ValidateConfigFiles for the validation details.
+// Configs are read by a ConfigReader with the default options.
+// This function panics when the given server is nil.
+func ShowValidation(server api.API[*api.Request, *api.Response], paths []string, patches ...string) {
+	(&ConfigReader{}).ShowValidation(server, paths, patches...)
+}
+
+// ShowValidation validates the config files, shows the found errors and exit.
+// See the ShowValidation function.
+func (r *ConfigReader) ShowValidation(server api.API[*api.Request, *api.Response], paths []string, patches ...string) {
+	errs := r.ValidateConfigFiles(server, paths, patches...)
+	for _, e := range errs {
+		fmt.Println(e.String())
+	}
+	if len(errs) > 0 {
+		fmt.Printf("%d error(s) found.\n", len(errs))
+		Exit(1)
+		return
+	}
+	fmt.Println("no errors found.")
+	Exit(0)
+}
+
+// configDoc is a document loaded from a config file.
+type configDoc struct {
+	file  string
+	index int
+	id    string
+}
+
+// ValidateConfigFiles validates the config files without running any resources
+// and returns all found errors.
+// Config files are loaded to the server in the same way as LoadConfigFiles
+// so that the server validates the manifests with the protovalidate rules.
+// Secret references such as "${file:/run/secrets/password}" are not resolved.
+// Only their providers, options and fields are checked
+// and the references themselves are validated as the values.
+// After all manifests were loaded, following checks are performed
+// for the manifests loaded from the config files.
+//   - Resources referred through kernel.Reference must exist and their kinds must be registered.
+//   - Objects referred through the fields marked with the (kernel.refer) option
+//     must implement the interfaces named by the option.
+//     Types are checked with the object types declared by the registered resources without creating any objects.
+//     Kinds without the declared types such as GoPlugin are reported as unknown.
+//   - Files in the fields marked with the (kernel.file) option must exist.
+//
+// Patches are applied to the config files before validation.
+// Errors in the patches are returned without validating the configs.
+// Errors are sorted by the file paths and the document indexes.
+// Configs are read by a ConfigReader with the default options.
+// This function panics when the given server is nil.
+func ValidateConfigFiles(server api.API[*api.Request, *api.Response], paths []string, patches ...string) []*ConfigError {
+	return (&ConfigReader{}).ValidateConfigFiles(server, paths, patches...)
+}
+
+// ValidateConfigFiles validates the config files without running any resources
+// and returns all found errors.
+// See the ValidateConfigFiles function.
+func (r *ConfigReader) ValidateConfigFiles(server api.API[*api.Request, *api.Response], paths []string, patches ...string) []*ConfigError {
+	docs, err := r.readDocuments(paths)
+	if err != nil {
+		return []*ConfigError{{Message: err.Error()}}
+	}
+	docs, errs := r.applyPatches(docs, patches)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	var loaded []*configDoc
+	for _, doc := range docs {
+		path, i, format, b := doc.file, doc.index, doc.format, doc.content
+		into := &header{}
+		if err := unmarshal(format, b, into); err != nil {
+			errs = append(errs, &ConfigError{File: path, Index: i, Message: err.Error()})
+			continue
+		}
+		if into.APIVersion == "" && into.Kind == "" {
+			continue // Skip
+		}
+
+		key := into.APIVersion + "/" + into.Kind
+		tpl, err := templateOf(server, into.APIVersion, into.Kind)
+		if err != nil {
+			errs = append(errs, &ConfigError{File: path, Index: i, Message: err.Error()})
+			continue
+		}
+		req := &api.Request{
+			Method:  api.MethodPost,
+			Key:     key,
+			Format:  format,
+			Content: b,
+		}
+		var msg proto.Message
+		if refs := secret.FindReferences(b); len(refs) > 0 {
+			msg, err = resolveSecrets(server, format, into.APIVersion, into.Kind, b, refs, checkSecret)
+			req.Format = api.FormatProtoMessage
+			req.Content = msg
+		} else {
+			msg, err = api.ProtoMessage(format, b, tpl, nil)
+		}
+		if err != nil {
+			errs = append(errs, &ConfigError{File: path, Index: i, Message: err.Error()})
+			continue
+		}
+		id, err := api.ParseID(msg)
+		if err != nil {
+			errs = append(errs, &ConfigError{File: path, Index: i, Message: err.Error()})
+			continue
+		}
+
+		if _, err := server.Serve(context.Background(), req); err != nil {
+			errs = append(errs, postErrors(path, i, msg, err)...)
+			continue
+		}
+		loaded = append(loaded, &configDoc{file: path, index: i, id: id})
+	}
+
+	manifests, err := listManifests(server)
+	if err != nil {
+		return append(errs, &ConfigError{Message: err.Error()})
+	}
+	for _, doc := range loaded {
+		msg, ok := manifests[doc.id]
+		if !ok {
+			continue
+		}
+		api.WalkReferences(msg, func(path string, ref *k.Reference) {
+			id := api.ReferenceID(ref)
+			if _, ok := manifests[id]; ok {
+				return
+			}
+			e := &ConfigError{File: doc.file, Index: doc.index, Path: path}
+			if _, err := templateOf(server, ref.APIVersion, ref.Kind); err != nil {
+				e.Message = "referred kind " + ref.APIVersion + "/" + ref.Kind + " is not registered."
+			} else {
+				e.Message = "referred resource " + id + " not found."
+			}
+			errs = append(errs, e)
+		})
+		api.WalkReferTypes(msg, func(path string, refer string, ref *k.Reference) {
+			if _, ok := manifests[api.ReferenceID(ref)]; !ok {
+				return // Already reported.
+			}
+			if msg := referTypeError(server, refer, ref); msg != "" {
+				errs = append(errs, &ConfigError{File: doc.file, Index: doc.index, Path: path, Message: msg})
+			}
+		})
+		api.WalkFiles(msg, func(path string, file string) {
+			if strings.Contains(file, "://") {
+				return // Remote resources such as "https://example.com/bundle.tar.gz".
+			}
+			if _, err := os.Stat(file); err != nil {
+				errs = append(errs, &ConfigError{File: doc.file, Index: doc.index, Path: path, Message: err.Error()})
+			}
+		})
+	}
+	slices.SortStableFunc(errs, func(x, y *ConfigError) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Index, y.Index))
+	})
+	return errs
+}
+
+// referTypes is the interfaces named by the (kernel.refer) option.
+// The key is the interface name used in the option.
+var referTypes = map[string]reflect.Type{
+	"app.AuthenticationHandler": reflect.TypeFor[apps.AuthenticationHandler](),
+	"app.HealthChecker":         reflect.TypeFor[apps.HealthChecker](),
+	"app.Tracer":                reflect.TypeFor[apps.Tracer](),
+	"core.CertificateProvider":  reflect.TypeFor[core.CertificateProvider](),
+	"core.ErrorHandler":         reflect.TypeFor[core.ErrorHandler](),
+	"core.Finalizer":            reflect.TypeFor[core.Finalizer](),
+	"core.Initializer":          reflect.TypeFor[core.Initializer](),
+	"core.Middleware":           reflect.TypeFor[core.Middleware](),
+	"core.Runner":               reflect.TypeFor[core.Runner](),
+	"core.Tripperware":          reflect.TypeFor[core.Tripperware](),
+	"http.Handler":              reflect.TypeFor[http.Handler](),
+	"http.RoundTripper":         reflect.TypeFor[http.RoundTripper](),
+	"kvs.Commander":             reflect.TypeFor[kvs.Commander[string, []byte]](),
+	"log.Logger":                reflect.TypeFor[log.Logger](),
+}
+
+// referTypeError returns the error message when the object referred by the ref
+// cannot implement the interface named by the refer.
+// The types of the objects are obtained from the resource registered to the server
+// without creating any objects.
+// An error message is also returned when the types of the referred kind
+// are unknown such as GoPlugin whose objects are defined by plugins.
+func referTypeError(server api.API[*api.Request, *api.Response], refer string, ref *k.Reference) string {
+	key := ref.APIVersion + "/" + ref.Kind
+	it, ok := referTypes[refer]
+	if !ok {
+		return fmt.Sprintf("interface %s is unknown. %s cannot be checked.", refer, api.ReferenceID(ref))
+	}
+	types, err := typesOf(server, ref.APIVersion, ref.Kind)
+	if err != nil || len(types) == 0 {
+		return fmt.Sprintf("interfaces of referred kind %s are unknown. %s cannot be checked.", key, refer)
+	}
+	for _, t := range types {
+		if t.Implements(it) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("referred resource %s does not implement %s.", api.ReferenceID(ref), refer)
+}
+
+// typesOf returns the types of the objects of the kind.
+// No resources are created by getting the types.
+// Nil is returned when the types are unknown.
+func typesOf(server api.API[*api.Request, *api.Response], apiVersion, kind string) ([]reflect.Type, error) {
+	req := &api.Request{
+		Method: api.MethodGet,
+		Key:    apiVersion + "/" + kind,
+		Format: api.FormatProtoReference,
+		Params: map[string]string{api.KeyAccept: string(api.FormatGoTypes)},
+		Content: &k.Reference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  "template",
+			Name:       "template",
+		},
+	}
+	res, err := server.Serve(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	types, _ := res.Content.([]reflect.Type)
+	return types, nil
+}
+
+// templateOf returns the template manifest of the kind.
+// No resources are created by getting the template.
+// An error is returned when the kind is not registered to the server.
+func templateOf(server api.API[*api.Request, *api.Response], apiVersion, kind string) (proto.Message, error) {
+	req := &api.Request{
+		Method: api.MethodGet,
+		Key:    apiVersion + "/" + kind,
+		Format: api.FormatProtoReference,
+		Params: map[string]string{api.KeyAccept: string(api.FormatProtoMessage)},
+		Content: &k.Reference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  "template",
+			Name:       "template",
+		},
+	}
+	res, err := server.Serve(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := res.Content.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("template of %s/%s is not a proto message", apiVersion, kind)
+	}
+	return msg, nil
+}
+
+// listManifests returns all manifests stored in the server.
+// The key of the returned map is the ID of the manifest.
+func listManifests(server api.API[*api.Request, *api.Response]) (map[string]proto.Message, error) {
+	res, err := server.Serve(context.Background(), &api.Request{Method: api.MethodList})
+	if err != nil {
+		return nil, err
+	}
+	manifests := map[string]proto.Message{}
+	list, _ := res.Content.([]*api.Manifest)
+	for _, m := range list {
+		if m.Message != nil {
+			manifests[m.ID] = m.Message
+		}
+	}
+	return manifests, nil
+}
+
+// postErrors returns the errors of posting the msg.
+// The msg is validated again with the protovalidate rules
+// because errors returned from the server do not contain the violations.
+// Violations are returned with the JSON paths of the fields.
+// The err is returned as it is when the msg has no violations.
+func postErrors(file string, index int, msg proto.Message, err error) []*ConfigError {
+	v, _ := protovalidate.New()
+	var ve *protovalidate.ValidationError
+	if !errors.As(v.Validate(msg), &ve) || len(ve.Violations) == 0 {
+		return []*ConfigError{{File: file, Index: index, Message: err.Error()}}
+	}
+	md := msg.ProtoReflect().Descriptor()
+	errs := make([]*ConfigError, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		msg := v.Proto.GetMessage()
+		if id := v.Proto.GetRuleId(); id != "" {
+			msg += " [" + id + "]"
+		}
+		errs = append(errs, &ConfigError{
+			File:    file,
+			Index:   index,
+			Path:    jsonPath(md, v.Proto.GetField()),
+			Message: msg,
+		})
+	}
+	slices.SortStableFunc(errs, func(x, y *ConfigError) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Index, y.Index))
+	})
+	return errs
+}
+
+// jsonPath converts the field path of a violation into the JSON path
+// using the JSON names of the fields such as "spec.virtualHosts[0].hosts[1]".
+// Proto field names are used for the fields not found in the descriptor.
+func jsonPath(md protoreflect.MessageDescriptor, fp *validate.FieldPath) string {
+	var b strings.Builder
+	for i, elem := range fp.GetElements() {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		var fd protoreflect.FieldDescriptor
+		if md != nil {
+			fd = md.Fields().ByNumber(protoreflect.FieldNumber(elem.GetFieldNumber()))
+		}
+		if fd == nil {
+			b.WriteString(elem.GetFieldName())
+			md = nil
+		} else {
+			b.WriteString(fd.JSONName())
+			md = fd.Message()
+			if fd.IsMap() {
+				md = fd.MapValue().Message()
+			}
+		}
+		switch elem.WhichSubscript() {
+		case validate.FieldPathElement_Index_case:
+			b.WriteString("[" + strconv.FormatUint(elem.GetIndex(), 10) + "]")
+		case validate.FieldPathElement_BoolKey_case:
+			b.WriteString("[" + strconv.Quote(strconv.FormatBool(elem.GetBoolKey())) + "]")
+		case validate.FieldPathElement_IntKey_case:
+			b.WriteString("[" + strconv.Quote(strconv.FormatInt(elem.GetIntKey(), 10)) + "]")
+		case validate.FieldPathElement_UintKey_case:
+			b.WriteString("[" + strconv.Quote(strconv.FormatUint(elem.GetUintKey(), 10)) + "]")
+		case validate.FieldPathElement_StringKey_case:
+			b.WriteString("[" + strconv.Quote(elem.GetStringKey()) + "]")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/aileron-gateway/aileron-gateway/util/register"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// TestReferTypes checks that all kernel.Reference fields of the gateway
+// are marked with the (kernel.refer) option naming a known interface.
+func TestReferTypes(t *testing.T) {
+	referenceName := (&k.Reference{}).ProtoReflect().Descriptor().FullName()
+
+	var walk func(md protoreflect.MessageDescriptor)
+	walk = func(md protoreflect.MessageDescriptor) {
+		for i := range md.Fields().Len() {
+			fd := md.Fields().Get(i)
+			if fd.Message() == nil || fd.Message().FullName() != referenceName {
+				continue
+			}
+			opts, _ := fd.Options().(*descriptorpb.FieldOptions)
+			refer, _ := proto.GetExtension(opts, k.E_Refer).(string)
+			t.Run(string(fd.FullName()), func(t *testing.T) {
+				_, ok := referTypes[refer]
+				testutil.Diff(t, true, ok)
+			})
+		}
+		for i := range md.Messages().Len() {
+			walk(md.Messages().Get(i))
+		}
+	}
+
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if !strings.HasPrefix(string(fd.Package()), "core.") && !strings.HasPrefix(string(fd.Package()), "app.") && fd.Package() != "kernel" {
+			return true
+		}
+		for i := range fd.Messages().Len() {
+			walk(fd.Messages().Get(i))
+		}
+		return true
+	})
+}
+
+// TestKindTypes checks that all registered kinds declare the types of their objects
+// and that the objects of the types implement at least one interface of the referTypes.
+// Objects are not created because creating objects can have side effects
+// such as binding ports or connecting to servers.
+func TestKindTypes(t *testing.T) {
+	svr := api.NewDefaultServeMux()
+	f := api.NewFactoryAPI()
+	register.RegisterAll(f)
+	_ = svr.Handle("core/", f)
+	_ = svr.Handle("app/", f)
+
+	for _, key := range register.Keys() {
+		t.Run(key, func(t *testing.T) {
+			apiVersion, kind := key[:strings.LastIndex(key, "/")], key[strings.LastIndex(key, "/")+1:]
+			types, err := typesOf(svr, apiVersion, kind)
+			testutil.DiffError(t, nil, nil, err)
+			if key == "core/v1/GoPlugin" {
+				testutil.Diff(t, 0, len(types)) // Types are defined by plugins.
+				return
+			}
+			testutil.Diff(t, true, len(types) > 0)
+			for _, typ := range types {
+				implemented := false
+				for _, it := range referTypes {
+					implemented = implemented || typ.Implements(it)
+				}
+				testutil.Diff(t, true, implemented)
+			}
+		})
+	}
+}
+
+func TestReferTypeError(t *testing.T) {
+	type condition struct {
+		refer string
+		ref   *k.Reference
+	}
+
+	type action struct {
+		msg string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"implemented",
+			&condition{
+				refer: "core.Middleware",
+				ref:   &k.Reference{APIVersion: "app/v1", Kind: "CORSMiddleware", Namespace: "ns", Name: "cors"},
+			},
+			&action{},
+		),
+		gen(
+			"not implemented",
+			&condition{
+				refer: "core.Middleware",
+				ref:   &k.Reference{APIVersion: "core/v1", Kind: "TemplateHandler", Namespace: "ns", Name: "tpl"},
+			},
+			&action{
+				msg: "referred resource core/v1/TemplateHandler/ns/tpl does not implement core.Middleware.",
+			},
+		),
+		gen(
+			"interface of the kind",
+			&condition{
+				refer: "http.RoundTripper",
+				ref:   &k.Reference{APIVersion: "core/v1", Kind: "HTTPClient", Namespace: "ns", Name: "client"},
+			},
+			&action{},
+		),
+		gen(
+			"implemented by one of the types",
+			&condition{
+				refer: "core.Tripperware",
+				ref:   &k.Reference{APIVersion: "core/v1", Kind: "HTTPLogger", Namespace: "ns", Name: "logger"},
+			},
+			&action{},
+		),
+		gen(
+			"unknown interface",
+			&condition{
+				refer: "foo.Bar",
+				ref:   &k.Reference{APIVersion: "app/v1", Kind: "CORSMiddleware", Namespace: "ns", Name: "cors"},
+			},
+			&action{
+				msg: "interface foo.Bar is unknown. app/v1/CORSMiddleware/ns/cors cannot be checked.",
+			},
+		),
+		gen(
+			"unregistered kind",
+			&condition{
+				refer: "core.Middleware",
+				ref:   &k.Reference{APIVersion: "core/v1", Kind: "NotExist", Namespace: "ns", Name: "foo"},
+			},
+			&action{
+				msg: "interfaces of referred kind core/v1/NotExist are unknown. core.Middleware cannot be checked.",
+			},
+		),
+		gen(
+			"unknown kind",
+			&condition{
+				refer: "core.Middleware",
+				ref:   &k.Reference{APIVersion: "core/v1", Kind: "GoPlugin", Namespace: "ns", Name: "plugin"},
+			},
+			&action{
+				msg: "interfaces of referred kind core/v1/GoPlugin are unknown. core.Middleware cannot be checked.",
+			},
+		),
+	}
+
+	svr := api.NewDefaultServeMux()
+	f := api.NewFactoryAPI()
+	register.RegisterAll(f)
+	_ = svr.Handle("core/", f)
+	_ = svr.Handle("app/", f)
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			testutil.Diff(t, tt.A.msg, referTypeError(svr, tt.C.refer, tt.C.ref))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package app_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/cmd/aileron/app"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/aileron-gateway/aileron-gateway/util/register"
+)
+
+func newValidateServer() api.API[*api.Request, *api.Response] {
+	svr := api.NewDefaultServeMux()
+	f := api.NewFactoryAPI()
+	register.RegisterAll(f)
+	_ = svr.Handle("core/", f)
+	_ = svr.Handle("app/", f)
+	return svr
+}
+
+func TestConfigError_String(t *testing.T) {
+	testutil.Diff(t, "test message", (&app.ConfigError{Message: "test message"}).String())
+	testutil.Diff(t, "config.yaml#1: test message", (&app.ConfigError{File: "config.yaml", Index: 1, Message: "test message"}).String())
+	testutil.Diff(t, "config.yaml#1 spec.addr: test message", (&app.ConfigError{File: "config.yaml", Index: 1, Path: "spec.addr", Message: "test message"}).String())
+}
+
+func TestValidateConfigFiles(t *testing.T) {
+	type condition struct {
+		paths []string
+	}
+
+	type action struct {
+		errs []*app.ConfigError
+	}
+
+	valid := testDir + "ut/cmd/aileron/app/validate/valid.yaml"
+	invalid := testDir + "ut/cmd/aileron/app/validate/invalid.yaml"
+	wrongType := testDir + "ut/cmd/aileron/app/validate/wrong-type.yaml"
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no files",
+			&condition{
+				paths: []string{},
+			},
+			&action{},
+		),
+		gen(
+			"valid configs",
+			&condition{
+				paths: []string{valid},
+			},
+			&action{},
+		),
+		gen(
+			"file not found",
+			&condition{
+				paths: []string{testDir + "ut/cmd/aileron/app/validate/not-exist.yaml"},
+			},
+			&action{
+				errs: []*app.ConfigError{
+					{Message: "not-exist.yaml"},
+				},
+			},
+		),
+		gen(
+			"invalid configs",
+			&condition{
+				paths: []string{invalid},
+			},
+			&action{
+				errs: []*app.ConfigError{
+					{File: invalid, Index: 0, Path: "spec.runners[0]", Message: "referred resource core/v1/HTTPServer/default/not-exist not found."},
+					{File: invalid, Index: 1, Path: "spec.virtualHosts[0].hosts[0]", Message: "[string.pattern]"},
+					{File: invalid, Index: 2, Path: "spec.middleware[0]", Message: "referred kind app/v1/NotRegistered is not registered."},
+					{File: invalid, Index: 3, Path: "spec.mimeContents[0].templateFile", Message: "not-exist.txt"},
+					{File: invalid, Index: 4, Message: "kernel/api: api is not registered."},
+				},
+			},
+		),
+		gen(
+			"wrong type of referred object",
+			&condition{
+				paths: []string{wrongType},
+			},
+			&action{
+				errs: []*app.ConfigError{
+					{File: wrongType, Index: 0, Path: "spec.middleware[0]", Message: "referred resource core/v1/TemplateHandler/default/default does not implement core.Middleware."},
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			errs := app.ValidateConfigFiles(newValidateServer(), tt.C.paths)
+			testutil.Diff(t, len(tt.A.errs), len(errs))
+			for i, e := range errs {
+				t.Log(e.String())
+				if i >= len(tt.A.errs) {
+					continue
+				}
+				testutil.Diff(t, tt.A.errs[i].File, e.File)
+				testutil.Diff(t, tt.A.errs[i].Index, e.Index)
+				testutil.Diff(t, tt.A.errs[i].Path, e.Path)
+				testutil.Diff(t, true, strings.Contains(e.Message, tt.A.errs[i].Message))
+			}
+		})
+	}
+}
+
+func TestShowValidation(t *testing.T) {
+	type condition struct {
+		paths []string
+	}
+
+	type action struct {
+		exitCode int
+		contains string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no errors",
+			&condition{
+				paths: []string{testDir + "ut/cmd/aileron/app/validate/valid.yaml"},
+			},
+			&action{
+				exitCode: 0,
+				contains: "no errors found.",
+			},
+		),
+		gen(
+			"errors found",
+			&condition{
+				paths: []string{testDir + "ut/cmd/aileron/app/validate/invalid.yaml"},
+			},
+			&action{
+				exitCode: 1,
+				contains: "5 error(s) found.",
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tmp := os.Stdout
+			defer func() {
+				os.Stdout = tmp
+			}()
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			exitCode := -1
+			app.Exit = func(code int) { exitCode = code }
+			defer func() { app.Exit = os.Exit }()
+
+			app.ShowValidation(newValidateServer(), tt.C.paths)
+			w.Close()
+
+			out, err := io.ReadAll(r)
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, tt.A.exitCode, exitCode)
+			t.Log(string(out))
+			testutil.Diff(t, true, strings.Contains(string(out), tt.A.contains))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package acme
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "core/v1"
+	kind       = "ACMEManager"
+	Key        = apiVersion + "/" + kind
+)
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.ACMEManager{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.ACMEManagerSpec{
+				DirectoryURL: autocert.DefaultACMEDirectory,
+				CacheDir:     "./acme-cache/",
+				RenewBefore:  30 * 24 * 60 * 60, // 30 days in second.
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*manager]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.ACMEManager)
+
+	// Use http.DefaultTransport as the default round tripper.
+	// Replace it if the c.Spec.RoundTripper is set.
+	var roundTripper http.RoundTripper = network.DefaultHTTPTransport
+	if c.Spec.RoundTripper != nil {
+		rt, err := api.ReferTypedObject[http.RoundTripper](a, c.Spec.RoundTripper)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		roundTripper = rt
+	}
+
+	// Certificates are not issued when the terms of service are not accepted.
+	// autocert.Manager returns an error when the Prompt is nil.
+	var prompt func(string) bool
+	if c.Spec.AcceptTOS {
+		prompt = autocert.AcceptTOS
+	}
+
+	m := &autocert.Manager{
+		Prompt:      prompt,
+		Cache:       autocert.DirCache(c.Spec.CacheDir),
+		HostPolicy:  autocert.HostWhitelist(c.Spec.Hosts...),
+		RenewBefore: time.Second * time.Duration(c.Spec.RenewBefore),
+		Email:       c.Spec.Email,
+		Client: &acme.Client{
+			DirectoryURL: c.Spec.DirectoryURL,
+			HTTPClient:   &http.Client{Transport: roundTripper},
+		},
+	}
+
+	return &manager{
+		m:       m,
+		handler: m.HTTPHandler(http.NotFoundHandler()),
+	}, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package acme
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err          any // error or errorutil.Kind
+		errPattern   *regexp.Regexp
+		directoryURL string
+		cacheDir     string
+		renewBefore  time.Duration
+		email        string
+		acceptTOS    bool
+		transport    http.RoundTripper
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default manifest",
+			&condition{
+				manifest: Resource.Default(),
+			},
+			&action{
+				directoryURL: autocert.DefaultACMEDirectory,
+				cacheDir:     "./acme-cache/",
+				renewBefore:  30 * 24 * time.Hour,
+				transport:    network.DefaultHTTPTransport,
+			},
+		),
+		gen(
+			"create with spec",
+			&condition{
+				manifest: &v1.ACMEManager{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ACMEManagerSpec{
+						DirectoryURL: "https://localhost:14000/dir",
+						Email:        "test@example.com",
+						Hosts:        []string{"example.com"},
+						CacheDir:     "/tmp/acme/",
+						RenewBefore:  3600,
+						AcceptTOS:    true,
+					},
+				},
+			},
+			&action{
+				directoryURL: "https://localhost:14000/dir",
+				cacheDir:     "/tmp/acme/",
+				renewBefore:  time.Hour,
+				email:        "test@example.com",
+				acceptTOS:    true,
+				transport:    network.DefaultHTTPTransport,
+			},
+		),
+		gen(
+			"round tripper not found",
+			&condition{
+				manifest: &v1.ACMEManager{
+					Metadata: &k.Metadata{},
+					Spec: &v1.ACMEManagerSpec{
+						RoundTripper: &k.Reference{
+							APIVersion: "core/v1",
+							Kind:       "HTTPClient",
+							Name:       "not-exist",
+						},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create ACMEManager`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			m := got.(*manager)
+			testutil.Diff(t, tt.A.directoryURL, m.m.Client.DirectoryURL)
+			testutil.Diff(t, autocert.DirCache(tt.A.cacheDir), m.m.Cache)
+			testutil.Diff(t, tt.A.renewBefore, m.m.RenewBefore)
+			testutil.Diff(t, tt.A.email, m.m.Email)
+			testutil.Diff(t, tt.A.acceptTOS, m.m.Prompt != nil)
+			testutil.Diff(t, true, tt.A.transport == m.m.Client.HTTPClient.Transport)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package acme
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// challengePath is the path prefix of the HTTP-01 challenge.
+// See https://datatracker.ietf.org/doc/html/rfc8555#section-8.3
+const challengePath = "/.well-known/acme-challenge/"
+
+// manager is the ACME certificate manager.
+// This implements core.CertificateProvider interface and http.Handler interface.
+// Certificates are obtained on the first TLS handshake for each host
+// and renewed in background before they expire.
+type manager struct {
+	m *autocert.Manager
+	// handler responds to the HTTP-01 challenges.
+	handler http.Handler
+}
+
+// GetCertificate returns a certificate for the given ClientHello.
+// TLS-ALPN-01 challenges are also handled by this method.
+func (m *manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return m.m.GetCertificate(hello)
+}
+
+// NextProtos returns the ALPN protocol of the TLS-ALPN-01 challenge.
+func (m *manager) NextProtos() []string {
+	return []string{acme.ALPNProto}
+}
+
+// Patterns returns the path pattern of the HTTP-01 challenge.
+func (m *manager) Patterns() []string {
+	return []string{challengePath}
+}
+
+// Methods returns the allowed methods of the HTTP-01 challenge.
+func (m *manager) Methods() []string {
+	return []string{http.MethodGet, http.MethodHead}
+}
+
+// ServeHTTP responds to the HTTP-01 challenges.
+// Port number in the host header is removed
+// because hosts are matched without port numbers.
+// The given request is not modified.
+func (m *manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		r = r.Clone(r.Context())
+		r.Host = host
+	}
+	m.handler.ServeHTTP(w, r)
+}
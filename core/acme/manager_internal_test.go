@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package acme
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// testManager returns a new manager which does not communicate with any CA.
+func testManager(t *testing.T, hosts ...string) *manager {
+	t.Helper()
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(t.TempDir()),
+		HostPolicy: autocert.HostWhitelist(hosts...),
+	}
+	return &manager{
+		m:       m,
+		handler: m.HTTPHandler(http.NotFoundHandler()),
+	}
+}
+
+func TestManager(t *testing.T) {
+	var _ core.CertificateProvider = &manager{}
+	var _ http.Handler = &manager{}
+
+	m := testManager(t, "example.com")
+	testutil.Diff(t, []string{"acme-tls/1"}, m.NextProtos())
+	testutil.Diff(t, []string{"/.well-known/acme-challenge/"}, m.Patterns())
+	testutil.Diff(t, []string{http.MethodGet, http.MethodHead}, m.Methods())
+}
+
+func TestManager_GetCertificate(t *testing.T) {
+	type condition struct {
+		serverName string
+	}
+
+	type action struct {
+		err bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen("no SNI", &condition{serverName: ""}, &action{err: true}),
+		gen("host not allowed", &condition{serverName: "not-allowed.com"}, &action{err: true}),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			m := testManager(t, "example.com")
+			_, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: tt.C.serverName})
+			testutil.Diff(t, tt.A.err, err != nil)
+		})
+	}
+}
+
+func TestManager_ServeHTTP(t *testing.T) {
+	type condition struct {
+		host string
+		path string
+	}
+
+	type action struct {
+		status int
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"host not allowed",
+			&condition{host: "not-allowed.com", path: "/.well-known/acme-challenge/token"},
+			&action{status: http.StatusForbidden},
+		),
+		gen(
+			"token not found",
+			&condition{host: "example.com", path: "/.well-known/acme-challenge/token"},
+			&action{status: http.StatusNotFound},
+		),
+		gen(
+			"host with port",
+			&condition{host: "example.com:8080", path: "/.well-known/acme-challenge/token"},
+			&action{status: http.StatusNotFound},
+		),
+		gen(
+			"not challenge path",
+			&condition{host: "example.com", path: "/foo"},
+			&action{status: http.StatusNotFound},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			m := testManager(t, "example.com")
+			r := httptest.NewRequest(http.MethodGet, "http://"+tt.C.host+tt.C.path, nil)
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, r)
+			testutil.Diff(t, tt.A.status, w.Code)
+			testutil.Diff(t, tt.C.host, r.Host) // Request is not modified.
+		})
+	}
+}
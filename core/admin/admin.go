@@ -0,0 +1,444 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package admin
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/encoder"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// resource is an entry of the resource list.
+type resource struct {
+	ID         string `json:"id"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	// Created is true when the object has already been created.
+	Created bool `json:"created"`
+}
+
+// newResource returns a resource from the manifest.
+// False is returned when the ID of the manifest is not
+// in the format of "APIGroup/APIVersion/Kind/Namespace/Name".
+func newResource(m *api.Manifest) (*resource, bool) {
+	parts := strings.Split(m.ID, "/")
+	if len(parts) != 5 {
+		return nil, false
+	}
+	return &resource{
+		ID:         m.ID,
+		APIVersion: parts[0] + "/" + parts[1],
+		Kind:       parts[2],
+		Namespace:  parts[3],
+		Name:       parts[4],
+		Created:    m.Object != nil,
+	}, true
+}
+
+// route is an entry of the route table.
+type route struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods"`
+}
+
+// virtualHost is the routes of a virtual host.
+type virtualHost struct {
+	Host   string   `json:"host"`
+	Routes []*route `json:"routes"`
+}
+
+// serverRoutes is the route table of a server.
+type serverRoutes struct {
+	ID           string         `json:"id"`
+	VirtualHosts []*virtualHost `json:"virtualHosts"`
+}
+
+// handler serves the admin endpoints.
+// The handler reads the resources through the api.
+// Resources are modified only when the write is enabled.
+type handler struct {
+	a  api.API[*api.Request, *api.Response]
+	eh core.ErrorHandler
+	// write enables the write endpoints.
+	write bool
+	// tokens is the list of bearer tokens
+	// allowed to call the write endpoints.
+	tokens [][]byte
+	// maxBody is the maximum body size of the write requests.
+	maxBody int64
+}
+
+// mux returns the handler which serves following endpoints.
+//   - GET /resources
+//   - GET /resources/{group}/{version}/{kind}/{namespace}/{name}
+//   - GET /routes
+//
+// Following endpoints are served when the write is enabled.
+//   - POST /resources
+//   - PUT /resources/{group}/{version}/{kind}/{namespace}/{name}
+//   - DELETE /resources/{group}/{version}/{kind}/{namespace}/{name}
+func (h *handler) mux() http.Handler {
+	mux := &http.ServeMux{}
+	mux.HandleFunc("GET /resources", h.resources)
+	mux.HandleFunc("GET /resources/{group}/{version}/{kind}/{namespace}/{name}", h.resource)
+	mux.HandleFunc("GET /routes", h.routes)
+	if h.write {
+		mux.Handle("POST /resources", h.authorize(http.HandlerFunc(h.create)))
+		mux.Handle("PUT /resources/{group}/{version}/{kind}/{namespace}/{name}", h.authorize(http.HandlerFunc(h.replace)))
+		mux.Handle("DELETE /resources/{group}/{version}/{kind}/{namespace}/{name}", h.authorize(http.HandlerFunc(h.delete)))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		h.eh.ServeHTTPError(w, r, utilhttp.ErrNotFound)
+	})
+	return mux
+}
+
+// list returns all manifests known by the api.
+func (h *handler) list(ctx context.Context) ([]*api.Manifest, error) {
+	res, err := h.a.Serve(ctx, &api.Request{Method: api.MethodList, Key: ""})
+	if err != nil {
+		return nil, err
+	}
+	ms, _ := res.Content.([]*api.Manifest)
+	return ms, nil
+}
+
+// resources lists resources.
+// Resources can be filtered by the query parameters
+// "apiVersion", "kind", "namespace" and "name".
+func (h *handler) resources(w http.ResponseWriter, r *http.Request) {
+	ms, err := h.list(r.Context())
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	q := r.URL.Query()
+	match := func(key, val string) bool {
+		return !q.Has(key) || q.Get(key) == val
+	}
+	list := []*resource{}
+	for _, m := range ms {
+		rs, ok := newResource(m)
+		if !ok {
+			continue
+		}
+		if match("apiVersion", rs.APIVersion) && match("kind", rs.Kind) &&
+			match("namespace", rs.Namespace) && match("name", rs.Name) {
+			list = append(list, rs)
+		}
+	}
+	h.writeJSON(w, r, list)
+}
+
+// pathID returns the resource ID given by the path parameters.
+func pathID(r *http.Request) string {
+	return strings.Join([]string{
+		r.PathValue("group"), r.PathValue("version"), r.PathValue("kind"),
+		r.PathValue("namespace"), r.PathValue("name"),
+	}, "/")
+}
+
+// find returns the manifest of the resource.
+// An error response is written and nil is returned when not found.
+func (h *handler) find(w http.ResponseWriter, r *http.Request, id string) *api.Manifest {
+	ms, err := h.list(r.Context())
+	if err != nil {
+		h.serveError(w, r, err)
+		return nil
+	}
+	i := slices.IndexFunc(ms, func(m *api.Manifest) bool { return m.ID == id })
+	if i < 0 || ms[i].Message == nil {
+		err := core.ErrCoreAdminNotFound.WithoutStack(nil, map[string]any{"id": id})
+		h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusNotFound))
+		return nil
+	}
+	return ms[i]
+}
+
+// resource returns the effective config of a resource.
+// Values of sensitive fields are redacted.
+func (h *handler) resource(w http.ResponseWriter, r *http.Request) {
+	m := h.find(w, r, pathID(r))
+	if m == nil {
+		return
+	}
+	b, err := encoder.MarshalProtoToJSON(api.Redact(m.Message), &protojson.MarshalOptions{
+		Multiline:       true,
+		Indent:          "  ",
+		AllowPartial:    true,
+		EmitUnpopulated: true,
+	})
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// routes returns the route tables of the created servers
+// grouped by the virtual hosts.
+func (h *handler) routes(w http.ResponseWriter, r *http.Request) {
+	ms, err := h.list(r.Context())
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	list := []*serverRoutes{}
+	for _, m := range ms {
+		rl, ok := m.Object.(core.RouteLister)
+		if !ok {
+			continue
+		}
+		sr := &serverRoutes{ID: m.ID, VirtualHosts: []*virtualHost{}}
+		hosts := map[string]*virtualHost{}
+		for _, rt := range rl.Routes() {
+			vh, ok := hosts[rt.Host]
+			if !ok {
+				vh = &virtualHost{Host: rt.Host}
+				hosts[rt.Host] = vh
+				sr.VirtualHosts = append(sr.VirtualHosts, vh)
+			}
+			vh.Routes = append(vh.Routes, &route{Path: rt.Path, Methods: rt.Methods})
+		}
+		list = append(list, sr)
+	}
+	h.writeJSON(w, r, list)
+}
+
+// authorize allows requests that have one of the bearer tokens
+// or a verified client certificate.
+func (h *handler) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		auth := r.Header.Get("Authorization")
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			for _, t := range h.tokens {
+				if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		h.eh.ServeHTTPError(w, r, utilhttp.ErrUnauthorized)
+	})
+}
+
+// readManifest reads the manifest from the request body.
+// It returns the key of the resource which is in the format of
+// "APIGroup/APIVersion/Kind" and the format of the body.
+// An error response is written and false is returned when failed.
+func (h *handler) readManifest(w http.ResponseWriter, r *http.Request) (body []byte, key string, format api.Format, ok bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBody))
+	if err != nil {
+		err := core.ErrCoreAdmin.WithoutStack(err, map[string]any{"reason": "failed to read body"})
+		h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusRequestEntityTooLarge))
+		return nil, "", "", false
+	}
+
+	format = api.FormatYAML // JSON is accepted as YAML.
+	if strings.Contains(r.Header.Get("Content-Type"), "json") {
+		format = api.FormatJSON
+	}
+	into := &struct {
+		APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+		Kind       string `json:"kind" yaml:"kind"`
+	}{}
+	if err := format.Unmarshal(body, into); err != nil || into.APIVersion == "" || into.Kind == "" {
+		err := core.ErrCoreAdmin.WithoutStack(err, map[string]any{"reason": "apiVersion and kind are required"})
+		h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusBadRequest))
+		return nil, "", "", false
+	}
+	return body, into.APIVersion + "/" + into.Kind, format, true
+}
+
+// apply applies the manifest with the given method
+// and writes the resource entry of the resulting manifest.
+func (h *handler) apply(w http.ResponseWriter, r *http.Request, method api.Method, id string, status int) {
+	body, key, format, ok := h.readManifest(w, r)
+	if !ok {
+		return
+	}
+	req := &api.Request{Method: method, Key: key, Format: format, Content: body}
+	if id != "" {
+		// Make sure that the manifest is for the resource of the path.
+		msgID, err := manifestID(req)
+		if err != nil || msgID != id {
+			err := core.ErrCoreAdmin.WithoutStack(err, map[string]any{"reason": "manifest does not match the path " + id})
+			h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusBadRequest))
+			return
+		}
+	}
+	if _, err := h.a.Serve(r.Context(), req); err != nil {
+		err := core.ErrCoreAdmin.WithoutStack(err, map[string]any{"reason": "failed to " + strings.ToLower(string(method)) + " manifest"})
+		h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusBadRequest))
+		return
+	}
+	id, _ = manifestID(req)
+	m := h.find(w, r, id)
+	if m == nil {
+		return
+	}
+	rs, _ := newResource(m)
+	h.writeJSONStatus(w, r, status, rs)
+}
+
+// create creates a new resource.
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, api.MethodPost, "", http.StatusCreated)
+}
+
+// replace creates or replaces a resource.
+// Objects which depend on the replaced resource are re-created.
+func (h *handler) replace(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, api.MethodPut, pathID(r), http.StatusOK)
+}
+
+// delete deletes a resource.
+// Resources referred from others cannot be deleted.
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r)
+	if h.find(w, r, id) == nil {
+		return
+	}
+	ref := &kernel.Reference{
+		APIVersion: r.PathValue("group") + "/" + r.PathValue("version"),
+		Kind:       r.PathValue("kind"),
+		Namespace:  r.PathValue("namespace"),
+		Name:       r.PathValue("name"),
+	}
+	req := &api.Request{
+		Method:  api.MethodDelete,
+		Key:     ref.APIVersion + "/" + ref.Kind,
+		Format:  api.FormatProtoReference,
+		Content: ref,
+	}
+	if _, err := h.a.Serve(r.Context(), req); err != nil {
+		err := core.ErrCoreAdmin.WithoutStack(err, map[string]any{"reason": "failed to delete manifest"})
+		h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusConflict))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// manifestID returns the ID of the manifest in the request.
+func manifestID(req *api.Request) (string, error) {
+	into := &struct {
+		APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+		Kind       string `json:"kind" yaml:"kind"`
+		Metadata   struct {
+			Namespace string `json:"namespace" yaml:"namespace"`
+			Name      string `json:"name" yaml:"name"`
+		} `json:"metadata" yaml:"metadata"`
+	}{}
+	if err := req.Format.Unmarshal(req.Content, into); err != nil {
+		return "", err
+	}
+	return into.APIVersion + "/" + into.Kind + "/" +
+		cmp.Or(into.Metadata.Namespace, "default") + "/" + cmp.Or(into.Metadata.Name, "default"), nil
+}
+
+func (h *handler) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	h.writeJSONStatus(w, r, http.StatusOK, v)
+}
+
+func (h *handler) writeJSONStatus(w http.ResponseWriter, r *http.Request, status int, v any) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func (h *handler) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	err = core.ErrCoreAdmin.WithStack(err, map[string]any{"reason": "failed to read resources"})
+	h.eh.ServeHTTPError(w, r, utilhttp.NewHTTPError(err, http.StatusInternalServerError))
+}
+
+// swapHandler is the http.Handler that
+// can atomically replace the underlying handler.
+type swapHandler struct {
+	h atomic.Pointer[http.Handler]
+}
+
+func (s *swapHandler) store(h http.Handler) {
+	s.h.Store(&h)
+}
+
+func (s *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.h.Load()).ServeHTTP(w, r)
+}
+
+// runner runs the admin server.
+// This implements core.Runner interface.
+type runner struct {
+	svr *http.Server
+	ln  net.Listener
+	// lc is the listen config used to create the ln.
+	lc      *kernel.ListenConfig
+	lg      log.Logger
+	timeout time.Duration
+	// handler is the handler of the server
+	// which can be replaced while running.
+	handler *swapHandler
+}
+
+// Run starts the admin server.
+// The server is shut down gracefully when the ctx is done.
+func (s *runner) Run(ctx context.Context) error {
+	serverClosed := make(chan struct{})
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		msg := fmt.Sprintf("admin server shutting down %s with graceful period %.0f seconds.", s.ln.Addr().String(), s.timeout.Seconds())
+		s.lg.Info(ctx, msg)
+		if err := s.svr.Shutdown(shutdownCtx); err != nil {
+			msg := fmt.Sprintf("admin server shut down failed. [%v]", err)
+			s.lg.Info(ctx, msg) // May be shutdown timeout. We do not treat this as ERROR.
+		}
+		s.svr.Close()
+		close(serverClosed)
+	}()
+
+	s.lg.Info(ctx, "admin server started. listening on "+s.ln.Addr().String())
+	err := s.svr.Serve(s.ln)
+	<-serverClosed // Wait the server fully closed.
+	if err != nil && err != http.ErrServerClosed {
+		err := core.ErrCoreServer.WithStack(err, nil)
+		s.lg.Error(ctx, "error serving.", err.Name(), err.Map())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,468 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/proto"
+)
+
+type testResource struct {
+	*api.BaseResource
+}
+
+func (r *testResource) Validate(_ proto.Message) error {
+	return nil
+}
+
+func (r *testResource) Create(_ api.API[*api.Request, *api.Response], _ proto.Message) (any, error) {
+	return "created", nil
+}
+
+type testRouteLister []core.Route
+
+func (l testRouteLister) Routes() []core.Route {
+	return l
+}
+
+// testAPI returns a root api that has a CSRFMiddleware
+// "app/v1/CSRFMiddleware/ns/csrf1", which is created, and
+// "app/v1/CSRFMiddleware/ns/csrf2", which is not created,
+// and a route lister "core/v1/HTTPServer/ns/server".
+func testAPI(t *testing.T) api.API[*api.Request, *api.Response] {
+	t.Helper()
+	ctx := context.Background()
+	f := api.NewFactoryAPI()
+	_ = f.Register("app/v1/CSRFMiddleware", &testResource{
+		BaseResource: &api.BaseResource{DefaultProto: &v1.CSRFMiddleware{}},
+	})
+	c := api.NewContainerAPI()
+	root := api.NewDefaultServeMux()
+	_ = root.Handle("app/", f)
+	_ = root.Handle("core/", c)
+
+	for _, name := range []string{"csrf1", "csrf2"} {
+		_, err := root.Serve(ctx, &api.Request{
+			Method: api.MethodPost,
+			Key:    "app/v1/CSRFMiddleware",
+			Format: api.FormatProtoMessage,
+			Content: &v1.CSRFMiddleware{
+				APIVersion: "app/v1",
+				Kind:       "CSRFMiddleware",
+				Metadata:   &k.Metadata{Namespace: "ns", Name: name},
+				Spec:       &v1.CSRFMiddlewareSpec{Secret: "c2VjcmV0"},
+			},
+		})
+		testutil.Diff(t, nil, err)
+	}
+	_, err := api.ReferObject(root, &k.Reference{APIVersion: "app/v1", Kind: "CSRFMiddleware", Namespace: "ns", Name: "csrf1"})
+	testutil.Diff(t, nil, err)
+
+	_, err = root.Serve(ctx, &api.Request{
+		Method: api.MethodPost,
+		Key:    "core/v1/HTTPServer/ns/server",
+		Content: testRouteLister{
+			{Host: "", Path: "/foo", Methods: []string{"GET"}},
+			{Host: "example.com", Path: "/bar"},
+			{Host: "", Path: "/baz", Methods: []string{"GET", "POST"}},
+		},
+	})
+	testutil.Diff(t, nil, err)
+	return root
+}
+
+type errorAPI struct{}
+
+func (errorAPI) Serve(_ context.Context, _ *api.Request) (*api.Response, error) {
+	return nil, io.EOF
+}
+
+func TestHandler(t *testing.T) {
+	type condition struct {
+		a    api.API[*api.Request, *api.Response]
+		path string
+	}
+
+	type action struct {
+		status int
+		body   any // Expected body decoded from JSON.
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"list all resources",
+			&condition{
+				a:    testAPI(t),
+				path: "/resources",
+			},
+			&action{
+				status: http.StatusOK,
+				body: []any{
+					map[string]any{"id": "app/v1/CSRFMiddleware/ns/csrf1", "apiVersion": "app/v1", "kind": "CSRFMiddleware", "namespace": "ns", "name": "csrf1", "created": true},
+					map[string]any{"id": "app/v1/CSRFMiddleware/ns/csrf2", "apiVersion": "app/v1", "kind": "CSRFMiddleware", "namespace": "ns", "name": "csrf2", "created": false},
+					map[string]any{"id": "core/v1/HTTPServer/ns/server", "apiVersion": "core/v1", "kind": "HTTPServer", "namespace": "ns", "name": "server", "created": true},
+				},
+			},
+		),
+		gen(
+			"filter resources",
+			&condition{
+				a:    testAPI(t),
+				path: "/resources?kind=CSRFMiddleware&name=csrf2",
+			},
+			&action{
+				status: http.StatusOK,
+				body: []any{
+					map[string]any{"id": "app/v1/CSRFMiddleware/ns/csrf2", "apiVersion": "app/v1", "kind": "CSRFMiddleware", "namespace": "ns", "name": "csrf2", "created": false},
+				},
+			},
+		),
+		gen(
+			"filter resources no match",
+			&condition{
+				a:    testAPI(t),
+				path: "/resources?namespace=default",
+			},
+			&action{
+				status: http.StatusOK,
+				body:   []any{},
+			},
+		),
+		gen(
+			"list routes",
+			&condition{
+				a:    testAPI(t),
+				path: "/routes",
+			},
+			&action{
+				status: http.StatusOK,
+				body: []any{
+					map[string]any{
+						"id": "core/v1/HTTPServer/ns/server",
+						"virtualHosts": []any{
+							map[string]any{"host": "", "routes": []any{
+								map[string]any{"path": "/foo", "methods": []any{"GET"}},
+								map[string]any{"path": "/baz", "methods": []any{"GET", "POST"}},
+							}},
+							map[string]any{"host": "example.com", "routes": []any{
+								map[string]any{"path": "/bar", "methods": nil},
+							}},
+						},
+					},
+				},
+			},
+		),
+		gen(
+			"list error",
+			&condition{
+				a:    errorAPI{},
+				path: "/resources",
+			},
+			&action{
+				status: http.StatusInternalServerError,
+			},
+		),
+		gen(
+			"resource not found",
+			&condition{
+				a:    testAPI(t),
+				path: "/resources/app/v1/CSRFMiddleware/ns/not-exist",
+			},
+			&action{
+				status: http.StatusNotFound,
+			},
+		),
+		gen(
+			"config of objects without manifest",
+			&condition{
+				a:    testAPI(t),
+				path: "/resources/core/v1/HTTPServer/ns/server",
+			},
+			&action{
+				status: http.StatusNotFound,
+			},
+		),
+		gen(
+			"unknown path",
+			&condition{
+				a:    testAPI(t),
+				path: "/unknown",
+			},
+			&action{
+				status: http.StatusNotFound,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			h := &handler{a: tt.C.a, eh: utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName)}
+			r := httptest.NewRequest(http.MethodGet, "http://admin.com"+tt.C.path, nil)
+			w := httptest.NewRecorder()
+			h.mux().ServeHTTP(w, r)
+			testutil.Diff(t, tt.A.status, w.Code)
+			if tt.A.status != http.StatusOK {
+				return
+			}
+			testutil.Diff(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			var body any
+			testutil.Diff(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+			testutil.Diff(t, tt.A.body, body)
+		})
+	}
+}
+
+func TestHandler_resource(t *testing.T) {
+	h := &handler{a: testAPI(t), eh: utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName)}
+	r := httptest.NewRequest(http.MethodGet, "http://admin.com/resources/app/v1/CSRFMiddleware/ns/csrf2", nil)
+	w := httptest.NewRecorder()
+	h.mux().ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusOK, w.Code)
+
+	var body struct {
+		APIVersion string `json:"apiVersion"`
+		Metadata   struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec map[string]any `json:"spec"`
+	}
+	testutil.Diff(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+	testutil.Diff(t, "app/v1", body.APIVersion)
+	testutil.Diff(t, "csrf2", body.Metadata.Name)
+	testutil.Diff(t, "[REDACTED]", body.Spec["secret"])
+	_, ok := body.Spec["seedSize"] // Unpopulated fields are shown.
+	testutil.Diff(t, true, ok)
+}
+
+func TestRunner(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	h := &handler{a: testAPI(t), eh: utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName)}
+	r := &runner{
+		svr:     &http.Server{Handler: h.mux()},
+		ln:      ln,
+		lg:      log.GlobalLogger(log.DefaultLoggerName),
+		timeout: time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() { errCh <- r.Run(ctx) }()
+
+	res, err := http.Get("http://" + ln.Addr().String() + "/resources")
+	testutil.Diff(t, nil, err)
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	testutil.Diff(t, http.StatusOK, res.StatusCode)
+	testutil.Diff(t, true, strings.Contains(string(b), "app/v1/CSRFMiddleware/ns/csrf1"))
+
+	cancel()
+	select {
+	case err := <-errCh:
+		testutil.Diff(t, nil, err)
+	case <-time.After(3 * time.Second):
+		t.Error("runner did not stop")
+	}
+}
+
+func TestHandler_write(t *testing.T) {
+	type condition struct {
+		write       bool
+		method      string
+		path        string
+		contentType string
+		body        string
+		token       string
+		verified    bool
+	}
+
+	type action struct {
+		status int
+		ids    []string // IDs of the resources after the request.
+	}
+
+	all := []string{"app/v1/CSRFMiddleware/ns/csrf1", "app/v1/CSRFMiddleware/ns/csrf2", "core/v1/HTTPServer/ns/server"}
+	csrf3JSON := `{"apiVersion":"app/v1","kind":"CSRFMiddleware","metadata":{"namespace":"ns","name":"csrf3"}}`
+	csrf3YAML := "apiVersion: app/v1\nkind: CSRFMiddleware\nmetadata:\n  namespace: ns\n  name: csrf3\n"
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"write disabled",
+			&condition{
+				method: http.MethodPost, path: "/resources", body: csrf3JSON, token: "token",
+			},
+			&action{
+				status: http.StatusNotFound,
+				ids:    all,
+			},
+		),
+		gen(
+			"no token",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: csrf3JSON,
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				ids:    all,
+			},
+		),
+		gen(
+			"invalid token",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: csrf3JSON, token: "invalid",
+			},
+			&action{
+				status: http.StatusUnauthorized,
+				ids:    all,
+			},
+		),
+		gen(
+			"create with JSON",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: csrf3JSON, token: "token",
+				contentType: "application/json",
+			},
+			&action{
+				status: http.StatusCreated,
+				ids:    []string{all[0], all[1], "app/v1/CSRFMiddleware/ns/csrf3", all[2]},
+			},
+		),
+		gen(
+			"create with YAML by verified client",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: csrf3YAML, verified: true,
+			},
+			&action{
+				status: http.StatusCreated,
+				ids:    []string{all[0], all[1], "app/v1/CSRFMiddleware/ns/csrf3", all[2]},
+			},
+		),
+		gen(
+			"create duplicate",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", token: "token",
+				body: `{"apiVersion":"app/v1","kind":"CSRFMiddleware","metadata":{"namespace":"ns","name":"csrf1"}}`,
+			},
+			&action{
+				status: http.StatusBadRequest,
+				ids:    all,
+			},
+		),
+		gen(
+			"create without kind",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: `{"apiVersion":"app/v1"}`, token: "token",
+			},
+			&action{
+				status: http.StatusBadRequest,
+				ids:    all,
+			},
+		),
+		gen(
+			"body too large",
+			&condition{
+				write: true, method: http.MethodPost, path: "/resources", body: csrf3JSON + strings.Repeat(" ", 1024), token: "token",
+			},
+			&action{
+				status: http.StatusRequestEntityTooLarge,
+				ids:    all,
+			},
+		),
+		gen(
+			"replace",
+			&condition{
+				write: true, method: http.MethodPut, path: "/resources/app/v1/CSRFMiddleware/ns/csrf1", token: "token",
+				body: `{"apiVersion":"app/v1","kind":"CSRFMiddleware","metadata":{"namespace":"ns","name":"csrf1"},"spec":{"seedSize":10}}`,
+			},
+			&action{
+				status: http.StatusOK,
+				ids:    all,
+			},
+		),
+		gen(
+			"replace with mismatched path",
+			&condition{
+				write: true, method: http.MethodPut, path: "/resources/app/v1/CSRFMiddleware/ns/csrf2", token: "token",
+				body: `{"apiVersion":"app/v1","kind":"CSRFMiddleware","metadata":{"namespace":"ns","name":"csrf1"}}`,
+			},
+			&action{
+				status: http.StatusBadRequest,
+				ids:    all,
+			},
+		),
+		gen(
+			"delete",
+			&condition{
+				write: true, method: http.MethodDelete, path: "/resources/app/v1/CSRFMiddleware/ns/csrf2", token: "token",
+			},
+			&action{
+				status: http.StatusNoContent,
+				ids:    []string{all[0], all[2]},
+			},
+		),
+		gen(
+			"delete not found",
+			&condition{
+				write: true, method: http.MethodDelete, path: "/resources/app/v1/CSRFMiddleware/ns/not-exist", token: "token",
+			},
+			&action{
+				status: http.StatusNotFound,
+				ids:    all,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			h := &handler{
+				a:       testAPI(t),
+				eh:      utilhttp.GlobalErrorHandler(utilhttp.DefaultErrorHandlerName),
+				write:   tt.C.write,
+				tokens:  [][]byte{[]byte("token")},
+				maxBody: 1024,
+			}
+			r := httptest.NewRequest(tt.C.method, "http://admin.com"+tt.C.path, strings.NewReader(tt.C.body))
+			if tt.C.contentType != "" {
+				r.Header.Set("Content-Type", tt.C.contentType)
+			}
+			if tt.C.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.C.token)
+			}
+			if tt.C.verified {
+				r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}
+			}
+			w := httptest.NewRecorder()
+			h.mux().ServeHTTP(w, r)
+			testutil.Diff(t, tt.A.status, w.Code)
+
+			ms, err := h.list(context.Background())
+			testutil.Diff(t, nil, err)
+			ids := []string{}
+			for _, m := range ms {
+				ids = append(ids, m.ID)
+			}
+			testutil.Diff(t, tt.A.ids, ids)
+		})
+	}
+}
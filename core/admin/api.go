@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package admin
+
+import (
+	"cmp"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/internal/network"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/log"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	apiVersion = "core/v1"
+	kind       = "AdminServer"
+	Key        = apiVersion + "/" + kind
+)
+
+var Resource api.Resource = &API{
+	BaseResource: &api.BaseResource{
+		DefaultProto: &v1.AdminServer{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Metadata: &kernel.Metadata{
+				Namespace: "default",
+				Name:      "default",
+			},
+			Spec: &v1.AdminServerSpec{
+				Addr:            "127.0.0.1:9090",
+				ShutdownTimeout: 30,      // In second.
+				MaxBodySize:     1 << 20, // 1 MiB.
+			},
+		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*runner]()},
+	},
+}
+
+type API struct {
+	*api.BaseResource
+}
+
+func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.AdminServer)
+
+	lg := log.DefaultOr(c.Metadata.Logger)
+
+	lc := listenConfig(c.Spec)
+	if err := checkWrite(c.Spec, lc); err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
+	h, err := newHandler(a, c)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	handler := &swapHandler{}
+	handler.store(h)
+
+	config, err := network.ListenConfigFromSpec(lc)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	ln, err := network.NewListener(config)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
+	return &runner{
+		svr: &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 30 * time.Second,
+		},
+		ln:      ln,
+		lc:      lc,
+		lg:      lg,
+		timeout: time.Duration(c.Spec.ShutdownTimeout) * time.Second,
+		handler: handler,
+	}, nil
+}
+
+// Update replaces the middleware and the write settings of the running admin server.
+// Configurations of the listener and the shutdown timeout cannot be updated.
+// An error is returned when they were changed.
+func (*API) Update(a api.API[*api.Request, *api.Response], msg proto.Message, obj any) error {
+	c := msg.(*v1.AdminServer)
+	r, ok := obj.(*runner)
+	if !ok {
+		return nil
+	}
+	lc := listenConfig(c.Spec)
+	var fields []string
+	if !proto.Equal(r.lc, lc) {
+		fields = append(fields, "addr or listenConfig")
+	}
+	if r.timeout != time.Duration(c.Spec.ShutdownTimeout)*time.Second {
+		fields = append(fields, "shutdownTimeout")
+	}
+	if len(fields) > 0 {
+		err := core.ErrCoreAdmin.WithoutStack(nil, map[string]any{"reason": strings.Join(fields, ", ") + " cannot be updated while running"})
+		return core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	if err := checkWrite(c.Spec, lc); err != nil {
+		return core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	h, err := newHandler(a, c)
+	if err != nil {
+		return core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	r.handler.store(h)
+	return nil
+}
+
+// newHandler returns the handler of the admin server
+// with the middleware applied.
+func newHandler(a api.API[*api.Request, *api.Response], c *v1.AdminServer) (http.Handler, error) {
+	middleware, err := api.ReferTypedObjects[core.Middleware](a, c.Spec.Middleware...)
+	if err != nil {
+		return nil, err
+	}
+	h := &handler{
+		a:       a,
+		eh:      utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName)),
+		write:   c.Spec.EnableWrite,
+		maxBody: c.Spec.MaxBodySize,
+	}
+	for _, t := range c.Spec.Tokens {
+		h.tokens = append(h.tokens, []byte(t))
+	}
+	return utilhttp.MiddlewareChain(middleware, h.mux()), nil
+}
+
+// listenConfig returns the listen config of the admin server.
+// The Addr of the spec is used when the listen address is not set.
+// The spec is not modified.
+func listenConfig(spec *v1.AdminServerSpec) *kernel.ListenConfig {
+	lc := &kernel.ListenConfig{}
+	if spec.ListenConfig != nil {
+		lc = proto.Clone(spec.ListenConfig).(*kernel.ListenConfig)
+	}
+	lc.Addr = cmp.Or(lc.Addr, spec.Addr)
+	return lc
+}
+
+// checkWrite returns an error when write is enabled
+// without any authorization methods.
+func checkWrite(spec *v1.AdminServerSpec, lc *kernel.ListenConfig) error {
+	if spec.EnableWrite && len(spec.Tokens) == 0 && !verifiesClientCert(lc.TLSConfig) {
+		return core.ErrCoreAdmin.WithoutStack(nil, map[string]any{"reason": "tokens or client certificate verification is required to enable write"})
+	}
+	return nil
+}
+
+// verifiesClientCert reports if the TLS config
+// verifies client certificates.
+func verifiesClientCert(c *kernel.TLSConfig) bool {
+	if c == nil {
+		return false
+	}
+	return c.ClientAuth == kernel.ClientAuthType_VerifyClientCertIfGiven ||
+		c.ClientAuth == kernel.ClientAuthType_RequireAndVerifyClientCert
+}
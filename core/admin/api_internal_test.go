@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate(t *testing.T) {
+	type condition struct {
+		manifest proto.Message
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+		addr       string
+		timeout    time.Duration
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"create with default manifest",
+			&condition{
+				manifest: Resource.Default(),
+			},
+			&action{
+				addr:    "127.0.0.1:9090",
+				timeout: 30 * time.Second,
+			},
+		),
+		gen(
+			"listen config address takes precedence",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						Addr:            "127.0.0.1:9090",
+						ListenConfig:    &k.ListenConfig{Addr: "127.0.0.1:9091"},
+						ShutdownTimeout: 10,
+					},
+				},
+			},
+			&action{
+				addr:    "127.0.0.1:9091",
+				timeout: 10 * time.Second,
+			},
+		),
+		gen(
+			"middleware not found",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						Addr: "127.0.0.1:0",
+						Middleware: []*k.Reference{
+							{APIVersion: "app/v1", Kind: "AuthenticationMiddleware", Name: "not-exist"},
+						},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create AdminServer`),
+			},
+		),
+		gen(
+			"write with tokens",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						Addr:        "127.0.0.1:0",
+						EnableWrite: true,
+						Tokens:      []string{"token"},
+					},
+				},
+			},
+			&action{
+				addr: "127.0.0.1:0",
+			},
+		),
+		gen(
+			"write without authentication",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						Addr:         "127.0.0.1:0",
+						EnableWrite:  true,
+						ListenConfig: &k.ListenConfig{TLSConfig: &k.TLSConfig{ClientAuth: k.ClientAuthType_RequireAnyClientCert}},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create AdminServer`),
+			},
+		),
+		gen(
+			"invalid listen config",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						ListenConfig: &k.ListenConfig{
+							Addr:      "127.0.0.1:0",
+							TLSConfig: &k.TLSConfig{RootCAs: []string{"not-exist.pem"}},
+						},
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create AdminServer`),
+			},
+		),
+		gen(
+			"listen failed",
+			&condition{
+				manifest: &v1.AdminServer{
+					Metadata: &k.Metadata{},
+					Spec: &v1.AdminServerSpec{
+						Addr: "127.0.0.1:-1",
+					},
+				},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateObject,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create AdminServer`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := Resource.Create(api.NewContainerAPI(), tt.C.manifest)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			r := got.(*runner)
+			defer r.ln.Close()
+			if tt.A.addr != "127.0.0.1:0" {
+				testutil.Diff(t, tt.A.addr, r.ln.Addr().String())
+			}
+			testutil.Diff(t, tt.A.timeout, r.timeout)
+			testutil.Diff(t, true, r.svr.Handler != nil)
+		})
+	}
+}
+
+func TestVerifiesClientCert(t *testing.T) {
+	testutil.Diff(t, false, verifiesClientCert(nil))
+	testutil.Diff(t, false, verifiesClientCert(&k.TLSConfig{ClientAuth: k.ClientAuthType_RequireAnyClientCert}))
+	testutil.Diff(t, true, verifiesClientCert(&k.TLSConfig{ClientAuth: k.ClientAuthType_VerifyClientCertIfGiven}))
+	testutil.Diff(t, true, verifiesClientCert(&k.TLSConfig{ClientAuth: k.ClientAuthType_RequireAndVerifyClientCert}))
+}
+
+func TestUpdate(t *testing.T) {
+	manifest := &v1.AdminServer{
+		Metadata: &k.Metadata{},
+		Spec:     &v1.AdminServerSpec{Addr: "127.0.0.1:0", MaxBodySize: 1024},
+	}
+	got, err := Resource.Create(api.NewContainerAPI(), manifest)
+	testutil.Diff(t, nil, err)
+	r := got.(*runner)
+	defer r.ln.Close()
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://admin.com/resources", strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer token")
+		r.svr.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+	testutil.Diff(t, http.StatusNotFound, serve())
+
+	// Enable write.
+	manifest.Spec.EnableWrite = true
+	manifest.Spec.Tokens = []string{"token"}
+	updater := Resource.(api.Updater)
+	testutil.Diff(t, nil, updater.Update(api.NewContainerAPI(), manifest, r))
+	testutil.Diff(t, http.StatusBadRequest, serve())
+
+	// Handler is kept when failed.
+	manifest.Spec.Middleware = []*k.Reference{{APIVersion: "app/v1", Kind: "Foo", Name: "not-exist"}}
+	err = updater.Update(api.NewContainerAPI(), manifest, r)
+	testutil.DiffError(t, core.ErrCoreGenCreateObject, regexp.MustCompile(core.ErrPrefix+`failed to create AdminServer`), err)
+	testutil.Diff(t, http.StatusBadRequest, serve())
+	manifest.Spec.Middleware = nil
+
+	// Write cannot be enabled without authorization.
+	manifest.Spec.Tokens = nil
+	err = updater.Update(api.NewContainerAPI(), manifest, r)
+	testutil.DiffError(t, core.ErrCoreGenCreateObject, regexp.MustCompile(`tokens or client certificate verification is required`), err)
+	testutil.Diff(t, http.StatusBadRequest, serve())
+	manifest.Spec.Tokens = []string{"token"}
+
+	// Listener and shutdown timeout cannot be updated.
+	manifest.Spec.ListenConfig = &k.ListenConfig{Addr: "127.0.0.1:12345"}
+	manifest.Spec.ShutdownTimeout = 10
+	err = updater.Update(api.NewContainerAPI(), manifest, r)
+	testutil.DiffError(t, core.ErrCoreGenCreateObject, regexp.MustCompile(`addr or listenConfig, shutdownTimeout cannot be updated while running`), err)
+	testutil.Diff(t, http.StatusBadRequest, serve())
+
+	// Same listen address is not a change.
+	manifest.Spec.ListenConfig = &k.ListenConfig{Addr: "127.0.0.1:0"}
+	manifest.Spec.ShutdownTimeout = 0
+	testutil.Diff(t, nil, updater.Update(api.NewContainerAPI(), manifest, r))
+
+	// Objects other than the runner are ignored.
+	testutil.Diff(t, nil, updater.Update(api.NewContainerAPI(), manifest, "not a runner"))
+}
@@ -5,6 +5,7 @@ package entrypoint
 
 import (
 	"cmp"
+	"reflect"
 	"strings"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.EntrypointSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*channelGroup]()},
 	},
 }
 
@@ -22,6 +22,8 @@ type channelGroup struct {
 // Run run the registered runner in a new goroutine.
 // This is the implements of core.Runner.Run method.
 // This returns an error or nil when at least one runner is done.
+// Readiness is notified through the core.Ready
+// after the initializers succeeded and all runners were started.
 func (g *channelGroup) Run(ctx context.Context) (err error) {
 	if len(g.runners) == 0 {
 		return nil
@@ -57,6 +59,10 @@ func (g *channelGroup) Run(ctx context.Context) (err error) {
 		<-waitGoroutine
 	}
 
+	// Notify the readiness after the initializers succeeded
+	// and all runners were started.
+	core.Ready(ctx)
+
 	// Return an error if any.
 	if err := <-errChan; err != nil {
 		err := core.ErrCoreEntrypointRun.WithStack(err, nil)
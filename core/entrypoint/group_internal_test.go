@@ -129,6 +129,63 @@ func TestChannelGroup_Run(t *testing.T) {
 	}
 }
 
+func TestChannelGroup_Run_ready(t *testing.T) {
+	type condition struct {
+		runners      []core.Runner
+		initializers []core.Initializer
+	}
+
+	type action struct {
+		ready bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no runners",
+			&condition{},
+			&action{
+				ready: false,
+			},
+		),
+		gen(
+			"runners started",
+			&condition{
+				runners:      []core.Runner{&testRunner{}},
+				initializers: []core.Initializer{&testInitializer{}},
+			},
+			&action{
+				ready: true,
+			},
+		),
+		gen(
+			"initializer failed",
+			&condition{
+				runners:      []core.Runner{&testRunner{}},
+				initializers: []core.Initializer{&testInitializer{err: errors.New("test error")}},
+			},
+			&action{
+				ready: false,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			cg := &channelGroup{
+				lg:           log.GlobalLogger(log.DefaultLoggerName),
+				runners:      tt.C.runners,
+				initializers: tt.C.initializers,
+			}
+
+			ready := false
+			ctx := core.ContextWithReady(context.Background(), func() { ready = true })
+			_ = cg.Run(ctx)
+			testutil.Diff(t, tt.A.ready, ready)
+		})
+	}
+}
+
 func TestChannelGroup_Finalize(t *testing.T) {
 	type condition struct {
 		runner *channelGroup
@@ -11,6 +11,7 @@ import (
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
 	"google.golang.org/protobuf/proto"
+	"reflect"
 )
 
 const (
@@ -30,6 +31,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.ErrorHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*utilhttp.DefaultErrorHandler]()},
 	},
 }
 
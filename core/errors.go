@@ -37,7 +37,12 @@ var (
 	ErrCoreServer         = errorutil.NewKind("E2130", "CoreServer", "error was returned from server")
 	ErrCoreServerNotFound = errorutil.NewKind("E2131", "CoreServerNotFound", "handler not found for {{pattern}}")
 	ErrCoreServerRecover  = errorutil.NewKind("E2132", "CoreServerRecover", "panic recovered")
+	ErrCoreServerUpdate   = errorutil.NewKind("E2133", "CoreServerUpdate", "{{fields}} cannot be updated while running")
 
 	// core/static: E2140 - E2149
 	ErrCoreStaticServer = errorutil.NewKind("E2140", "CoreStaticServer", "failed to serve static file. {{body}}")
+
+	// core/admin: E2150 - E2159
+	ErrCoreAdmin         = errorutil.NewKind("E2150", "CoreAdmin", "admin api error. {{reason}}")
+	ErrCoreAdminNotFound = errorutil.NewKind("E2151", "CoreAdminNotFound", "resource not found {{id}}")
 )
@@ -5,6 +5,7 @@ package httpclient
 
 import (
 	"net/http"
+	"reflect"
 	"slices"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -33,6 +34,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.HTTPClientSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[http.RoundTripper]()},
 	},
 }
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"reflect"
 	"slices"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -34,6 +35,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.HTTPHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*handler]()},
 	},
 }
 
@@ -4,6 +4,7 @@
 package httplogger
 
 import (
+	"reflect"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -42,6 +43,10 @@ var Resource api.Resource = &API{
 				},
 			},
 		},
+		ObjectTypes: []reflect.Type{
+			reflect.TypeFor[*journalLogger](),
+			reflect.TypeFor[*httpLogger](),
+		},
 	},
 }
 
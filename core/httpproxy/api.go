@@ -8,6 +8,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -40,6 +41,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.ReverseProxyHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*reverseProxy]()},
 	},
 }
 
@@ -93,10 +95,11 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 			AcceptPatterns: c.Spec.Patterns,
 			AcceptMethods:  utilhttp.Methods(c.Spec.Methods),
 		},
-		lg:  log.DefaultOr(c.Metadata.Logger),
-		eh:  eh,
-		rt:  utilhttp.TripperwareChain(ts, roundTripper),
-		lbs: lbs,
+		lg:          log.DefaultOr(c.Metadata.Logger),
+		eh:          eh,
+		rt:          utilhttp.TripperwareChain(ts, roundTripper),
+		lbs:         lbs,
+		cacheClaims: usesClaims(c.Spec.LoadBalancers),
 	}, nil
 }
 
@@ -125,10 +128,11 @@ func newLoadBalancers(rt http.RoundTripper, specs []*v1.LoadBalancerSpec) ([]loa
 		hMatchers, hErr := headerMatchers(spec.HeaderMatchers...)
 		qMatchers, qErr := queryMatchers(spec.QueryMatchers...)
 		pMatchers, pErr := pathParamMatchers(spec.PathParamMatchers...)
-		if err = errors.Join(hErr, qErr, pErr); err != nil {
+		cMatchers, cErr := claimMatchers(cmp.Or(spec.ClaimsKey, defaultClaimsKey), spec.ClaimMatchers...)
+		if err = errors.Join(hErr, qErr, pErr, cErr); err != nil {
 			return nil, core.ErrCoreGenCreateComponent.WithStack(err, map[string]any{"reason": "invalid parameter matcher config"})
 		}
-		matchers := slices.Clip(append(append(hMatchers, qMatchers...), pMatchers...))
+		matchers := slices.Clip(append(append(append(hMatchers, qMatchers...), pMatchers...), cMatchers...))
 
 		m := &lbMatcher{
 			pathMatchers:  pathMatchers,
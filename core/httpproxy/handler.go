@@ -36,6 +36,11 @@ type reverseProxy struct {
 
 	// rt is the round tripper to be used for proxy requests.
 	rt http.RoundTripper
+
+	// cacheClaims caches encoded authentication claims
+	// while finding the upstream when true.
+	// It should be true when any of the lbs refers claims.
+	cacheClaims bool
 }
 
 // findUpstream returns a proxy upstream.
@@ -74,6 +79,9 @@ func (p *reverseProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if p.cacheClaims {
+		r = withClaimsCache(r)
+	}
 	upstream, upstreamURL, findErr := p.findUpstream(r)
 	if findErr != nil {
 		p.eh.ServeHTTPError(w, r, findErr)
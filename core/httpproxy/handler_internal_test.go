@@ -17,6 +17,7 @@ import (
 	"regexp"
 	"testing"
 
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
 	"github.com/aileron-gateway/aileron-gateway/core"
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
@@ -476,6 +477,43 @@ func TestReverseProxy_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestReverseProxy_ServeHTTP_claimsCache(t *testing.T) {
+	lb := func(path, pattern string) loadBalancer {
+		matchers, err := claimMatchers("AuthnClaims", &v1.ParamMatcherSpec{Key: path, Patterns: []string{pattern}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ups := &noopUpstream{
+			weight:    1,
+			rawURL:    "http://upstream.com/proxy",
+			parsedURL: &url.URL{Scheme: "http", Host: "upstream.com", Path: "/proxy"},
+		}
+		return &loadbalancer{
+			lbMatcher: &lbMatcher{
+				pathMatchers:  []matcherFunc{func(string) (string, bool) { return "", true }},
+				paramMatchers: matchers,
+			},
+			LoadBalancer: zlb.NewBasicRoundRobin[upstream](ups),
+		}
+	}
+	proxy := &reverseProxy{
+		lg:          log.GlobalLogger(log.DefaultLoggerName),
+		eh:          &testErrorHandler{},
+		lbs:         []loadBalancer{lb("sub", "bob"), lb("groups", "c"), lb("sub", "alice")},
+		rt:          &testRoundTripper{status: http.StatusOK, body: io.NopCloser(bytes.NewReader([]byte("test")))},
+		cacheClaims: true,
+	}
+
+	count := 0
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/test", nil)
+	//nolint:staticcheck // SA1029: should not use built-in type string as key for value; define your own type to avoid collisions
+	r = r.WithContext(context.WithValue(r.Context(), "AuthnClaims", countingClaims{count: &count}))
+	w := httptest.NewRecorder()
+	proxy.ServeHTTP(w, r)
+	testutil.Diff(t, http.StatusOK, w.Code)
+	testutil.Diff(t, 1, count) // Encoded once for 3 matchers.
+}
+
 func TestProxyErrorResponse(t *testing.T) {
 	type condition struct {
 		err error
@@ -4,6 +4,7 @@
 package httpproxy
 
 import (
+	"cmp"
 	"net/http"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -33,6 +34,11 @@ func newHTTPHasher(spec *v1.HTTPHasherSpec) HTTPHasher {
 		return queryHasher(spec.Key)
 	case v1.HTTPHasherSpec_PathParam:
 		return pathParamHasher(spec.Key)
+	case v1.HTTPHasherSpec_Claim:
+		return &claimHasher{
+			key:  cmp.Or(spec.ClaimsKey, defaultClaimsKey),
+			path: spec.Key,
+		}
 	default:
 		return clientAddrHasher("")
 	}
@@ -80,3 +86,16 @@ func (h pathParamHasher) Hash(r *http.Request) uint64 {
 	name := string(h)
 	return xxhash.Sum64String(r.PathValue(name))
 }
+
+// claimHasher calculates hash value from authentication claim.
+type claimHasher struct {
+	// key is the context key to get claims.
+	key string
+	// path is the JSON path to the claim.
+	path string
+}
+
+func (h *claimHasher) Hash(r *http.Request) uint64 {
+	v, _ := claimValue(r, h.key, h.path)
+	return xxhash.Sum64String(v)
+}
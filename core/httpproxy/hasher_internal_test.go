@@ -4,6 +4,7 @@
 package httpproxy
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -149,3 +150,38 @@ func TestPathParamHasher(t *testing.T) {
 		})
 	}
 }
+
+func TestClaimHasher(t *testing.T) {
+	t.Parallel()
+
+	r, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
+	claims := map[string]any{
+		"foo":   "FOO",
+		"bar":   map[string]any{"baz": "BAZ"},
+		"roles": []any{"alice", "bob"},
+	}
+	//nolint:staticcheck // SA1029: should not use built-in type string as key for value; define your own type to avoid collisions
+	r = r.WithContext(context.WithValue(r.Context(), "AuthnClaims", claims))
+
+	testCases := map[string]struct {
+		key   string
+		path  string
+		value uint64
+	}{
+		"case01": {"AuthnClaims", "foo", xxhash.Sum64String("FOO")},
+		"case02": {"AuthnClaims", "bar.baz", xxhash.Sum64String("BAZ")},
+		"case03": {"AuthnClaims", "roles", xxhash.Sum64String("alice,bob")},
+		"case04": {"AuthnClaims", "alice", xxhash.Sum64String("")},
+		"case05": {"OtherClaims", "foo", xxhash.Sum64String("")},
+		"case06": {"AuthnClaims", "", xxhash.Sum64String("")},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := &claimHasher{key: tc.key, path: tc.path}
+			v := h.Hash(r)
+			if v != tc.value {
+				t.Error("hash value not match.", "want:", tc.value, "got:", v)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package httpproxy
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/textproto"
 	"path"
@@ -15,6 +17,7 @@ import (
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-gateway/aileron-gateway/core"
 	"github.com/aileron-gateway/aileron-gateway/internal/txtutil"
+	"github.com/tidwall/gjson"
 )
 
 // newMatcher returns a new matcherFunc.
@@ -278,3 +281,132 @@ func (m *queryMatcher) Match(r *http.Request) bool {
 		return m.f(strings.Join(v, ","))
 	}
 }
+
+// defaultClaimsKey is the default context key
+// that authentication middleware save claims with.
+const defaultClaimsKey = "AuthnClaims"
+
+// claimValue returns the claim value specified by the path.
+// Claims are obtained from the request context with the given key.
+// The path follows the syntax of https://github.com/tidwall/gjson/blob/master/SYNTAX.md
+// Array values are joined with a comma ",".
+// The returned bool is false when the claim was not found.
+func claimValue(r *http.Request, key, path string) (string, bool) {
+	b, ok := claimsJSON(r.Context(), key)
+	if !ok {
+		return "", false
+	}
+	res := gjson.GetBytes(b, path)
+	if !res.Exists() {
+		return "", false
+	}
+	if !res.IsArray() {
+		return res.String(), true
+	}
+	arr := res.Array()
+	vs := make([]string, 0, len(arr))
+	for _, v := range arr {
+		vs = append(vs, v.String())
+	}
+	return strings.Join(vs, ","), true
+}
+
+// claimsJSON returns the JSON encoded claims saved in the ctx with the key.
+// Encoded claims are cached in the claimsCache of the ctx if exists.
+// The returned bool is false when the claims were not found
+// or could not be encoded.
+func claimsJSON(ctx context.Context, key string) ([]byte, bool) {
+	claims := ctx.Value(key)
+	if claims == nil {
+		return nil, false
+	}
+	if b, ok := claims.([]byte); ok {
+		return b, true
+	}
+	cache, _ := ctx.Value(claimsCacheKey{}).(claimsCache)
+	if b, ok := cache[key]; ok {
+		return b, b != nil
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		b = nil
+	}
+	if cache != nil {
+		cache[key] = b
+	}
+	return b, b != nil
+}
+
+// claimsCacheKey is the context key of the claimsCache.
+type claimsCacheKey struct{}
+
+// claimsCache caches the JSON encoded claims per context key
+// so that the claims are encoded only once in a request
+// even when multiple claim matchers and hashers are evaluated.
+// Nil value means that the claims could not be encoded.
+// A claimsCache is request scoped and must not be used concurrently.
+type claimsCache map[string][]byte
+
+// withClaimsCache returns a shallow copy of the request
+// whose context has an empty claimsCache.
+func withClaimsCache(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), claimsCacheKey{}, claimsCache{}))
+}
+
+// usesClaims reports whether any of the load balancers
+// refers authentication claims in matchers or hashers.
+func usesClaims(specs []*v1.LoadBalancerSpec) bool {
+	for _, spec := range specs {
+		if spec.Hasher.GetHashSource() == v1.HTTPHasherSpec_Claim {
+			return true
+		}
+		for _, s := range spec.ClaimMatchers {
+			if s != nil && s.Key != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimMatchers returns claim matchers.
+// Claims are obtained from the request context with the given key.
+// nil spec and specs with empty key string are ignored.
+func claimMatchers(key string, specs ...*v1.ParamMatcherSpec) ([]txtutil.Matcher[*http.Request], error) {
+	matchers := make([]txtutil.Matcher[*http.Request], 0, len(specs))
+	for _, s := range specs {
+		if s == nil || s.Key == "" {
+			continue
+		}
+		matchFunc, err := txtutil.NewStringMatcher(txtutil.MatchType(s.MatchType), s.Patterns...)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, &claimMatcher{
+			key:  key,
+			path: s.Key,
+			f:    matchFunc.Match,
+		})
+	}
+	return matchers, nil
+}
+
+// claimMatcher is a matcher for an authentication claim.
+// If the claim was an array, joined string by commas
+// "," is input for the match function.
+// claimMatcher implements core.Matcher[*http.Request] interface.
+type claimMatcher struct {
+	// key is the context key to get claims.
+	key string
+	// path is the JSON path to the claim.
+	path string
+	f    txtutil.MatchFunc[string]
+}
+
+func (m *claimMatcher) Match(r *http.Request) bool {
+	v, ok := claimValue(r, m.key, m.path)
+	if !ok {
+		return false
+	}
+	return m.f(v)
+}
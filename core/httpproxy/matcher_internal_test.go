@@ -4,6 +4,7 @@
 package httpproxy
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -1571,3 +1572,285 @@ func TestHeaderMatchers(t *testing.T) {
 		})
 	}
 }
+
+func TestClaimMatchers(t *testing.T) {
+	type condition struct {
+		key    string
+		specs  []*v1.ParamMatcherSpec
+		claims any
+	}
+
+	type action struct {
+		numMatcher int
+		matchIndex int // -1 is not match.
+		err        error
+	}
+
+	type testClaims struct {
+		Tenant string   `json:"tenant"`
+		Roles  []string `json:"roles"`
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no spec",
+			&condition{
+				key:    "AuthnClaims",
+				specs:  nil,
+				claims: map[string]any{"tenant": "foo"},
+			},
+			&action{
+				numMatcher: 0,
+				matchIndex: -1,
+			},
+		),
+		gen(
+			"empty key",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{Key: ""},
+				},
+				claims: map[string]any{"tenant": "foo"},
+			},
+			&action{
+				numMatcher: 0,
+				matchIndex: -1,
+			},
+		),
+		gen(
+			"claims not found",
+			&condition{
+				key: "OtherClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: map[string]any{"tenant": "foo"},
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: -1,
+			},
+		),
+		gen(
+			"claim not found",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: map[string]any{"alice": "bob"},
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: -1,
+			},
+		),
+		gen(
+			"single matcher",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: map[string]any{"tenant": "foo"},
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: 0,
+			},
+		),
+		gen(
+			"nested claim",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "at_claims.tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: map[string]any{"at_claims": map[string]any{"tenant": "foo"}},
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: 0,
+			},
+		),
+		gen(
+			"struct claims",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "roles",
+						Patterns:  []string{"bar,baz"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: &testClaims{Tenant: "foo", Roles: []string{"bar", "baz"}},
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: 0,
+			},
+		),
+		gen(
+			"byte claims",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: []byte(`{"tenant":"foo"}`),
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: 0,
+			},
+		),
+		gen(
+			"unmarshalable claims",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"foo"},
+						MatchType: k.MatchType_Exact,
+					},
+				},
+				claims: make(chan int),
+			},
+			&action{
+				numMatcher: 1,
+				matchIndex: -1,
+			},
+		),
+		gen(
+			"matcher create error",
+			&condition{
+				key: "AuthnClaims",
+				specs: []*v1.ParamMatcherSpec{
+					{
+						Key:       "tenant",
+						Patterns:  []string{"[0-9a-"},
+						MatchType: k.MatchType_Regex,
+					},
+				},
+				claims: map[string]any{"tenant": "foo"},
+			},
+			&action{
+				numMatcher: 0,
+				matchIndex: -1,
+				err:        &zerrors.Err{Message: "internal/txtutil: invalid pattern for Regex matcher."},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			matchers, err := claimMatchers(tt.C.key, tt.C.specs...)
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			testutil.Diff(t, tt.A.numMatcher, len(matchers))
+
+			r, err := http.NewRequest(http.MethodGet, "http://test.com/", nil)
+			testutil.Diff(t, nil, err)
+			//nolint:staticcheck // SA1029: should not use built-in type string as key for value; define your own type to avoid collisions
+			r = r.WithContext(context.WithValue(r.Context(), "AuthnClaims", tt.C.claims))
+
+			matchIndex := -1
+			for i, matcher := range matchers {
+				ok := matcher.Match(r)
+				if ok {
+					matchIndex = i
+					break
+				}
+			}
+			testutil.Diff(t, tt.A.matchIndex, matchIndex)
+		})
+	}
+}
+
+// countingClaims counts the number of times the claims are encoded.
+type countingClaims struct {
+	count *int
+}
+
+func (c countingClaims) MarshalJSON() ([]byte, error) {
+	*c.count++
+	return []byte(`{"sub":"alice","groups":["a","b"]}`), nil
+}
+
+func TestClaimValue_cache(t *testing.T) {
+	count := 0
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	//nolint:staticcheck // SA1029: should not use built-in type string as key for value; define your own type to avoid collisions
+	r = r.WithContext(context.WithValue(r.Context(), "AuthnClaims", countingClaims{count: &count}))
+
+	// Claims are encoded on every call without cache.
+	v, ok := claimValue(r, "AuthnClaims", "sub")
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, "alice", v)
+	_, _ = claimValue(r, "AuthnClaims", "groups")
+	testutil.Diff(t, 2, count)
+
+	// Claims are encoded only once with cache.
+	count = 0
+	r = withClaimsCache(r)
+	v, ok = claimValue(r, "AuthnClaims", "sub")
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, "alice", v)
+	v, ok = claimValue(r, "AuthnClaims", "groups")
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, "a,b", v)
+	testutil.Diff(t, 1, count)
+
+	// Claims not found.
+	_, ok = claimValue(r, "NotExist", "sub")
+	testutil.Diff(t, false, ok)
+}
+
+func TestClaimValue_cacheInvalid(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://test.com/", nil)
+	//nolint:staticcheck // SA1029: should not use built-in type string as key for value; define your own type to avoid collisions
+	r = r.WithContext(context.WithValue(r.Context(), "AuthnClaims", func() {}))
+	r = withClaimsCache(r)
+	_, ok := claimValue(r, "AuthnClaims", "sub")
+	testutil.Diff(t, false, ok)
+	_, ok = claimValue(r, "AuthnClaims", "sub")
+	testutil.Diff(t, false, ok)
+}
+
+func TestUsesClaims(t *testing.T) {
+	testutil.Diff(t, false, usesClaims(nil))
+	testutil.Diff(t, false, usesClaims([]*v1.LoadBalancerSpec{
+		{Hasher: &v1.HTTPHasherSpec{HashSource: v1.HTTPHasherSpec_Header, Key: "foo"}},
+		{ClaimMatchers: []*v1.ParamMatcherSpec{nil, {Key: ""}}},
+	}))
+	testutil.Diff(t, true, usesClaims([]*v1.LoadBalancerSpec{
+		{Hasher: &v1.HTTPHasherSpec{HashSource: v1.HTTPHasherSpec_Claim, Key: "sub"}},
+	}))
+	testutil.Diff(t, true, usesClaims([]*v1.LoadBalancerSpec{
+		{ClaimMatchers: []*v1.ParamMatcherSpec{{Key: "sub"}}},
+	}))
+}
@@ -5,11 +5,15 @@ package httpserver
 
 import (
 	"cmp"
+	"context"
 	"crypto/tls"
 	"expvar"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"reflect"
 	"slices"
+	"strings"
 	"time"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
@@ -19,6 +23,7 @@ import (
 	"github.com/aileron-gateway/aileron-gateway/kernel/api"
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"github.com/quic-go/quic-go"
 	"github.com/quic-go/quic-go/http3"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -52,6 +57,7 @@ var Resource api.Resource = &API{
 				},
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*runner]()},
 	},
 }
 
@@ -78,66 +84,222 @@ func (*API) Create(a api.API[*api.Request, *api.Response], msg proto.Message) (a
 	c := msg.(*v1.HTTPServer)
 
 	lg := log.DefaultOr(c.Metadata.Logger)
-	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
-
-	mux := &http.ServeMux{}
-	registerProfile(mux, c.Spec.EnableProfile)
-	registerExpvar(mux, c.Spec.EnableExpvar)
 
-	nfh := notFoundHandler(eh)
-	if err := registerHandlers(a, mux, c.Spec.VirtualHosts, nfh); err != nil {
-		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
-	}
-
-	middleware, err := api.ReferTypedObjects[core.Middleware](a, c.Spec.Middleware...)
+	h, routes, err := newHandler(a, c)
 	if err != nil {
 		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
 	}
-	middleware = append([]core.Middleware{&recoverer{lg: lg, eh: eh}}, middleware...)
-	handler := utilhttp.MiddlewareChain(middleware, mux)
+	handler := &swapHandler{}
+	handler.store(h, routes)
 
 	runner := &runner{
 		svr:     nil,
 		lg:      lg,
 		timeout: time.Duration(c.Spec.ShutdownTimeout) * time.Second,
+		handler: handler,
 	}
 
 	if c.Spec.HTTP3Config != nil {
-		svr, err := newHTTP3Server(c.Spec.Addr, handler, c.Spec.HTTP3Config)
+		cp, err := certProvider(a, c.Spec.HTTP3Config.TLSConfig)
+		if err != nil {
+			return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+		}
+		svr, err := newHTTP3Server(lg, c.Spec.Addr, handler, c.Spec.HTTP3Config, cp)
 		if err != nil {
 			return nil, err
 		}
 		runner.svr = svr
 	} else {
-		svr, err := newHTTP2Server(c.Spec.Addr, handler, c.Spec.HTTPConfig, c.Spec.HTTP2Config)
+		var cp core.CertificateProvider
+		if c.Spec.HTTPConfig != nil && c.Spec.HTTPConfig.ListenConfig != nil {
+			cp, err = certProvider(a, c.Spec.HTTPConfig.ListenConfig.TLSConfig)
+			if err != nil {
+				return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+			}
+		}
+		svr, err := newHTTP2Server(lg, c.Spec.Addr, handler, c.Spec.HTTPConfig, c.Spec.HTTP2Config, cp)
 		if err != nil {
 			return nil, err
 		}
 		runner.svr = svr
 	}
+	// Clone the spec after creating the server
+	// because the defaults of the listener are set to the spec.
+	runner.spec = proto.Clone(c.Spec).(*v1.HTTPServerSpec)
 	return runner, nil
 }
 
+// Update replaces the handlers of the running server with the ones
+// created from the given manifest.
+// Handlers and middleware are obtained again from the API
+// so that the replaced resources are applied without restarting the server.
+// Configurations of the listener, TLS and timeouts cannot be updated.
+// An error is returned when they were changed.
+func (*API) Update(a api.API[*api.Request, *api.Response], msg proto.Message, obj any) error {
+	c := msg.(*v1.HTTPServer)
+	r, ok := obj.(*runner)
+	if !ok {
+		return nil
+	}
+	if fields := staticFields(r.spec, c.Spec); len(fields) > 0 {
+		err := core.ErrCoreServerUpdate.WithoutStack(nil, map[string]any{"fields": strings.Join(fields, ", ")})
+		return core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	h, routes, err := newHandler(a, c)
+	if err != nil {
+		return core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	r.handler.store(h, routes)
+	return nil
+}
+
+// staticFields returns the names of the fields which are
+// different between the specs and cannot be updated while running.
+// Fields used for the handlers, such as the virtual hosts
+// and the middleware, are not checked.
+func staticFields(old, spec *v1.HTTPServerSpec) []string {
+	spec = proto.Clone(spec).(*v1.HTTPServerSpec)
+	if spec.HTTPConfig != nil {
+		withListenConfig(spec.HTTPConfig, spec.Addr)
+	}
+	var fields []string
+	if old.Addr != spec.Addr {
+		fields = append(fields, "addr")
+	}
+	if old.ShutdownTimeout != spec.ShutdownTimeout {
+		fields = append(fields, "shutdownTimeout")
+	}
+	if !proto.Equal(old.HTTPConfig, spec.HTTPConfig) {
+		fields = append(fields, "httpConfig")
+	}
+	if !proto.Equal(old.HTTP2Config, spec.HTTP2Config) {
+		fields = append(fields, "http2Config")
+	}
+	if !proto.Equal(old.HTTP3Config, spec.HTTP3Config) {
+		fields = append(fields, "http3Config")
+	}
+	return fields
+}
+
+// newHandler returns a new handler of the server
+// which consists of the server middleware and the virtual hosts.
+// Routes registered to the handler are returned together.
+func newHandler(a api.API[*api.Request, *api.Response], c *v1.HTTPServer) (http.Handler, []core.Route, error) {
+	lg := log.DefaultOr(c.Metadata.Logger)
+	eh := utilhttp.GlobalErrorHandler(cmp.Or(c.Metadata.ErrorHandler, utilhttp.DefaultErrorHandlerName))
+
+	mux := &http.ServeMux{}
+	registerProfile(mux, c.Spec.EnableProfile)
+	registerExpvar(mux, c.Spec.EnableExpvar)
+
+	nfh := notFoundHandler(eh)
+	rec := &routeRecorder{Mux: mux}
+	vh, err := registerVirtualHosts(a, rec, c.Spec.VirtualHosts, nfh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	middleware, err := api.ReferTypedObjects[core.Middleware](a, c.Spec.Middleware...)
+	if err != nil {
+		return nil, nil, err
+	}
+	middleware = append([]core.Middleware{&recoverer{lg: lg, eh: eh}}, middleware...)
+	return utilhttp.MiddlewareChain(middleware, vh), rec.routes, nil
+}
+
+// certProvider returns the certificate provider
+// referred from the CertManager field of the given TLS config.
+// This function returns nil provider and nil error when not referred.
+func certProvider(a api.API[*api.Request, *api.Response], spec *kernel.TLSConfig) (core.CertificateProvider, error) {
+	if spec == nil || spec.CertManager == nil {
+		return nil, nil
+	}
+	return api.ReferTypedObject[core.CertificateProvider](a, spec.CertManager)
+}
+
+// withCertProvider configures the tls config to use
+// certificates provided by the cp.
+// The tls config is not modified when the tls config or the cp is nil.
+// When the tls config has static certificates, they are used as fallback
+// when clients did not send SNI or the cp failed to provide a certificate.
+// Errors of the cp are logged with the lg when falling back to static certificates.
+func withCertProvider(lg log.Logger, c *tls.Config, cp core.CertificateProvider) {
+	if c == nil || cp == nil {
+		return
+	}
+	c.GetCertificate = cp.GetCertificate
+	if len(c.Certificates) > 0 {
+		c.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName == "" {
+				return nil, nil // Use static certificates.
+			}
+			cert, err := cp.GetCertificate(hello)
+			if err != nil {
+				// Log the error so that failures of issuing or renewing
+				// certificates are noticed before static certificates expire.
+				lg.Warn(cmp.Or(hello.Context(), context.Background()), "failed to get certificate from the certificate manager. fall back to static certificates.",
+					"serverName", hello.ServerName, "error", err.Error())
+				// Returning nil certificate without error makes
+				// the crypto/tls select one from the static certificates.
+				return nil, nil
+			}
+			return cert, nil
+		}
+	}
+	if len(c.NextProtos) == 0 {
+		// Adding protocols to the empty NextProtos rejects
+		// clients that only support HTTP/1.1.
+		c.NextProtos = []string{"http/1.1"}
+	}
+	for _, p := range cp.NextProtos() {
+		if !slices.Contains(c.NextProtos, p) {
+			c.NextProtos = append(c.NextProtos, p)
+		}
+	}
+}
+
+// connContext saves the client connection in the context
+// so that the addresses of the client can be sent to
+// upstream servers by PROXY protocol.
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	return network.ContextWithConnAddrs(ctx, c)
+}
+
+// withListenConfig sets the default listen config to the c.
+// The addr is used when the listen address is not set.
+func withListenConfig(c *v1.HTTPConfig, addr string) {
+	c.ListenConfig = cmp.Or(c.ListenConfig, &kernel.ListenConfig{})
+	c.ListenConfig.Addr = cmp.Or(c.ListenConfig.Addr, addr)
+}
+
 // newHTTP2Server returns a new http2 server.
 // This function returns nil if the given HTTPConfig was nil.
 // The listen address addr must not be an empty string.
 // The http.Handler of h should not be nil.
-func newHTTP2Server(addr string, h http.Handler, c *v1.HTTPConfig, c2 *v1.HTTP2Config) (*http2Server, error) {
+// The certificate provider cp can be nil.
+// Errors of the cp are logged with the lg.
+func newHTTP2Server(lg log.Logger, addr string, h http.Handler, c *v1.HTTPConfig, c2 *v1.HTTP2Config, cp core.CertificateProvider) (*http2Server, error) {
 	if c == nil {
 		return nil, nil
 	}
 
-	c.ListenConfig = cmp.Or(c.ListenConfig, &kernel.ListenConfig{})
-	c.ListenConfig.Addr = cmp.Or(c.ListenConfig.Addr, addr)
-	listener, err := network.NewListenerFromSpec(c.ListenConfig)
+	withListenConfig(c, addr)
+	lc, err := network.ListenConfigFromSpec(c.ListenConfig)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+	withCertProvider(lg, lc.TLSConfig, cp)
+	listener, err := network.NewListener(lc)
 	if err != nil {
 		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
 	}
 
 	// Even the TLS has already been configured for the listener,
 	// tlsConfig is required when configuring http2 server.
-	// No errors here.
-	tlsConfig, _ := network.TLSConfig(c.ListenConfig.TLSConfig)
+	// The config of the listener is shared so that certificates
+	// are reloaded and OCSP responses are stapled only once.
+	// Modifications by the server such as NextProtos are applied to the listener.
+	tlsConfig := lc.TLSConfig
 
 	if c.AltSvc != "" {
 		h = altSvcMiddleware(c.AltSvc).Middleware(h)
@@ -153,6 +315,7 @@ func newHTTP2Server(addr string, h http.Handler, c *v1.HTTPConfig, c2 *v1.HTTP2C
 		WriteTimeout:                 time.Second * time.Duration(c.WriteTimeout),
 		IdleTimeout:                  time.Second * time.Duration(c.IdleTimeout),
 		MaxHeaderBytes:               int(c.MaxHeaderBytes),
+		ConnContext:                  connContext,
 	}
 	svr.SetKeepAlivesEnabled(!c.DisableKeepAlive)
 	if !c.AllowHTTP2 {
@@ -190,7 +353,9 @@ func newHTTP2Server(addr string, h http.Handler, c *v1.HTTPConfig, c2 *v1.HTTP2C
 // This function returns nil if the given HTTP3Config was nil.
 // The listen address addr must not be an empty string.
 // The http.Handler of h should not be nil.
-func newHTTP3Server(addr string, h http.Handler, c *v1.HTTP3Config) (*http3Server, error) {
+// The certificate provider cp can be nil.
+// Errors of the cp are logged with the lg.
+func newHTTP3Server(lg log.Logger, addr string, h http.Handler, c *v1.HTTP3Config, cp core.CertificateProvider) (*http3Server, error) {
 	if c == nil {
 		return nil, nil
 	}
@@ -199,6 +364,7 @@ func newHTTP3Server(addr string, h http.Handler, c *v1.HTTP3Config) (*http3Serve
 	if err != nil {
 		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
 	}
+	withCertProvider(lg, tlsConfig, cp)
 
 	qc, _ := network.QuicConfig(c.QuicConfig) // No error here.
 
@@ -206,6 +372,13 @@ func newHTTP3Server(addr string, h http.Handler, c *v1.HTTP3Config) (*http3Serve
 		h = altSvcMiddleware(c.AltSvc).Middleware(h)
 	}
 
+	// UDP socket is created here rather than in the Serve
+	// so that the socket can be shared when reloading configs.
+	conn, err := network.ListenPacket(addr)
+	if err != nil {
+		return nil, core.ErrCoreGenCreateObject.WithStack(err, map[string]any{"kind": kind})
+	}
+
 	return &http3Server{
 		svr: &http3.Server{
 			Addr:            addr,
@@ -215,7 +388,11 @@ func newHTTP3Server(addr string, h http.Handler, c *v1.HTTP3Config) (*http3Serve
 			Handler:         h,
 			EnableDatagrams: false, // Should be set in QuicConfig.
 			MaxHeaderBytes:  int(c.MaxHeaderBytes),
+			ConnContext: func(ctx context.Context, c *quic.Conn) context.Context {
+				return network.ContextWithConnAddrs(ctx, c)
+			},
 		},
+		conn: conn,
 	}, nil
 }
 
@@ -4,16 +4,20 @@
 package httpserver
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"expvar"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/http/pprof"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -458,13 +462,19 @@ func TestCreate(t *testing.T) {
 			opts := []cmp.Option{
 				cmp.Comparer(testutil.ComparePointer[log.Logger]),
 				cmp.AllowUnexported(runner{}),
+				cmpopts.IgnoreFields(runner{}, "handler"), // Handlers are tested in mux tests.
+				cmpopts.IgnoreFields(runner{}, "spec"),    // Spec is tested in update tests.
 				cmp.AllowUnexported(http2Server{}, http3Server{}),
+				cmpopts.IgnoreFields(http2Server{}, "listener"), // Listener is wrapped by the network package.
+				cmpopts.IgnoreFields(http3Server{}, "conn"),     // Conn is wrapped by the network package.
 				cmpopts.IgnoreUnexported(http.Server{}, http2.Server{}, http.ServeMux{}, http3.Server{}, net.TCPListener{}, tls.Config{}),
 				cmpopts.IgnoreUnexported(atomic.Int32{}),
 				cmpopts.IgnoreTypes(http.HandlerFunc(nil)),
 				cmpopts.IgnoreInterfaces(struct{ http.Handler }{}),
 				cmpopts.IgnoreFields(http.Server{}, "TLSNextProto"),
 				cmpopts.IgnoreFields(http.Server{}, "Addr"),
+				cmpopts.IgnoreFields(http.Server{}, "ConnContext"),
+				cmpopts.IgnoreFields(http3.Server{}, "ConnContext"),
 			}
 			testutil.Diff(t, tt.A.expect, got, opts...)
 		})
@@ -673,7 +683,7 @@ func TestNewHTTP2Server(t *testing.T) {
 	for _, tt := range testCases {
 		tt := tt
 		t.Run(tt.Name, func(t *testing.T) {
-			srv, err := newHTTP2Server(tt.C.addr, tt.C.h, tt.C.c1, tt.C.c2)
+			srv, err := newHTTP2Server(log.GlobalLogger(log.DefaultLoggerName), tt.C.addr, tt.C.h, tt.C.c1, tt.C.c2, nil)
 			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
 			if srv != nil {
 				srv.Shutdown(context.Background())
@@ -682,10 +692,12 @@ func TestNewHTTP2Server(t *testing.T) {
 
 			opts := []cmp.Option{
 				cmp.AllowUnexported(http2Server{}, testHandler{}),
+				cmpopts.IgnoreFields(http2Server{}, "listener"), // Listener is wrapped by the network package.
 				cmpopts.IgnoreUnexported(http.Server{}, http2.Server{}),
 				cmpopts.IgnoreUnexported(net.TCPListener{}, tls.Config{}),
 				cmpopts.IgnoreTypes(http.HandlerFunc(nil)), // Skip alt-svc middlewarte.
 				cmpopts.IgnoreFields(http.Server{}, "TLSNextProto"),
+				cmpopts.IgnoreFields(http.Server{}, "ConnContext"),
 				deepAllowUnexported(h2c.NewHandler(nil, nil)),
 			}
 			testutil.Diff(t, tt.A.svr, srv, opts...)
@@ -843,7 +855,7 @@ func TestNewHTTP3Server(t *testing.T) {
 	for _, tt := range testCases {
 		tt := tt
 		t.Run(tt.Name, func(t *testing.T) {
-			srv, err := newHTTP3Server(tt.C.addr, tt.C.h, tt.C.c)
+			srv, err := newHTTP3Server(log.GlobalLogger(log.DefaultLoggerName), tt.C.addr, tt.C.h, tt.C.c, nil)
 			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
 			if srv != nil {
 				srv.Shutdown(context.Background())
@@ -851,7 +863,9 @@ func TestNewHTTP3Server(t *testing.T) {
 
 			opts := []cmp.Option{
 				cmp.AllowUnexported(http3Server{}, testHandler{}, quic.Config{}),
+				cmpopts.IgnoreFields(http3Server{}, "conn"), // Conn is wrapped by the network package.
 				cmpopts.IgnoreUnexported(http3.Server{}),
+				cmpopts.IgnoreFields(http3.Server{}, "ConnContext"),
 				cmpopts.IgnoreUnexported(tls.Config{}),
 				cmpopts.IgnoreFields(tls.Config{}, "RootCAs", "ClientCAs"),
 				cmpopts.IgnoreTypes(http.HandlerFunc(nil)), // Skip alt-svc middlewarte.
@@ -861,6 +875,147 @@ func TestNewHTTP3Server(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	testAPI := api.NewContainerAPI()
+	postTestResource(testAPI, "handler", &testHandler{body: "old", patterns: []string{"/test"}})
+
+	manifest := &v1.HTTPServer{
+		Metadata: &k.Metadata{},
+		Spec: &v1.HTTPServerSpec{
+			Addr:       "127.0.0.1:0",
+			HTTPConfig: &v1.HTTPConfig{},
+			VirtualHosts: []*v1.VirtualHostSpec{
+				{Handlers: []*v1.HTTPHandlerSpec{{Handler: testResourceRef("handler")}}},
+			},
+		},
+	}
+	got, err := Resource.Create(testAPI, manifest)
+	testutil.Diff(t, nil, err)
+	r := got.(*runner)
+	defer r.svr.(*http2Server).listener.Close()
+
+	serve := func() string {
+		w := httptest.NewRecorder()
+		r.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://test.com/test", nil))
+		return w.Body.String()
+	}
+	testutil.Diff(t, "old", serve())
+
+	// Replace the referred handler and update the server.
+	ref := testResourceRef("handler")
+	_, err = testAPI.Serve(context.Background(), &api.Request{
+		Method:  api.MethodPut,
+		Key:     ref.APIVersion + "/" + ref.Kind + "/" + ref.Namespace + "/" + ref.Name,
+		Content: &testHandler{body: "new", patterns: []string{"/test", "/new"}},
+	})
+	testutil.Diff(t, nil, err)
+	updater := Resource.(api.Updater)
+	testutil.Diff(t, nil, updater.Update(testAPI, manifest, r))
+	testutil.Diff(t, "new", serve())
+	testutil.Diff(t, 2, len(r.Routes()))
+
+	// Handlers are kept when failed to update.
+	manifest.Spec.VirtualHosts[0].Handlers[0].Handler = testResourceRef("not-exist")
+	err = updater.Update(testAPI, manifest, r)
+	testutil.DiffError(t, core.ErrCoreGenCreateObject, regexp.MustCompile(core.ErrPrefix+`failed to create HTTPServer`), err)
+	testutil.Diff(t, "new", serve())
+
+	// Fields other than the handlers cannot be updated.
+	manifest.Spec.VirtualHosts[0].Handlers[0].Handler = testResourceRef("handler")
+	manifest.Spec.Addr = "127.0.0.1:12345"
+	manifest.Spec.HTTPConfig.ReadTimeout = 10
+	err = updater.Update(testAPI, manifest, r)
+	testutil.DiffError(t, core.ErrCoreGenCreateObject, regexp.MustCompile(`addr, httpConfig cannot be updated while running`), err)
+	testutil.Diff(t, "new", serve())
+
+	// Objects other than the runner are ignored.
+	testutil.Diff(t, nil, updater.Update(testAPI, manifest, "not a runner"))
+}
+
+func TestStaticFields(t *testing.T) {
+	type condition struct {
+		old  *v1.HTTPServerSpec
+		spec *v1.HTTPServerSpec
+	}
+
+	type action struct {
+		fields []string
+	}
+
+	// Defaults of the listener are set to the specs of created servers.
+	created := &v1.HTTPServerSpec{
+		Addr:            ":8080",
+		ShutdownTimeout: 30,
+		HTTPConfig:      &v1.HTTPConfig{ReadTimeout: 30, ListenConfig: &k.ListenConfig{Addr: ":8080"}},
+		VirtualHosts:    []*v1.VirtualHostSpec{{Hosts: []string{"old.com"}}},
+	}
+	base := func() *v1.HTTPServerSpec {
+		return &v1.HTTPServerSpec{
+			Addr:            ":8080",
+			ShutdownTimeout: 30,
+			HTTPConfig:      &v1.HTTPConfig{ReadTimeout: 30},
+			VirtualHosts:    []*v1.VirtualHostSpec{{Hosts: []string{"old.com"}}},
+		}
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no changes",
+			&condition{old: created, spec: base()},
+			&action{},
+		),
+		gen(
+			"handler fields changed",
+			&condition{
+				old: created,
+				spec: func() *v1.HTTPServerSpec {
+					s := base()
+					s.VirtualHosts = []*v1.VirtualHostSpec{{Hosts: []string{"new.com"}}}
+					s.Middleware = []*k.Reference{testResourceRef("middleware")}
+					s.EnableProfile = true
+					s.EnableExpvar = true
+					return s
+				}(),
+			},
+			&action{},
+		),
+		gen(
+			"all static fields changed",
+			&condition{
+				old: created,
+				spec: &v1.HTTPServerSpec{
+					Addr:            ":8443",
+					ShutdownTimeout: 10,
+					HTTPConfig:      &v1.HTTPConfig{ReadTimeout: 10},
+					HTTP2Config:     &v1.HTTP2Config{},
+					HTTP3Config:     &v1.HTTP3Config{},
+				},
+			},
+			&action{fields: []string{"addr", "shutdownTimeout", "httpConfig", "http2Config", "http3Config"}},
+		),
+		gen(
+			"listen address changed",
+			&condition{
+				old: created,
+				spec: func() *v1.HTTPServerSpec {
+					s := base()
+					s.HTTPConfig.ListenConfig = &k.ListenConfig{Addr: ":9090"}
+					return s
+				}(),
+			},
+			&action{fields: []string{"httpConfig"}},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			fields := staticFields(tt.C.old, tt.C.spec)
+			testutil.Diff(t, tt.A.fields, fields)
+		})
+	}
+}
+
 type testMiddleware struct {
 	id      string
 	headers map[string]string
@@ -1088,3 +1243,148 @@ func testResourceRef(name string) *k.Reference {
 		Name:       name,
 	}
 }
+
+// testCertProvider is the certificate provider for testing.
+type testCertProvider struct {
+	cert *tls.Certificate
+	err  error
+}
+
+func (p *testCertProvider) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return p.cert, p.err
+}
+
+func (p *testCertProvider) NextProtos() []string {
+	return []string{"acme-tls/1"}
+}
+
+func TestWithCertProvider(t *testing.T) {
+	type condition struct {
+		config *tls.Config
+		cp     core.CertificateProvider
+	}
+
+	type action struct {
+		nextProtos []string
+		getCert    bool
+		serverName string
+		cert       *tls.Certificate
+		err        error
+	}
+
+	cert := &tls.Certificate{}
+	static := []tls.Certificate{{}}
+	errGet := errors.New("get certificate error")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil provider",
+			&condition{config: &tls.Config{NextProtos: []string{"h2"}}},
+			&action{nextProtos: []string{"h2"}, getCert: false},
+		),
+		gen(
+			"add next protos",
+			&condition{config: &tls.Config{NextProtos: []string{"h2"}}, cp: &testCertProvider{cert: cert}},
+			&action{nextProtos: []string{"h2", "acme-tls/1"}, getCert: true, cert: cert},
+		),
+		gen(
+			"next protos already exist",
+			&condition{config: &tls.Config{NextProtos: []string{"acme-tls/1"}}, cp: &testCertProvider{cert: cert}},
+			&action{nextProtos: []string{"acme-tls/1"}, getCert: true, cert: cert},
+		),
+		gen(
+			"provider error without static certs",
+			&condition{config: &tls.Config{NextProtos: []string{"h2"}}, cp: &testCertProvider{err: errGet}},
+			&action{nextProtos: []string{"h2", "acme-tls/1"}, getCert: true, serverName: "test.com", err: errGet},
+		),
+		gen(
+			"provide cert with static certs",
+			&condition{config: &tls.Config{Certificates: static}, cp: &testCertProvider{cert: cert}},
+			&action{nextProtos: []string{"http/1.1", "acme-tls/1"}, getCert: true, serverName: "test.com", cert: cert},
+		),
+		gen(
+			"fallback on provider error",
+			&condition{config: &tls.Config{Certificates: static}, cp: &testCertProvider{err: errGet}},
+			&action{nextProtos: []string{"http/1.1", "acme-tls/1"}, getCert: true, serverName: "test.com"},
+		),
+		gen(
+			"fallback without SNI",
+			&condition{config: &tls.Config{Certificates: static}, cp: &testCertProvider{cert: cert}},
+			&action{nextProtos: []string{"http/1.1", "acme-tls/1"}, getCert: true},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			withCertProvider(log.GlobalLogger(log.DefaultLoggerName), tt.C.config, tt.C.cp)
+			testutil.Diff(t, tt.A.nextProtos, tt.C.config.NextProtos)
+			testutil.Diff(t, tt.A.getCert, tt.C.config.GetCertificate != nil)
+			if tt.A.getCert {
+				got, err := tt.C.config.GetCertificate(&tls.ClientHelloInfo{ServerName: tt.A.serverName})
+				testutil.Diff(t, true, got == tt.A.cert)
+				testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			}
+		})
+	}
+
+	withCertProvider(log.GlobalLogger(log.DefaultLoggerName), nil, &testCertProvider{}) // Must not panic.
+}
+
+func TestWithCertProviderHandshake(t *testing.T) {
+	certFile := testDir + "ut/core/server/server.crt"
+	keyFile := testDir + "ut/core/server/server.key"
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	testutil.DiffError(t, nil, nil, err)
+
+	for _, serverName := range []string{"", "unknown.example.com"} {
+		t.Run("server name="+serverName, func(t *testing.T) {
+			var buf bytes.Buffer
+			lg := log.NewJSONSLogger(&buf, nil)
+			c := &tls.Config{Certificates: []tls.Certificate{cert}}
+			withCertProvider(lg, c, &testCertProvider{err: errors.New("host not configured")})
+
+			sc, cc := net.Pipe()
+			defer sc.Close()
+			defer cc.Close()
+			go func() {
+				_ = tls.Server(sc, c).Handshake()
+			}()
+			client := tls.Client(cc, &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+			err := client.Handshake()
+			testutil.DiffError(t, nil, nil, err)
+			peer := client.ConnectionState().PeerCertificates
+			testutil.Diff(t, 1, len(peer))
+			testutil.Diff(t, cert.Certificate[0], peer[0].Raw)
+
+			// Errors of the provider are logged when falling back.
+			logged := strings.Contains(buf.String(), "host not configured")
+			testutil.Diff(t, serverName != "", logged)
+		})
+	}
+}
+
+func TestNewHTTP2Server_sharedTLSConfig(t *testing.T) {
+	c := &v1.HTTPConfig{
+		ListenConfig: &k.ListenConfig{
+			Addr: "127.0.0.1:0",
+			TLSConfig: &k.TLSConfig{
+				CertKeyPairs: []*k.CertKeyPair{{
+					CertFile: testDir + "ut/core/server/server.crt",
+					KeyFile:  testDir + "ut/core/server/server.key",
+				}},
+			},
+		},
+	}
+	srv, err := newHTTP2Server(log.GlobalLogger(log.DefaultLoggerName), "", &testHandler{id: "test"}, c, &v1.HTTP2Config{}, nil)
+	testutil.Diff(t, nil, err)
+	go srv.Serve()
+	defer srv.Shutdown(context.Background())
+
+	// The listener shares the TLS config of the server.
+	// So the protocols configured by the server are negotiated.
+	conn, err := tls.Dial("tcp", srv.Addr(), &tls.Config{NextProtos: []string{"h2"}, InsecureSkipVerify: true})
+	testutil.Diff(t, nil, err)
+	defer conn.Close()
+	testutil.Diff(t, "h2", conn.ConnectionState().NegotiatedProtocol)
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package httpserver
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// hostMatcher matches request hosts to a virtual host
+// by a wildcard host or a regular expression.
+type hostMatcher struct {
+	// suffix is the lower cased suffix of the wildcard host
+	// such as ".example.com" for "*.example.com".
+	suffix string
+	// re is the case-insensitive regular expression of the host.
+	re *regexp.Regexp
+	// handler is the handler of the virtual host.
+	handler http.Handler
+}
+
+// match reports whether the host matches to the matcher.
+// Parameters captured from the host are returned when matched.
+// The given host must be lower cased and must not contain port.
+func (m *hostMatcher) match(host string) (map[string]string, bool) {
+	if m.re == nil {
+		if len(host) <= len(m.suffix) || !strings.HasSuffix(host, m.suffix) {
+			return nil, false
+		}
+		return map[string]string{"wildcard": host[:len(host)-len(m.suffix)]}, true
+	}
+	matches := m.re.FindStringSubmatch(host)
+	if matches == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(matches)-1)
+	for i, name := range m.re.SubexpNames() {
+		if i == 0 {
+			continue
+		}
+		params[strconv.Itoa(i)] = matches[i]
+		if name != "" {
+			params[name] = matches[i]
+		}
+	}
+	return params, true
+}
+
+// hostRouter routes requests to virtual hosts by the request host.
+// Exact hosts take precedence over wildcard hosts
+// and wildcard hosts take precedence over regular expressions.
+// Requests that do not match to any wildcard hosts
+// and regular expressions are served by the next handler,
+// which routes requests by exact hosts.
+type hostRouter struct {
+	next http.Handler
+	// exact is the set of lower cased exact hosts
+	// registered to the next handler.
+	exact map[string]struct{}
+	// wildcards are the matchers of wildcard hosts
+	// sorted in the descending order of the suffix length.
+	wildcards []*hostMatcher
+	// regexps are the matchers of regular expressions
+	// in the order of the configuration.
+	regexps []*hostMatcher
+}
+
+func (h *hostRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := hostname(r.Host)
+	if _, ok := h.exact[host]; ok {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	for _, m := range h.wildcards {
+		if params, ok := m.match(host); ok {
+			m.handler.ServeHTTP(w, r.WithContext(utilhttp.ContextWithHostParams(r.Context(), params)))
+			return
+		}
+	}
+	for _, m := range h.regexps {
+		if params, ok := m.match(host); ok {
+			m.handler.ServeHTTP(w, r.WithContext(utilhttp.ContextWithHostParams(r.Context(), params)))
+			return
+		}
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// hostname returns the lower cased host without port.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// isWildcardHost reports whether the host is a wildcard host such as "*.example.com".
+func isWildcardHost(host string) bool {
+	return strings.HasPrefix(host, "*.")
+}
+
+// registerVirtualHosts registers the virtual hosts to the mux.
+// Virtual hosts with exact hosts or without hosts are registered to the given mux.
+// Virtual hosts with wildcard hosts or host patterns are registered to
+// their own mux and routed by the returned handler.
+// The mux is returned as-is when there is no wildcard hosts and no host patterns.
+// Routes registered to the own mux are recorded to the mux
+// when the mux is a *routeRecorder.
+func registerVirtualHosts(a api.API[*api.Request, *api.Response], mux Mux, specs []*v1.VirtualHostSpec, notFound http.Handler) (http.Handler, error) {
+	router := &hostRouter{
+		next:  mux,
+		exact: map[string]struct{}{},
+	}
+	exactSpecs := make([]*v1.VirtualHostSpec, 0, len(specs))
+
+	for _, spec := range specs {
+		var exact, wildcards []string
+		for _, h := range spec.Hosts {
+			if isWildcardHost(h) {
+				wildcards = append(wildcards, h)
+			} else {
+				exact = append(exact, h)
+				router.exact[strings.ToLower(h)] = struct{}{}
+			}
+		}
+		if len(wildcards) == 0 && len(spec.HostPatterns) == 0 {
+			exactSpecs = append(exactSpecs, spec)
+			continue
+		}
+		if len(exact) > 0 {
+			s := proto.Clone(spec).(*v1.VirtualHostSpec)
+			s.Hosts = exact
+			s.HostPatterns = nil
+			exactSpecs = append(exactSpecs, s)
+		}
+
+		s := proto.Clone(spec).(*v1.VirtualHostSpec)
+		s.Hosts = nil
+		s.HostPatterns = nil
+		sub := &routeRecorder{Mux: &http.ServeMux{}}
+		if err := registerHandlers(a, sub, []*v1.VirtualHostSpec{s}, notFound); err != nil {
+			return nil, err
+		}
+
+		hosts := wildcards
+		for _, p := range spec.HostPatterns {
+			// Hosts are case-insensitive and the request hosts are lower cased.
+			// So the patterns are also matched case-insensitively.
+			re, err := regexp.Compile("(?i)^(?:" + p + ")$")
+			if err != nil {
+				reason := "invalid host pattern `" + p + "`"
+				return nil, core.ErrCoreGenCreateComponent.WithStack(err, map[string]any{"reason": reason})
+			}
+			router.regexps = append(router.regexps, &hostMatcher{re: re, handler: sub})
+			hosts = append(hosts, p)
+		}
+		for _, h := range wildcards {
+			router.wildcards = append(router.wildcards, &hostMatcher{suffix: strings.ToLower(h[1:]), handler: sub})
+		}
+		if rec, ok := mux.(*routeRecorder); ok {
+			for _, h := range hosts {
+				for _, route := range sub.routes {
+					route.Host = h
+					rec.routes = append(rec.routes, route)
+				}
+			}
+		}
+	}
+
+	if err := registerHandlers(a, mux, exactSpecs, notFound); err != nil {
+		return nil, err
+	}
+	if len(router.wildcards) == 0 && len(router.regexps) == 0 {
+		return mux, nil
+	}
+	slices.SortStableFunc(router.wildcards, func(x, y *hostMatcher) int {
+		return len(y.suffix) - len(x.suffix)
+	})
+	return router, nil
+}
@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	utilhttp "github.com/aileron-gateway/aileron-gateway/util/http"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// hostParamsHandler writes its id and the host params to the response.
+type hostParamsHandler struct {
+	id string
+}
+
+func (h *hostParamsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Handler", h.id)
+	for k, v := range utilhttp.HostParamsFromContext(r.Context()) {
+		w.Header().Set("Param-"+k, v)
+	}
+}
+
+func TestHostMatcher_match(t *testing.T) {
+	type condition struct {
+		matcher *hostMatcher
+		host    string
+	}
+
+	type action struct {
+		params  map[string]string
+		matched bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"wildcard matched",
+			&condition{
+				matcher: &hostMatcher{suffix: ".example.com"},
+				host:    "foo.example.com",
+			},
+			&action{
+				params:  map[string]string{"wildcard": "foo"},
+				matched: true,
+			},
+		),
+		gen(
+			"wildcard matched multiple labels",
+			&condition{
+				matcher: &hostMatcher{suffix: ".example.com"},
+				host:    "foo.bar.example.com",
+			},
+			&action{
+				params:  map[string]string{"wildcard": "foo.bar"},
+				matched: true,
+			},
+		),
+		gen(
+			"wildcard not matched to the base domain",
+			&condition{
+				matcher: &hostMatcher{suffix: ".example.com"},
+				host:    "example.com",
+			},
+			&action{},
+		),
+		gen(
+			"wildcard not matched to the suffix",
+			&condition{
+				matcher: &hostMatcher{suffix: ".example.com"},
+				host:    ".example.com",
+			},
+			&action{},
+		),
+		gen(
+			"wildcard not matched",
+			&condition{
+				matcher: &hostMatcher{suffix: ".example.com"},
+				host:    "fooexample.com",
+			},
+			&action{},
+		),
+		gen(
+			"regexp matched",
+			&condition{
+				matcher: &hostMatcher{re: regexp.MustCompile(`^(?:(?P<tenant>[a-z]+)-([0-9]+)\.example\.com)$`)},
+				host:    "foo-123.example.com",
+			},
+			&action{
+				params:  map[string]string{"tenant": "foo", "1": "foo", "2": "123"},
+				matched: true,
+			},
+		),
+		gen(
+			"regexp without groups",
+			&condition{
+				matcher: &hostMatcher{re: regexp.MustCompile(`^(?:.+\.example\.com)$`)},
+				host:    "foo.example.com",
+			},
+			&action{
+				params:  map[string]string{},
+				matched: true,
+			},
+		),
+		gen(
+			"regexp not matched",
+			&condition{
+				matcher: &hostMatcher{re: regexp.MustCompile(`^(?:[a-z]+\.example\.com)$`)},
+				host:    "foo.example.com.evil",
+			},
+			&action{},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			params, matched := tt.C.matcher.match(tt.C.host)
+			testutil.Diff(t, tt.A.matched, matched)
+			testutil.Diff(t, tt.A.params, params)
+		})
+	}
+}
+
+func TestHostname(t *testing.T) {
+	testutil.Diff(t, "example.com", hostname("example.com"))
+	testutil.Diff(t, "example.com", hostname("Example.COM:8080"))
+	testutil.Diff(t, "::1", hostname("[::1]:8080"))
+	testutil.Diff(t, "", hostname(""))
+}
+
+func TestRegisterVirtualHosts(t *testing.T) {
+	type condition struct {
+		specs []*v1.VirtualHostSpec
+		host  string
+	}
+
+	type action struct {
+		handler    string
+		params     map[string]string
+		router     bool
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	testAPI := api.NewContainerAPI()
+	for _, id := range []string{"exact", "wildcard", "long-wildcard", "regexp", "default"} {
+		postTestResource(testAPI, id, &hostParamsHandler{id: id})
+	}
+	vh := func(handler string, hosts []string, patterns []string) *v1.VirtualHostSpec {
+		return &v1.VirtualHostSpec{
+			Hosts:        hosts,
+			HostPatterns: patterns,
+			Handlers:     []*v1.HTTPHandlerSpec{{Handler: testResourceRef(handler)}},
+		}
+	}
+	specs := []*v1.VirtualHostSpec{
+		vh("regexp", nil, []string{`(?P<tenant>[a-z]+)-(?P<region>[a-z]+)\.example\.com`}),
+		vh("wildcard", []string{"*.example.com"}, nil),
+		vh("long-wildcard", []string{"*.api.example.com"}, nil),
+		vh("exact", []string{"foo.example.com", "foo-bar.example.com"}, nil),
+		vh("default", nil, nil),
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"exact hosts only",
+			&condition{
+				specs: []*v1.VirtualHostSpec{vh("exact", []string{"foo.example.com"}, nil)},
+				host:  "foo.example.com",
+			},
+			&action{
+				handler: "exact",
+			},
+		),
+		gen(
+			"exact host precedes wildcard and regexp",
+			&condition{
+				specs: specs,
+				host:  "foo-bar.example.com:8443",
+			},
+			&action{
+				handler: "exact",
+				router:  true,
+			},
+		),
+		gen(
+			"wildcard precedes regexp",
+			&condition{
+				specs: specs,
+				host:  "baz-qux.example.com",
+			},
+			&action{
+				handler: "wildcard",
+				params:  map[string]string{"wildcard": "baz-qux"},
+				router:  true,
+			},
+		),
+		gen(
+			"longer wildcard precedes",
+			&condition{
+				specs: specs,
+				host:  "v1.api.example.com",
+			},
+			&action{
+				handler: "long-wildcard",
+				params:  map[string]string{"wildcard": "v1"},
+				router:  true,
+			},
+		),
+		gen(
+			"regexp matched",
+			&condition{
+				specs: []*v1.VirtualHostSpec{specs[0], specs[3], specs[4]},
+				host:  "baz-qux.example.com",
+			},
+			&action{
+				handler: "regexp",
+				params:  map[string]string{"tenant": "baz", "region": "qux", "1": "baz", "2": "qux"},
+				router:  true,
+			},
+		),
+		gen(
+			"regexp must match entire host",
+			&condition{
+				specs: []*v1.VirtualHostSpec{specs[0], specs[3], specs[4]},
+				host:  "baz-qux.example.com.test",
+			},
+			&action{
+				handler: "default",
+				router:  true,
+			},
+		),
+		gen(
+			"regexp matched case-insensitively",
+			&condition{
+				specs: []*v1.VirtualHostSpec{vh("regexp", nil, []string{`(?P<tenant>[A-Z]+)\.API\.Example\.com`})},
+				host:  "Foo.api.EXAMPLE.com",
+			},
+			&action{
+				handler: "regexp",
+				params:  map[string]string{"tenant": "foo", "1": "foo"},
+				router:  true,
+			},
+		),
+		gen(
+			"exact and wildcard hosts in a virtual host",
+			&condition{
+				specs: []*v1.VirtualHostSpec{vh("wildcard", []string{"example.com", "*.example.com"}, nil)},
+				host:  "example.com",
+			},
+			&action{
+				handler: "wildcard",
+				router:  true,
+			},
+		),
+		gen(
+			"invalid host pattern",
+			&condition{
+				specs: []*v1.VirtualHostSpec{vh("regexp", nil, []string{`[a-z`})},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateComponent,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create component. invalid host pattern`),
+			},
+		),
+		gen(
+			"handler not found",
+			&condition{
+				specs: []*v1.VirtualHostSpec{vh("not-exist", []string{"*.example.com"}, nil)},
+			},
+			&action{
+				err:        core.ErrCoreGenCreateComponent,
+				errPattern: regexp.MustCompile(core.ErrPrefix + `failed to create component. failed to create handler`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+			h, err := registerVirtualHosts(testAPI, &http.ServeMux{}, tt.C.specs, notFound)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err)
+			if err != nil {
+				return
+			}
+			_, ok := h.(*hostRouter)
+			testutil.Diff(t, tt.A.router, ok)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "http://"+tt.C.host+"/", nil)
+			h.ServeHTTP(w, r)
+			testutil.Diff(t, tt.A.handler, w.Header().Get("Handler"))
+			params := map[string]string{}
+			for k := range w.Header() {
+				if len(k) > 6 && k[:6] == "Param-" {
+					params[http.CanonicalHeaderKey(k[6:])] = w.Header().Get(k)
+				}
+			}
+			want := map[string]string{}
+			for k, v := range tt.A.params {
+				want[http.CanonicalHeaderKey(k)] = v
+			}
+			testutil.Diff(t, want, params)
+		})
+	}
+}
+
+func TestRegisterVirtualHosts_routes(t *testing.T) {
+	testAPI := api.NewContainerAPI()
+	postTestResource(testAPI, "handler", &testHandler{
+		id:       "handler",
+		patterns: []string{"/foo"},
+		methods:  []string{http.MethodGet},
+	})
+	specs := []*v1.VirtualHostSpec{
+		{
+			Hosts:        []string{"example.com", "*.example.com"},
+			HostPatterns: []string{`[a-z]+\.example\.org`},
+			Handlers:     []*v1.HTTPHandlerSpec{{Handler: testResourceRef("handler")}},
+		},
+	}
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := &routeRecorder{Mux: &http.ServeMux{}}
+	_, err := registerVirtualHosts(testAPI, rec, specs, notFound)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, []core.Route{
+		{Host: "*.example.com", Path: "/foo", Methods: []string{http.MethodGet}},
+		{Host: `[a-z]+\.example\.org`, Path: "/foo", Methods: []string{http.MethodGet}},
+		{Host: "example.com", Path: "/foo", Methods: []string{http.MethodGet}},
+	}, rec.routes, cmpopts.EquateEmpty())
+}
@@ -30,6 +30,28 @@ type Mux interface {
 	Handle(pattern string, h http.Handler)
 }
 
+// routeRecorder records the routes registered to the mux.
+// Only the handlers registered with methodCheckHandler are recorded
+// so that not found handlers are excluded.
+type routeRecorder struct {
+	Mux
+	routes []core.Route
+}
+
+func (r *routeRecorder) Handle(pattern string, h http.Handler) {
+	r.Mux.Handle(pattern, h)
+	mh, ok := h.(*methodCheckHandler)
+	if !ok {
+		return
+	}
+	i := strings.Index(pattern, "/")
+	r.routes = append(r.routes, core.Route{
+		Host:    pattern[:i],
+		Path:    pattern[i:],
+		Methods: mh.allowMethods,
+	})
+}
+
 // registerHandlers register virtual host handlers to the given mux..
 // The function panics if the mux is nil.
 func registerHandlers(a api.API[*api.Request, *api.Response], mux Mux, specs []*v1.VirtualHostSpec, notFound http.Handler) (err error) {
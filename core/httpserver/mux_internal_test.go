@@ -601,6 +601,45 @@ func TestRegisterHandlers(t *testing.T) {
 	}
 }
 
+func TestRouteRecorder(t *testing.T) {
+	testAPI := api.NewContainerAPI()
+	h1 := &testHandler{
+		id:       "handler1",
+		patterns: []string{"/foo", "/bar/"},
+		methods:  []string{http.MethodGet},
+	}
+	h2 := &testHandler{
+		id: "handler2",
+	}
+	postTestResource(testAPI, "handler1", h1)
+	postTestResource(testAPI, "handler2", h2)
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	specs := []*v1.VirtualHostSpec{
+		{
+			Hosts:   []string{"example.com"},
+			Pattern: "/api",
+			Handlers: []*v1.HTTPHandlerSpec{
+				{Handler: testResourceRef("handler1")},
+			},
+		},
+		{
+			Handlers: []*v1.HTTPHandlerSpec{
+				{Handler: testResourceRef("handler2"), Pattern: "/baz"},
+			},
+		},
+	}
+
+	rec := &routeRecorder{Mux: &http.ServeMux{}}
+	err := registerHandlers(testAPI, rec, specs, notFound)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, []core.Route{
+		{Host: "example.com", Path: "/api/bar/", Methods: []string{http.MethodGet}},
+		{Host: "example.com", Path: "/api/foo", Methods: []string{http.MethodGet}},
+		{Host: "", Path: "/baz"},
+	}, rec.routes, cmpopts.EquateEmpty())
+}
+
 func TestIntersectionString(t *testing.T) {
 	type condition struct {
 		set1 []string
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
 	"github.com/aileron-gateway/aileron-gateway/core"
 	"github.com/aileron-gateway/aileron-gateway/kernel/log"
 	"github.com/quic-go/quic-go/http3"
@@ -23,11 +25,50 @@ type server interface {
 }
 
 // runner is a HTTP/HTTPS server runner.
-// This implements core.Runner interfaces.
+// This implements core.Runner and core.RouteLister interfaces.
 type runner struct {
 	svr     server
 	lg      log.Logger
 	timeout time.Duration
+	// handler is the handler of the server.
+	// The handler can be replaced while running.
+	handler *swapHandler
+	// spec is the spec used to create the server.
+	// Fields other than the handlers must not be changed by updates.
+	spec *v1.HTTPServerSpec
+}
+
+// Routes returns the routes registered to the server.
+func (s *runner) Routes() []core.Route {
+	return s.handler.load().routes
+}
+
+// handlerEntry is the pair of a handler
+// and the routes registered to it.
+type handlerEntry struct {
+	handler http.Handler
+	routes  []core.Route
+}
+
+// swapHandler is the http.Handler that
+// can atomically replace the underlying handler.
+type swapHandler struct {
+	entry atomic.Pointer[handlerEntry]
+}
+
+func (h *swapHandler) store(handler http.Handler, routes []core.Route) {
+	h.entry.Store(&handlerEntry{handler: handler, routes: routes})
+}
+
+func (h *swapHandler) load() *handlerEntry {
+	if e := h.entry.Load(); e != nil {
+		return e
+	}
+	return &handlerEntry{handler: http.NotFoundHandler()}
+}
+
+func (h *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.load().handler.ServeHTTP(w, r)
 }
 
 // Run starts this server.
@@ -99,6 +140,10 @@ func (s *http2Server) Serve() error {
 // This implements server interface.
 type http3Server struct {
 	svr *http3.Server
+	// conn is the UDP connection used by the server.
+	// conn cannot be reused once closed.
+	// This conn will be closed after the http3.Server existed.
+	conn net.PacketConn
 }
 
 func (s *http3Server) Addr() string {
@@ -109,14 +154,19 @@ func (s *http3Server) Shutdown(ctx context.Context) error {
 	// No need to care the type of returned error
 	// even if it is http.ErrServerClosed.
 	// The returned error will be handled by the runner.
-	// TODO: Change to graceful shutdown when it is implemented in the quic package.
-	err := s.svr.Close()
+	err := s.svr.Shutdown(ctx)
 	s.svr.Close()
+	if s.conn != nil {
+		s.conn.Close() // http3.Server does not close the conn.
+	}
 	return err
 }
 
 func (s *http3Server) Serve() error {
-	return s.svr.ListenAndServe()
+	if s.conn == nil {
+		return s.svr.ListenAndServe()
+	}
+	return s.svr.Serve(s.conn)
 }
 
 // altSrvMiddleware is a middleware that append
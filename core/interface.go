@@ -5,6 +5,7 @@ package core
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 )
 
@@ -129,3 +130,33 @@ type CookieCreator interface {
 	// Callers should change at least name and value fields.
 	NewCookie() *http.Cookie
 }
+
+// CertificateProvider provides server certificates for TLS handshakes.
+type CertificateProvider interface {
+	// GetCertificate returns a certificate for the given ClientHello.
+	// This method is used as the GetCertificate of tls.Config.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// NextProtos returns the ALPN protocols required by the provider
+	// such as "acme-tls/1" for ACME TLS-ALPN-01 challenge.
+	// They are added to the NextProtos of tls.Config.
+	NextProtos() []string
+}
+
+// Route is a route registered to a HTTP server.
+type Route struct {
+	// Host is the host pattern of the route.
+	// Empty string matches all hosts.
+	Host string
+	// Path is the path pattern of the route.
+	Path string
+	// Methods is the list of allowed HTTP methods.
+	// All methods are allowed when empty.
+	Methods []string
+}
+
+// RouteLister lists the routes registered to a server.
+type RouteLister interface {
+	// Routes returns the registered routes.
+	// Callers must not modify the returned slice.
+	Routes() []Route
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package core
+
+import (
+	"context"
+)
+
+// readyKey is the context key of the function
+// that is called when runners got ready.
+type readyKey struct{}
+
+// ContextWithReady returns a new context with the function ready.
+// Runners call the ready function through the Ready
+// when they got ready to serve.
+// Functions in the parent contexts are also called
+// after the given ready function.
+func ContextWithReady(ctx context.Context, ready func()) context.Context {
+	parent, _ := ctx.Value(readyKey{}).(func())
+	return context.WithValue(ctx, readyKey{}, func() {
+		ready()
+		if parent != nil {
+			parent()
+		}
+	})
+}
+
+// Ready notifies that the runner got ready to serve
+// by calling the functions saved in the ctx with ContextWithReady.
+// Ready is no-op when no functions are saved.
+// Functions can be called multiple times
+// because runners can be run more than once.
+func Ready(ctx context.Context) {
+	if ready, ok := ctx.Value(readyKey{}).(func()); ok {
+		ready()
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/core"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+func TestReady(t *testing.T) {
+	core.Ready(context.Background()) // Must not panic.
+
+	var called []string
+	ctx := core.ContextWithReady(context.Background(), func() { called = append(called, "parent") })
+	ctx = core.ContextWithReady(ctx, func() { called = append(called, "child") })
+	core.Ready(ctx)
+	testutil.Diff(t, []string{"child", "parent"}, called)
+}
@@ -7,6 +7,7 @@ import (
 	"cmp"
 	"net/http"
 	"path"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -34,6 +35,7 @@ var Resource api.Resource = &API{
 				RootDir: "./",
 			},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*handler]()},
 	},
 }
 
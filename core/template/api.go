@@ -6,6 +6,7 @@ package template
 import (
 	"cmp"
 	"net/http"
+	"reflect"
 
 	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
 	"github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -32,6 +33,7 @@ var Resource api.Resource = &API{
 			},
 			Spec: &v1.TemplateHandlerSpec{},
 		},
+		ObjectTypes: []reflect.Type{reflect.TypeFor[*templateHandler]()},
 	},
 }
 
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+// Package grpcutil provides minimal gRPC client utilities
+// to call gRPC methods over HTTP/2 transports without the gRPC runtime.
+// Only uncompressed messages encoded in protocol buffers are supported.
+// See https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md
+package grpcutil
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrCompressed is returned when a received message is compressed.
+	ErrCompressed = errors.New("internal/grpcutil: compressed messages are not supported")
+	// ErrMessageTooLarge is returned when a received message exceeds the size limit.
+	ErrMessageTooLarge = errors.New("internal/grpcutil: received message too large")
+)
+
+// NewRequest returns a new request of the gRPC method of the url.
+// The body should be the length-prefixed messages written by WriteMessage.
+func NewRequest(ctx context.Context, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("Te", "trailers")
+	return req, nil
+}
+
+// CheckResponse checks the response headers of gRPC.
+// An error is returned when the response is not a gRPC response
+// or is a Trailers-Only response with non-OK status.
+// The response body is not closed.
+func CheckResponse(res *http.Response) error {
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("internal/grpcutil: unexpected HTTP status %d", res.StatusCode)
+	}
+	mt, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mt != "application/grpc" && !strings.HasPrefix(mt, "application/grpc+") {
+		return fmt.Errorf("internal/grpcutil: unexpected content type %q", mt)
+	}
+	return Status(res.Header) // Trailers-Only response.
+}
+
+// Status returns an error if the grpc-status of the header is not OK.
+// Missing grpc-status is considered as OK.
+func Status(h http.Header) error {
+	code := h.Get("Grpc-Status")
+	if code == "" || code == "0" {
+		return nil
+	}
+	return fmt.Errorf("internal/grpcutil: gRPC error code=%s message=%s", code, h.Get("Grpc-Message"))
+}
+
+// WriteMessage writes the msg to the w as a length-prefixed message.
+func WriteMessage(w io.Writer, msg proto.Message) error {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 5+len(b))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(b)))
+	copy(frame[5:], b)
+	_, err = w.Write(frame)
+	return err
+}
+
+// ReadMessage reads a length-prefixed message from the r into the msg.
+// ErrMessageTooLarge is returned when the message is larger than maxSize.
+// io.EOF is returned only when the r reached EOF before a message.
+func ReadMessage(r io.Reader, msg proto.Message, maxSize int) error {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	if header[0] != 0 {
+		return ErrCompressed
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if uint64(size) > uint64(maxSize) {
+		return ErrMessageTooLarge
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return proto.Unmarshal(b, msg)
+}
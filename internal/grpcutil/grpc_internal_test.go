@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package grpcutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewRequest(t *testing.T) {
+	req, err := NewRequest(context.Background(), "http://test.com/foo.v1.Foo/Bar", http.NoBody)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, http.MethodPost, req.Method)
+	testutil.Diff(t, "application/grpc+proto", req.Header.Get("Content-Type"))
+	testutil.Diff(t, "trailers", req.Header.Get("Te"))
+
+	_, err = NewRequest(context.Background(), "http://invalid\n", http.NoBody)
+	testutil.Diff(t, true, err != nil)
+}
+
+func TestCheckResponse(t *testing.T) {
+	type condition struct {
+		status int
+		header http.Header
+	}
+
+	type action struct {
+		errPattern *regexp.Regexp
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"valid response",
+			&condition{
+				status: http.StatusOK,
+				header: http.Header{"Content-Type": {"application/grpc+proto"}},
+			},
+			&action{},
+		),
+		gen(
+			"content type without sub type",
+			&condition{
+				status: http.StatusOK,
+				header: http.Header{"Content-Type": {"application/grpc"}},
+			},
+			&action{},
+		),
+		gen(
+			"unexpected status",
+			&condition{
+				status: http.StatusBadGateway,
+				header: http.Header{"Content-Type": {"application/grpc"}},
+			},
+			&action{
+				errPattern: regexp.MustCompile(`unexpected HTTP status 502`),
+			},
+		),
+		gen(
+			"unexpected content type",
+			&condition{
+				status: http.StatusOK,
+				header: http.Header{"Content-Type": {"text/plain"}},
+			},
+			&action{
+				errPattern: regexp.MustCompile(`unexpected content type "text/plain"`),
+			},
+		),
+		gen(
+			"trailers only response",
+			&condition{
+				status: http.StatusOK,
+				header: http.Header{
+					"Content-Type": {"application/grpc"},
+					"Grpc-Status":  {"14"},
+					"Grpc-Message": {"unavailable"},
+				},
+			},
+			&action{
+				errPattern: regexp.MustCompile(`gRPC error code=14 message=unavailable`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := CheckResponse(&http.Response{StatusCode: tt.C.status, Header: tt.C.header})
+			if tt.A.errPattern == nil {
+				testutil.Diff(t, nil, err)
+				return
+			}
+			testutil.Diff(t, true, tt.A.errPattern.MatchString(err.Error()))
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	testutil.Diff(t, nil, Status(http.Header{}))
+	testutil.Diff(t, nil, Status(http.Header{"Grpc-Status": {"0"}}))
+	err := Status(http.Header{"Grpc-Status": {"7"}, "Grpc-Message": {"denied"}})
+	testutil.Diff(t, "internal/grpcutil: gRPC error code=7 message=denied", err.Error())
+}
+
+func TestReadMessage(t *testing.T) {
+	type condition struct {
+		body    []byte
+		maxSize int
+	}
+
+	type action struct {
+		msg proto.Message
+		err error
+	}
+
+	var valid bytes.Buffer
+	if err := WriteMessage(&valid, wrapperspb.String("foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"valid message",
+			&condition{body: valid.Bytes(), maxSize: 100},
+			&action{msg: wrapperspb.String("foo")},
+		),
+		gen(
+			"empty message",
+			&condition{body: []byte{0, 0, 0, 0, 0}, maxSize: 100},
+			&action{msg: &wrapperspb.StringValue{}},
+		),
+		gen(
+			"no message",
+			&condition{body: nil, maxSize: 100},
+			&action{err: io.EOF},
+		),
+		gen(
+			"truncated prefix",
+			&condition{body: []byte{0, 0}, maxSize: 100},
+			&action{err: io.ErrUnexpectedEOF},
+		),
+		gen(
+			"truncated message",
+			&condition{body: valid.Bytes()[:valid.Len()-1], maxSize: 100},
+			&action{err: io.ErrUnexpectedEOF},
+		),
+		gen(
+			"message without body",
+			&condition{body: valid.Bytes()[:5], maxSize: 100},
+			&action{err: io.ErrUnexpectedEOF},
+		),
+		gen(
+			"compressed message",
+			&condition{body: []byte{1, 0, 0, 0, 0}, maxSize: 100},
+			&action{err: ErrCompressed},
+		),
+		gen(
+			"too large message",
+			&condition{body: valid.Bytes(), maxSize: valid.Len() - 6},
+			&action{err: ErrMessageTooLarge},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			msg := &wrapperspb.StringValue{}
+			err := ReadMessage(bytes.NewReader(tt.C.body), msg, tt.C.maxSize)
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			if tt.A.err != nil {
+				return
+			}
+			testutil.Diff(t, tt.A.msg, proto.Message(msg), protocmp.Transform())
+		})
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	var b strings.Builder
+	err := WriteMessage(&b, wrapperspb.String("foo"))
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "\x00\x00\x00\x00\x05\x0a\x03foo", b.String())
+}
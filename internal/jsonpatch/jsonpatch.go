@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+// Package jsonpatch provides JSON Merge Patch defined in RFC 7386
+// and JSON Patch defined in RFC 6902.
+// Documents are the values decoded from JSON into any,
+// which consist of map[string]any, []any and primitive values.
+package jsonpatch
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aileron-projects/go/zerrors"
+)
+
+// MergePatch applies the JSON Merge Patch to the doc
+// and returns the patched document.
+// Null values in the patch remove the members from the doc.
+// The doc may be modified.
+func MergePatch(doc, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return deepCopy(patch)
+	}
+	d, ok := doc.(map[string]any)
+	if !ok {
+		d = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+		d[k] = MergePatch(d[k], v)
+	}
+	return d
+}
+
+// Operation is an operation of JSON Patch.
+type Operation struct {
+	// Op is the operation.
+	// "add", "remove", "replace", "move", "copy" or "test".
+	Op string `json:"op" yaml:"op"`
+	// Path is the JSON Pointer defined in RFC 6901
+	// to the target location such as "/spec/addr".
+	Path string `json:"path" yaml:"path"`
+	// From is the JSON Pointer to the source location.
+	// Used by "move" and "copy".
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	// Value is the value to add, replace or test.
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Apply applies the JSON Patch operations to the doc in order
+// and returns the patched document.
+// The doc may be modified even when an error was returned.
+func Apply(doc any, ops []*Operation) (any, error) {
+	for i, op := range ops {
+		var err error
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, zerrors.NewErr(err, "internal/jsonpatch: applying patch failed.", "index=%d op=%s path=%s", i, op.Op, op.Path)
+		}
+	}
+	return doc, nil
+}
+
+func apply(doc any, op *Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return add(doc, path, deepCopy(op.Value))
+	case "remove":
+		return remove(doc, path)
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		doc, _ = remove(doc, path)
+		return add(doc, path, deepCopy(op.Value))
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(from) && hasPrefix(path, from) {
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: cannot move into its children.", "from=%s", op.From)
+		}
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		doc, _ = remove(doc, from)
+		return add(doc, path, v)
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(v))
+	case "test":
+		v, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, op.Value) {
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: test failed.", "")
+		}
+		return doc, nil
+	default:
+		return nil, zerrors.NewErr(nil, "internal/jsonpatch: unsupported operation.", "op=%s", op.Op)
+	}
+}
+
+// parsePointer parses the JSON Pointer into reference tokens.
+// Empty pointer refers the whole document and returns nil.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, zerrors.NewErr(nil, "internal/jsonpatch: invalid JSON pointer.", "pointer=%s", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func hasPrefix(s, prefix []string) bool {
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex returns the index of the array.
+// The index can be equal to the length when the end is true.
+func arrayIndex(token string, length int, end bool) (int, error) {
+	if end && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (i == length && !end) || (len(token) > 1 && token[0] == '0') {
+		return 0, zerrors.NewErr(nil, "internal/jsonpatch: invalid array index.", "index=%s", token)
+	}
+	return i, nil
+}
+
+// get returns the value at the path.
+func get(doc any, path []string) (any, error) {
+	for _, t := range path {
+		switch c := doc.(type) {
+		case map[string]any:
+			v, ok := c[t]
+			if !ok {
+				return nil, zerrors.NewErr(nil, "internal/jsonpatch: member not found.", "member=%s", t)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: value is not a container.", "token=%s", t)
+		}
+	}
+	return doc, nil
+}
+
+// modify calls the fn with the container of the last token
+// and replaces the container with the one returned by the fn.
+func modify(doc any, path []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		v, ok := c[path[0]]
+		if !ok {
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: member not found.", "member=%s", path[0])
+		}
+		v, err := modify(v, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[path[0]] = v
+		return c, nil
+	case []any:
+		i, err := arrayIndex(path[0], len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		v, err := modify(c[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = v
+		return c, nil
+	default:
+		return nil, zerrors.NewErr(nil, "internal/jsonpatch: value is not a container.", "token=%s", path[0])
+	}
+}
+
+// add adds the value at the path.
+// Existing members of objects are replaced and
+// values are inserted into arrays.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil // Replace the whole document.
+	}
+	return modify(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			i, err := arrayIndex(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		default:
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: value is not a container.", "token=%s", token)
+		}
+	})
+}
+
+// remove removes the value at the path.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, zerrors.NewErr(nil, "internal/jsonpatch: cannot remove the whole document.", "")
+	}
+	return modify(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[token]; !ok {
+				return nil, zerrors.NewErr(nil, "internal/jsonpatch: member not found.", "member=%s", token)
+			}
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, zerrors.NewErr(nil, "internal/jsonpatch: value is not a container.", "token=%s", token)
+		}
+	})
+}
+
+// deepCopy returns a deep copy of the value.
+func deepCopy(v any) any {
+	switch c := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(c))
+		for k, v := range c {
+			m[k] = deepCopy(v)
+		}
+		return m
+	case []any:
+		s := make([]any, len(c))
+		for i, v := range c {
+			s[i] = deepCopy(v)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package jsonpatch
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/aileron-projects/go/zerrors"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMergePatch(t *testing.T) {
+	type condition struct {
+		doc   string
+		patch string
+	}
+
+	type action struct {
+		doc string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"replace member",
+			&condition{
+				doc:   `{"a":"b"}`,
+				patch: `{"a":"c"}`,
+			},
+			&action{
+				doc: `{"a":"c"}`,
+			},
+		),
+		gen(
+			"add member",
+			&condition{
+				doc:   `{"a":"b"}`,
+				patch: `{"b":"c"}`,
+			},
+			&action{
+				doc: `{"a":"b","b":"c"}`,
+			},
+		),
+		gen(
+			"remove member",
+			&condition{
+				doc:   `{"a":"b","b":"c"}`,
+				patch: `{"a":null}`,
+			},
+			&action{
+				doc: `{"b":"c"}`,
+			},
+		),
+		gen(
+			"replace array",
+			&condition{
+				doc:   `{"a":["b"]}`,
+				patch: `{"a":["c","d"]}`,
+			},
+			&action{
+				doc: `{"a":["c","d"]}`,
+			},
+		),
+		gen(
+			"nested objects",
+			&condition{
+				doc:   `{"a":{"b":"c","d":"e"}}`,
+				patch: `{"a":{"b":"x","d":null,"f":{"g":null,"h":1}}}`,
+			},
+			&action{
+				doc: `{"a":{"b":"x","f":{"h":1}}}`,
+			},
+		),
+		gen(
+			"non object patch",
+			&condition{
+				doc:   `{"a":"b"}`,
+				patch: `["c"]`,
+			},
+			&action{
+				doc: `["c"]`,
+			},
+		),
+		gen(
+			"non object doc",
+			&condition{
+				doc:   `["a"]`,
+				patch: `{"a":"b"}`,
+			},
+			&action{
+				doc: `{"a":"b"}`,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			doc := MergePatch(decode(t, tt.C.doc), decode(t, tt.C.patch))
+			testutil.Diff(t, decode(t, tt.A.doc), doc)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	type condition struct {
+		doc string
+		ops string
+	}
+
+	type action struct {
+		doc        string
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"add object member",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			},
+			&action{
+				doc: `{"baz":"qux","foo":"bar"}`,
+			},
+		),
+		gen(
+			"add array element",
+			&condition{
+				doc: `{"foo":["bar","baz"]}`,
+				ops: `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":"end"}]`,
+			},
+			&action{
+				doc: `{"foo":["bar","qux","baz","end"]}`,
+			},
+		),
+		gen(
+			"remove",
+			&condition{
+				doc: `{"baz":"qux","foo":["bar","qux","baz"]}`,
+				ops: `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/1"}]`,
+			},
+			&action{
+				doc: `{"foo":["bar","baz"]}`,
+			},
+		),
+		gen(
+			"replace",
+			&condition{
+				doc: `{"baz":"qux","foo":["bar"]}`,
+				ops: `[{"op":"replace","path":"/baz","value":"boo"},{"op":"replace","path":"/foo/0","value":{"a":1}}]`,
+			},
+			&action{
+				doc: `{"baz":"boo","foo":[{"a":1}]}`,
+			},
+		),
+		gen(
+			"replace whole document",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"replace","path":"","value":{"baz":"qux"}}]`,
+			},
+			&action{
+				doc: `{"baz":"qux"}`,
+			},
+		),
+		gen(
+			"move",
+			&condition{
+				doc: `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+				ops: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			},
+			&action{
+				doc: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+			},
+		),
+		gen(
+			"move array element",
+			&condition{
+				doc: `{"foo":["all","grass","cows","eat"]}`,
+				ops: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			},
+			&action{
+				doc: `{"foo":["all","cows","eat","grass"]}`,
+			},
+		),
+		gen(
+			"copy",
+			&condition{
+				doc: `{"foo":{"bar":["a"]}}`,
+				ops: `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"add","path":"/baz/bar/-","value":"b"}]`,
+			},
+			&action{
+				doc: `{"foo":{"bar":["a"]},"baz":{"bar":["a","b"]}}`,
+			},
+		),
+		gen(
+			"test and escaped pointer",
+			&condition{
+				doc: `{"a/b":{"m~n":[1,"2"]}}`,
+				ops: `[{"op":"test","path":"/a~1b/m~0n","value":[1,"2"]},{"op":"add","path":"/a~1b/c","value":true}]`,
+			},
+			&action{
+				doc: `{"a/b":{"m~n":[1,"2"],"c":true}}`,
+			},
+		),
+		gen(
+			"test failed",
+			&condition{
+				doc: `{"baz":"qux"}`,
+				ops: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`test failed`),
+			},
+		),
+		gen(
+			"member not found",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`member not found`),
+			},
+		),
+		gen(
+			"replace not found",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"replace","path":"/baz","value":"qux"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`member=baz`),
+			},
+		),
+		gen(
+			"invalid array index",
+			&condition{
+				doc: `{"foo":["bar"]}`,
+				ops: `[{"op":"add","path":"/foo/2","value":"qux"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`invalid array index`),
+			},
+		),
+		gen(
+			"move into children",
+			&condition{
+				doc: `{"foo":{"bar":"baz"}}`,
+				ops: `[{"op":"move","from":"/foo","path":"/foo/bar/qux"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`cannot move into its children`),
+			},
+		),
+		gen(
+			"invalid pointer",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"remove","path":"foo"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`invalid JSON pointer`),
+			},
+		),
+		gen(
+			"unsupported operation",
+			&condition{
+				doc: `{"foo":"bar"}`,
+				ops: `[{"op":"merge","path":"/foo"}]`,
+			},
+			&action{
+				err:        &zerrors.Err{Message: "internal/jsonpatch: applying patch failed."},
+				errPattern: regexp.MustCompile(`op=merge`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var ops []*Operation
+			if err := json.Unmarshal([]byte(tt.C.ops), &ops); err != nil {
+				t.Fatal(err)
+			}
+			doc, err := Apply(decode(t, tt.C.doc), ops)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err, cmpopts.EquateErrors())
+			if tt.A.doc != "" {
+				testutil.Diff(t, decode(t, tt.A.doc), doc)
+			}
+		})
+	}
+}
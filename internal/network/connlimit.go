@@ -0,0 +1,384 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rejectReason is the reason why a connection was rejected.
+type rejectReason int
+
+const (
+	// rejectLimit means that the connection was rejected
+	// because the total number of connections exceeded the limit.
+	rejectLimit rejectReason = iota
+	// rejectPerIP means that the connection was rejected
+	// because the number of connections from the source IP exceeded the limit.
+	rejectPerIP
+)
+
+// listenerStats holds the cumulative stats per listener address.
+// Keys are the addresses and values are *connStats.
+// Stats are shared by the listeners on the same address and
+// are kept after the listeners are closed so that the totals
+// never decrease when listeners are replaced by reloading configs.
+var listenerStats sync.Map
+
+// ListenerStat is the snapshot of the connection statistics of a listener address.
+type ListenerStat struct {
+	// Addr is the address of the listener.
+	Addr string
+	// Accepted is the total number of accepted connections.
+	Accepted int64
+	// RejectedLimit is the total number of connections rejected
+	// because the total number of connections exceeded the limit.
+	RejectedLimit int64
+	// RejectedPerIP is the total number of connections rejected
+	// because the number of connections from the same IP exceeded the limit.
+	RejectedPerIP int64
+	// Active is the number of currently active connections.
+	Active int64
+}
+
+// ListenerStats returns the connection statistics of
+// all addresses that listeners created by NewListener have listened on.
+// Stats are cumulative per address since the process started.
+// They include the listeners on the same address, for example,
+// old and new listeners while reloading configs, and closed listeners.
+// Returned stats are sorted by the addresses.
+func ListenerStats() []ListenerStat {
+	var stats []ListenerStat
+	listenerStats.Range(func(_, value any) bool {
+		stats = append(stats, value.(*connStats).snapshot())
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Addr < stats[j].Addr })
+	return stats
+}
+
+// connStats is the connection statistics of a listener address.
+type connStats struct {
+	addr          string
+	accepted      atomic.Int64
+	rejectedLimit atomic.Int64
+	rejectedPerIP atomic.Int64
+	active        atomic.Int64
+}
+
+func (s *connStats) reject(reason rejectReason) {
+	if reason == rejectPerIP {
+		s.rejectedPerIP.Add(1)
+	} else {
+		s.rejectedLimit.Add(1)
+	}
+}
+
+func (s *connStats) snapshot() ListenerStat {
+	return ListenerStat{
+		Addr:          s.addr,
+		Accepted:      s.accepted.Load(),
+		RejectedLimit: s.rejectedLimit.Load(),
+		RejectedPerIP: s.rejectedPerIP.Load(),
+		Active:        s.active.Load(),
+	}
+}
+
+// newConnLimitListener returns a new listener that limits
+// the number of connections and collects connection statistics.
+// The total number of connections is not limited when limit<=0.
+// The number of connections per source IP is not limited when perIP<=0.
+// See the ListenConfig.ConnectionWaitTimeout for the timeout.
+func newConnLimitListener(ln net.Listener, limit, perIP int, timeout time.Duration) *connLimitListener {
+	l := &connLimitListener{
+		Listener: ln,
+		timeout:  timeout,
+		done:     make(chan struct{}),
+	}
+	addr := ln.Addr().String()
+	stats, _ := listenerStats.LoadOrStore(addr, &connStats{addr: addr})
+	l.stats = stats.(*connStats)
+	if limit > 0 {
+		l.sem = make(chan struct{}, limit)
+	}
+	l.ips = newIPLimiter(perIP, l.stats)
+	return l
+}
+
+// newIPLimiter returns a new ipLimiter.
+// Nil is returned when limit<=0.
+func newIPLimiter(limit int, stats *connStats) *ipLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &ipLimiter{
+		limit: limit,
+		stats: stats,
+		conns: map[netip.Addr]int{},
+	}
+}
+
+// ipLimiter limits the number of connections per source IP address.
+type ipLimiter struct {
+	limit int
+	stats *connStats
+
+	mu    sync.Mutex
+	conns map[netip.Addr]int // Number of connections per IP.
+}
+
+// acquire acquires a slot for the remote IP of the connection.
+// False is returned when the number of connections from the IP exceeded the limit.
+// The returned release function must be called once when the connection was closed.
+// Connections which are not IP connections are not limited.
+func (l *ipLimiter) acquire(c net.Conn) (release func(), ok bool) {
+	ip, hasIP := remoteIP(c)
+	if !hasIP {
+		return func() {}, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns[ip] >= l.limit {
+		l.stats.reject(rejectPerIP)
+		return nil, false
+	}
+	l.conns[ip]++
+	return func() {
+		l.mu.Lock()
+		if l.conns[ip]--; l.conns[ip] <= 0 {
+			delete(l.conns, ip)
+		}
+		l.mu.Unlock()
+	}, true
+}
+
+// connLimitListener limits the number of connections
+// in total and per source IP address.
+// Connections exceeding the per IP limit are closed immediately.
+// Connections exceeding the total limit wait for the available
+// slot as configured by the timeout.
+type connLimitListener struct {
+	net.Listener
+	// sem is the semaphore to limit the total number of connections.
+	// Nil when the total number is not limited.
+	sem chan struct{}
+	// ips limits the number of connections per IP.
+	// Nil when the number per IP is not limited.
+	ips     *ipLimiter
+	timeout time.Duration
+	stats   *connStats
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *connLimitListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return l.Listener.Close()
+}
+
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	for {
+		// Wait for an available slot before accepting when timeout is 0
+		// so that the exceeded connections wait in the accept queue.
+		waitBefore := l.sem != nil && l.timeout == 0
+		if waitBefore {
+			select {
+			case l.sem <- struct{}{}:
+			case <-l.done:
+				return nil, net.ErrClosed
+			}
+		}
+		c, err := l.Listener.Accept()
+		if err != nil {
+			if waitBefore {
+				<-l.sem
+			}
+			return nil, err
+		}
+		conn, err := l.admit(c, waitBefore)
+		if err == nil {
+			return conn, nil
+		}
+		if errors.Is(err, net.ErrClosed) {
+			return nil, err
+		}
+		// Rejected connections are closed and
+		// next connection is accepted.
+	}
+}
+
+// errRejected is the error used when a connection was rejected.
+var errRejected = errors.New("internal/network: connection rejected")
+
+// admit applies limits to the accepted connection.
+// The connection is closed when rejected.
+// acquired should be true when the semaphore was already acquired.
+func (l *connLimitListener) admit(c net.Conn, acquired bool) (net.Conn, error) {
+	release := func() {}
+	if l.ips != nil {
+		r, ok := l.ips.acquire(c)
+		if !ok {
+			if acquired {
+				<-l.sem
+			}
+			c.Close()
+			return nil, errRejected
+		}
+		release = r
+	}
+
+	if l.sem != nil && !acquired {
+		if err := l.acquire(); err != nil {
+			release()
+			c.Close()
+			if err == errRejected {
+				l.stats.reject(rejectLimit)
+			}
+			return nil, err
+		}
+	}
+
+	l.stats.accepted.Add(1)
+	l.stats.active.Add(1)
+	return &connLimitConn{
+		Conn: c,
+		release: func() {
+			release()
+			if l.sem != nil {
+				<-l.sem
+			}
+			l.stats.active.Add(-1)
+		},
+	}, nil
+}
+
+// acquire acquires the semaphore.
+// Negative timeout does not wait for the semaphore.
+func (l *connLimitListener) acquire() error {
+	if l.timeout < 0 {
+		select {
+		case l.sem <- struct{}{}:
+			return nil
+		default:
+			return errRejected
+		}
+	}
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return errRejected
+	case <-l.done:
+		return net.ErrClosed
+	}
+}
+
+// remoteIP returns the IP address of the remote peer.
+// False is returned when the connection is not an IP connection.
+func remoteIP(c net.Conn) (netip.Addr, bool) {
+	switch addr := c.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		return addr.AddrPort().Addr().Unmap(), true
+	case *net.UDPAddr:
+		return addr.AddrPort().Addr().Unmap(), true
+	default:
+		return netip.Addr{}, false
+	}
+}
+
+// connLimitConn is the connection accepted by connLimitListener.
+// The release function is called once when the connection is closed.
+type connLimitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *connLimitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
+
+// ipLimitListener limits the number of connections per source IP address
+// using the remote addresses of the accepted connections lazily.
+// This listener is used with PROXY protocol so that the limit is applied
+// to the addresses in the headers without blocking the accept loop.
+type ipLimitListener struct {
+	net.Listener
+	ips *ipLimiter
+}
+
+func (l *ipLimitListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &ipLimitConn{Conn: c, ips: l.ips}, nil
+}
+
+// ipLimitConn is the connection accepted by ipLimitListener.
+// The limit is applied on the first call of Read or Write.
+// The connection is closed and errRejected is returned when rejected.
+type ipLimitConn struct {
+	net.Conn
+	ips *ipLimiter
+
+	once    sync.Once
+	err     error
+	release func() // Nil when not admitted.
+
+	closeOnce sync.Once
+}
+
+// admit applies the limit only once.
+func (c *ipLimitConn) admit() error {
+	c.once.Do(func() {
+		release, ok := c.ips.acquire(c.Conn)
+		if !ok {
+			c.Conn.Close()
+			c.err = errRejected
+			return
+		}
+		c.release = release
+	})
+	return c.err
+}
+
+func (c *ipLimitConn) Read(b []byte) (int, error) {
+	if err := c.admit(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *ipLimitConn) Write(b []byte) (int, error) {
+	if err := c.admit(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *ipLimitConn) Close() error {
+	// Close the connection first so that the pending admit is unblocked.
+	// It can be waiting for the PROXY protocol header in the RemoteAddr.
+	err := c.Conn.Close()
+	// Connections closed before admitted are never admitted.
+	c.once.Do(func() { c.err = net.ErrClosed })
+	if c.release != nil {
+		c.closeOnce.Do(c.release)
+	}
+	return err
+}
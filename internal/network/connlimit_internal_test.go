@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// acceptAsync accepts a connection in background.
+func acceptAsync(ln net.Listener) <-chan net.Conn {
+	ch := make(chan net.Conn, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			close(ch)
+			return
+		}
+		ch <- c
+	}()
+	return ch
+}
+
+// dial connects to the listener.
+func dial(t *testing.T, ln net.Listener) net.Conn {
+	t.Helper()
+	c, err := net.Dial("tcp", ln.Addr().String())
+	testutil.Diff(t, nil, err)
+	return c
+}
+
+// closedByPeer reports if the connection was closed by the peer.
+func closedByPeer(c net.Conn) bool {
+	_ = c.SetReadDeadline(time.Now().Add(time.Second))
+	_, err := c.Read(make([]byte, 1))
+	var ne net.Error
+	return err != nil && !(errors.As(err, &ne) && ne.Timeout())
+}
+
+func stat(ln net.Listener) ListenerStat {
+	for _, s := range ListenerStats() {
+		if s.Addr == ln.Addr().String() {
+			return s
+		}
+	}
+	return ListenerStat{}
+}
+
+func TestConnLimitListener_reject(t *testing.T) {
+	ln, err := NewListener(&ListenConfig{
+		Address:               "127.0.0.1:0",
+		ConnectionLimit:       1,
+		ConnectionWaitTimeout: -1,
+	})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	c1 := dial(t, ln)
+	defer c1.Close()
+	s1 := <-acceptAsync(ln)
+
+	ch := acceptAsync(ln)
+	c2 := dial(t, ln)
+	defer c2.Close()
+	testutil.Diff(t, true, closedByPeer(c2))
+
+	c3 := dial(t, ln)
+	defer c3.Close()
+	select {
+	case <-ch:
+		t.Error("connection exceeding the limit was accepted")
+	case <-time.After(100 * time.Millisecond):
+	}
+	s1.Close() // Release the slot.
+	c4 := dial(t, ln)
+	defer c4.Close()
+	s := <-ch
+	testutil.Diff(t, true, s != nil)
+	defer s.Close()
+
+	got := stat(ln)
+	testutil.Diff(t, int64(2), got.Accepted)
+	testutil.Diff(t, int64(1), got.Active)
+	testutil.Diff(t, true, got.RejectedLimit >= 1)
+	testutil.Diff(t, int64(0), got.RejectedPerIP)
+}
+
+func TestConnLimitListener_timeout(t *testing.T) {
+	ln, err := NewListener(&ListenConfig{
+		Address:               "127.0.0.1:0",
+		ConnectionLimit:       1,
+		ConnectionWaitTimeout: 200 * time.Millisecond,
+	})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	c1 := dial(t, ln)
+	defer c1.Close()
+	s1 := <-acceptAsync(ln)
+
+	// Waiting connection is accepted when the slot became available.
+	ch := acceptAsync(ln)
+	c2 := dial(t, ln)
+	defer c2.Close()
+	time.Sleep(50 * time.Millisecond)
+	s1.Close()
+	s2 := <-ch
+	testutil.Diff(t, true, s2 != nil)
+
+	// Waiting connection is closed after the timeout.
+	ch = acceptAsync(ln)
+	c3 := dial(t, ln)
+	defer c3.Close()
+	testutil.Diff(t, true, closedByPeer(c3))
+	s2.Close()
+	c4 := dial(t, ln)
+	defer c4.Close()
+	s4 := <-ch
+	testutil.Diff(t, true, s4 != nil)
+	s4.Close()
+
+	got := stat(ln)
+	testutil.Diff(t, int64(3), got.Accepted)
+	testutil.Diff(t, int64(1), got.RejectedLimit)
+}
+
+func TestConnLimitListener_perIP(t *testing.T) {
+	ln, err := NewListener(&ListenConfig{
+		Address:              "127.0.0.1:0",
+		ConnectionLimitPerIP: 1,
+	})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	c1 := dial(t, ln)
+	defer c1.Close()
+	s1 := <-acceptAsync(ln)
+
+	ch := acceptAsync(ln)
+	c2 := dial(t, ln)
+	defer c2.Close()
+	testutil.Diff(t, true, closedByPeer(c2))
+
+	s1.Close()
+	s1.Close() // Released only once.
+	c3 := dial(t, ln)
+	defer c3.Close()
+	s3 := <-ch
+	testutil.Diff(t, true, s3 != nil)
+	s3.Close()
+
+	got := stat(ln)
+	testutil.Diff(t, int64(2), got.Accepted)
+	testutil.Diff(t, int64(0), got.Active)
+	testutil.Diff(t, int64(1), got.RejectedPerIP)
+}
+
+func TestConnLimitListener_close(t *testing.T) {
+	ln, err := NewListener(&ListenConfig{
+		Address:         "127.0.0.1:0",
+		ConnectionLimit: 1,
+	})
+	testutil.Diff(t, nil, err)
+	addr := ln.Addr().String()
+
+	c1 := dial(t, ln)
+	defer c1.Close()
+	s1 := <-acceptAsync(ln)
+	defer s1.Close()
+
+	// Waiting for the slot is cancelled by closing the listener.
+	ch := acceptAsync(ln)
+	time.Sleep(50 * time.Millisecond)
+	ln.Close()
+	select {
+	case c := <-ch:
+		testutil.Diff(t, true, c == nil)
+	case <-time.After(time.Second):
+		t.Error("accept was not unblocked")
+	}
+
+	// Stats are kept after the listener was closed.
+	s := stat(ln)
+	testutil.Diff(t, addr, s.Addr)
+	testutil.Diff(t, int64(1), s.Accepted)
+	testutil.Diff(t, int64(1), s.Active)
+}
+
+func TestRemoteIP(t *testing.T) {
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+	_, ok := remoteIP(sc)
+	testutil.Diff(t, false, ok)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+	c := dial(t, ln)
+	defer c.Close()
+	ip, ok := remoteIP(c)
+	testutil.Diff(t, true, ok)
+	testutil.Diff(t, "127.0.0.1", ip.String())
+}
+
+func TestConnLimitListener_perIPProxyProtocol(t *testing.T) {
+	ln, err := NewListener(&ListenConfig{
+		Address:              "127.0.0.1:0",
+		ConnectionLimitPerIP: 1,
+		ProxyProtocol: &ProxyProtocolConfig{
+			TrustedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+		},
+	})
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	// send connects to the listener with the PROXY protocol header
+	// and returns the server side connection.
+	send := func(src string) (net.Conn, net.Conn) {
+		c := dial(t, ln)
+		_, err := c.Write([]byte("PROXY TCP4 " + src + " 192.0.2.100 56324 443\r\nhello"))
+		testutil.Diff(t, nil, err)
+		s := <-acceptAsync(ln)
+		testutil.Diff(t, true, s != nil)
+		return c, s
+	}
+
+	// Clients behind the same load balancer are limited by their own addresses.
+	c1, s1 := send("192.0.2.1")
+	defer c1.Close()
+	defer s1.Close()
+	_, err = io.ReadFull(s1, make([]byte, 5))
+	testutil.Diff(t, nil, err)
+
+	c2, s2 := send("192.0.2.2")
+	defer c2.Close()
+	defer s2.Close()
+	_, err = io.ReadFull(s2, make([]byte, 5))
+	testutil.Diff(t, nil, err)
+
+	c3, s3 := send("192.0.2.1")
+	defer c3.Close()
+	defer s3.Close()
+	_, err = s3.Read(make([]byte, 5))
+	testutil.Diff(t, errRejected, err, cmpopts.EquateErrors())
+	testutil.Diff(t, true, closedByPeer(c3))
+
+	// Slot is released when closed.
+	s1.Close()
+	s1.Close() // Released only once.
+	c4, s4 := send("192.0.2.1")
+	defer c4.Close()
+	defer s4.Close()
+	_, err = io.ReadFull(s4, make([]byte, 5))
+	testutil.Diff(t, nil, err)
+
+	got := stat(ln)
+	testutil.Diff(t, int64(1), got.RejectedPerIP)
+}
+
+func TestIPLimitConn_closeBeforeAdmit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+	c := dial(t, ln)
+	defer c.Close()
+	s := <-acceptAsync(ln)
+
+	ips := newIPLimiter(1, &connStats{})
+	ic := &ipLimitConn{Conn: s, ips: ips}
+	testutil.Diff(t, nil, ic.Close())
+	_, err = ic.Write([]byte("test"))
+	testutil.Diff(t, net.ErrClosed, err, cmpopts.EquateErrors())
+	testutil.Diff(t, 0, len(ips.conns))
+}
+
+// blockingAddrConn is a connection whose RemoteAddr blocks until closed
+// like the connections waiting for the PROXY protocol header.
+type blockingAddrConn struct {
+	net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *blockingAddrConn) RemoteAddr() net.Addr {
+	<-c.closed
+	return c.Conn.RemoteAddr()
+}
+
+func (c *blockingAddrConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+func TestIPLimitConn_closeWhileAdmitting(t *testing.T) {
+	c, s := net.Pipe()
+	defer c.Close()
+
+	ips := newIPLimiter(1, &connStats{})
+	ic := &ipLimitConn{Conn: &blockingAddrConn{Conn: s, closed: make(chan struct{})}, ips: ips}
+	readErr := make(chan error)
+	go func() {
+		_, err := ic.Read(make([]byte, 1))
+		readErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // Wait for the Read to block in admit.
+
+	closed := make(chan error)
+	go func() { closed <- ic.Close() }()
+	select {
+	case err := <-closed:
+		testutil.Diff(t, nil, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked while admitting")
+	}
+	testutil.Diff(t, true, <-readErr != nil)
+	testutil.Diff(t, 0, len(ips.conns))
+}
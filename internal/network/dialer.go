@@ -171,6 +171,7 @@ func NewDialerFromSpec(spec *k.DialConfig) (Dialer, error) {
 		Timeout:        time.Duration(spec.Timeout) * time.Millisecond,
 		FallbackDelay:  time.Duration(spec.FallbackDelay) * time.Millisecond,
 		SockOption:     SockOptionFromSpec(spec.SockOption),
+		ProxyProtocol:  spec.ProxyProtocol,
 	}
 	return NewDialer(config)
 }
@@ -203,7 +204,13 @@ func NewDialer(c *DialConfig) (Dialer, error) {
 		Control:       c.SockOption.ControlFunc(zsyscall.SockOptSO | zsyscall.SockOptIP | zsyscall.SockOptIPV6 | zsyscall.SockOptTCP | zsyscall.SockOptUDP),
 	}
 	d = dd
-	if c.TLSConfig != nil {
+	if c.ProxyProtocol {
+		// Header must be sent before TLS handshake.
+		d = &proxyProtocolDialer{
+			Dialer:    dd,
+			tlsConfig: c.TLSConfig,
+		}
+	} else if c.TLSConfig != nil {
 		d = &tls.Dialer{
 			NetDialer: dd,
 			Config:    c.TLSConfig,
@@ -251,4 +258,9 @@ type DialConfig struct {
 	FallbackDelay time.Duration
 	// SockOption is the socket option.
 	SockOption *zsyscall.SockOption
+	// ProxyProtocol sends PROXY protocol version 2 headers
+	// to the servers when true.
+	// Addresses of the client connection saved in the context
+	// with ContextWithConnAddrs are sent.
+	ProxyProtocol bool
 }
@@ -5,10 +5,10 @@ package network
 
 import (
 	"cmp"
-	"context"
 	"crypto/tls"
 	"errors"
 	"net"
+	"net/netip"
 	"os"
 	"reflect"
 	"strings"
@@ -59,6 +59,9 @@ type ListenConfig struct {
 	// Address can have network type prefix in the format of "<Network>://<Address>".
 	// Known networks are "tcp", "tcp4" (IPv4-only), "tcp6" (IPv6-only),
 	// "udp", "udp4" (IPv4-only), "udp6" (IPv6-only), "unix", and "unixpacket".
+	// Network "systemd" is also available to use the socket passed by
+	// systemd socket activation. The address is the name of the socket
+	// given by FileDescriptorName such as "systemd://aileron".
 	// See the net.Dial document for valid values.
 	// https://pkg.go.dev/net#Dial
 	Address string
@@ -68,10 +71,23 @@ type ListenConfig struct {
 	TLSConfig *tls.Config
 	// ConnectionLimit is the maximum number of connection
 	// that allowed to connect at a point of time.
-	// Connections that exceeds this limit until
-	// they dis-connect the connections or
-	// until they are accepted.
+	// Connections that exceeds this limit wait
+	// as configured by the ConnectionWaitTimeout.
+	// The number of connections is not limited when zero or negative.
 	ConnectionLimit int
+	// ConnectionLimitPerIP is the maximum number of connections
+	// from a single source IP address.
+	// Connections that exceeds this limit are closed immediately.
+	// When ProxyProtocol is configured, this limit is applied to
+	// the source addresses in the PROXY protocol headers.
+	// The number of connections is not limited when zero or negative.
+	ConnectionLimitPerIP int
+	// ConnectionWaitTimeout is the maximum duration that connections
+	// exceeding the ConnectionLimit wait for available slots.
+	// If zero, connections wait in the accept queue of the socket without timeout.
+	// If positive, connections are accepted and closed after the timeout.
+	// If negative, connections are accepted and closed immediately.
+	ConnectionWaitTimeout time.Duration
 	// ReadDeadline apply read deadline for connection.
 	// If zero, the deadline is not explicitly applied.
 	ReadDeadline time.Duration
@@ -89,6 +105,9 @@ type ListenConfig struct {
 	// SockOption is the socket option.
 	// SocketOption is not applied for DTLS.
 	SockOption *zsyscall.SockOption
+	// ProxyProtocol is the configuration of PROXY protocol.
+	// PROXY protocol is disabled when nil.
+	ProxyProtocol *ProxyProtocolConfig
 }
 
 // NewListenerFromSpec returns a new net.Listener from the given spec.
@@ -99,6 +118,22 @@ func NewListenerFromSpec(spec *kernel.ListenConfig) (net.Listener, error) {
 	if spec == nil {
 		return nil, nil
 	}
+	config, err := ListenConfigFromSpec(spec)
+	if err != nil {
+		return nil, err // Return err as-is.
+	}
+	return NewListener(config)
+}
+
+// ListenConfigFromSpec returns a new listen config from the given spec.
+// This function returns nil config and nil error when a nil spec was
+// given as an argument.
+// Use this function instead of NewListenerFromSpec
+// when the config should be modified before creating a listener.
+func ListenConfigFromSpec(spec *kernel.ListenConfig) (*ListenConfig, error) {
+	if spec == nil {
+		return nil, nil
+	}
 
 	tlsConfig, err := TLSConfig(spec.TLSConfig)
 	if err != nil {
@@ -106,13 +141,15 @@ func NewListenerFromSpec(spec *kernel.ListenConfig) (net.Listener, error) {
 	}
 
 	config := &ListenConfig{
-		TLSConfig:       tlsConfig,
-		Address:         spec.Addr,
-		ConnectionLimit: int(spec.ConnectionLimit),
-		Networks:        spec.Networks,
-		ReadDeadline:    time.Duration(spec.ReadDeadline) * time.Millisecond,
-		WriteDeadline:   time.Duration(spec.WriteDeadline) * time.Millisecond,
-		SockOption:      SockOptionFromSpec(spec.SockOption),
+		TLSConfig:             tlsConfig,
+		Address:               spec.Addr,
+		ConnectionLimit:       int(spec.ConnectionLimit),
+		ConnectionLimitPerIP:  int(spec.ConnectionLimitPerIP),
+		ConnectionWaitTimeout: time.Duration(spec.ConnectionWaitTimeout) * time.Millisecond,
+		Networks:              spec.Networks,
+		ReadDeadline:          time.Duration(spec.ReadDeadline) * time.Millisecond,
+		WriteDeadline:         time.Duration(spec.WriteDeadline) * time.Millisecond,
+		SockOption:            SockOptionFromSpec(spec.SockOption),
 	}
 	if spec.KeepAliveConfig != nil {
 		kc := spec.KeepAliveConfig
@@ -130,7 +167,21 @@ func NewListenerFromSpec(spec *kernel.ListenConfig) (net.Listener, error) {
 			}
 		}
 	}
-	return NewListener(config)
+	if spec.ProxyProtocol != nil {
+		pc := &ProxyProtocolConfig{
+			TrustedNetworks: make([]netip.Prefix, 0, len(spec.ProxyProtocol.TrustedNetworks)),
+			HeaderTimeout:   time.Duration(spec.ProxyProtocol.HeaderTimeout) * time.Millisecond,
+		}
+		for _, n := range spec.ProxyProtocol.TrustedNetworks {
+			prefix, err := netip.ParsePrefix(n)
+			if err != nil {
+				return nil, zerrors.NewErr(err, "internal/network: failed to create new listener", "")
+			}
+			pc.TrustedNetworks = append(pc.TrustedNetworks, prefix.Masked())
+		}
+		config.ProxyProtocol = pc
+	}
+	return config, nil
 }
 
 // NewListener returns a new net.Listener from the given config.
@@ -140,6 +191,7 @@ func NewListenerFromSpec(spec *kernel.ListenConfig) (net.Listener, error) {
 //   - "tcp4", "127.0.0.1:80"
 //   - "tcp6", "127.0.0.1:80"
 //   - "unix", "/var/run/example.sock"
+//   - "systemd", "aileron"
 func NewListener(c *ListenConfig) (net.Listener, error) {
 	if c == nil {
 		return nil, zerrors.NewErr(nil, "internal/network: nil listener spec", "")
@@ -153,20 +205,15 @@ func NewListener(c *ListenConfig) (net.Listener, error) {
 
 	var ln net.Listener
 	var err error
-	net, addr := znet.ParseNetAddr(c.Address)
+	net, addr := parseNetAddr(c.Address)
 	switch net {
 	case "", "tcp", "tcp4", "tcp6":
 		net = cmp.Or(net, "tcp") // Default tcp.
-		ln, err = lc.Listen(context.Background(), net, addr)
-		if c.TLSConfig != nil {
-			ln = tls.NewListener(ln, c.TLSConfig)
-		}
-	case "unix", "unixpacket":
-		ln, err = lc.Listen(context.Background(), net, addr)
-		if c.TLSConfig != nil {
-			ln = tls.NewListener(ln, c.TLSConfig)
-		}
-		ln = RemoveSocketListener(ln) // Remove .socket file when closed if necessary.
+		ln, err = sockets.listen(lc, net, addr)
+	case "unix", "unixpacket", systemdNetwork:
+		// Socket file is removed by the socket store when closed if necessary.
+		// Sockets passed by systemd are looked up by the name given as addr.
+		ln, err = sockets.listen(lc, net, addr)
 	default:
 		err = errors.New("kernel/network: unknown address `" + c.Address + "`")
 	}
@@ -174,13 +221,11 @@ func NewListener(c *ListenConfig) (net.Listener, error) {
 		return nil, zerrors.NewErr(err, "internal/network: failed to create new listener", "")
 	}
 
-	if c.ReadDeadline != 0 || c.WriteDeadline != 0 {
-		ln = &deadlineListener{
-			Listener: ln,
-			read:     c.ReadDeadline,
-			write:    c.WriteDeadline,
-		}
-	}
+	// Networks and the total connection limit are applied to the addresses of the peers
+	// rather than the addresses in the PROXY protocol headers
+	// not to block accepting connections until the headers are read.
+	// The connection limit per IP is applied to the addresses in the headers
+	// after they were read because all clients share the addresses of the load balancers.
 	if len(c.Networks) > 0 {
 		wln, err := znet.NewWhiteListListener(ln, c.Networks...)
 		if err != nil {
@@ -189,8 +234,29 @@ func NewListener(c *ListenConfig) (net.Listener, error) {
 		}
 		ln = wln
 	}
-	if c.ConnectionLimit > 0 { // Connection limit if >0.
-		ln = znet.NewLimitListener(ln, c.ConnectionLimit)
+	if c.ProxyProtocol == nil {
+		ln = newConnLimitListener(ln, c.ConnectionLimit, c.ConnectionLimitPerIP, c.ConnectionWaitTimeout)
+	} else {
+		cl := newConnLimitListener(ln, c.ConnectionLimit, 0, c.ConnectionWaitTimeout)
+		// PROXY protocol headers are sent before TLS handshake.
+		ln = &proxyProtocolListener{
+			Listener: cl,
+			config:   c.ProxyProtocol,
+		}
+		if ips := newIPLimiter(c.ConnectionLimitPerIP, cl.stats); ips != nil {
+			ln = &ipLimitListener{Listener: ln, ips: ips}
+		}
+	}
+	if c.TLSConfig != nil {
+		ln = tls.NewListener(ln, c.TLSConfig)
+	}
+
+	if c.ReadDeadline != 0 || c.WriteDeadline != 0 {
+		ln = &deadlineListener{
+			Listener: ln,
+			read:     c.ReadDeadline,
+			write:    c.WriteDeadline,
+		}
 	}
 	return ln, nil
 }
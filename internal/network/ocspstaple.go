@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspRetryInterval is the interval to retry
+// obtaining OCSP responses after failure.
+const ocspRetryInterval = time.Minute
+
+// ocspStapler obtains OCSP responses of the server certificates
+// in background and staples them to the certificates.
+type ocspStapler struct {
+	client *http.Client
+	now    func() time.Time
+	// staples is the map of staples.
+	// Key is the raw leaf certificate.
+	staples sync.Map
+}
+
+// newOCSPStapler returns a new ocspStapler.
+func newOCSPStapler() *ocspStapler {
+	return &ocspStapler{
+		client: &http.Client{
+			Transport: DefaultHTTPTransport,
+			Timeout:   defaultOCSPTimeout,
+		},
+		now: time.Now,
+	}
+}
+
+// staple is the OCSP response of a certificate.
+type staple struct {
+	leaf   *x509.Certificate
+	issuer *x509.Certificate
+
+	mu       sync.Mutex
+	raw      []byte    // Raw OCSP response.
+	expires  time.Time // NextUpdate of the response.
+	refresh  time.Time // Time to refresh the response.
+	fetching atomic.Bool
+}
+
+// response returns the OCSP response if it is not expired.
+func (s *staple) response(now time.Time) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.raw == nil || (!s.expires.IsZero() && now.After(s.expires)) {
+		return nil
+	}
+	return s.raw
+}
+
+// stale reports if the response should be refreshed.
+func (s *staple) stale(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !now.Before(s.refresh)
+}
+
+// staple returns the copy of the certificates with OCSP responses.
+// Certificates without available responses are returned as-is.
+// Responses are obtained in background when they have not been obtained yet
+// or they should be refreshed.
+func (o *ocspStapler) staple(certs []tls.Certificate) []tls.Certificate {
+	now := o.now()
+	result := make([]tls.Certificate, len(certs))
+	for i, cert := range certs {
+		result[i] = cert
+		s := o.get(cert)
+		if s == nil {
+			continue // Certificates without OCSP responders.
+		}
+		if raw := s.response(now); raw != nil {
+			result[i].OCSPStaple = raw
+		}
+		if s.stale(now) && s.fetching.CompareAndSwap(false, true) {
+			go o.fetch(s)
+		}
+	}
+	return result
+}
+
+// get returns the staple of the certificate.
+// Nil is returned when the certificate does not support OCSP.
+func (o *ocspStapler) get(cert tls.Certificate) *staple {
+	if len(cert.Certificate) < 2 {
+		return nil // Issuer not found.
+	}
+	key := string(cert.Certificate[0])
+	if v, ok := o.staples.Load(key); ok {
+		return v.(*staple)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil || len(leaf.OCSPServer) == 0 {
+		o.staples.Store(key, (*staple)(nil))
+		return nil
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		o.staples.Store(key, (*staple)(nil))
+		return nil
+	}
+	v, _ := o.staples.LoadOrStore(key, &staple{leaf: leaf, issuer: issuer})
+	return v.(*staple)
+}
+
+// fetch obtains the OCSP response and schedules the next refresh.
+// Responses are refreshed at the half of their validity period.
+// Current response is kept when failed to obtain a new one.
+func (o *ocspStapler) fetch(s *staple) {
+	defer s.fetching.Store(false)
+	resp, err := fetchOCSP(context.Background(), o.client, s.leaf, s.issuer)
+	now := o.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil || resp.Status != ocsp.Good {
+		// Revoked responses are not stapled
+		// because clients will reject the connections.
+		s.refresh = now.Add(ocspRetryInterval)
+		return
+	}
+	s.raw = resp.Raw
+	s.expires = resp.NextUpdate
+	if resp.NextUpdate.IsZero() {
+		s.refresh = now.Add(defaultOCSPCacheTTL)
+	} else {
+		s.refresh = resp.ThisUpdate.Add(resp.NextUpdate.Sub(resp.ThisUpdate) / 2)
+	}
+}
@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PROXY protocol.
+// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+const (
+	// proxyV1Prefix is the prefix of the version 1 header.
+	proxyV1Prefix = "PROXY "
+	// proxyV1MaxLen is the maximum length of the version 1 header
+	// including the CRLF.
+	proxyV1MaxLen = 107
+	// proxyV2Signature is the signature of the version 2 header.
+	proxyV2Signature = "\r\n\r\n\x00\r\nQUIT\n"
+	// proxyV2Version is the protocol version of the version 2 header.
+	proxyV2Version = 0x20
+	// proxyV2CmdLocal and proxyV2CmdProxy are the commands of the version 2 header.
+	proxyV2CmdLocal = 0x00
+	proxyV2CmdProxy = 0x01
+	// Address families and transport protocols of the version 2 header.
+	proxyV2Unspec = 0x00
+	proxyV2TCP4   = 0x11
+	proxyV2UDP4   = 0x12
+	proxyV2TCP6   = 0x21
+	proxyV2UDP6   = 0x22
+	proxyV2Unix   = 0x31
+	proxyV2Dgram  = 0x32
+	// defaultProxyHeaderTimeout is the default timeout
+	// to read a PROXY protocol header.
+	defaultProxyHeaderTimeout = 10 * time.Second
+)
+
+var (
+	errProxyHeader   = errors.New("internal/network: invalid PROXY protocol header")
+	errProxyNotFound = errors.New("internal/network: PROXY protocol header not found")
+)
+
+// ProxyProtocolConfig is the configuration of PROXY protocol for listeners.
+type ProxyProtocolConfig struct {
+	// TrustedNetworks is the networks that are allowed
+	// to send PROXY protocol headers.
+	// Connections from trusted networks must send a header.
+	// No networks are trusted when empty so that
+	// clients cannot spoof their addresses.
+	TrustedNetworks []netip.Prefix
+	// HeaderTimeout is the timeout to read a PROXY protocol header.
+	// Default timeout is used when zero.
+	HeaderTimeout time.Duration
+}
+
+// trusted returns if the PROXY protocol header
+// should be read from the connection from the addr.
+func (c *ProxyProtocolConfig) trusted(addr net.Addr) bool {
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false // Non IP networks such as unix sockets.
+	}
+	ip := ap.Addr().Unmap()
+	for _, n := range c.TrustedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyProtocolListener accepts connections
+// with the PROXY protocol headers.
+type proxyProtocolListener struct {
+	net.Listener
+	config *ProxyProtocolConfig
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.config.trusted(c.RemoteAddr()) {
+		return c, nil
+	}
+	return &proxyProtocolConn{
+		Conn:    c,
+		r:       bufio.NewReader(c),
+		timeout: cmpDuration(l.config.HeaderTimeout, defaultProxyHeaderTimeout),
+	}, nil
+}
+
+// cmpDuration returns d if it is positive, otherwise returns def.
+func cmpDuration(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+// proxyProtocolConn is a connection which starts with
+// a PROXY protocol header.
+// The header is read lazily on the first call of Read, LocalAddr or RemoteAddr
+// so that the listener's accept loop is not blocked.
+type proxyProtocolConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once   sync.Once
+	err    error
+	local  net.Addr
+	remote net.Addr
+
+	mu       sync.Mutex
+	deadline time.Time // Read deadline set by the user.
+}
+
+// readHeader reads the PROXY protocol header only once.
+func (c *proxyProtocolConn) readHeader() error {
+	c.once.Do(func() {
+		c.mu.Lock()
+		deadline := c.deadline
+		c.mu.Unlock()
+		timeout := time.Now().Add(c.timeout)
+		if !deadline.IsZero() && deadline.Before(timeout) {
+			timeout = deadline
+		}
+		_ = c.Conn.SetReadDeadline(timeout)
+		c.remote, c.local, c.err = readProxyHeader(c.r)
+		c.mu.Lock()
+		_ = c.Conn.SetReadDeadline(c.deadline) // Restore the deadline.
+		c.mu.Unlock()
+	})
+	return c.err
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	if err := c.readHeader(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(b)
+}
+
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	if c.readHeader() == nil && c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	if c.readHeader() == nil && c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *proxyProtocolConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+// readProxyHeader reads a PROXY protocol version 1 or 2 header.
+// Nil addresses are returned when the header does not have
+// address information such as LOCAL command and UNKNOWN protocol.
+func readProxyHeader(r *bufio.Reader) (remote, local net.Addr, err error) {
+	b, err := r.Peek(len(proxyV1Prefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(b) == proxyV1Prefix {
+		return readProxyV1(r)
+	}
+	b, err = r.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(b) == proxyV2Signature {
+		return readProxyV2(r)
+	}
+	return nil, nil, errProxyNotFound
+}
+
+// readProxyV1 reads a PROXY protocol version 1 header.
+// For example "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n".
+func readProxyV1(r *bufio.Reader) (remote, local net.Addr, err error) {
+	var line []byte
+	for len(line) < proxyV1MaxLen {
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, c)
+		if c == '\n' {
+			break
+		}
+	}
+	header, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, nil, errProxyHeader
+	}
+	fields := strings.Split(header, " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil // Addresses must be ignored.
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, errProxyHeader
+	}
+	src, err1 := netip.ParseAddr(fields[2])
+	dst, err2 := netip.ParseAddr(fields[3])
+	sport, err3 := strconv.ParseUint(fields[4], 10, 16)
+	dport, err4 := strconv.ParseUint(fields[5], 10, 16)
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		return nil, nil, errProxyHeader
+	}
+	if src.Is4() != (fields[1] == "TCP4") || dst.Is4() != (fields[1] == "TCP4") {
+		return nil, nil, errProxyHeader
+	}
+	remote = net.TCPAddrFromAddrPort(netip.AddrPortFrom(src, uint16(sport)))
+	local = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dst, uint16(dport)))
+	return remote, local, nil
+}
+
+// readProxyV2 reads a PROXY protocol version 2 header.
+// TLVs are read but ignored.
+func readProxyV2(r *bufio.Reader) (remote, local net.Addr, err error) {
+	header := make([]byte, len(proxyV2Signature)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+	verCmd, family := header[12], header[13]
+	body := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, nil, err
+	}
+	if verCmd&0xf0 != proxyV2Version {
+		return nil, nil, errProxyHeader
+	}
+	switch verCmd & 0x0f {
+	case proxyV2CmdLocal:
+		return nil, nil, nil // Connection from the proxy itself such as health checks.
+	case proxyV2CmdProxy:
+	default:
+		return nil, nil, errProxyHeader
+	}
+
+	var size int
+	switch family {
+	case proxyV2TCP4, proxyV2UDP4:
+		size = 4
+	case proxyV2TCP6, proxyV2UDP6:
+		size = 16
+	case proxyV2Unspec, proxyV2Unix, proxyV2Dgram:
+		return nil, nil, nil // Addresses are not used.
+	default:
+		return nil, nil, errProxyHeader
+	}
+	if len(body) < 2*size+4 {
+		return nil, nil, errProxyHeader
+	}
+	src, _ := netip.AddrFromSlice(body[:size])
+	dst, _ := netip.AddrFromSlice(body[size : 2*size])
+	sport := binary.BigEndian.Uint16(body[2*size:])
+	dport := binary.BigEndian.Uint16(body[2*size+2:])
+	if family == proxyV2UDP4 || family == proxyV2UDP6 {
+		remote = net.UDPAddrFromAddrPort(netip.AddrPortFrom(src, sport))
+		local = net.UDPAddrFromAddrPort(netip.AddrPortFrom(dst, dport))
+		return remote, local, nil
+	}
+	remote = net.TCPAddrFromAddrPort(netip.AddrPortFrom(src, sport))
+	local = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dst, dport))
+	return remote, local, nil
+}
+
+// proxyV2Header returns a PROXY protocol version 2 header.
+// LOCAL command is returned when the addresses
+// are not TCP addresses.
+func proxyV2Header(remote, local net.Addr) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(proxyV2Signature)
+	src, ok1 := remote.(*net.TCPAddr)
+	dst, ok2 := local.(*net.TCPAddr)
+	if !ok1 || !ok2 || src == nil || dst == nil {
+		buf.Write([]byte{proxyV2Version | proxyV2CmdLocal, proxyV2Unspec, 0, 0})
+		return buf.Bytes()
+	}
+	sap, dap := src.AddrPort(), dst.AddrPort()
+	sip, dip := sap.Addr().Unmap(), dap.Addr().Unmap()
+	if sip.Is4() && dip.Is4() {
+		buf.Write([]byte{proxyV2Version | proxyV2CmdProxy, proxyV2TCP4, 0, 12})
+		buf.Write(sip.AsSlice())
+		buf.Write(dip.AsSlice())
+	} else {
+		buf.Write([]byte{proxyV2Version | proxyV2CmdProxy, proxyV2TCP6, 0, 36})
+		s16, d16 := sap.Addr().As16(), dap.Addr().As16()
+		buf.Write(s16[:])
+		buf.Write(d16[:])
+	}
+	buf.Write(binary.BigEndian.AppendUint16(nil, sap.Port()))
+	buf.Write(binary.BigEndian.AppendUint16(nil, dap.Port()))
+	return buf.Bytes()
+}
+
+// ConnAddrs is the addresses of a connection.
+// This interface is implemented by net.Conn and *quic.Conn.
+type ConnAddrs interface {
+	LocalAddr() net.Addr
+	RemoteAddr() net.Addr
+}
+
+// connAddrsKey is the context key to save the client connection.
+type connAddrsKey struct{}
+
+// ContextWithConnAddrs returns a new context with the client connection c.
+// The addresses of c are sent to upstream servers as PROXY protocol headers
+// when dialing with the context.
+// Addresses are not obtained from c in this function
+// because it can block until PROXY protocol header is read.
+func ContextWithConnAddrs(ctx context.Context, c ConnAddrs) context.Context {
+	return context.WithValue(ctx, connAddrsKey{}, c)
+}
+
+// proxyProtocolDialer sends PROXY protocol version 2 headers
+// right after the connections are established.
+// TLS handshake is run after sending the header when tlsConfig is set.
+// This implements Dialer interface.
+type proxyProtocolDialer struct {
+	Dialer
+	tlsConfig *tls.Config
+}
+
+func (d *proxyProtocolDialer) Dial(network string, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *proxyProtocolDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	var remote, local net.Addr
+	if c, ok := ctx.Value(connAddrsKey{}).(ConnAddrs); ok {
+		remote, local = c.RemoteAddr(), c.LocalAddr()
+	}
+	if _, err := conn.Write(proxyV2Header(remote, local)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if d.tlsConfig == nil {
+		return conn, nil
+	}
+
+	config := d.tlsConfig
+	if config.ServerName == "" {
+		// Same as the tls.Dialer.
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+	tc := tls.Client(conn, config)
+	if err := tc.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
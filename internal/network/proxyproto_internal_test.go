@@ -0,0 +1,367 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestReadProxyHeader(t *testing.T) {
+	type condition struct {
+		header string
+	}
+
+	type action struct {
+		remote string
+		local  string
+		rest   string
+		err    error
+	}
+
+	v2 := func(b ...byte) string { return proxyV2Signature + string(b) }
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"v1 TCP4",
+			&condition{header: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\nGET"},
+			&action{remote: "192.0.2.1:56324", local: "192.0.2.2:443", rest: "GET"},
+		),
+		gen(
+			"v1 TCP6",
+			&condition{header: "PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\nGET"},
+			&action{remote: "[2001:db8::1]:56324", local: "[2001:db8::2]:443", rest: "GET"},
+		),
+		gen(
+			"v1 UNKNOWN",
+			&condition{header: "PROXY UNKNOWN\r\nGET"},
+			&action{rest: "GET"},
+		),
+		gen(
+			"v1 family mismatch",
+			&condition{header: "PROXY TCP4 2001:db8::1 192.0.2.2 56324 443\r\n"},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"v1 invalid port",
+			&condition{header: "PROXY TCP4 192.0.2.1 192.0.2.2 99999 443\r\n"},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"v1 no CRLF",
+			&condition{header: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\n"},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"v1 too long",
+			&condition{header: "PROXY " + strings.Repeat("x", proxyV1MaxLen) + "\r\n"},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"v2 TCP4",
+			&condition{header: v2(0x21, 0x11, 0, 12, 192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x01, 0xbb) + "GET"},
+			&action{remote: "192.0.2.1:56324", local: "192.0.2.2:443", rest: "GET"},
+		),
+		gen(
+			"v2 TCP4 with TLV",
+			&condition{header: v2(0x21, 0x11, 0, 16, 192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x01, 0xbb, 0x04, 0x00, 0x01, 0x00) + "GET"},
+			&action{remote: "192.0.2.1:56324", local: "192.0.2.2:443", rest: "GET"},
+		),
+		gen(
+			"v2 LOCAL",
+			&condition{header: v2(0x20, 0x00, 0, 0) + "GET"},
+			&action{rest: "GET"},
+		),
+		gen(
+			"v2 invalid version",
+			&condition{header: v2(0x11, 0x11, 0, 0)},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"v2 short addresses",
+			&condition{header: v2(0x21, 0x11, 0, 4, 192, 0, 2, 1)},
+			&action{err: errProxyHeader},
+		),
+		gen(
+			"no header",
+			&condition{header: "GET / HTTP/1.1\r\n\r\n"},
+			&action{err: errProxyNotFound},
+		),
+		gen(
+			"EOF",
+			&condition{header: "PRO"},
+			&action{err: io.EOF},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.C.header))
+			remote, local, err := readProxyHeader(r)
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			if err != nil {
+				return
+			}
+			if tt.A.remote == "" {
+				testutil.Diff(t, nil, remote)
+				testutil.Diff(t, nil, local)
+			} else {
+				testutil.Diff(t, tt.A.remote, remote.String())
+				testutil.Diff(t, tt.A.local, local.String())
+			}
+			rest, _ := io.ReadAll(r)
+			testutil.Diff(t, tt.A.rest, string(rest))
+		})
+	}
+}
+
+func TestProxyV2Header(t *testing.T) {
+	type condition struct {
+		remote net.Addr
+		local  net.Addr
+	}
+
+	type action struct {
+		remote string
+		local  string
+	}
+
+	tcpAddr := func(s string) net.Addr { return net.TCPAddrFromAddrPort(netip.MustParseAddrPort(s)) }
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"IPv4",
+			&condition{remote: tcpAddr("192.0.2.1:56324"), local: tcpAddr("192.0.2.2:443")},
+			&action{remote: "192.0.2.1:56324", local: "192.0.2.2:443"},
+		),
+		gen(
+			"IPv6",
+			&condition{remote: tcpAddr("[2001:db8::1]:56324"), local: tcpAddr("[2001:db8::2]:443")},
+			&action{remote: "[2001:db8::1]:56324", local: "[2001:db8::2]:443"},
+		),
+		gen(
+			"mixed",
+			&condition{remote: tcpAddr("192.0.2.1:56324"), local: tcpAddr("[2001:db8::2]:443")},
+			&action{remote: "192.0.2.1:56324", local: "[2001:db8::2]:443"},
+		),
+		gen(
+			"no addresses",
+			&condition{},
+			&action{},
+		),
+		gen(
+			"unix",
+			&condition{remote: &net.UnixAddr{Name: "@test", Net: "unix"}, local: &net.UnixAddr{Name: "@test", Net: "unix"}},
+			&action{},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			header := proxyV2Header(tt.C.remote, tt.C.local)
+			remote, local, err := readProxyHeader(bufio.NewReader(strings.NewReader(string(header))))
+			testutil.Diff(t, nil, err)
+			if tt.A.remote == "" {
+				testutil.Diff(t, nil, remote)
+				return
+			}
+			testutil.Diff(t, tt.A.remote, remote.String())
+			testutil.Diff(t, tt.A.local, local.String())
+		})
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	type condition struct {
+		trusted []string
+		send    string
+	}
+
+	type action struct {
+		remote string // Empty means the actual address.
+		read   string
+		err    bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"trusted",
+			&condition{trusted: []string{"127.0.0.0/8"}, send: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\nhello"},
+			&action{remote: "192.0.2.1:56324", read: "hello"},
+		),
+		gen(
+			"all trusted",
+			&condition{trusted: []string{"0.0.0.0/0", "::/0"}, send: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\nhello"},
+			&action{remote: "192.0.2.1:56324", read: "hello"},
+		),
+		gen(
+			"no trusted networks",
+			&condition{send: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\nhello"},
+			&action{read: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\nhello"},
+		),
+		gen(
+			"not trusted",
+			&condition{trusted: []string{"10.0.0.0/8"}, send: "hello"},
+			&action{read: "hello"},
+		),
+		gen(
+			"header required",
+			&condition{trusted: []string{"127.0.0.0/8"}, send: "hello"},
+			&action{read: "hello", err: true},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			lc, err := ListenConfigFromSpec(&k.ListenConfig{
+				Addr: "127.0.0.1:0",
+				ProxyProtocol: &k.ProxyProtocolConfig{
+					TrustedNetworks: tt.C.trusted,
+					HeaderTimeout:   1000,
+				},
+			})
+			testutil.Diff(t, nil, err)
+			ln, err := NewListener(lc)
+			testutil.Diff(t, nil, err)
+			defer ln.Close()
+
+			c, err := net.Dial("tcp", ln.Addr().String())
+			testutil.Diff(t, nil, err)
+			defer c.Close()
+			_, err = c.Write([]byte(tt.C.send))
+			testutil.Diff(t, nil, err)
+
+			sc, err := ln.Accept()
+			testutil.Diff(t, nil, err)
+			defer sc.Close()
+			remote := tt.A.remote
+			if remote == "" {
+				remote = c.LocalAddr().String()
+			}
+			testutil.Diff(t, remote, sc.RemoteAddr().String())
+
+			b := make([]byte, len(tt.A.read))
+			_, err = io.ReadFull(sc, b)
+			testutil.Diff(t, tt.A.err, err != nil)
+			if err == nil {
+				testutil.Diff(t, tt.A.read, string(b))
+			}
+		})
+	}
+}
+
+func TestProxyProtocolListener_invalidNetwork(t *testing.T) {
+	_, err := ListenConfigFromSpec(&k.ListenConfig{
+		ProxyProtocol: &k.ProxyProtocolConfig{TrustedNetworks: []string{"invalid"}},
+	})
+	testutil.Diff(t, false, err == nil)
+}
+
+// testConnAddrs is the addresses of a client connection.
+type testConnAddrs struct {
+	local, remote net.Addr
+}
+
+func (c *testConnAddrs) LocalAddr() net.Addr  { return c.local }
+func (c *testConnAddrs) RemoteAddr() net.Addr { return c.remote }
+
+func TestProxyProtocolDialer(t *testing.T) {
+	lc, err := ListenConfigFromSpec(&k.ListenConfig{
+		Addr:          "127.0.0.1:0",
+		ProxyProtocol: &k.ProxyProtocolConfig{TrustedNetworks: []string{"127.0.0.0/8"}},
+	})
+	testutil.Diff(t, nil, err)
+	ln, err := NewListener(lc)
+	testutil.Diff(t, nil, err)
+	defer ln.Close()
+
+	d, err := NewDialerFromSpec(&k.DialConfig{ProxyProtocol: true})
+	testutil.Diff(t, nil, err)
+
+	client := &testConnAddrs{
+		remote: net.TCPAddrFromAddrPort(netip.MustParseAddrPort("192.0.2.1:56324")),
+		local:  net.TCPAddrFromAddrPort(netip.MustParseAddrPort("192.0.2.2:443")),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Client addresses are sent.
+	c1, err := d.DialContext(ContextWithConnAddrs(ctx, client), "tcp", ln.Addr().String())
+	testutil.Diff(t, nil, err)
+	defer c1.Close()
+	sc1, err := ln.Accept()
+	testutil.Diff(t, nil, err)
+	defer sc1.Close()
+	testutil.Diff(t, "192.0.2.1:56324", sc1.RemoteAddr().String())
+	testutil.Diff(t, "192.0.2.2:443", sc1.LocalAddr().String())
+
+	// LOCAL command is sent without client addresses.
+	c2, err := d.DialContext(ctx, "tcp", ln.Addr().String())
+	testutil.Diff(t, nil, err)
+	defer c2.Close()
+	sc2, err := ln.Accept()
+	testutil.Diff(t, nil, err)
+	defer sc2.Close()
+	testutil.Diff(t, c2.LocalAddr().String(), sc2.RemoteAddr().String())
+}
+
+func TestProxyProtocolTransport(t *testing.T) {
+	lc, err := ListenConfigFromSpec(&k.ListenConfig{
+		Addr:          "127.0.0.1:0",
+		ProxyProtocol: &k.ProxyProtocolConfig{TrustedNetworks: []string{"127.0.0.0/8"}},
+	})
+	testutil.Diff(t, nil, err)
+	ln, err := NewListener(lc)
+	testutil.Diff(t, nil, err)
+	svr := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.RemoteAddr))
+		}),
+	}
+	go func() { _ = svr.Serve(ln) }()
+	defer svr.Close()
+
+	transport, err := HTTPTransport(&k.HTTPTransportConfig{
+		DialConfig: &k.DialConfig{ProxyProtocol: true},
+	})
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, true, transport.DisableKeepAlives)
+
+	// Each request must be sent with the addresses of its own client
+	// even the requests are sent to the same upstream one after another.
+	for _, addr := range []string{"192.0.2.1:56324", "192.0.2.3:56325"} {
+		client := &testConnAddrs{
+			remote: net.TCPAddrFromAddrPort(netip.MustParseAddrPort(addr)),
+			local:  net.TCPAddrFromAddrPort(netip.MustParseAddrPort("192.0.2.2:443")),
+		}
+		ctx := ContextWithConnAddrs(context.Background(), client)
+		r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+ln.Addr().String(), nil)
+		res, err := transport.RoundTrip(r)
+		testutil.Diff(t, nil, err)
+		b, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		testutil.Diff(t, addr, string(b))
+	}
+}
+
+func TestProxyProtocolTransport_http2(t *testing.T) {
+	_, err := HTTP2Transport(&k.HTTP2TransportConfig{
+		DialConfig: &k.DialConfig{ProxyProtocol: true},
+	})
+	testutil.Diff(t, false, err == nil)
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-projects/go/zerrors"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// defaultCRLReloadInterval is the default interval
+	// to check the modification of CRL files.
+	defaultCRLReloadInterval = 60 * time.Second
+	// defaultOCSPTimeout is the default timeout of OCSP requests.
+	defaultOCSPTimeout = 5 * time.Second
+	// defaultOCSPCacheTTL is the cache duration of OCSP responses
+	// that do not have NextUpdate.
+	defaultOCSPCacheTTL = time.Hour
+	// maxOCSPResponseSize is the maximum size of OCSP responses.
+	maxOCSPResponseSize = 1 << 20
+)
+
+var (
+	// ErrCertRevoked is the error returned when a certificate was revoked.
+	ErrCertRevoked = errors.New("internal/network: certificate revoked")
+	// ErrRevocationUnknown is the error returned when the revocation status
+	// of a certificate could not be determined in hard-fail mode.
+	ErrRevocationUnknown = errors.New("internal/network: revocation status unknown")
+)
+
+// revocationStatus is the revocation status of a certificate.
+type revocationStatus int
+
+const (
+	statusUnknown revocationStatus = iota
+	statusGood
+	statusRevoked
+)
+
+// RevocationChecker checks revocation status of certificates
+// using CRLs and OCSP responders.
+type RevocationChecker struct {
+	crlFiles []string
+	ocsp     bool
+	softFail bool
+	client   *http.Client
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex // Lock for reloading CRLs.
+	next time.Time
+	crls atomic.Pointer[crlSet]
+
+	cache sync.Map // Cache of OCSP responses. Key is ocspKey.
+}
+
+// crlSet is the set of loaded CRLs.
+type crlSet struct {
+	lists  []*x509.RevocationList
+	stamps []fileStamp
+}
+
+// ocspKey is the key of OCSP response cache.
+type ocspKey struct {
+	issuer string
+	serial string
+}
+
+// ocspEntry is a cached OCSP response.
+type ocspEntry struct {
+	status  revocationStatus
+	expires time.Time
+}
+
+// NewRevocationChecker returns a new revocation checker from the given spec.
+// This function returns nil checker and nil error when the spec was nil.
+func NewRevocationChecker(spec *k.RevocationConfig) (*RevocationChecker, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	r := &RevocationChecker{
+		crlFiles: spec.CRLFiles,
+		ocsp:     spec.OCSP,
+		softFail: spec.SoftFail,
+		client: &http.Client{
+			Transport: DefaultHTTPTransport,
+			Timeout:   cmpDuration(time.Duration(spec.OCSPTimeout)*time.Millisecond, defaultOCSPTimeout),
+		},
+		interval: cmpDuration(time.Duration(spec.CRLReloadInterval)*time.Second, defaultCRLReloadInterval),
+		now:      time.Now,
+	}
+	crls, err := r.loadCRLs()
+	if err != nil {
+		return nil, zerrors.NewErr(err, "internal/network: failed to load CRLs", "")
+	}
+	r.crls.Store(crls)
+	r.next = r.now().Add(r.interval)
+	return r, nil
+}
+
+// stamps returns the current states of the CRL files.
+func (r *RevocationChecker) stamps() []fileStamp {
+	stamps := make([]fileStamp, len(r.crlFiles))
+	for i, file := range r.crlFiles {
+		if info, err := os.Stat(file); err == nil {
+			stamps[i] = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// loadCRLs loads CRLs from the files.
+// Both PEM and DER formats are accepted.
+func (r *RevocationChecker) loadCRLs() (*crlSet, error) {
+	stamps := r.stamps()
+	lists := make([]*x509.RevocationList, 0, len(r.crlFiles))
+	for _, file := range r.crlFiles {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		ders := [][]byte{b}
+		if bytes.Contains(b, []byte("-----BEGIN")) {
+			ders = ders[:0]
+			for block, rest := pem.Decode(b); block != nil; block, rest = pem.Decode(rest) {
+				if block.Type == "X509 CRL" {
+					ders = append(ders, block.Bytes)
+				}
+			}
+		}
+		for _, der := range ders {
+			crl, err := x509.ParseRevocationList(der)
+			if err != nil {
+				return nil, zerrors.NewErr(err, "internal/network: invalid CRL", "file=%s", file)
+			}
+			lists = append(lists, crl)
+		}
+	}
+	return &crlSet{lists: lists, stamps: stamps}, nil
+}
+
+// currentCRLs returns the loaded CRLs.
+// CRLs are reloaded if the files were modified.
+// Currently loaded CRLs are kept when failed to reload.
+func (r *RevocationChecker) currentCRLs() *crlSet {
+	current := r.crls.Load()
+	if len(r.crlFiles) == 0 || !r.mu.TryLock() {
+		return current
+	}
+	defer r.mu.Unlock()
+	now := r.now()
+	if now.Before(r.next) {
+		return current
+	}
+	r.next = now.Add(r.interval)
+	if slices.Equal(current.stamps, r.stamps()) {
+		return current
+	}
+	crls, err := r.loadCRLs()
+	if err != nil {
+		return current // Files may be being rewritten. Retry next time.
+	}
+	r.crls.Store(crls)
+	return crls
+}
+
+// Check checks the revocation status of the leaf certificate of the
+// verified chain. The chain must be ordered from the leaf to the root
+// as the x509.Certificate.Verify returns.
+// ErrCertRevoked is returned when the certificate was revoked.
+// ErrRevocationUnknown is returned when the status could not be determined
+// and soft-fail is not enabled.
+// Self-signed certificates, or chains without issuer, are not checked.
+func (r *RevocationChecker) Check(ctx context.Context, chain []*x509.Certificate) error {
+	if len(chain) < 2 {
+		return nil
+	}
+	leaf, issuer := chain[0], chain[1]
+
+	status := r.checkCRL(leaf, issuer)
+	if status == statusRevoked {
+		return ErrCertRevoked
+	}
+	if status == statusUnknown && r.ocsp {
+		status = r.checkOCSP(ctx, leaf, issuer)
+	}
+	switch {
+	case status == statusRevoked:
+		return ErrCertRevoked
+	case status == statusUnknown && !r.softFail:
+		return ErrRevocationUnknown
+	default:
+		return nil
+	}
+}
+
+// checkCRL checks the revocation status with the CRLs.
+// Expired CRLs and CRLs of other issuers are ignored.
+func (r *RevocationChecker) checkCRL(leaf, issuer *x509.Certificate) revocationStatus {
+	now := r.now()
+	status := statusUnknown
+	for _, crl := range r.currentCRLs().lists {
+		if !bytes.Equal(crl.RawIssuer, leaf.RawIssuer) {
+			continue
+		}
+		if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+			continue // Expired.
+		}
+		if crl.CheckSignatureFrom(issuer) != nil {
+			continue
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				return statusRevoked
+			}
+		}
+		status = statusGood
+	}
+	return status
+}
+
+// checkOCSP checks the revocation status with the OCSP responders
+// written in the leaf certificate.
+// Responses are cached until their NextUpdate.
+func (r *RevocationChecker) checkOCSP(ctx context.Context, leaf, issuer *x509.Certificate) revocationStatus {
+	key := ocspKey{issuer: string(issuer.RawSubjectPublicKeyInfo), serial: leaf.SerialNumber.String()}
+	now := r.now()
+	if v, ok := r.cache.Load(key); ok {
+		if e := v.(*ocspEntry); now.Before(e.expires) {
+			return e.status
+		}
+		r.cache.Delete(key)
+	}
+
+	resp, err := fetchOCSP(ctx, r.client, leaf, issuer)
+	if err != nil {
+		return statusUnknown // Unknown responses are not cached.
+	}
+	var status revocationStatus
+	switch resp.Status {
+	case ocsp.Good:
+		status = statusGood
+	case ocsp.Revoked:
+		status = statusRevoked
+	default:
+		return statusUnknown
+	}
+	expires := resp.NextUpdate
+	if expires.IsZero() {
+		expires = now.Add(defaultOCSPCacheTTL)
+	}
+	r.cache.Store(key, &ocspEntry{status: status, expires: expires})
+	return status
+}
+
+// fetchOCSP obtains the OCSP response of the leaf certificate
+// from the first available OCSP responder written in the certificate.
+func fetchOCSP(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, errors.New("internal/network: no OCSP responder")
+	}
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, server := range leaf.OCSPServer {
+		raw, err := postOCSP(ctx, client, server, req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// postOCSP sends the OCSP request to the server and returns the raw response.
+func postOCSP(ctx context.Context, client *http.Client, server string, req []byte) ([]byte, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/ocsp-request")
+	r.Header.Set("Accept", "application/ocsp-response")
+	res, err := client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("internal/network: OCSP responder returned status " + res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxOCSPResponseSize))
+}
@@ -0,0 +1,295 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/crypto/ocsp"
+)
+
+// leaf returns a new leaf certificate signed by the ca.
+func (ca *testCA) leaf(t *testing.T, serial int64, ocspServer string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.Diff(t, nil, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test.com"},
+		DNSNames:     []string{"test.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ocspServer != "" {
+		tmpl.OCSPServer = []string{ocspServer}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	testutil.Diff(t, nil, err)
+	cert, err := x509.ParseCertificate(der)
+	testutil.Diff(t, nil, err)
+	return cert, key
+}
+
+// writeCRL writes a CRL which revokes the given serials.
+func (ca *testCA) writeCRL(t *testing.T, file string, nextUpdate time.Time, serials ...int64) {
+	t.Helper()
+	entries := make([]x509.RevocationListEntry, 0, len(serials))
+	for _, s := range serials {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(s), RevocationTime: time.Now()})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, nil, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0o600))
+}
+
+// ocspResponder returns a new OCSP responder of the ca.
+// Certificates which have serials listed in the revoked are reported as revoked.
+// The number of requests are counted by count.
+func (ca *testCA) ocspResponder(t *testing.T, count *atomic.Int32, revoked ...int64) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		tmpl := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		for _, s := range revoked {
+			if req.SerialNumber.Int64() == s {
+				tmpl.Status = ocsp.Revoked
+				tmpl.RevokedAt = time.Now().Add(-time.Minute)
+			}
+		}
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, tmpl, ca.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(resp)
+	}))
+}
+
+func TestRevocationChecker_Check(t *testing.T) {
+	type condition struct {
+		crl      bool  // Write a CRL.
+		expired  bool  // CRL is expired.
+		revoked  int64 // Serial to be revoked.
+		ocsp     bool
+		softFail bool
+		serial   int64
+		noIssuer bool
+	}
+
+	type action struct {
+		err error
+	}
+
+	ca := newTestCA(t, "ca")
+	var count atomic.Int32
+	responder := ca.ocspResponder(t, &count, 99)
+	defer responder.Close()
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen("CRL good", &condition{crl: true, revoked: 10, serial: 1}, &action{}),
+		gen("CRL revoked", &condition{crl: true, revoked: 1, serial: 1}, &action{err: ErrCertRevoked}),
+		gen("CRL revoked soft-fail", &condition{crl: true, revoked: 1, serial: 1, softFail: true}, &action{err: ErrCertRevoked}),
+		gen("CRL expired", &condition{crl: true, expired: true, revoked: 10, serial: 1}, &action{err: ErrRevocationUnknown}),
+		gen("unknown hard-fail", &condition{serial: 1}, &action{err: ErrRevocationUnknown}),
+		gen("unknown soft-fail", &condition{serial: 1, softFail: true}, &action{}),
+		gen("OCSP good", &condition{ocsp: true, serial: 1}, &action{}),
+		gen("OCSP revoked", &condition{ocsp: true, serial: 99}, &action{err: ErrCertRevoked}),
+		gen("CRL preferred", &condition{crl: true, revoked: 10, ocsp: true, serial: 99}, &action{}),
+		gen("no issuer", &condition{noIssuer: true}, &action{}),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			spec := &k.RevocationConfig{OCSP: tt.C.ocsp, SoftFail: tt.C.softFail}
+			if tt.C.crl {
+				file := filepath.Join(t.TempDir(), "test.crl")
+				next := time.Now().Add(time.Hour)
+				if tt.C.expired {
+					next = time.Now().Add(-time.Second)
+				}
+				ca.writeCRL(t, file, next, tt.C.revoked)
+				spec.CRLFiles = []string{file}
+			}
+			r, err := NewRevocationChecker(spec)
+			testutil.Diff(t, nil, err)
+
+			chain := []*x509.Certificate{ca.cert}
+			if !tt.C.noIssuer {
+				leaf, _ := ca.leaf(t, tt.C.serial, responder.URL)
+				chain = []*x509.Certificate{leaf, ca.cert}
+			}
+			err = r.Check(context.Background(), chain)
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+		})
+	}
+}
+
+func TestRevocationChecker_reload(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	file := filepath.Join(t.TempDir(), "test.crl")
+	ca.writeCRL(t, file, time.Now().Add(time.Hour))
+
+	r, err := NewRevocationChecker(&k.RevocationConfig{CRLFiles: []string{file}})
+	testutil.Diff(t, nil, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.next = now.Add(r.interval)
+
+	leaf, _ := ca.leaf(t, 1, "")
+	chain := []*x509.Certificate{leaf, ca.cert}
+	testutil.Diff(t, nil, r.Check(context.Background(), chain))
+
+	// Revoke the certificate.
+	ca.writeCRL(t, file, time.Now().Add(time.Hour), 1)
+	touch(t, file, time.Minute)
+	testutil.Diff(t, nil, r.Check(context.Background(), chain)) // Not reloaded within the interval.
+	now = now.Add(r.interval)
+	testutil.Diff(t, ErrCertRevoked, r.Check(context.Background(), chain), cmpopts.EquateErrors())
+
+	// Invalid CRL is not loaded.
+	testutil.Diff(t, nil, os.WriteFile(file, []byte("invalid"), 0o600))
+	touch(t, file, 2*time.Minute)
+	now = now.Add(r.interval)
+	testutil.Diff(t, ErrCertRevoked, r.Check(context.Background(), chain), cmpopts.EquateErrors())
+}
+
+func TestRevocationChecker_ocspCache(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	var count atomic.Int32
+	responder := ca.ocspResponder(t, &count)
+	defer responder.Close()
+
+	r, err := NewRevocationChecker(&k.RevocationConfig{OCSP: true})
+	testutil.Diff(t, nil, err)
+	leaf, _ := ca.leaf(t, 1, responder.URL)
+	chain := []*x509.Certificate{leaf, ca.cert}
+	testutil.Diff(t, nil, r.Check(context.Background(), chain))
+	testutil.Diff(t, nil, r.Check(context.Background(), chain))
+	testutil.Diff(t, int32(1), count.Load())
+
+	// Responder not available.
+	leaf, _ = ca.leaf(t, 2, "http://127.0.0.1:0/")
+	err = r.Check(context.Background(), []*x509.Certificate{leaf, ca.cert})
+	testutil.Diff(t, ErrRevocationUnknown, err, cmpopts.EquateErrors())
+}
+
+func TestRevocationChecker_invalidCRL(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.crl")
+	testutil.Diff(t, nil, os.WriteFile(file, []byte("invalid"), 0o600))
+	_, err := NewRevocationChecker(&k.RevocationConfig{CRLFiles: []string{file}})
+	testutil.Diff(t, false, err == nil)
+}
+
+func TestTLSConfig_revocation(t *testing.T) {
+	dir := t.TempDir()
+	serverCert := filepath.Join(dir, "server.crt")
+	serverKey := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.pem")
+	crlFile := filepath.Join(dir, "ca.crl")
+
+	ca := newTestCA(t, "ca")
+	ca.issue(t, serverCert, serverKey)
+	testutil.Diff(t, nil, os.WriteFile(caFile, ca.pem, 0o600))
+	ca.writeCRL(t, crlFile, time.Now().Add(time.Hour), 2)
+
+	server, err := TLSConfig(&k.TLSConfig{
+		CertKeyPairs: []*k.CertKeyPair{{CertFile: serverCert, KeyFile: serverKey}},
+		ClientCAs:    []string{caFile},
+		ClientAuth:   k.ClientAuthType_RequireAndVerifyClientCert,
+		Revocation:   &k.RevocationConfig{CRLFiles: []string{crlFile}},
+	})
+	testutil.Diff(t, nil, err)
+
+	client := func(serial int64) *tls.Config {
+		leaf, key := ca.leaf(t, serial, "")
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.cert)
+		return &tls.Config{
+			ServerName:   "test.com",
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{{Certificate: [][]byte{leaf.Raw}, PrivateKey: key}},
+		}
+	}
+
+	_, _, err = handshake(server, client(1))
+	testutil.Diff(t, nil, err)
+	_, _, err = handshake(server, client(2))
+	testutil.Diff(t, false, err == nil)
+}
+
+func TestOCSPStapler(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	var count atomic.Int32
+	responder := ca.ocspResponder(t, &count)
+	defer responder.Close()
+
+	leaf, key := ca.leaf(t, 1, responder.URL)
+	noOCSP, _ := ca.leaf(t, 2, "")
+	certs := []tls.Certificate{
+		{Certificate: [][]byte{leaf.Raw, ca.cert.Raw}, PrivateKey: key},
+		{Certificate: [][]byte{noOCSP.Raw, ca.cert.Raw}, PrivateKey: key},
+		{Certificate: [][]byte{leaf.Raw}, PrivateKey: key}, // No issuer.
+	}
+
+	s := newOCSPStapler()
+	stapled := s.staple(certs)
+	testutil.Diff(t, 3, len(stapled))
+	for range 100 {
+		if stapled[0].OCSPStaple != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+		stapled = s.staple(certs)
+	}
+	resp, err := ocsp.ParseResponseForCert(stapled[0].OCSPStaple, leaf, ca.cert)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, ocsp.Good, resp.Status)
+	testutil.Diff(t, true, stapled[1].OCSPStaple == nil)
+	testutil.Diff(t, true, stapled[2].OCSPStaple == nil)
+	testutil.Diff(t, true, certs[0].OCSPStaple == nil) // Original is not modified.
+
+	// Not refreshed until the half of the validity period.
+	s.staple(certs)
+	time.Sleep(10 * time.Millisecond)
+	testutil.Diff(t, int32(1), count.Load())
+}
@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aileron-projects/go/zerrors"
+)
+
+// sockets is the store of listening sockets
+// that are created by NewListener.
+var sockets = &socketStore{
+	entries: map[string]*socketEntry{},
+}
+
+// InheritSockets makes all open listening sockets inheritable.
+// Listeners created after calling this function with the same
+// network and address reuse the inherited socket instead of binding a new one.
+// Each socket can be inherited only once.
+// Call the returned function when all listeners were created.
+// Sockets that were not inherited are closed if no listener uses them.
+// Listeners created after calling InheritSockets are closed
+// when the returned function is called with abort=true.
+func InheritSockets() (done func(abort bool)) {
+	sockets.inheritAll()
+	return sockets.releaseAll
+}
+
+// SocketFiles returns the keys and files of the listening sockets
+// which are currently stored.
+// Keys are in the format of "<network>://<address>" such as "tcp://:8080".
+// Returned files are owned by this package and must not be closed by callers.
+// This function is intended to be used for passing listening sockets to a new process.
+func SocketFiles() (keys []string, files []*os.File) {
+	return sockets.files()
+}
+
+// AddSocketFiles adds listening sockets received from another process.
+// Keys must be in the format of "<network>://<address>" as returned by SocketFiles.
+// Added sockets are inherited by the listeners created with the same network and address
+// after calling InheritSockets.
+// Sockets that were not inherited are closed when the function returned by InheritSockets is called.
+func AddSocketFiles(keys []string, files []*os.File) {
+	for i := range min(len(keys), len(files)) {
+		sockets.add(keys[i], files[i])
+	}
+}
+
+// DetachSockets prevents the stored sockets from being cleaned up when closed.
+// Currently, it means that unix socket files are not removed.
+// Call this function after the sockets were passed to another process
+// that continues to use them.
+func DetachSockets() {
+	sockets.detach()
+}
+
+// socketKey returns the key of a socket in the format of "<network>://<address>".
+// Empty network is treated as "tcp".
+func socketKey(network, address string) string {
+	if network == "" {
+		network = "tcp"
+	}
+	return network + "://" + address
+}
+
+// shareable returns if the socket with the given network and address
+// can be shared between listeners.
+// Sockets with automatically chosen port are not shareable.
+func shareable(network, address string) bool {
+	switch network {
+	case "", "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		_, port, err := net.SplitHostPort(address)
+		return err == nil && port != "" && port != "0"
+	case "unix", "unixpacket", systemdNetwork:
+		return address != ""
+	}
+	return false
+}
+
+// unlinkFunc returns the function that removes the unix socket file.
+// Nil is returned for non-unix sockets and abstract unix sockets.
+func unlinkFunc(network, address string) func() {
+	if network != "unix" && network != "unixpacket" {
+		return nil
+	}
+	if address == "" || strings.HasPrefix(address, "@") {
+		return nil
+	}
+	return func() { os.Remove(address) }
+}
+
+// filer is the interface of listeners and connections
+// which can return its file descriptor.
+// *net.TCPListener, *net.UnixListener and *net.UDPConn implement this interface.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// socketEntry is a listening socket stored in the socketStore.
+type socketEntry struct {
+	// file is the duplicated file of the socket.
+	// This file is kept open while the socket is used or inheritable.
+	file *os.File
+	// refs is the number of listeners using this socket.
+	refs int
+	// inheritable is the flag that this socket
+	// can be inherited by a new listener.
+	inheritable bool
+	// onClose is called when the file was closed.
+	// This can be nil.
+	onClose func()
+}
+
+// socketStore stores listening sockets so they can be shared
+// between the old and new listeners when reloading configs.
+type socketStore struct {
+	mu      sync.Mutex
+	entries map[string]*socketEntry
+	// created is the list of listeners created
+	// while inheriting sockets.
+	// This is nil when not inheriting.
+	created []io.Closer
+}
+
+func (s *socketStore) inheritAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		e.inheritable = true
+	}
+	s.created = []io.Closer{}
+}
+
+func (s *socketStore) releaseAll(abort bool) {
+	s.mu.Lock()
+	created := s.created
+	s.created = nil
+	s.mu.Unlock()
+	if abort {
+		for _, c := range created {
+			c.Close()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entries {
+		e.inheritable = false
+		if e.refs <= 0 {
+			s.closeEntry(key, e)
+		}
+	}
+}
+
+// closeEntry closes the file and remove the entry from the store.
+// Callers must hold the lock.
+func (s *socketStore) closeEntry(key string, e *socketEntry) {
+	e.file.Close()
+	if s.entries[key] == e {
+		delete(s.entries, key)
+	}
+	if e.onClose != nil {
+		e.onClose()
+	}
+}
+
+// release decrements the reference count of the socket.
+// The socket file is closed when no listener uses it.
+func (s *socketStore) release(key string, e *socketEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.refs--
+	if e.refs <= 0 && !e.inheritable {
+		s.closeEntry(key, e)
+	}
+}
+
+// inherit returns the inheritable socket entry bound to the key.
+// Nil will be returned when no inheritable socket was found.
+// Returned entry is not inheritable anymore.
+// Callers must hold the lock.
+func (s *socketStore) inherit(key string) *socketEntry {
+	e, ok := s.entries[key]
+	if !ok || !e.inheritable {
+		return nil
+	}
+	e.inheritable = false
+	e.refs++
+	return e
+}
+
+// store stores the socket with the key.
+// The socket is duplicated and the given f can be closed after calling this.
+// Callers must hold the lock.
+func (s *socketStore) store(key string, f filer, onClose func()) (*socketEntry, error) {
+	file, err := f.File()
+	if err != nil {
+		return nil, err
+	}
+	e := &socketEntry{
+		file:    file,
+		refs:    1,
+		onClose: onClose,
+	}
+	s.entries[key] = e
+	return e, nil
+}
+
+// add adds the socket file received from another process.
+// The socket is inheritable and will be closed
+// if it was not inherited until releaseAll is called.
+func (s *socketStore) add(key string, file *os.File) {
+	network, address, _ := strings.Cut(key, "://")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.refs <= 0 {
+		s.closeEntry(key, e) // Replace the unused socket.
+	}
+	s.entries[key] = &socketEntry{
+		file:        file,
+		inheritable: true,
+		onClose:     unlinkFunc(network, address),
+	}
+}
+
+// files returns the keys and files of the stored sockets.
+// Keys are sorted in ascending order.
+func (s *socketStore) files() ([]string, []*os.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	files := make([]*os.File, 0, len(keys))
+	for _, key := range keys {
+		files = append(files, s.entries[key].file)
+	}
+	return keys, files
+}
+
+// detach disables the cleanup of all stored sockets.
+func (s *socketStore) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		e.onClose = nil
+	}
+}
+
+// track records the listener or conn created while inheriting sockets.
+// Callers must hold the lock.
+func track[T io.Closer](s *socketStore, c T) T {
+	if s.created != nil {
+		s.created = append(s.created, c)
+	}
+	return c
+}
+
+// listen returns a new listener.
+// The listener uses an inherited socket if available
+// and binds a new socket otherwise.
+func (s *socketStore) listen(lc *net.ListenConfig, network, address string) (net.Listener, error) {
+	if !shareable(network, address) {
+		return lc.Listen(context.Background(), network, address)
+	}
+
+	if network == systemdNetwork {
+		loadSystemdOnce.Do(loadSystemdSockets)
+	}
+
+	key := socketKey(network, address)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.inherit(key)
+	if e == nil && network == systemdNetwork {
+		return nil, zerrors.NewErr(nil, "internal/network: systemd socket not found", "name=%s", address)
+	}
+	if e == nil {
+		ln, err := lc.Listen(context.Background(), network, address)
+		if err != nil {
+			return nil, err
+		}
+		if ul, ok := ln.(*net.UnixListener); ok {
+			// Unix socket file should be kept until the socket is fully closed.
+			ul.SetUnlinkOnClose(false)
+		}
+		e, err = s.store(key, ln.(filer), unlinkFunc(network, address))
+		if err != nil {
+			ln.Close()
+			return nil, err
+		}
+		return track(s, &socketListener{
+			Listener: ln,
+			release:  func() { s.release(key, e) },
+		}), nil
+	}
+
+	ln, err := net.FileListener(e.file)
+	if err != nil {
+		e.refs--
+		return nil, zerrors.NewErr(err, "internal/network: failed to inherit socket", "key=%s", key)
+	}
+	return track(s, &socketListener{
+		Listener: ln,
+		release:  func() { s.release(key, e) },
+	}), nil
+}
+
+// listenPacket returns a new packet conn.
+// The conn uses an inherited socket if available
+// and binds a new socket otherwise.
+func (s *socketStore) listenPacket(lc *net.ListenConfig, network, address string) (net.PacketConn, error) {
+	if !shareable(network, address) {
+		return lc.ListenPacket(context.Background(), network, address)
+	}
+
+	key := socketKey(network, address)
+	if network == systemdNetwork {
+		loadSystemdOnce.Do(loadSystemdSockets)
+		key = socketKey(systemdPacketNetwork, address)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.inherit(key)
+	if e == nil && network == systemdNetwork {
+		return nil, zerrors.NewErr(nil, "internal/network: systemd socket not found", "name=%s", address)
+	}
+	if e == nil {
+		conn, err := lc.ListenPacket(context.Background(), network, address)
+		if err != nil {
+			return nil, err
+		}
+		uc, ok := conn.(*net.UDPConn)
+		if !ok {
+			return conn, nil // Not shareable.
+		}
+		e, err = s.store(key, uc, nil)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return track(s, &socketUDPConn{
+			UDPConn: uc,
+			release: func() { s.release(key, e) },
+		}), nil
+	}
+
+	conn, err := net.FilePacketConn(e.file)
+	if err != nil {
+		e.refs--
+		return nil, zerrors.NewErr(err, "internal/network: failed to inherit socket", "key=%s", key)
+	}
+	uc, ok := conn.(*net.UDPConn)
+	if !ok {
+		conn.Close()
+		e.refs--
+		return nil, zerrors.NewErr(nil, "internal/network: failed to inherit socket", "key=%s is not a udp socket", key)
+	}
+	return track(s, &socketUDPConn{
+		UDPConn: uc,
+		release: func() { s.release(key, e) },
+	}), nil
+}
+
+// socketListener is the listener that uses a socket stored in the socketStore.
+// socketListener releases the socket when closed.
+type socketListener struct {
+	net.Listener
+	once    sync.Once
+	release func()
+}
+
+func (l *socketListener) Close() error {
+	err := l.Listener.Close()
+	l.once.Do(l.release)
+	return err
+}
+
+// socketUDPConn is the udp conn that uses a socket stored in the socketStore.
+// socketUDPConn releases the socket when closed.
+// *net.UDPConn is embedded so that the optional methods
+// such as ReadMsgUDP and SyscallConn are available.
+type socketUDPConn struct {
+	*net.UDPConn
+	once    sync.Once
+	release func()
+}
+
+func (c *socketUDPConn) Close() error {
+	err := c.UDPConn.Close()
+	c.once.Do(c.release)
+	return err
+}
+
+// ListenPacket returns a new packet conn listening on the given address.
+// Address can have network type prefix in the format of "<Network>://<Address>".
+// Network must be "udp", "udp4", "udp6" or "systemd". Default is "udp".
+// Network "systemd" uses the datagram socket passed by systemd socket activation
+// which has the name given as the address such as "systemd://aileron".
+// The socket is shared with the listeners created while reloading configs.
+// See InheritSockets.
+func ListenPacket(address string) (net.PacketConn, error) {
+	network, addr := parseNetAddr(address)
+	network = cmp.Or(network, "udp") // Default udp.
+	switch network {
+	case "udp", "udp4", "udp6", systemdNetwork:
+	default:
+		return nil, zerrors.NewErr(nil, "internal/network: unknown address `"+address+"`", "")
+	}
+	conn, err := sockets.listenPacket(&net.ListenConfig{}, network, addr)
+	if err != nil {
+		return nil, zerrors.NewErr(err, "internal/network: failed to create new packet conn", "")
+	}
+	return conn, nil
+}
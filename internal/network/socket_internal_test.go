@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+// freeAddr returns an available local TCP address.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestShareable(t *testing.T) {
+	type condition struct {
+		network string
+		address string
+	}
+
+	type action struct {
+		shareable bool
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen("tcp", &condition{network: "tcp", address: "127.0.0.1:8080"}, &action{shareable: true}),
+		gen("empty network", &condition{network: "", address: ":8080"}, &action{shareable: true}),
+		gen("udp", &condition{network: "udp", address: ":8080"}, &action{shareable: true}),
+		gen("port 0", &condition{network: "tcp", address: "127.0.0.1:0"}, &action{shareable: false}),
+		gen("no port", &condition{network: "tcp", address: "127.0.0.1"}, &action{shareable: false}),
+		gen("unix", &condition{network: "unix", address: "/tmp/test.sock"}, &action{shareable: true}),
+		gen("empty unix", &condition{network: "unix", address: ""}, &action{shareable: false}),
+		gen("unknown", &condition{network: "ip", address: "127.0.0.1"}, &action{shareable: false}),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			testutil.Diff(t, tt.A.shareable, shareable(tt.C.network, tt.C.address))
+		})
+	}
+}
+
+func TestInheritSockets(t *testing.T) {
+	addr := freeAddr(t)
+
+	ln1, err := NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+
+	// Same address cannot be used without inheriting.
+	_, err = NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, false, err == nil)
+
+	done := InheritSockets()
+	ln2, err := NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+	// A socket can be inherited only once.
+	_, err = NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, false, err == nil)
+	done(false)
+
+	// Connections are accepted by the new listener
+	// after the old listener was closed.
+	ln1.Close()
+	go func() {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := ln2.Accept()
+	testutil.Diff(t, nil, err)
+	conn.Close()
+
+	// Socket is closed after all listeners were closed.
+	ln2.Close()
+	sockets.mu.Lock()
+	testutil.Diff(t, 0, len(sockets.entries))
+	sockets.mu.Unlock()
+	ln3, err := NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+	ln3.Close()
+}
+
+func TestInheritSockets_abort(t *testing.T) {
+	addr1 := freeAddr(t)
+	addr2 := freeAddr(t)
+
+	ln1, err := NewListener(&ListenConfig{Address: addr1})
+	testutil.Diff(t, nil, err)
+	defer ln1.Close()
+
+	done := InheritSockets()
+	ln2, err := NewListener(&ListenConfig{Address: addr1}) // Inherited.
+	testutil.Diff(t, nil, err)
+	ln3, err := NewListener(&ListenConfig{Address: addr2}) // Newly created.
+	testutil.Diff(t, nil, err)
+	done(true)
+
+	// Listeners created while inheriting are closed.
+	_, err = ln2.Accept()
+	testutil.Diff(t, false, err == nil)
+	_, err = ln3.Accept()
+	testutil.Diff(t, false, err == nil)
+
+	// New socket was closed and the inherited one is still available.
+	ln4, err := NewListener(&ListenConfig{Address: addr2})
+	testutil.Diff(t, nil, err)
+	ln4.Close()
+	_, err = NewListener(&ListenConfig{Address: addr1})
+	testutil.Diff(t, false, err == nil)
+}
+
+func TestInheritSockets_unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket file is not tested on windows")
+	}
+
+	sock := filepath.Join(t.TempDir(), "test.sock")
+
+	ln1, err := NewListener(&ListenConfig{Address: "unix://" + sock})
+	testutil.Diff(t, nil, err)
+
+	done := InheritSockets()
+	ln2, err := NewListener(&ListenConfig{Address: "unix://" + sock})
+	testutil.Diff(t, nil, err)
+	done(false)
+
+	// Socket file must be kept while the socket is used.
+	ln1.Close()
+	_, err = os.Stat(sock)
+	testutil.Diff(t, nil, err)
+
+	ln2.Close()
+	_, err = os.Stat(sock)
+	testutil.Diff(t, true, os.IsNotExist(err))
+}
+
+func TestListenPacket(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	addr := conn.LocalAddr().String()
+	conn.Close()
+
+	c1, err := ListenPacket("udp://" + addr)
+	testutil.Diff(t, nil, err)
+
+	done := InheritSockets()
+	c2, err := ListenPacket(addr)
+	testutil.Diff(t, nil, err)
+	done(false)
+
+	c1.Close()
+	testutil.Diff(t, addr, c2.LocalAddr().String())
+	c2.Close()
+
+	_, err = ListenPacket("tcp://" + addr)
+	testutil.Diff(t, false, err == nil)
+}
+
+func TestAddSocketFiles(t *testing.T) {
+	addr := freeAddr(t)
+
+	// Emulate the sockets passed from another process.
+	ln, err := net.Listen("tcp", addr)
+	testutil.Diff(t, nil, err)
+	f, err := ln.(*net.TCPListener).File()
+	testutil.Diff(t, nil, err)
+	ln.Close()
+
+	AddSocketFiles([]string{"tcp://" + addr}, []*os.File{f})
+	keys, files := SocketFiles()
+	testutil.Diff(t, []string{"tcp://" + addr}, keys)
+	testutil.Diff(t, true, f == files[0])
+
+	done := InheritSockets()
+	ln1, err := NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+	done(false)
+
+	go func() {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := ln1.Accept()
+	testutil.Diff(t, nil, err)
+	conn.Close()
+	ln1.Close()
+
+	keys, _ = SocketFiles()
+	testutil.Diff(t, 0, len(keys))
+}
+
+func TestAddSocketFiles_notInherited(t *testing.T) {
+	addr := freeAddr(t)
+
+	ln, err := net.Listen("tcp", addr)
+	testutil.Diff(t, nil, err)
+	f, err := ln.(*net.TCPListener).File()
+	testutil.Diff(t, nil, err)
+	ln.Close()
+
+	AddSocketFiles([]string{"tcp://" + addr}, []*os.File{f})
+	done := InheritSockets()
+	done(false)
+
+	// Sockets not inherited are closed.
+	keys, _ := SocketFiles()
+	testutil.Diff(t, 0, len(keys))
+	ln1, err := NewListener(&ListenConfig{Address: addr})
+	testutil.Diff(t, nil, err)
+	ln1.Close()
+}
+
+func TestDetachSockets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket file is not tested on windows")
+	}
+
+	sock := filepath.Join(t.TempDir(), "test.sock")
+	ln, err := NewListener(&ListenConfig{Address: "unix://" + sock})
+	testutil.Diff(t, nil, err)
+
+	DetachSockets()
+	ln.Close()
+
+	// Socket file is not removed.
+	_, err = os.Stat(sock)
+	testutil.Diff(t, nil, err)
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aileron-projects/go/znet"
+)
+
+const (
+	// systemdNetwork is the network type of addresses
+	// that refer sockets passed by systemd socket activation.
+	// Addresses are in the format of "systemd://<Name>".
+	systemdNetwork = "systemd"
+	// systemdPacketNetwork is the network type used for the keys
+	// of datagram sockets passed by systemd socket activation.
+	// This is used only internally to distinguish
+	// stream and datagram sockets that have the same name.
+	systemdPacketNetwork = "systemd-dgram"
+	// listenFDsStart is the first file descriptor number
+	// passed by systemd socket activation.
+	// See https://www.freedesktop.org/software/systemd/man/latest/sd_listen_fds.html
+	listenFDsStart = 3
+)
+
+// parseNetAddr parses the address in the format of "<Network>://<Address>".
+// In addition to the networks supported by znet.ParseNetAddr,
+// "systemd" network is parsed.
+func parseNetAddr(addr string) (network, address string) {
+	if name, found := strings.CutPrefix(addr, systemdNetwork+"://"); found {
+		return systemdNetwork, name
+	}
+	return znet.ParseNetAddr(addr)
+}
+
+// loadSystemdOnce loads systemd sockets only once.
+var loadSystemdOnce sync.Once
+
+// systemdFD is a file descriptor passed by systemd socket activation.
+type systemdFD struct {
+	fd   int
+	name string
+}
+
+// parseListenFDs parses the environmental variables of
+// systemd socket activation and returns the passed file descriptors.
+// LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES should be given as pid, fds and names.
+// Nil is returned when the sockets were not passed to the process.
+// Names that were not given are "unknown" as described in the sd_listen_fds(3).
+func parseListenFDs(pid, fds, names string, getpid int) []systemdFD {
+	if pid != "" && pid != strconv.Itoa(getpid) {
+		return nil // Sockets are passed to other process.
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n <= 0 {
+		return nil
+	}
+	var nameList []string
+	if names != "" {
+		nameList = strings.Split(names, ":")
+	}
+	result := make([]systemdFD, 0, n)
+	for i := range n {
+		name := "unknown"
+		if i < len(nameList) && nameList[i] != "" {
+			name = nameList[i]
+		}
+		result = append(result, systemdFD{fd: listenFDsStart + i, name: name})
+	}
+	return result
+}
+
+// loadSystemdSockets loads the sockets passed by systemd socket activation
+// into the socket store.
+// Environmental variables of socket activation are unset after loaded
+// so that they are not passed to child processes.
+func loadSystemdSockets() {
+	fds := parseListenFDs(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES"), os.Getpid())
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	for _, fd := range fds {
+		sockets.addSystemd(fd.name, os.NewFile(uintptr(fd.fd), fd.name))
+	}
+}
+
+// addSystemd adds the socket passed by systemd socket activation.
+// Stream sockets are stored with the key "systemd://<Name>" and
+// datagram sockets are stored with the key "systemd-dgram://<Name>".
+// When multiple sockets with the same name and type were passed,
+// the first one is used and others are closed.
+// Files that are not sockets are closed and ignored.
+func (s *socketStore) addSystemd(name string, f *os.File) {
+	var key string
+	if ln, err := net.FileListener(f); err == nil {
+		ln.Close() // Closing duplicated file does not close the socket.
+		key = socketKey(systemdNetwork, name)
+	} else if conn, err := net.FilePacketConn(f); err == nil {
+		conn.Close() // Closing duplicated file does not close the socket.
+		key = socketKey(systemdPacketNetwork, name)
+	} else {
+		f.Close()
+		return
+	}
+
+	s.mu.Lock()
+	_, exists := s.entries[key]
+	s.mu.Unlock()
+	if exists {
+		f.Close()
+		return
+	}
+	s.add(key, f)
+}
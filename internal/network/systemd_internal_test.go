@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseListenFDs(t *testing.T) {
+	type condition struct {
+		pid   string
+		fds   string
+		names string
+	}
+
+	type action struct {
+		fds []systemdFD
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"not activated",
+			&condition{},
+			&action{fds: nil},
+		),
+		gen(
+			"other pid",
+			&condition{pid: "999", fds: "1", names: "web"},
+			&action{fds: nil},
+		),
+		gen(
+			"invalid fds",
+			&condition{pid: "100", fds: "foo"},
+			&action{fds: nil},
+		),
+		gen(
+			"named fds",
+			&condition{pid: "100", fds: "2", names: "web:quic"},
+			&action{fds: []systemdFD{{fd: 3, name: "web"}, {fd: 4, name: "quic"}}},
+		),
+		gen(
+			"no pid",
+			&condition{fds: "1", names: "web"},
+			&action{fds: []systemdFD{{fd: 3, name: "web"}}},
+		),
+		gen(
+			"missing names",
+			&condition{pid: "100", fds: "3", names: "web:"},
+			&action{fds: []systemdFD{{fd: 3, name: "web"}, {fd: 4, name: "unknown"}, {fd: 5, name: "unknown"}}},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			fds := parseListenFDs(tt.C.pid, tt.C.fds, tt.C.names, 100)
+			testutil.Diff(t, len(tt.A.fds), len(fds))
+			for i := range fds {
+				testutil.Diff(t, tt.A.fds[i].fd, fds[i].fd)
+				testutil.Diff(t, tt.A.fds[i].name, fds[i].name)
+			}
+		})
+	}
+}
+
+func TestSystemdSockets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd socket activation is not available on windows")
+	}
+
+	// Emulate the sockets passed by systemd.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	lf, err := ln.(*net.TCPListener).File()
+	testutil.Diff(t, nil, err)
+	addr := ln.Addr().String()
+	ln.Close()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	testutil.Diff(t, nil, err)
+	cf, err := conn.(*net.UDPConn).File()
+	testutil.Diff(t, nil, err)
+	conn.Close()
+
+	sockets.addSystemd("test", lf)
+	sockets.addSystemd("test", cf)
+
+	ln1, err := NewListener(&ListenConfig{Address: "systemd://test"})
+	testutil.Diff(t, nil, err)
+	defer ln1.Close()
+	testutil.Diff(t, addr, ln1.Addr().String())
+
+	c1, err := ListenPacket("systemd://test")
+	testutil.Diff(t, nil, err)
+	defer c1.Close()
+	testutil.Diff(t, "udp", c1.LocalAddr().Network())
+
+	// Files that are not sockets are closed.
+	f, err := os.CreateTemp(t.TempDir(), "not-socket")
+	testutil.Diff(t, nil, err)
+	sockets.addSystemd("file", f)
+	_, err = f.Stat()
+	testutil.Diff(t, os.ErrClosed, err, cmpopts.EquateErrors())
+	_, err = NewListener(&ListenConfig{Address: "systemd://file"})
+	testutil.Diff(t, false, err == nil)
+
+	// Socket is not bound when not found.
+	_, err = NewListener(&ListenConfig{Address: "systemd://not-exist"})
+	testutil.Diff(t, false, err == nil)
+	_, err = ListenPacket("systemd://not-exist")
+	testutil.Diff(t, false, err == nil)
+}
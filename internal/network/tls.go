@@ -47,19 +47,16 @@ var tlsCipher = map[k.TLSCipher]uint16{
 // TLSConfig returns a new *tls.Config from the given spec.
 // This function returns nil config and nil error when
 // the given spec was nil.
+// Certificates, root CAs and client CAs are reloaded
+// for new handshakes when their files were modified.
 func TLSConfig(spec *k.TLSConfig) (*tls.Config, error) {
 	if spec == nil {
 		return nil, nil
 	}
 
-	rootCAs, err := certPool(spec.RootCAs)
+	reloader, err := newTLSReloader(spec)
 	if err != nil {
-		return nil, zerrors.NewErr(err, "internal/network: failed to load root CAs", "")
-	}
-
-	clientCAs, err := certPool(spec.ClientCAs)
-	if err != nil {
-		return nil, zerrors.NewErr(err, "internal/network: failed to load root CAs", "")
+		return nil, err // Return err as-is.
 	}
 
 	if spec.ClientAuth > 4 {
@@ -69,24 +66,13 @@ func TLSConfig(spec *k.TLSConfig) (*tls.Config, error) {
 	if spec.Renegotiation > 2 {
 		return nil, zerrors.NewErr(nil, "internal/network: RenegotiationSupport must be 0 to 2.", "given=%s", spec.Renegotiation.String())
 	}
-	certs := make([]tls.Certificate, 0, len(spec.CertKeyPairs))
-	for _, pair := range spec.CertKeyPairs {
-		cert, err := tls.LoadX509KeyPair(pair.CertFile, pair.KeyFile)
-		if err != nil {
-			return nil, zerrors.NewErr(err, "internal/network: failed to load cert file.", "")
-		}
-		certs = append(certs, cert)
-	}
 
-	return &tls.Config{
+	c := &tls.Config{
 		Rand:                        nil, // Use default crypto/rand
 		Time:                        nil, // Use default time.Now
-		Certificates:                certs,
-		RootCAs:                     rootCAs,
 		NextProtos:                  spec.NextProtos,
 		ServerName:                  spec.ServerName,
 		ClientAuth:                  tls.ClientAuthType(spec.ClientAuth),
-		ClientCAs:                   clientCAs,
 		InsecureSkipVerify:          spec.InsecureSkipVerify, //nolint:gosec // G402: TLS InsecureSkipVerify may be true.
 		CipherSuites:                tlsCiphers(spec.TLSCiphers),
 		SessionTicketsDisabled:      spec.SessionTicketsDisabled,
@@ -95,7 +81,9 @@ func TLSConfig(spec *k.TLSConfig) (*tls.Config, error) {
 		CurvePreferences:            curveIDs(spec.CurvePreferences),
 		DynamicRecordSizingDisabled: spec.DynamicRecordSizingDisabled,
 		Renegotiation:               tls.RenegotiationSupport(spec.Renegotiation),
-	}, nil
+	}
+	reloader.configure(c)
+	return c, nil
 }
 
 // tlsCiphers return a new slice of tls ciphers.
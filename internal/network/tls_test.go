@@ -155,6 +155,7 @@ func TestTLSConfig(t *testing.T) {
 				cmpopts.IgnoreUnexported(tls.Config{}),
 				cmpopts.IgnoreFields(tls.Config{}, "Certificates"),
 				cmpopts.IgnoreFields(tls.Config{}, "RootCAs", "ClientCAs"),
+				cmpopts.IgnoreFields(tls.Config{}, "GetConfigForClient", "GetClientCertificate"),
 			}
 			testutil.Diff(t, tt.A.config, config, opts...)
 			testutil.Diff(t, len(tt.C.spec.CertKeyPairs), len(config.Certificates)) // TODO: Check better way.
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-projects/go/zerrors"
+)
+
+// tlsReloadInterval is the minimum interval to check
+// the modification of certificate and CA files.
+const tlsReloadInterval = 10 * time.Second
+
+// fileStamp is the state of a file used to detect modification.
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// tlsFiles is the certificates and CAs loaded from files.
+type tlsFiles struct {
+	certs     []tls.Certificate
+	rootCAs   *x509.CertPool
+	clientCAs *x509.CertPool
+	// stamps is the states of the files when they were loaded.
+	stamps []fileStamp
+}
+
+// tlsReloader loads certificates and CAs from files
+// and reloads them when the files were modified.
+// Files are checked on TLS handshakes at most once in the interval
+// so that no background goroutine is required.
+// Currently loaded files are kept when failed to reload
+// and reloading is retried after the interval.
+type tlsReloader struct {
+	pairs     []*k.CertKeyPair
+	rootCAs   []string
+	clientCAs []string
+	// insecure is the InsecureSkipVerify
+	// configured in the spec.
+	insecure bool
+	// revocation checks revocation status of client certificates.
+	// Nil if not configured.
+	revocation *RevocationChecker
+	// stapler staples OCSP responses to the server certificates.
+	// Nil if not configured.
+	stapler *ocspStapler
+
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	next    time.Time
+	current atomic.Pointer[tlsFiles]
+}
+
+// newTLSReloader returns a new tlsReloader
+// with initially loaded certificates and CAs.
+func newTLSReloader(spec *k.TLSConfig) (*tlsReloader, error) {
+	r := &tlsReloader{
+		pairs:     spec.CertKeyPairs,
+		rootCAs:   spec.RootCAs,
+		clientCAs: spec.ClientCAs,
+		insecure:  spec.InsecureSkipVerify,
+		interval:  tlsReloadInterval,
+		now:       time.Now,
+	}
+	files, err := r.load()
+	if err != nil {
+		return nil, err // Return err as-is.
+	}
+	r.current.Store(files)
+	r.next = r.now().Add(r.interval)
+
+	r.revocation, err = NewRevocationChecker(spec.Revocation)
+	if err != nil {
+		return nil, err // Return err as-is.
+	}
+	if spec.OCSPStapling {
+		r.stapler = newOCSPStapler()
+		r.stapler.staple(files.certs) // Obtain responses in background.
+	}
+	return r, nil
+}
+
+// watched reports if there are any files to be watched.
+func (r *tlsReloader) watched() bool {
+	return len(r.pairs)+len(r.rootCAs)+len(r.clientCAs) > 0
+}
+
+// files returns the paths of all watched files.
+func (r *tlsReloader) files() []string {
+	files := make([]string, 0, 2*len(r.pairs)+len(r.rootCAs)+len(r.clientCAs))
+	for _, pair := range r.pairs {
+		files = append(files, pair.CertFile, pair.KeyFile)
+	}
+	files = append(files, r.rootCAs...)
+	return append(files, r.clientCAs...)
+}
+
+// stamps returns the current states of the watched files.
+// Zero stamp is used for the files that cannot be stat.
+func (r *tlsReloader) stamps() []fileStamp {
+	files := r.files()
+	stamps := make([]fileStamp, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			stamps[i] = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// load loads certificates and CAs from the files.
+func (r *tlsReloader) load() (*tlsFiles, error) {
+	// Take stamps before reading files so that
+	// the files modified while loading are reloaded next time.
+	stamps := r.stamps()
+
+	rootCAs, err := certPool(r.rootCAs)
+	if err != nil {
+		return nil, zerrors.NewErr(err, "internal/network: failed to load root CAs", "")
+	}
+
+	clientCAs, err := certPool(r.clientCAs)
+	if err != nil {
+		return nil, zerrors.NewErr(err, "internal/network: failed to load root CAs", "")
+	}
+
+	certs := make([]tls.Certificate, 0, len(r.pairs))
+	for _, pair := range r.pairs {
+		cert, err := tls.LoadX509KeyPair(pair.CertFile, pair.KeyFile)
+		if err != nil {
+			return nil, zerrors.NewErr(err, "internal/network: failed to load cert file.", "")
+		}
+		certs = append(certs, cert)
+	}
+
+	return &tlsFiles{
+		certs:     certs,
+		rootCAs:   rootCAs,
+		clientCAs: clientCAs,
+		stamps:    stamps,
+	}, nil
+}
+
+// get returns the currently loaded files.
+// Files are reloaded if they were modified after loaded.
+func (r *tlsReloader) get() *tlsFiles {
+	current := r.current.Load()
+	if !r.mu.TryLock() {
+		return current // Other handshake is checking the files.
+	}
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Before(r.next) {
+		return current
+	}
+	r.next = now.Add(r.interval)
+
+	if slices.Equal(current.stamps, r.stamps()) {
+		return current
+	}
+	files, err := r.load()
+	if err != nil {
+		return current // Files may be being rewritten. Retry next time.
+	}
+	r.current.Store(files)
+	return files
+}
+
+// configure configures the given tls config to use
+// reloaded certificates and CAs for new handshakes.
+// Existing connections are not affected by reloading.
+func (r *tlsReloader) configure(c *tls.Config) {
+	files := r.current.Load()
+	c.Certificates = files.certs
+	c.RootCAs = files.rootCAs
+	c.ClientCAs = files.clientCAs
+	if !r.watched() && r.revocation == nil {
+		return
+	}
+
+	// Server side.
+	// Given config c is captured so that the modification of
+	// the config after this function returned, for example NextProtos,
+	// is applied to the config for clients.
+	c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return r.serverConfig(c), nil
+	}
+
+	// Client side.
+	if len(r.pairs) > 0 {
+		c.GetClientCertificate = r.clientCertificate
+	}
+	if len(r.rootCAs) > 0 && !r.insecure {
+		// Server certificates are verified in the VerifyConnection
+		// with reloaded root CAs instead of the default verification.
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = r.verifyConnection
+	}
+}
+
+// serverConfig returns the config for a new client
+// cloning the base config.
+func (r *tlsReloader) serverConfig(base *tls.Config) *tls.Config {
+	files := r.get()
+	c := base.Clone()
+	c.GetConfigForClient = nil
+	c.Certificates = files.certs
+	if r.stapler != nil {
+		c.Certificates = r.stapler.staple(files.certs)
+	}
+	c.ClientCAs = files.clientCAs
+	// Client side verification is not used for servers.
+	c.InsecureSkipVerify = r.insecure
+	if len(r.rootCAs) > 0 && !r.insecure {
+		c.VerifyConnection = nil
+	}
+	if r.revocation != nil {
+		c.VerifyConnection = r.verifyClient
+	}
+	return c
+}
+
+// verifyClient checks the revocation status of the verified client certificate.
+// Client certificates that were not verified, for example,
+// when ClientAuth is RequestClientCert, are not checked.
+func (r *tlsReloader) verifyClient(cs tls.ConnectionState) error {
+	if len(cs.VerifiedChains) == 0 {
+		return nil
+	}
+	return r.revocation.Check(context.Background(), cs.VerifiedChains[0])
+}
+
+// clientCertificate returns the client certificate
+// which is acceptable for the server.
+// This works as the same as the default selection of
+// client certificates in the crypto/tls package.
+func (r *tlsReloader) clientCertificate(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certs := r.get().certs
+	for i := range certs {
+		if cri.SupportsCertificate(&certs[i]) == nil {
+			return &certs[i], nil
+		}
+	}
+	return &tls.Certificate{}, nil // No acceptable certificate. Don't send any.
+}
+
+// verifyConnection verifies the server certificates
+// with the currently loaded root CAs.
+// This works as the same as the default verification
+// in the crypto/tls package.
+func (r *tlsReloader) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("internal/network: no server certificates")
+	}
+	if cs.ServerName == "" {
+		return errors.New("internal/network: either ServerName or InsecureSkipVerify must be specified")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         r.get().rootCAs,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+)
+
+// testCA is a certificate authority used for testing.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.Diff(t, nil, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	testutil.Diff(t, nil, err)
+	cert, err := x509.ParseCertificate(der)
+	testutil.Diff(t, nil, err)
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes a new certificate and key signed by the ca to the files.
+func (ca *testCA) issue(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.Diff(t, nil, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test.com"},
+		DNSNames:     []string{"test.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	testutil.Diff(t, nil, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	testutil.Diff(t, nil, err)
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	testutil.Diff(t, nil, err)
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	testutil.Diff(t, nil, err)
+}
+
+// touch changes the modification time of the file
+// to make sure that the modification is detected.
+func touch(t *testing.T, file string, d time.Duration) {
+	t.Helper()
+	mt := time.Now().Add(d)
+	testutil.Diff(t, nil, os.Chtimes(file, mt, mt))
+}
+
+// handshake runs TLS handshake between the server and the client.
+// Leaf certificates presented by the server and the client are returned.
+func handshake(server, client *tls.Config) (*x509.Certificate, *x509.Certificate, error) {
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+	s := tls.Server(sc, server)
+	c := tls.Client(cc, client)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Handshake()
+		sc.Close() // Unblock the client when the server failed.
+	}()
+	err := c.Handshake()
+	if err == nil {
+		// In TLS 1.3, the server verifies client certificates after the client
+		// finished the handshake. Read alerts sent by the server.
+		go c.Read(make([]byte, 1))
+	}
+	if serr := <-errCh; err == nil {
+		err = serr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var serverPeer *x509.Certificate
+	if certs := s.ConnectionState().PeerCertificates; len(certs) > 0 {
+		serverPeer = certs[0]
+	}
+	return c.ConnectionState().PeerCertificates[0], serverPeer, nil
+}
+
+func TestTLSReloader_get(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "test.crt")
+	keyFile := filepath.Join(dir, "test.key")
+	ca := newTestCA(t, "ca")
+	ca.issue(t, certFile, keyFile)
+
+	r, err := newTLSReloader(&k.TLSConfig{
+		CertKeyPairs: []*k.CertKeyPair{{CertFile: certFile, KeyFile: keyFile}},
+	})
+	testutil.Diff(t, nil, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.next = now.Add(r.interval)
+	first := r.get()
+
+	// Files are not checked within the interval.
+	ca.issue(t, certFile, keyFile)
+	touch(t, certFile, time.Minute)
+	testutil.Diff(t, true, first == r.get())
+
+	// Modified files are reloaded after the interval.
+	now = now.Add(r.interval)
+	second := r.get()
+	testutil.Diff(t, false, first == second)
+	testutil.Diff(t, false, string(first.certs[0].Certificate[0]) == string(second.certs[0].Certificate[0]))
+
+	// Not modified files are not reloaded.
+	now = now.Add(r.interval)
+	testutil.Diff(t, true, second == r.get())
+
+	// Current files are kept when failed to reload.
+	testutil.Diff(t, nil, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	touch(t, keyFile, 2*time.Minute)
+	now = now.Add(r.interval)
+	testutil.Diff(t, true, second == r.get())
+}
+
+func TestTLSReloader_handshake(t *testing.T) {
+	dir := t.TempDir()
+	serverCert := filepath.Join(dir, "server.crt")
+	serverKey := filepath.Join(dir, "server.key")
+	clientCert := filepath.Join(dir, "client.crt")
+	clientKey := filepath.Join(dir, "client.key")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca1 := newTestCA(t, "ca1")
+	ca1.issue(t, serverCert, serverKey)
+	ca1.issue(t, clientCert, clientKey)
+	testutil.Diff(t, nil, os.WriteFile(caFile, ca1.pem, 0o600))
+
+	sr, err := newTLSReloader(&k.TLSConfig{
+		CertKeyPairs: []*k.CertKeyPair{{CertFile: serverCert, KeyFile: serverKey}},
+		ClientCAs:    []string{caFile},
+	})
+	testutil.Diff(t, nil, err)
+	server := &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}
+	sr.configure(server)
+
+	cr, err := newTLSReloader(&k.TLSConfig{
+		CertKeyPairs: []*k.CertKeyPair{{CertFile: clientCert, KeyFile: clientKey}},
+		RootCAs:      []string{caFile},
+	})
+	testutil.Diff(t, nil, err)
+	client := &tls.Config{ServerName: "test.com"}
+	cr.configure(client)
+	testutil.Diff(t, true, client.InsecureSkipVerify)
+
+	s1, c1, err := handshake(server, client)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "ca1", s1.Issuer.CommonName)
+	testutil.Diff(t, "ca1", c1.Issuer.CommonName)
+
+	// Rotate all certificates and the CA.
+	ca2 := newTestCA(t, "ca2")
+	ca2.issue(t, serverCert, serverKey)
+	ca2.issue(t, clientCert, clientKey)
+	testutil.Diff(t, nil, os.WriteFile(caFile, ca2.pem, 0o600))
+	for _, f := range []string{serverCert, serverKey, clientCert, clientKey, caFile} {
+		touch(t, f, time.Minute)
+	}
+
+	// Old files are used within the interval.
+	s2, c2, err := handshake(server, client)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "ca1", s2.Issuer.CommonName)
+	testutil.Diff(t, "ca1", c2.Issuer.CommonName)
+
+	// New files are used after the interval.
+	// Emulate elapsed time by resetting the next check time.
+	resetNext := func(r *tlsReloader) {
+		r.mu.Lock()
+		r.next = time.Time{}
+		r.mu.Unlock()
+	}
+	resetNext(sr)
+	resetNext(cr)
+	s3, c3, err := handshake(server, client)
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "ca2", s3.Issuer.CommonName)
+	testutil.Diff(t, "ca2", c3.Issuer.CommonName)
+
+	// Server certificate signed by an untrusted CA is rejected.
+	ca3 := newTestCA(t, "ca3")
+	ca3.issue(t, serverCert, serverKey)
+	touch(t, serverCert, 2*time.Minute)
+	resetNext(sr)
+	_, _, err = handshake(server, client)
+	testutil.Diff(t, false, err == nil)
+}
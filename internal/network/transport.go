@@ -86,6 +86,12 @@ func HTTPTransport(spec *k.HTTPTransportConfig) (*http.Transport, error) {
 		}
 		transport.DialContext = dialer.DialContext
 		transport.DialTLSContext = dialer.DialContext
+		if spec.DialConfig.ProxyProtocol {
+			// PROXY protocol header is sent only once per connection.
+			// Connections must not be reused for requests of other clients
+			// because the header has the addresses of the client that triggered the dialing.
+			transport.DisableKeepAlives = true
+		}
 	}
 
 	return transport, nil
@@ -135,6 +141,10 @@ func HTTP2Transport(spec *k.HTTP2TransportConfig) (*http2.Transport, error) {
 		spec.DialConfig = &k.DialConfig{}
 	}
 	if spec.DialConfig != nil {
+		if spec.DialConfig.ProxyProtocol {
+			// HTTP/2 connections are shared by the requests of different clients.
+			return nil, zerrors.NewErr(nil, "internal/network: failed to create a new HTTP2 transport.", "PROXY protocol is not available for HTTP/2.")
+		}
 		tc := spec.DialConfig.TLSConfig
 		tc = cmp.Or(tc, spec.TLSConfig)
 		if tc != nil && !slices.Contains(tc.NextProtos, http2.NextProtoTLS) {
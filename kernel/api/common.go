@@ -56,6 +56,8 @@ const (
 	MethodDelete Method = "DELETE" // Delete operation for APIs.
 	MethodGet    Method = "GET"    // Get operation for APIs.
 	MethodPost   Method = "POST"   // Post operation for APIs.
+	MethodList   Method = "LIST"   // List operation for APIs.
+	MethodPut    Method = "PUT"    // Put operation for APIs.
 )
 
 // Format is the type of data format that
@@ -67,6 +69,8 @@ const (
 	FormatYAML           Format = "YAML"           // YAML []byte
 	FormatProtoMessage   Format = "ProtoMessage"   // proto.Message
 	FormatProtoReference Format = "ProtoReference" // proto.Message only for kernel.Reference
+	FormatJSONSchema     Format = "JSONSchema"     // JSON Schema in JSON []byte. Only for the accept format.
+	FormatGoTypes        Format = "GoTypes"        // []reflect.Type of the objects. Only for the accept format.
 )
 
 // Unmarshal un-marshals the in to into with this format.
@@ -102,6 +106,22 @@ type Response struct {
 	Content any
 }
 
+// Manifest is an entry of the list returned by the List operation.
+// The Content of the response of the List operation is []*Manifest
+// which is sorted by the ID.
+type Manifest struct {
+	// ID is the ID of the manifest in the format of
+	// "APIGroup/APIVersion/Kind/Namespace/Name".
+	ID string
+	// Message is the stored manifest.
+	// Callers must not modify the message.
+	// Nil if the API does not store manifests.
+	Message proto.Message
+	// Object is the object created from the manifest.
+	// Nil if the object has not been created yet.
+	Object any
+}
+
 // NewDefaultServeMux returns a new instance of DefaultServeMux
 // which is the multiplexer for api.API[*api.Request, *api.Response].
 func NewDefaultServeMux() *DefaultServeMux {
@@ -127,6 +147,10 @@ func (m *DefaultServeMux) Serve(ctx context.Context, req *Request) (*Response, e
 		return nil, zerrors.NewErr(nil, "kernel/api: request is nil", "")
 	}
 
+	if req.Method == MethodList {
+		return m.list(ctx, req)
+	}
+
 	// Find API route with prefix matching.
 	// Note that the keys are sorted descending order.
 	for _, k := range m.keys {
@@ -138,6 +162,36 @@ func (m *DefaultServeMux) Serve(ctx context.Context, req *Request) (*Response, e
 	return nil, zerrors.NewErr(nil, "kernel/api: api is not registered.", "key=%s", req.Key)
 }
 
+// list returns the manifests of all APIs which may have IDs with the prefix req.Key.
+// Manifests returned by multiple APIs are de-duplicated by their IDs.
+func (m *DefaultServeMux) list(ctx context.Context, req *Request) (*Response, error) {
+	found := map[string]*Manifest{}
+	for _, k := range m.keys {
+		if !strings.HasPrefix(req.Key, k) && !strings.HasPrefix(k, req.Key) {
+			continue
+		}
+		res, err := m.apis[k].Serve(ctx, req)
+		if err != nil {
+			return nil, err // Return err as-is.
+		}
+		ms, _ := res.Content.([]*Manifest)
+		for _, mf := range ms {
+			found[mf.ID] = mf
+		}
+	}
+	return &Response{Content: sortManifests(found)}, nil
+}
+
+// sortManifests returns the manifests sorted by their IDs.
+func sortManifests(found map[string]*Manifest) []*Manifest {
+	ms := make([]*Manifest, 0, len(found))
+	for _, mf := range found {
+		ms = append(ms, mf)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].ID < ms[j].ID })
+	return ms
+}
+
 func (m *DefaultServeMux) Handle(key string, a API[*Request, *Response]) error {
 	if a == nil {
 		return nil // Ignore nil API.
@@ -360,3 +360,83 @@ func TestDefaultServeMux_Handle(t *testing.T) {
 		})
 	}
 }
+
+type manifestsAPI struct {
+	ids []string
+}
+
+func (a *manifestsAPI) Serve(_ context.Context, req *Request) (*Response, error) {
+	ms := []*Manifest{}
+	for _, id := range a.ids {
+		if strings.HasPrefix(id, req.Key) {
+			ms = append(ms, &Manifest{ID: id})
+		}
+	}
+	return &Response{Content: ms}, nil
+}
+
+func TestDefaultServeMux_list(t *testing.T) {
+	type condition struct {
+		key     string
+		failing bool
+	}
+
+	type action struct {
+		ids []string
+		err error
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"list all",
+			&condition{
+				key: "",
+			},
+			&action{
+				ids: []string{"app/v1/Bar/ns/b", "core/v1/Foo/ns/a"},
+			},
+		),
+		gen(
+			"key longer than the route",
+			&condition{
+				key: "core/v1/Foo",
+			},
+			&action{
+				ids: []string{"core/v1/Foo/ns/a"},
+			},
+		),
+		gen(
+			"api error",
+			&condition{
+				key:     "",
+				failing: true,
+			},
+			&action{
+				err: &zerrors.Err{Message: "test"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			m := NewDefaultServeMux()
+			m.Handle("core/", &manifestsAPI{ids: []string{"core/v1/Foo/ns/a"}})
+			m.Handle("app/", &manifestsAPI{ids: []string{"app/v1/Bar/ns/b", "core/v1/Foo/ns/a"}}) // Duplicated ID.
+			if tt.C.failing {
+				m.Handle("error/", &stringResponderAPI{err: &zerrors.Err{Message: "test"}})
+			}
+
+			res, err := m.Serve(context.Background(), &Request{Method: MethodList, Key: tt.C.key})
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			if err != nil {
+				return
+			}
+			ids := []string{}
+			for _, mf := range res.Content.([]*Manifest) {
+				ids = append(ids, mf.ID)
+			}
+			testutil.Diff(t, tt.A.ids, ids)
+		})
+	}
+}
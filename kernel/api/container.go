@@ -5,6 +5,8 @@ package api
 
 import (
 	"context"
+	"strings"
+	"sync"
 
 	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-projects/go/zerrors"
@@ -21,11 +23,15 @@ func NewContainerAPI() *ContainerAPI {
 // This implements api.API[*api.Request, *api.Response] interface.
 // Use api.NewContainerAPI() to obtain an instance of this struct.
 // Otherwise the instance will not be initialized properly.
+// ContainerAPI is safe for concurrent use.
 // Allowed operations are described below and see the examples for usages.
 //   - Post: Store objects inside the container.
+//   - Put: Store objects inside the container replacing the existing ones.
 //   - Get: Get the stored object from the container. Nil content will be returned when no object was found.
 //   - Delete: Delete objects from the container.
+//   - List: List stored objects which have IDs with the prefix of the request key.
 type ContainerAPI struct {
+	mu sync.RWMutex
 	// objStore stores objects given by clients.
 	// Typically, the key will be IDs in the format of "APIGroup/APIVersion/Kind/Namespace/Name".
 	objStore map[string]any
@@ -37,6 +43,19 @@ func (a *ContainerAPI) Serve(ctx context.Context, req *Request) (*Response, erro
 		return nil, zerrors.NewErr(nil, "kernel/api: request is nil.", "")
 	}
 
+	if req.Method == MethodList {
+		printDebug(debugLv2, "ContainerAPI:", "LIST:", "key="+req.Key)
+		a.mu.RLock()
+		defer a.mu.RUnlock()
+		found := map[string]*Manifest{}
+		for id, obj := range a.objStore {
+			if strings.HasPrefix(id, req.Key) {
+				found[id] = &Manifest{ID: id, Object: obj}
+			}
+		}
+		return &Response{Content: sortManifests(found)}, nil
+	}
+
 	id := req.Key
 	msg, err := ProtoMessage(req.Format, req.Content, &k.Reference{}, nil)
 	if err != nil {
@@ -52,19 +71,31 @@ func (a *ContainerAPI) Serve(ctx context.Context, req *Request) (*Response, erro
 	switch req.Method {
 	case MethodDelete: // Delete deletes stored object from this container.
 		printDebug(debugLv2, "ContainerAPI:", "DELETE:", "key="+id)
+		a.mu.Lock()
 		delete(a.objStore, id)
+		a.mu.Unlock()
 
 	case MethodGet: // Get returns stored object. Nil will be returned if no object found.
 		printDebug(debugLv2, "ContainerAPI:", "GET:", "key="+id)
+		a.mu.RLock()
 		content = a.objStore[id]
+		a.mu.RUnlock()
 
 	case MethodPost: // Post stores the given object in this container.
 		printDebug(debugLv2, "ContainerAPI:", "POST:", "key="+id)
+		a.mu.Lock()
+		defer a.mu.Unlock()
 		if _, ok := a.objStore[id]; ok {
 			return nil, zerrors.NewErr(nil, "kernel/api: key duplication error.", "key=%s", req.Key)
 		}
 		a.objStore[id] = req.Content
 
+	case MethodPut: // Put stores the given object in this container replacing the existing one.
+		printDebug(debugLv2, "ContainerAPI:", "PUT:", "key="+id)
+		a.mu.Lock()
+		a.objStore[id] = req.Content
+		a.mu.Unlock()
+
 	default:
 		printDebug(debugLv2, "ContainerAPI:", "UNDEFINED:", req.Method, "key="+id)
 		return nil, zerrors.NewErr(nil, "kernel/api: method not implemented.", "%s", string(req.Method))
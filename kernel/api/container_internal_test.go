@@ -4,10 +4,13 @@
 package api
 
 import (
+	"context"
+	"sync"
 	"testing"
 
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestNewContainerAPI(t *testing.T) {
@@ -35,7 +38,40 @@ func TestNewContainerAPI(t *testing.T) {
 		tt := tt
 		t.Run(tt.Name, func(t *testing.T) {
 			a := NewContainerAPI()
-			testutil.Diff(t, tt.A.a, a, cmp.AllowUnexported(ContainerAPI{}))
+			testutil.Diff(t, tt.A.a, a, cmp.AllowUnexported(ContainerAPI{}), cmpopts.IgnoreTypes(sync.RWMutex{}))
 		})
 	}
 }
+
+func TestContainerAPI_list(t *testing.T) {
+	a := &ContainerAPI{
+		objStore: map[string]any{
+			"container/v1/Foo/ns/a": "a",
+			"container/v1/Foo/ns/b": "b",
+			"container/v1/Bar/ns/c": "c",
+		},
+	}
+
+	res, err := a.Serve(context.Background(), &Request{Method: MethodList, Key: "container/v1/Foo"})
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, []*Manifest{
+		{ID: "container/v1/Foo/ns/a", Object: "a"},
+		{ID: "container/v1/Foo/ns/b", Object: "b"},
+	}, res.Content)
+}
+
+func TestContainerAPI_put(t *testing.T) {
+	a := NewContainerAPI()
+	ctx := context.Background()
+
+	_, err := a.Serve(ctx, &Request{Method: MethodPut, Key: "container/v1/Foo/ns/a", Content: "a1"})
+	testutil.Diff(t, nil, err)
+	_, err = a.Serve(ctx, &Request{Method: MethodPost, Key: "container/v1/Foo/ns/a", Content: "a2"})
+	testutil.Diff(t, true, err != nil) // Post does not replace.
+	_, err = a.Serve(ctx, &Request{Method: MethodPut, Key: "container/v1/Foo/ns/a", Content: "a3"})
+	testutil.Diff(t, nil, err)
+
+	res, err := a.Serve(ctx, &Request{Method: MethodGet, Key: "container/v1/Foo/ns/a"})
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "a3", res.Content)
+}
@@ -5,10 +5,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
 	"buf.build/go/protovalidate"
 	"github.com/aileron-gateway/aileron-gateway/internal/encoder"
@@ -44,6 +48,28 @@ type Resource interface {
 	Delete(API[*Request, *Response], proto.Message, any) error
 }
 
+// Updater is the optional interface of resources.
+// Resources that implement this interface update the created objects
+// in place instead of being re-created when their manifests or the
+// resources they refer were replaced by the Put method of the FactoryAPI.
+// Implement this interface for objects that cannot be re-created while running
+// such as servers that hold listeners.
+type Updater interface {
+	// Update applies the given ProtoMessage to the created object.
+	// Given ProtoMessage has the same type as the one returned by the Default().
+	// Referred objects obtained through the API are the re-created ones.
+	Update(API[*Request, *Response], proto.Message, any) error
+}
+
+// Typer is the optional interface of resources.
+// Resources that implement this interface tell the types of the objects
+// that the Create method returns without creating any objects.
+type Typer interface {
+	// Types returns the types of the objects that the Create method can return.
+	// Nil is returned when the types are unknown.
+	Types() []reflect.Type
+}
+
 // BaseResource is the base struct for api.Resource interface.
 // Embed this struct to avoid unnecessary method implementation
 // to satisfy api.Resource interface.
@@ -51,6 +77,14 @@ type Resource interface {
 // required by all resource implementations.
 type BaseResource struct {
 	DefaultProto proto.Message
+	// ObjectTypes is the types of the objects that the Create method can return.
+	// List all types when the type of the object depends on the spec.
+	// Nil means that the types are unknown.
+	ObjectTypes []reflect.Type
+}
+
+func (b *BaseResource) Types() []reflect.Type {
+	return b.ObjectTypes
 }
 
 func (b *BaseResource) Default() proto.Message {
@@ -60,7 +94,7 @@ func (b *BaseResource) Default() proto.Message {
 func (b *BaseResource) Validate(msg proto.Message) error {
 	v, _ := protovalidate.New()
 	if err := v.Validate(msg); err != nil {
-		json, _ := encoder.MarshalProtoToJSON(msg, &protojson.MarshalOptions{Multiline: true, Indent: "  ", AllowPartial: true})
+		json, _ := encoder.MarshalProtoToJSON(Redact(msg), &protojson.MarshalOptions{Multiline: true, Indent: "  ", AllowPartial: true})
 		return zerrors.NewErr(nil, "kernel/api: validating proto message failed.", "%s%s", reflect.TypeOf(msg).String(), string(addLineNumber(json)))
 	}
 	return nil
@@ -98,7 +132,20 @@ func NewFactoryAPI() *FactoryAPI {
 // This implements api.API[*api.Request, *api.Response] interface.
 // Use api.NewFactoryAPI() to obtain an instance of this struct.
 // Otherwise the instance will not be initialized properly.
+// FactoryAPI is safe for concurrent use.
+// Following methods are allowed.
+//   - Post: Store a new manifest.
+//   - Put: Store or replace a manifest. Objects which depend on the replaced manifest are re-created.
+//   - Get: Get the object created from the stored manifest, the manifest itself, the JSON Schema of the manifest
+//     or the types of the objects.
+//     Values of the sensitive fields are redacted when the manifest is returned in JSON or YAML.
+//   - Delete: Delete a manifest and its object. Manifests referred from others cannot be deleted.
+//   - List: List stored manifests which have IDs with the prefix of the request key.
 type FactoryAPI struct {
+	// mu protects protoStore and objStore.
+	// Objects are created without holding the lock
+	// because resources refer other objects through the API while creation.
+	mu sync.Mutex
 	// protoStore stores proto messages.
 	// The key will be IDs in the format of "APIGroup/APIVersion/Kind/Namespace/Name".
 	protoStore map[string]proto.Message
@@ -134,6 +181,14 @@ func (a *FactoryAPI) Serve(ctx context.Context, req *Request) (*Response, error)
 		return nil, zerrors.NewErr(nil, "kernel/api: request is nil.", "")
 	}
 
+	if req.Method == MethodList {
+		printDebug(debugLv2, "FactoryAPI:", "LIST:", "key="+req.Key)
+		return &Response{
+			Params:  map[string]string{},
+			Content: a.list(req.Key),
+		}, nil
+	}
+
 	r, ok := a.resources[req.Key]
 	if !ok {
 		return nil, zerrors.NewErr(nil, "kernel/api: api is not registered.", "key=%s", req.Key)
@@ -161,6 +216,12 @@ func (a *FactoryAPI) Serve(ctx context.Context, req *Request) (*Response, error)
 			return nil, err // Return err as-is.
 		}
 
+	case MethodPut:
+		printDebug(debugLv2, "FactoryAPI:", "PUT:", "key="+req.Key)
+		if err := a.put(ctx, req, r); err != nil {
+			return nil, err // Return err as-is.
+		}
+
 	default:
 		printDebug(debugLv2, "FactoryAPI:", "UNDEFINED:", req.Method, "key="+req.Key)
 		return nil, zerrors.NewErr(nil, "kernel/api: method not implemented.", "%s", string(req.Method))
@@ -172,6 +233,20 @@ func (a *FactoryAPI) Serve(ctx context.Context, req *Request) (*Response, error)
 	}, nil
 }
 
+// list returns the stored manifests which have IDs with the given prefix.
+// Objects are not created by listing.
+func (a *FactoryAPI) list(prefix string) []*Manifest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	found := map[string]*Manifest{}
+	for id, msg := range a.protoStore {
+		if strings.HasPrefix(id, prefix) {
+			found[id] = &Manifest{ID: id, Message: msg, Object: a.objStore[id]}
+		}
+	}
+	return sortManifests(found)
+}
+
 func (a *FactoryAPI) delete(ctx context.Context, req *Request, r Resource) error {
 	msg, err := ProtoMessage(req.Format, req.Content, r.Default(), nil)
 	if err != nil {
@@ -182,16 +257,36 @@ func (a *FactoryAPI) delete(ctx context.Context, req *Request, r Resource) error
 		return err // Return err as-is.
 	}
 
+	a.mu.Lock()
+	if dependents := a.dependents(id); len(dependents) > 0 {
+		a.mu.Unlock()
+		return zerrors.NewErr(nil, "kernel/api: manifest is referred from others.", "key=%s referred from %v", id, dependents)
+	}
 	p := a.protoStore[id]
-	o := a.objStore[id]
+	o, created := a.objStore[id]
+	delete(a.objStore, id) // Detach the object not to be used while deleting.
+	a.mu.Unlock()
 
 	root := RootAPIFromContext(ctx)
 	if err := r.Delete(root, p, o); err != nil {
+		// Keep the manifest and the object as they were.
+		a.mu.Lock()
+		if _, ok := a.objStore[id]; !ok && created {
+			a.objStore[id] = o
+		}
+		a.mu.Unlock()
 		return err // Return err as-is.
 	}
 
+	a.mu.Lock()
+	n, recreated := a.objStore[id]
 	delete(a.protoStore, id)
 	delete(a.objStore, id)
+	a.mu.Unlock()
+	if recreated {
+		// The object was created concurrently while deleting.
+		return r.Delete(root, p, n)
+	}
 	return nil
 }
 
@@ -205,10 +300,14 @@ func (a *FactoryAPI) get(ctx context.Context, req *Request, r Resource) (any, er
 		return nil, err // Return err as-is.
 	}
 
+	a.mu.Lock()
+	p, ok := a.protoStore[id]
+	obj, created := a.objStore[id]
+	a.mu.Unlock()
+
 	if strings.HasSuffix(id, "/template/template") {
 		msg = r.Mutate(r.Default())
 	} else {
-		p, ok := a.protoStore[id]
 		if !ok {
 			return nil, zerrors.NewErr(nil, "kernel/api: manifest not found.", "key=%s", id)
 		}
@@ -226,23 +325,40 @@ func (a *FactoryAPI) get(ctx context.Context, req *Request, r Resource) (any, er
 
 	switch Format(req.Params[KeyAccept]) {
 	case FormatJSON:
-		return encoder.MarshalProtoToJSON(msg, opt)
+		return encoder.MarshalProtoToJSON(Redact(msg), opt)
 	case FormatYAML:
-		return encoder.MarshalProtoToYAML(msg, opt)
+		return encoder.MarshalProtoToYAML(Redact(msg), opt)
 	case FormatProtoMessage:
 		return proto.Clone(msg), nil
+	case FormatJSONSchema:
+		return json.MarshalIndent(JSONSchema(req.Key, msg), "", "  ")
+	case FormatGoTypes:
+		var types []reflect.Type
+		if t, ok := r.(Typer); ok {
+			types = t.Types()
+		}
+		return types, nil
 	default:
-		if v, ok := a.objStore[id]; ok {
-			return v, nil
+		if created {
+			return obj, nil
 		}
 		root := RootAPIFromContext(ctx)
-		configJSON, _ := encoder.MarshalProtoToJSON(msg, nil)
+		configJSON, _ := encoder.MarshalProtoToJSON(Redact(msg), nil)
 		printDebug(debugLv1, "FactoryAPI:", "GET:", "Create Resource:", "key="+id, string(configJSON))
 		content, err := r.Create(root, msg)
 		if err != nil {
 			return nil, err // Return err as-is.
 		}
+		a.mu.Lock()
+		if v, ok := a.objStore[id]; ok {
+			// The object was created concurrently.
+			// Discard the one created here.
+			a.mu.Unlock()
+			_ = r.Delete(root, msg, content)
+			return v, nil
+		}
 		a.objStore[id] = content
+		a.mu.Unlock()
 		return content, nil
 	}
 }
@@ -253,11 +369,11 @@ func (a *FactoryAPI) post(_ context.Context, req *Request, r Resource) error {
 		return err // Return err as-is.
 	}
 
-	configJSON, _ := encoder.MarshalProtoToJSON(msg, nil)
+	configJSON, _ := encoder.MarshalProtoToJSON(Redact(msg), nil)
 	printDebug(debugLv2, "FactoryAPI:", "POST:", "Mutate Resource:", string(configJSON))
 	msg = r.Mutate(msg)
 
-	configJSON, _ = encoder.MarshalProtoToJSON(msg, nil)
+	configJSON, _ = encoder.MarshalProtoToJSON(Redact(msg), nil)
 	printDebug(debugLv2, "FactoryAPI:", "POST:", "Validate Resource:", string(configJSON))
 	if err := r.Validate(msg); err != nil {
 		return err // Return err as-is.
@@ -268,6 +384,8 @@ func (a *FactoryAPI) post(_ context.Context, req *Request, r Resource) error {
 		return err // Return err as-is.
 	}
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	if _, ok := a.protoStore[id]; ok {
 		return zerrors.NewErr(nil, "kernel/api: key duplication error.", "key=%s", req.Key)
 	}
@@ -276,3 +394,159 @@ func (a *FactoryAPI) post(_ context.Context, req *Request, r Resource) error {
 	a.protoStore[id] = msg
 	return nil
 }
+
+// put stores the manifest or replaces the stored one.
+// When replaced, the created object and the objects
+// which depend on it are re-created.
+// Objects of resources that implement the Updater interface
+// are updated in place instead of being re-created.
+// The replaced object is created with the new manifest before updating
+// and the dependents are re-created when they are referred.
+// Old objects are deleted only after all updates succeeded.
+// The old manifest and objects are restored when failed to create or update.
+func (a *FactoryAPI) put(ctx context.Context, req *Request, r Resource) error {
+	msg, err := ProtoMessage(req.Format, req.Content, r.Default(), nil)
+	if err != nil {
+		return err // Return err as-is.
+	}
+	msg = r.Mutate(msg)
+	if err := r.Validate(msg); err != nil {
+		return err // Return err as-is.
+	}
+	id, err := ParseID(msg)
+	if err != nil {
+		return err // Return err as-is.
+	}
+
+	var deletes, updates []*putTarget
+
+	a.mu.Lock()
+	old, existed := a.protoStore[id]
+	a.protoStore[id] = msg
+
+	// Detach objects from the replaced one to its dependents
+	// so that they are re-created with the new manifest.
+	// Propagation stops at the objects that are updated in place
+	// because the objects referring them are not changed.
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		obj, ok := a.objStore[cur]
+		if !ok {
+			continue // Not created yet. No one holds the object.
+		}
+		t := &putTarget{id: cur, r: a.resourceOf(cur), msg: a.protoStore[cur], obj: obj}
+		if t.r == nil {
+			continue
+		}
+		if _, ok := t.r.(Updater); ok {
+			updates = append(updates, t)
+			continue
+		}
+		if cur == id {
+			t.msg = old // Delete with the manifest used for creation.
+		}
+		deletes = append(deletes, t)
+		delete(a.objStore, cur)
+		for _, dep := range a.dependents(cur) {
+			if !visited[dep] {
+				visited[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	a.mu.Unlock()
+
+	root := RootAPIFromContext(ctx)
+	var updated []*putTarget
+	rollback := func() {
+		a.mu.Lock()
+		var created []*putTarget // Objects created with the new manifest.
+		for _, t := range deletes {
+			if obj, ok := a.objStore[t.id]; ok {
+				created = append(created, &putTarget{id: t.id, r: t.r, msg: a.protoStore[t.id], obj: obj})
+			}
+			a.objStore[t.id] = t.obj
+		}
+		if existed {
+			a.protoStore[id] = old
+		} else {
+			delete(a.protoStore, id)
+		}
+		a.mu.Unlock()
+		for _, t := range created {
+			printDebug(debugLv1, "FactoryAPI:", "PUT:", "Rollback Resource:", "key="+t.id)
+			_ = t.r.Delete(root, t.msg, t.obj)
+		}
+		for _, t := range updated {
+			printDebug(debugLv1, "FactoryAPI:", "PUT:", "Rollback Resource:", "key="+t.id)
+			m := t.msg
+			if t.id == id {
+				m = old
+			}
+			_ = t.r.(Updater).Update(root, m, t.obj)
+		}
+	}
+
+	if len(deletes) > 0 && deletes[0].id == id {
+		// Create the replaced object eagerly
+		// so that invalid manifests are rejected here.
+		printDebug(debugLv1, "FactoryAPI:", "PUT:", "Create Resource:", "key="+id)
+		if _, err := a.get(ctx, &Request{Format: FormatProtoMessage, Content: msg}, r); err != nil {
+			rollback()
+			return err // Return err as-is.
+		}
+	}
+	for _, t := range updates {
+		printDebug(debugLv1, "FactoryAPI:", "PUT:", "Update Resource:", "key="+t.id)
+		if err := t.r.(Updater).Update(root, t.msg, t.obj); err != nil {
+			rollback()
+			return err // Return err as-is.
+		}
+		updated = append(updated, t)
+	}
+
+	// Old objects are not used anymore.
+	var deleteErr error
+	for _, t := range deletes {
+		printDebug(debugLv1, "FactoryAPI:", "PUT:", "Delete Resource:", "key="+t.id)
+		if err := t.r.Delete(root, t.msg, t.obj); err != nil && deleteErr == nil {
+			deleteErr = err
+		}
+	}
+	return deleteErr
+}
+
+// putTarget is the object affected by the Put method.
+type putTarget struct {
+	id  string
+	r   Resource
+	msg proto.Message
+	obj any
+}
+
+// resourceOf returns the resource registered for the given ID.
+// Nil is returned when not found.
+func (a *FactoryAPI) resourceOf(id string) Resource {
+	parts := strings.Split(id, "/")
+	if len(parts) < 3 {
+		return nil
+	}
+	return a.resources[strings.Join(parts[:3], "/")]
+}
+
+// dependents returns the IDs of the manifests that refer the given ID.
+// Returned IDs are sorted.
+// a.mu must be held by the caller.
+func (a *FactoryAPI) dependents(id string) []string {
+	var ids []string
+	for key, msg := range a.protoStore {
+		if key != id && slices.Contains(ReferencedIDs(msg), id) {
+			ids = append(ids, key)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
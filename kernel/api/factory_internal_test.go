@@ -5,14 +5,20 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sync"
 	"testing"
 
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
 	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
 	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
 	"github.com/aileron-projects/go/zerrors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestNewFactoryAPI(t *testing.T) {
@@ -42,7 +48,7 @@ func TestNewFactoryAPI(t *testing.T) {
 		tt := tt
 		t.Run(tt.Name, func(t *testing.T) {
 			a := NewFactoryAPI()
-			testutil.Diff(t, tt.A.a, a, cmp.AllowUnexported(FactoryAPI{}))
+			testutil.Diff(t, tt.A.a, a, cmp.AllowUnexported(FactoryAPI{}), cmpopts.IgnoreTypes(sync.Mutex{}))
 		})
 	}
 }
@@ -236,6 +242,27 @@ func TestFactoryAPI_delete(t *testing.T) {
 				err:        &zerrors.Err{Message: "kernel/api: type assertion failed."},
 			},
 		),
+		gen(
+			"Delete fails keeps manifest",
+			&condition{
+				a: &FactoryAPI{
+					protoStore: map[string]proto.Message{"test1/test2/test3/test4": &k.Reference{}},
+					objStore:   map[string]any{"test1/test2/test3/test4": "test3 test4"},
+				},
+				resource: &testResource{err: &zerrors.Err{Message: "kernel/api: type assertion failed."}}, // Use APIError for dummy.
+				req: &Request{
+					Method:  MethodDelete,
+					Key:     "test1/test2",
+					Format:  FormatJSON,
+					Content: []byte(`{"apiVersion":"test1", "kind":"test2", "metadata": {"namespace":"test3", "name":"test4"}}`),
+				},
+			},
+			&action{
+				protoStore: map[string]proto.Message{"test1/test2/test3/test4": &k.Reference{}},
+				objStore:   map[string]any{"test1/test2/test3/test4": "test3 test4"},
+				err:        &zerrors.Err{Message: "kernel/api: type assertion failed."},
+			},
+		),
 		gen(
 			"Invalid content type",
 			&condition{
@@ -281,7 +308,7 @@ func TestFactoryAPI_delete(t *testing.T) {
 
 			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
 			testutil.Diff(t, tt.A.objStore, a.objStore)
-			testutil.Diff(t, tt.A.protoStore, a.protoStore)
+			testutil.Diff(t, tt.A.protoStore, a.protoStore, protocmp.Transform())
 		})
 	}
 }
@@ -647,6 +674,26 @@ func TestFactoryAPI_get(t *testing.T) {
 				obj: &k.Resource{},
 			},
 		),
+		gen(
+			"accept as JSON Schema",
+			&condition{
+				a:        &FactoryAPI{},
+				resource: &testResource{},
+				req: &Request{
+					Method:  MethodGet,
+					Key:     "test1/test2",
+					Params:  map[string]string{KeyAccept: string(FormatJSONSchema)},
+					Format:  FormatJSON,
+					Content: []byte(`{"apiVersion":"test1", "kind":"test2", "metadata": {"namespace":"template", "name":"template"}}`),
+				},
+			},
+			&action{
+				obj: func() []byte {
+					b, _ := json.MarshalIndent(JSONSchema("test1/test2", &k.Resource{}), "", "  ")
+					return b
+				}(),
+			},
+		),
 		gen(
 			"Get fails",
 			&condition{
@@ -724,3 +771,344 @@ func TestFactoryAPI_get(t *testing.T) {
 		})
 	}
 }
+
+// typedResource is the testResource that tells the types of the objects.
+type typedResource struct {
+	testResource
+}
+
+func (r *typedResource) Types() []reflect.Type {
+	return []reflect.Type{reflect.TypeFor[string]()}
+}
+
+func TestFactoryAPI_getTypes(t *testing.T) {
+	type condition struct {
+		resource Resource
+	}
+
+	type action struct {
+		types []reflect.Type
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"types are known",
+			&condition{
+				resource: &typedResource{},
+			},
+			&action{
+				types: []reflect.Type{reflect.TypeFor[string]()},
+			},
+		),
+		gen(
+			"types are unknown",
+			&condition{
+				resource: &testResource{},
+			},
+			&action{
+				types: nil,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			a := NewFactoryAPI()
+			req := &Request{
+				Method:  MethodGet,
+				Key:     "test1/test2",
+				Params:  map[string]string{KeyAccept: string(FormatGoTypes)},
+				Format:  FormatJSON,
+				Content: []byte(`{"apiVersion":"test1", "kind":"test2", "metadata": {"namespace":"template", "name":"template"}}`),
+			}
+			obj, err := a.get(context.Background(), req, tt.C.resource)
+			testutil.Diff(t, nil, err)
+			types, ok := obj.([]reflect.Type)
+			testutil.Diff(t, true, ok)
+			testutil.Diff(t, true, slices.Equal(tt.A.types, types))
+			testutil.Diff(t, map[string]any{}, a.objStore) // Objects are not created.
+		})
+	}
+}
+
+func TestFactoryAPI_list(t *testing.T) {
+	r1 := &k.Resource{APIVersion: "core/v1", Kind: "Foo", Metadata: &k.Metadata{Namespace: "ns", Name: "a"}}
+	r2 := &k.Resource{APIVersion: "core/v1", Kind: "Foo", Metadata: &k.Metadata{Namespace: "ns", Name: "b"}}
+	r3 := &k.Resource{APIVersion: "app/v1", Kind: "Bar", Metadata: &k.Metadata{Namespace: "ns", Name: "c"}}
+	a := &FactoryAPI{
+		protoStore: map[string]proto.Message{
+			"core/v1/Foo/ns/a": r1,
+			"core/v1/Foo/ns/b": r2,
+			"app/v1/Bar/ns/c":  r3,
+		},
+		objStore:  map[string]any{"core/v1/Foo/ns/b": "object"},
+		resources: map[string]Resource{},
+	}
+
+	type condition struct {
+		key string
+	}
+
+	type action struct {
+		manifests []*Manifest
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"list all",
+			&condition{
+				key: "",
+			},
+			&action{
+				manifests: []*Manifest{
+					{ID: "app/v1/Bar/ns/c", Message: r3},
+					{ID: "core/v1/Foo/ns/a", Message: r1},
+					{ID: "core/v1/Foo/ns/b", Message: r2, Object: "object"},
+				},
+			},
+		),
+		gen(
+			"list with prefix",
+			&condition{
+				key: "core/v1/Foo",
+			},
+			&action{
+				manifests: []*Manifest{
+					{ID: "core/v1/Foo/ns/a", Message: r1},
+					{ID: "core/v1/Foo/ns/b", Message: r2, Object: "object"},
+				},
+			},
+		),
+		gen(
+			"no match",
+			&condition{
+				key: "core/v2",
+			},
+			&action{
+				manifests: []*Manifest{},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			res, err := a.Serve(context.Background(), &Request{Method: MethodList, Key: tt.C.key})
+			testutil.Diff(t, nil, err)
+			testutil.Diff(t, tt.A.manifests, res.Content, cmpopts.IgnoreUnexported(k.Resource{}, k.Metadata{}))
+			testutil.Diff(t, 1, len(a.objStore)) // Objects are not created.
+		})
+	}
+}
+
+// eventResource records the events of the resource.
+// Created objects refer the object given by the Handler field
+// of the HTTPHandler manifest.
+// Creating objects with the pattern "/fail" fails.
+type eventResource struct {
+	*BaseResource
+	events *[]string
+}
+
+func (r *eventResource) Validate(msg proto.Message) error {
+	return nil
+}
+
+func (r *eventResource) Create(a API[*Request, *Response], msg proto.Message) (any, error) {
+	c := msg.(*v1.HTTPHandler)
+	if c.Spec.Pattern == "/fail" {
+		return nil, &zerrors.Err{Message: "create failed"}
+	}
+	if c.Spec.Handler != nil {
+		if _, err := ReferObject(a, c.Spec.Handler); err != nil {
+			return nil, err
+		}
+	}
+	*r.events = append(*r.events, "create "+c.Metadata.Name)
+	return &struct{ pattern string }{pattern: c.Spec.Pattern}, nil
+}
+
+func (r *eventResource) Delete(a API[*Request, *Response], msg proto.Message, obj any) error {
+	c := msg.(*v1.HTTPHandler)
+	*r.events = append(*r.events, "delete "+c.Metadata.Name+" "+c.Spec.Pattern)
+	return nil
+}
+
+// updaterResource is the eventResource that implements Updater interface.
+// Updating objects fails when the referred object has the pattern "/bad"
+// or the object has the pattern "/noupdate".
+type updaterResource struct {
+	*eventResource
+}
+
+func (r *updaterResource) Update(a API[*Request, *Response], msg proto.Message, obj any) error {
+	c := msg.(*v1.HTTPHandler)
+	if c.Spec.Pattern == "/noupdate" {
+		return &zerrors.Err{Message: "update failed"}
+	}
+	if c.Spec.Handler != nil {
+		ref, err := ReferObject(a, c.Spec.Handler)
+		if err != nil {
+			return err
+		}
+		if ref.(*struct{ pattern string }).pattern == "/bad" {
+			return &zerrors.Err{Message: "update failed"}
+		}
+	}
+	*r.events = append(*r.events, "update "+c.Metadata.Name)
+	obj.(*struct{ pattern string }).pattern = c.Spec.Pattern
+	return nil
+}
+
+func testHandlerManifest(kind, name, pattern, ref string) *v1.HTTPHandler {
+	c := &v1.HTTPHandler{
+		APIVersion: "test/v1",
+		Kind:       kind,
+		Metadata:   &k.Metadata{Namespace: "ns", Name: name},
+		Spec:       &v1.HTTPHandlerSpec{Pattern: pattern},
+	}
+	if ref != "" {
+		c.Spec.Handler = &k.Reference{APIVersion: "test/v1", Kind: "Handler", Namespace: "ns", Name: ref}
+	}
+	return c
+}
+
+func TestFactoryAPI_put(t *testing.T) {
+	ctx := context.Background()
+	events := []string{}
+	base := &eventResource{
+		BaseResource: &BaseResource{DefaultProto: &v1.HTTPHandler{}},
+		events:       &events,
+	}
+	f := NewFactoryAPI()
+	_ = f.Register("test/v1/Handler", base)
+	_ = f.Register("test/v1/Server", &updaterResource{eventResource: base})
+	root := NewDefaultServeMux()
+	_ = root.Handle("test/", f)
+
+	serve := func(method Method, msg *v1.HTTPHandler) error {
+		_, err := root.Serve(ctx, &Request{Method: method, Key: "test/v1/" + msg.Kind, Format: FormatProtoMessage, Content: msg})
+		return err
+	}
+	get := func(kind, name string) any {
+		obj, err := ReferObject(root, &k.Reference{APIVersion: "test/v1", Kind: kind, Namespace: "ns", Name: name})
+		testutil.Diff(t, nil, err)
+		return obj
+	}
+
+	// Put stores new manifests.
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Handler", "leaf", "/leaf", "")))
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Handler", "mid", "/mid", "leaf")))
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Server", "top", "/top", "mid")))
+	testutil.Diff(t, nil, serve(MethodPost, testHandlerManifest("Handler", "other", "/other", "")))
+	testutil.Diff(t, []string{}, events)
+	top := get("Server", "top")
+	get("Handler", "other")
+	testutil.Diff(t, []string{"create leaf", "create mid", "create top", "create other"}, events)
+
+	// Replacing the leaf re-creates the leaf and the mid, and updates the top.
+	events = events[:0]
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Handler", "leaf", "/leaf2", "")))
+	testutil.Diff(t, []string{"create leaf", "create mid", "update top", "delete leaf /leaf", "delete mid /mid"}, events)
+	testutil.Diff(t, true, top == get("Server", "top"))
+	leaf := get("Handler", "leaf")
+
+	// Failing to create the replaced object keeps the old manifest and objects.
+	events = events[:0]
+	err := serve(MethodPut, testHandlerManifest("Handler", "leaf", "/fail", ""))
+	testutil.Diff(t, &zerrors.Err{Message: "create failed"}, err, cmpopts.EquateErrors())
+	testutil.Diff(t, []string{}, events)
+	testutil.Diff(t, true, leaf == get("Handler", "leaf"))
+	testutil.Diff(t, "/leaf2", f.protoStore["test/v1/Handler/ns/leaf"].(*v1.HTTPHandler).Spec.Pattern)
+
+	// Failing to update the dependents keeps the old manifest and objects.
+	// Objects created with the new manifest are deleted.
+	mid := get("Handler", "mid")
+	events = events[:0]
+	err = serve(MethodPut, testHandlerManifest("Handler", "mid", "/bad", "leaf"))
+	testutil.Diff(t, &zerrors.Err{Message: "update failed"}, err, cmpopts.EquateErrors())
+	testutil.Diff(t, []string{"create mid", "delete mid /bad"}, events)
+	testutil.Diff(t, true, mid == get("Handler", "mid"))
+	testutil.Diff(t, "/mid", f.protoStore["test/v1/Handler/ns/mid"].(*v1.HTTPHandler).Spec.Pattern)
+
+	// Failing to update the replaced object keeps the old manifest.
+	testutil.Diff(t, nil, serve(MethodPost, testHandlerManifest("Handler", "bad", "/bad", "")))
+	events = events[:0]
+	err = serve(MethodPut, testHandlerManifest("Server", "top", "/top3", "bad"))
+	testutil.Diff(t, &zerrors.Err{Message: "update failed"}, err, cmpopts.EquateErrors())
+	testutil.Diff(t, []string{"create bad"}, events)
+	testutil.Diff(t, "/top", top.(*struct{ pattern string }).pattern)
+	testutil.Diff(t, "/top", f.protoStore["test/v1/Server/ns/top"].(*v1.HTTPHandler).Spec.Pattern)
+
+	// Replacing the top updates only the top.
+	events = events[:0]
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Server", "top", "/top2", "mid")))
+	testutil.Diff(t, []string{"update top"}, events)
+	testutil.Diff(t, "/top2", top.(*struct{ pattern string }).pattern)
+
+	// Replacing not created manifest does nothing.
+	events = events[:0]
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Handler", "new", "/new", "")))
+	testutil.Diff(t, nil, serve(MethodPut, testHandlerManifest("Handler", "new", "/new2", "")))
+	testutil.Diff(t, []string{}, events)
+
+	// Referred manifests cannot be deleted.
+	err = serve(MethodDelete, testHandlerManifest("Handler", "mid", "", ""))
+	testutil.Diff(t, &zerrors.Err{Message: "kernel/api: manifest is referred from others."}, err, cmpopts.EquateErrors())
+	testutil.Diff(t, nil, serve(MethodDelete, testHandlerManifest("Server", "top", "", "")))
+	testutil.Diff(t, nil, serve(MethodDelete, testHandlerManifest("Handler", "mid", "", "")))
+
+	// Invalid manifest.
+	_ = f.Register("test/v1/Invalid", &testResource{err: &zerrors.Err{Message: "invalid"}})
+	_, err = root.Serve(ctx, &Request{Method: MethodPut, Key: "test/v1/Invalid", Format: FormatProtoMessage, Content: &k.Resource{}})
+	testutil.Diff(t, &zerrors.Err{Message: "invalid"}, err, cmpopts.EquateErrors())
+}
+
+func TestFactoryAPI_putRollback(t *testing.T) {
+	ctx := context.Background()
+	events := []string{}
+	base := &eventResource{
+		BaseResource: &BaseResource{DefaultProto: &v1.HTTPHandler{}},
+		events:       &events,
+	}
+	f := NewFactoryAPI()
+	_ = f.Register("test/v1/Handler", base)
+	_ = f.Register("test/v1/Server", &updaterResource{eventResource: base})
+	root := NewDefaultServeMux()
+	_ = root.Handle("test/", f)
+
+	serve := func(method Method, msg *v1.HTTPHandler) error {
+		_, err := root.Serve(ctx, &Request{Method: method, Key: "test/v1/" + msg.Kind, Format: FormatProtoMessage, Content: msg})
+		return err
+	}
+	get := func(kind, name string) any {
+		obj, err := ReferObject(root, &k.Reference{APIVersion: "test/v1", Kind: kind, Namespace: "ns", Name: name})
+		testutil.Diff(t, nil, err)
+		return obj
+	}
+
+	testutil.Diff(t, nil, serve(MethodPost, testHandlerManifest("Handler", "leaf", "/leaf", "")))
+	testutil.Diff(t, nil, serve(MethodPost, testHandlerManifest("Server", "a", "/a", "leaf")))
+	testutil.Diff(t, nil, serve(MethodPost, testHandlerManifest("Server", "b", "/noupdate", "leaf")))
+	get("Server", "a")
+	get("Server", "b")
+	leaf := get("Handler", "leaf")
+
+	// Updated objects are updated again with the restored objects.
+	events = events[:0]
+	err := serve(MethodPut, testHandlerManifest("Handler", "leaf", "/leaf2", ""))
+	testutil.Diff(t, &zerrors.Err{Message: "update failed"}, err, cmpopts.EquateErrors())
+	testutil.Diff(t, []string{"create leaf", "update a", "delete leaf /leaf2", "update a"}, events)
+	testutil.Diff(t, true, leaf == get("Handler", "leaf"))
+	testutil.Diff(t, "/leaf", f.protoStore["test/v1/Handler/ns/leaf"].(*v1.HTTPHandler).Spec.Pattern)
+
+	// New manifest is removed when failed.
+	testutil.Diff(t, nil, serve(MethodDelete, testHandlerManifest("Server", "b", "", "")))
+	events = events[:0]
+	err = serve(MethodPut, testHandlerManifest("Server", "b", "/noupdate", "leaf"))
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, []string{}, events)
+	_, ok := f.protoStore["test/v1/Server/ns/b"]
+	testutil.Diff(t, true, ok)
+}
@@ -6,6 +6,8 @@ package api_test
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 
 	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
@@ -165,6 +167,13 @@ func TestBaseResource(t *testing.T) {
 	}
 }
 
+func TestBaseResource_Types(t *testing.T) {
+	r := &api.BaseResource{}
+	testutil.Diff(t, true, r.Types() == nil)
+	r = &api.BaseResource{ObjectTypes: []reflect.Type{reflect.TypeFor[*MyResource]()}}
+	testutil.Diff(t, true, slices.Equal([]reflect.Type{reflect.TypeFor[*MyResource]()}, r.Types()))
+}
+
 func TestFactoryAPI_Serve(t *testing.T) {
 	type condition struct {
 		resources map[string]api.Resource
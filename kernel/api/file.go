@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// WalkFiles calls the fn for each file path found in the message at any depth.
+// File paths are the non-empty string values of the fields
+// marked with the (kernel.file) option in the proto definitions,
+// including elements of repeated fields and values of maps.
+// The fn is called with the JSON path of the value such as "spec.rootCAs[0]".
+func WalkFiles(msg proto.Message, fn func(path string, file string)) {
+	if msg == nil {
+		return
+	}
+	walkMessage(msg.ProtoReflect(), "", func(path string, fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if isMessageValue(fd) {
+			return true
+		}
+		if isFile(fd) {
+			if s, ok := v.Interface().(string); ok && s != "" {
+				fn(path, s)
+			}
+		}
+		return false
+	})
+}
+
+// isFile reports if the field is marked as holding file paths.
+func isFile(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	v, _ := proto.GetExtension(opts, k.E_File).(bool)
+	return v
+}
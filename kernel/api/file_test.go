@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api_test
+
+import (
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWalkFiles(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+	}
+
+	type action struct {
+		paths []string
+		files []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{},
+		),
+		gen(
+			"no files",
+			&condition{
+				msg: &v1.AdminServer{Spec: &v1.AdminServerSpec{Addr: "127.0.0.1:9090"}},
+			},
+			&action{},
+		),
+		gen(
+			"nested files",
+			&condition{
+				msg: &v1.AdminServer{
+					Spec: &v1.AdminServerSpec{
+						ListenConfig: &k.ListenConfig{
+							TLSConfig: &k.TLSConfig{
+								CertKeyPairs: []*k.CertKeyPair{
+									{CertFile: "cert.pem", KeyFile: "key.pem"},
+									{CertFile: "", KeyFile: ""}, // Empty.
+								},
+								RootCAs:   []string{"root1.pem", "root2.pem"},
+								ClientCAs: []string{"client.pem"},
+							},
+						},
+					},
+				},
+			},
+			&action{
+				paths: []string{
+					"spec.listenConfig.tlsConfig.certKeyPairs[0].certFile",
+					"spec.listenConfig.tlsConfig.certKeyPairs[0].keyFile",
+					"spec.listenConfig.tlsConfig.rootCAs[0]",
+					"spec.listenConfig.tlsConfig.rootCAs[1]",
+					"spec.listenConfig.tlsConfig.clientCAs[0]",
+				},
+				files: []string{"cert.pem", "key.pem", "root1.pem", "root2.pem", "client.pem"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var paths, files []string
+			api.WalkFiles(tt.C.msg, func(path string, file string) {
+				paths = append(paths, path)
+				files = append(files, file)
+			})
+			testutil.Diff(t, tt.A.paths, paths)
+			testutil.Diff(t, tt.A.files, files)
+		})
+	}
+}
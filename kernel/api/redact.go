@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Redacted is the value that replaces the values of sensitive fields.
+const Redacted = "[REDACTED]"
+
+// Redact returns a copy of the given message
+// with the values of sensitive fields replaced by the Redacted.
+// Sensitive fields are the fields marked with the
+// (kernel.sensitive) option in the proto definitions.
+// String values, including elements of repeated fields and values of maps,
+// are replaced with the Redacted and bytes values are cleared.
+// Unset fields are kept unset.
+func Redact(msg proto.Message) proto.Message {
+	if msg == nil {
+		return nil
+	}
+	msg = proto.Clone(msg)
+	redact(msg.ProtoReflect())
+	return msg
+}
+
+// isSensitive reports if the field is marked as sensitive.
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	v, _ := proto.GetExtension(opts, k.E_Sensitive).(bool)
+	return v
+}
+
+// redactValue returns the redacted value of the given value.
+// Values other than string and bytes are returned as-is.
+func redactValue(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(Redacted)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(nil)
+	default:
+		return v
+	}
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sensitive := isSensitive(fd)
+		switch {
+		case fd.IsMap():
+			mp := v.Map()
+			vd := fd.MapValue()
+			mp.Range(func(key protoreflect.MapKey, mv protoreflect.Value) bool {
+				if vd.Message() != nil {
+					redact(mv.Message())
+				} else if sensitive {
+					mp.Set(key, redactValue(vd.Kind(), mv))
+				}
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := range list.Len() {
+				if fd.Message() != nil {
+					redact(list.Get(i).Message())
+				} else if sensitive {
+					list.Set(i, redactValue(fd.Kind(), list.Get(i)))
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message())
+		case sensitive:
+			m.Set(fd, redactValue(fd.Kind(), v))
+		}
+		return true
+	})
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api_test
+
+import (
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestRedact(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+	}
+
+	type action struct {
+		msg proto.Message
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{
+				msg: nil,
+			},
+		),
+		gen(
+			"no sensitive field",
+			&condition{
+				msg: &k.Reference{APIVersion: "core/v1", Kind: "Test", Name: "foo"},
+			},
+			&action{
+				msg: &k.Reference{APIVersion: "core/v1", Kind: "Test", Name: "foo"},
+			},
+		),
+		gen(
+			"nested sensitive string",
+			&condition{
+				msg: &k.ReplacerSpec{
+					Replacers: &k.ReplacerSpec_HMAC{
+						HMAC: &k.HMACReplacer{Pattern: "foo", Key: "secret"},
+					},
+				},
+			},
+			&action{
+				msg: &k.ReplacerSpec{
+					Replacers: &k.ReplacerSpec_HMAC{
+						HMAC: &k.HMACReplacer{Pattern: "foo", Key: api.Redacted},
+					},
+				},
+			},
+		),
+		gen(
+			"unset sensitive string",
+			&condition{
+				msg: &k.HMACReplacer{Pattern: "foo"},
+			},
+			&action{
+				msg: &k.HMACReplacer{Pattern: "foo"},
+			},
+		),
+		gen(
+			"sensitive map",
+			&condition{
+				msg: &v1.HTTPMetricsExporterSpec{
+					EndpointURL: "http://example.com",
+					Headers:     map[string]string{"Authorization": "Bearer token", "X-Foo": "bar"},
+				},
+			},
+			&action{
+				msg: &v1.HTTPMetricsExporterSpec{
+					EndpointURL: "http://example.com",
+					Headers:     map[string]string{"Authorization": api.Redacted, "X-Foo": api.Redacted},
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var orig proto.Message
+			if tt.C.msg != nil {
+				orig = proto.Clone(tt.C.msg)
+			}
+			got := api.Redact(tt.C.msg)
+			testutil.Diff(t, tt.A.msg, got, protocmp.Transform())
+			testutil.Diff(t, orig, tt.C.msg, protocmp.Transform()) // Original is not modified.
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	"cmp"
+	"sort"
+
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ReferencedIDs returns the IDs of the resources referred from the message
+// through kernel.Reference fields at any depth.
+// IDs are in the format of "APIGroup/APIVersion/Kind/Namespace/Name"
+// and the empty namespace and name are replaced with "default".
+// Returned IDs are sorted and do not contain duplicates.
+func ReferencedIDs(msg proto.Message) []string {
+	if msg == nil {
+		return nil
+	}
+	found := map[string]struct{}{}
+	WalkReferences(msg, func(_ string, ref *k.Reference) {
+		found[ReferenceID(ref)] = struct{}{}
+	})
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ReferenceID returns the ID of the resource referred by the reference.
+// The ID is in the format of "APIGroup/APIVersion/Kind/Namespace/Name"
+// and the empty namespace and name are replaced with "default".
+func ReferenceID(ref *k.Reference) string {
+	return ref.APIVersion + "/" + ref.Kind + "/" + cmp.Or(ref.Namespace, "default") + "/" + cmp.Or(ref.Name, "default")
+}
+
+var referenceName = (&k.Reference{}).ProtoReflect().Descriptor().FullName()
+
+// WalkReferences calls the fn for each kernel.Reference
+// found in the message at any depth.
+// The fn is called with the JSON path of the reference such as "spec.handlers[0]".
+// References without APIVersion or Kind are ignored
+// because they do not refer to any resource.
+// The path is empty when the given message is a reference.
+func WalkReferences(msg proto.Message, fn func(path string, ref *k.Reference)) {
+	if msg == nil {
+		return
+	}
+	visit := func(path string, m protoreflect.Message) bool {
+		if m.Descriptor().FullName() != referenceName {
+			return true
+		}
+		ref, ok := m.Interface().(*k.Reference)
+		if ok && ref.APIVersion != "" && ref.Kind != "" {
+			fn(path, ref)
+		}
+		return false
+	}
+	m := msg.ProtoReflect()
+	if !visit("", m) {
+		return
+	}
+	walkMessage(m, "", func(path string, fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if isMessageValue(fd) {
+			return visit(path, v.Message())
+		}
+		return false
+	})
+}
+
+// isMessageValue reports if the values of the field are messages.
+// Values of maps are checked for map fields.
+func isMessageValue(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		return fd.MapValue().Message() != nil
+	}
+	return fd.Message() != nil
+}
+
+// WalkReferTypes calls the fn for each kernel.Reference found in the message
+// at any depth which is held by the fields marked with the (kernel.refer) option.
+// The fn is called with the JSON path of the reference such as "spec.middleware[0]"
+// and the interface name given by the option such as "core.Middleware".
+// References without APIVersion or Kind are ignored
+// because they do not refer to any resource.
+func WalkReferTypes(msg proto.Message, fn func(path string, refer string, ref *k.Reference)) {
+	if msg == nil {
+		return
+	}
+	walkMessage(msg.ProtoReflect(), "", func(path string, fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !isMessageValue(fd) {
+			return false
+		}
+		if v.Message().Descriptor().FullName() != referenceName {
+			return true
+		}
+		ref, ok := v.Message().Interface().(*k.Reference)
+		if refer := referType(fd); ok && refer != "" && ref.APIVersion != "" && ref.Kind != "" {
+			fn(path, refer, ref)
+		}
+		return false
+	})
+}
+
+// referType returns the interface name given by the (kernel.refer) option of the field.
+// An empty string is returned when the field is not marked.
+func referType(fd protoreflect.FieldDescriptor) string {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return ""
+	}
+	v, _ := proto.GetExtension(opts, k.E_Refer).(string)
+	return v
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api_test
+
+import (
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReferencedIDs(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+	}
+
+	type action struct {
+		ids []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{
+				ids: nil,
+			},
+		),
+		gen(
+			"no reference",
+			&condition{
+				msg: &v1.HTTPHandler{APIVersion: "core/v1", Kind: "HTTPHandler"},
+			},
+			&action{
+				ids: []string{},
+			},
+		),
+		gen(
+			"reference itself",
+			&condition{
+				msg: &k.Reference{APIVersion: "core/v1", Kind: "Foo"},
+			},
+			&action{
+				ids: []string{"core/v1/Foo/default/default"},
+			},
+		),
+		gen(
+			"nested references",
+			&condition{
+				msg: &v1.HTTPServer{
+					Spec: &v1.HTTPServerSpec{
+						Middleware: []*k.Reference{
+							{APIVersion: "app/v1", Kind: "Foo", Namespace: "ns", Name: "a"},
+							{APIVersion: "app/v1", Kind: "Foo", Namespace: "ns", Name: "a"}, // Duplicate.
+							{APIVersion: "", Kind: ""},                                      // Empty.
+						},
+						VirtualHosts: []*v1.VirtualHostSpec{
+							{
+								Handlers: []*v1.HTTPHandlerSpec{
+									{Handler: &k.Reference{APIVersion: "core/v1", Kind: "Bar", Name: "b"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			&action{
+				ids: []string{"app/v1/Foo/ns/a", "core/v1/Bar/default/b"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			testutil.Diff(t, tt.A.ids, api.ReferencedIDs(tt.C.msg))
+		})
+	}
+}
+
+func TestWalkReferences(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+	}
+
+	type action struct {
+		paths []string
+		ids   []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{},
+		),
+		gen(
+			"reference itself",
+			&condition{
+				msg: &k.Reference{APIVersion: "core/v1", Kind: "Foo"},
+			},
+			&action{
+				paths: []string{""},
+				ids:   []string{"core/v1/Foo/default/default"},
+			},
+		),
+		gen(
+			"nested references",
+			&condition{
+				msg: &v1.HTTPServer{
+					Spec: &v1.HTTPServerSpec{
+						Middleware: []*k.Reference{
+							{APIVersion: "app/v1", Kind: "Foo", Namespace: "ns", Name: "a"},
+							{APIVersion: "", Kind: ""}, // Empty.
+							{APIVersion: "app/v1", Kind: "Foo", Namespace: "ns", Name: "a"},
+						},
+						VirtualHosts: []*v1.VirtualHostSpec{
+							{
+								Handlers: []*v1.HTTPHandlerSpec{
+									{Handler: &k.Reference{APIVersion: "core/v1", Kind: "Bar", Name: "b"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			&action{
+				paths: []string{"spec.middleware[0]", "spec.middleware[2]", "spec.virtualHosts[0].handlers[0].handler"},
+				ids:   []string{"app/v1/Foo/ns/a", "app/v1/Foo/ns/a", "core/v1/Bar/default/b"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var paths, ids []string
+			api.WalkReferences(tt.C.msg, func(path string, ref *k.Reference) {
+				paths = append(paths, path)
+				ids = append(ids, api.ReferenceID(ref))
+			})
+			testutil.Diff(t, tt.A.paths, paths)
+			testutil.Diff(t, tt.A.ids, ids)
+		})
+	}
+}
+
+func TestWalkReferTypes(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+	}
+
+	type action struct {
+		paths  []string
+		refers []string
+		ids    []string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{},
+		),
+		gen(
+			"reference itself",
+			&condition{
+				msg: &k.Reference{APIVersion: "core/v1", Kind: "Foo"},
+			},
+			&action{},
+		),
+		gen(
+			"nested references",
+			&condition{
+				msg: &v1.HTTPServer{
+					Spec: &v1.HTTPServerSpec{
+						Middleware: []*k.Reference{
+							{APIVersion: "app/v1", Kind: "Foo", Namespace: "ns", Name: "a"},
+							{APIVersion: "", Kind: ""}, // Empty.
+						},
+						HTTPConfig: &v1.HTTPConfig{
+							ListenConfig: &k.ListenConfig{
+								TLSConfig: &k.TLSConfig{
+									CertManager: &k.Reference{APIVersion: "core/v1", Kind: "ACMEManager"},
+								},
+							},
+						},
+						VirtualHosts: []*v1.VirtualHostSpec{
+							{
+								Handlers: []*v1.HTTPHandlerSpec{
+									{Handler: &k.Reference{APIVersion: "core/v1", Kind: "Bar", Name: "b"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			&action{
+				paths:  []string{"spec.httpConfig.listenConfig.tlsConfig.certManager", "spec.middleware[0]", "spec.virtualHosts[0].handlers[0].handler"},
+				refers: []string{"core.CertificateProvider", "core.Middleware", "http.Handler"},
+				ids:    []string{"core/v1/ACMEManager/default/default", "app/v1/Foo/ns/a", "core/v1/Bar/default/b"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var paths, refers, ids []string
+			api.WalkReferTypes(tt.C.msg, func(path string, refer string, ref *k.Reference) {
+				paths = append(paths, path)
+				refers = append(refers, refer)
+				ids = append(ids, api.ReferenceID(ref))
+			})
+			testutil.Diff(t, tt.A.paths, paths)
+			testutil.Diff(t, tt.A.refers, refers)
+			testutil.Diff(t, tt.A.ids, ids)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ReplaceStrings replaces the string values in the message at any depth
+// with the values returned by the fn,
+// including elements of repeated fields and values of maps.
+// The fn is called with the JSON path of the value such as "spec.password",
+// whether the field is marked with the (kernel.sensitive) option
+// and the current value. Empty strings are not passed to the fn.
+// Replacing stops at the first error returned by the fn.
+func ReplaceStrings(msg proto.Message, fn func(path string, sensitive bool, value string) (string, error)) error {
+	if msg == nil {
+		return nil
+	}
+	return replaceStrings(msg.ProtoReflect(), "", fn)
+}
+
+func replaceStrings(m protoreflect.Message, path string, fn func(string, bool, string) (string, error)) error {
+	fds := m.Descriptor().Fields()
+	for i := range fds.Len() {
+		fd := fds.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		p := joinPath(path, fd.JSONName())
+		sensitive := isSensitive(fd)
+		v := m.Get(fd)
+		switch {
+		case fd.IsMap():
+			mp := v.Map()
+			vd := fd.MapValue()
+			keys := make([]protoreflect.MapKey, 0, mp.Len())
+			mp.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, key)
+				return true
+			})
+			slices.SortFunc(keys, func(x, y protoreflect.MapKey) int {
+				return cmp.Compare(x.String(), y.String())
+			})
+			for _, key := range keys {
+				kp := p + "[" + strconv.Quote(key.String()) + "]"
+				if vd.Message() != nil {
+					if err := replaceStrings(mp.Get(key).Message(), kp, fn); err != nil {
+						return err
+					}
+				} else if vd.Kind() == protoreflect.StringKind {
+					s, err := replaceString(kp, sensitive, mp.Get(key).String(), fn)
+					if err != nil {
+						return err
+					}
+					mp.Set(key, protoreflect.ValueOfString(s))
+				}
+			}
+		case fd.IsList():
+			list := v.List()
+			for j := range list.Len() {
+				lp := p + "[" + strconv.Itoa(j) + "]"
+				if fd.Message() != nil {
+					if err := replaceStrings(list.Get(j).Message(), lp, fn); err != nil {
+						return err
+					}
+				} else if fd.Kind() == protoreflect.StringKind {
+					s, err := replaceString(lp, sensitive, list.Get(j).String(), fn)
+					if err != nil {
+						return err
+					}
+					list.Set(j, protoreflect.ValueOfString(s))
+				}
+			}
+		case fd.Message() != nil:
+			if err := replaceStrings(v.Message(), p, fn); err != nil {
+				return err
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			s, err := replaceString(p, sensitive, v.String(), fn)
+			if err != nil {
+				return err
+			}
+			m.Set(fd, protoreflect.ValueOfString(s))
+		}
+	}
+	return nil
+}
+
+// replaceString calls the fn for the non-empty value.
+// Empty values are returned as-is.
+func replaceString(path string, sensitive bool, value string, fn func(string, bool, string) (string, error)) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+	return fn(path, sensitive, value)
+}
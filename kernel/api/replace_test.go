@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/app/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestReplaceStrings(t *testing.T) {
+	type condition struct {
+		msg proto.Message
+		err error
+	}
+
+	type action struct {
+		msg    proto.Message
+		values []string
+		err    error
+	}
+
+	testErr := errors.New("test error")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil message",
+			&condition{
+				msg: nil,
+			},
+			&action{
+				msg: nil,
+			},
+		),
+		gen(
+			"nested strings",
+			&condition{
+				msg: &k.ReplacerSpec{
+					Replacers: &k.ReplacerSpec_HMAC{
+						HMAC: &k.HMACReplacer{Pattern: "foo", Key: "secret"},
+					},
+				},
+			},
+			&action{
+				msg: &k.ReplacerSpec{
+					Replacers: &k.ReplacerSpec_HMAC{
+						HMAC: &k.HMACReplacer{Pattern: "foo(hmac.pattern,false)", Key: "secret(hmac.key,true)"},
+					},
+				},
+				values: []string{"foo", "secret"},
+			},
+		),
+		gen(
+			"lists and maps",
+			&condition{
+				msg: &v1.HTTPMetricsExporterSpec{
+					EndpointURL: "http://example.com",
+					Headers:     map[string]string{"b": "bar", "a": "foo"},
+				},
+			},
+			&action{
+				msg: &v1.HTTPMetricsExporterSpec{
+					EndpointURL: `http://example.com(endpointURL,false)`,
+					Headers:     map[string]string{"a": `foo(headers["a"],true)`, "b": `bar(headers["b"],true)`},
+				},
+				values: []string{"http://example.com", "foo", "bar"},
+			},
+		),
+		gen(
+			"repeated strings",
+			&condition{
+				msg: &v1.MaintenanceBypassHeaderSpec{Name: "X-Bypass", Values: []string{"foo", "", "bar"}},
+			},
+			&action{
+				msg:    &v1.MaintenanceBypassHeaderSpec{Name: "X-Bypass(name,false)", Values: []string{"foo(values[0],true)", "", "bar(values[2],true)"}},
+				values: []string{"X-Bypass", "foo", "bar"},
+			},
+		),
+		gen(
+			"error",
+			&condition{
+				msg: &k.HMACReplacer{Pattern: "foo", Key: "secret"},
+				err: testErr,
+			},
+			&action{
+				msg:    &k.HMACReplacer{Pattern: "foo", Key: "secret"},
+				values: []string{"foo"},
+				err:    testErr,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			var values []string
+			err := api.ReplaceStrings(tt.C.msg, func(path string, sensitive bool, value string) (string, error) {
+				values = append(values, value)
+				if tt.C.err != nil {
+					return "", tt.C.err
+				}
+				return value + "(" + path + "," + strconv.FormatBool(sensitive) + ")", nil
+			})
+			testutil.Diff(t, tt.A.err, err, cmpopts.EquateErrors())
+			testutil.Diff(t, tt.A.values, values)
+			testutil.Diff(t, tt.A.msg, tt.C.msg, protocmp.Transform())
+		})
+	}
+}
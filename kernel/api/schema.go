@@ -0,0 +1,446 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// JSONSchemaDialect is the JSON Schema dialect of the generated schemas.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the JSON Schema of the manifest of the given key.
+// The key is "APIGroup/APIVersion/Kind" such as "core/v1/HTTPServer"
+// and the msg is the manifest of the kind.
+// Schemas are generated from the proto definitions.
+//   - Properties are named by the json_name of the fields.
+//   - Enums are the names of the values.
+//   - Rules of the buf.validate are converted to the keywords of the JSON Schema as possible.
+//     CEL expressions are not converted.
+//
+// Messages are defined in the "$defs" keyed by their full names.
+func JSONSchema(key string, msg proto.Message) map[string]any {
+	b := &schemaBuilder{defs: map[string]any{}}
+	schema := b.kind(key, msg.ProtoReflect().Descriptor())
+	schema["$schema"] = JSONSchemaDialect
+	schema["$defs"] = b.defs
+	return schema
+}
+
+// MergeJSONSchemas merges the JSON Schemas of kinds returned by the JSONSchema
+// into a single schema which accepts manifests of any of the kinds.
+// The schema of a manifest is chosen by its apiVersion and kind.
+func MergeJSONSchemas(schemas []map[string]any) map[string]any {
+	defs := map[string]any{}
+	conditions := make([]any, 0, len(schemas))
+	var apiVersions, kinds []string
+	for _, s := range schemas {
+		if d, ok := s["$defs"].(map[string]any); ok {
+			maps.Copy(defs, d)
+		}
+		props, _ := s["properties"].(map[string]any)
+		apiVersion, _ := props["apiVersion"].(map[string]any)
+		kind, _ := props["kind"].(map[string]any)
+		if apiVersion == nil || kind == nil {
+			continue
+		}
+		then := maps.Clone(s)
+		delete(then, "$schema")
+		delete(then, "$defs")
+		conditions = append(conditions, map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{"apiVersion": apiVersion, "kind": kind},
+				"required":   []string{"apiVersion", "kind"},
+			},
+			"then": then,
+		})
+		if v, ok := apiVersion["const"].(string); ok && !slices.Contains(apiVersions, v) {
+			apiVersions = append(apiVersions, v)
+		}
+		if v, ok := kind["const"].(string); ok && !slices.Contains(kinds, v) {
+			kinds = append(kinds, v)
+		}
+	}
+	slices.Sort(apiVersions)
+	slices.Sort(kinds)
+	return map[string]any{
+		"$schema": JSONSchemaDialect,
+		"type":    "object",
+		"properties": map[string]any{
+			"apiVersion": map[string]any{"type": "string", "enum": apiVersions},
+			"kind":       map[string]any{"type": "string", "enum": kinds},
+		},
+		"required": []string{"apiVersion", "kind"},
+		"allOf":    conditions,
+		"$defs":    defs,
+	}
+}
+
+// schemaBuilder builds JSON Schemas from proto descriptors.
+type schemaBuilder struct {
+	// defs is the schemas of messages keyed by their full names.
+	defs map[string]any
+}
+
+// kind returns the schema of the manifest of the key.
+// APIVersion and Kind of the manifest are fixed to the values of the key.
+func (b *schemaBuilder) kind(key string, md protoreflect.MessageDescriptor) map[string]any {
+	schema := b.message(md)
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return schema
+	}
+	schema["title"] = key
+	schema["type"] = "object"
+	schema["properties"] = map[string]any{
+		"apiVersion": map[string]any{"const": key[:i]},
+		"kind":       map[string]any{"const": key[i+1:]},
+	}
+	schema["required"] = []string{"apiVersion", "kind"}
+	return schema
+}
+
+// message returns the schema which refers to the definition of the message.
+// The definition is added to the defs if not exist.
+func (b *schemaBuilder) message(md protoreflect.MessageDescriptor) map[string]any {
+	name := string(md.FullName())
+	ref := map[string]any{"$ref": "#/$defs/" + name}
+	if _, ok := b.defs[name]; ok {
+		return ref
+	}
+	if s := wellKnownSchema(name); s != nil {
+		b.defs[name] = s
+		return ref
+	}
+
+	b.defs[name] = nil // Placeholder for recursive messages.
+	props := map[string]any{}
+	aliases := map[string]any{}
+	var required []string
+	fds := md.Fields()
+	for i := range fds.Len() {
+		fd := fds.Get(i)
+		rules := fieldRules(fd)
+		props[fd.JSONName()] = b.field(fd, rules)
+		if string(fd.Name()) != fd.JSONName() {
+			// Proto field names are also accepted by the protojson.
+			// They are defined as patterns not to be completed by editors.
+			aliases["^"+regexp.QuoteMeta(string(fd.Name()))+"$"] = props[fd.JSONName()]
+		}
+		if rules.GetRequired() {
+			required = append(required, fd.JSONName())
+		}
+	}
+	def := map[string]any{
+		// Null is accepted as the unset message by the protojson.
+		"type":                 []string{"object", "null"},
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(aliases) > 0 {
+		def["patternProperties"] = aliases
+	}
+	if len(required) > 0 {
+		def["required"] = required
+	}
+	var oneOfs []any
+	oods := md.Oneofs()
+	for i := range oods.Len() {
+		ood := oods.Get(i)
+		if ood.IsSynthetic() {
+			continue
+		}
+		names := make([]string, 0, ood.Fields().Len())
+		for j := range ood.Fields().Len() {
+			names = append(names, ood.Fields().Get(j).JSONName())
+		}
+		// At most one field of a oneof can be set.
+		// At least one must be set when the oneof is required.
+		oneOf := make([]any, 0, len(names)+1)
+		for _, n := range names {
+			oneOf = append(oneOf, map[string]any{"type": "object", "required": []string{n}})
+		}
+		if !oneofRequired(ood) {
+			oneOf = append(oneOf, map[string]any{"not": map[string]any{"anyOf": slices.Clone(oneOf)}})
+		}
+		oneOfs = append(oneOfs, map[string]any{"oneOf": oneOf})
+	}
+	if len(oneOfs) > 0 {
+		def["allOf"] = oneOfs
+	}
+	b.defs[name] = def
+	return ref
+}
+
+// field returns the schema of the field.
+func (b *schemaBuilder) field(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) map[string]any {
+	switch {
+	case fd.IsMap():
+		s := map[string]any{
+			"type":                 "object",
+			"additionalProperties": b.singular(fd.MapValue(), rules.GetMap().GetValues()),
+		}
+		if r := rules.GetMap(); r != nil {
+			if r.HasMinPairs() {
+				s["minProperties"] = r.GetMinPairs()
+			}
+			if r.HasMaxPairs() {
+				s["maxProperties"] = r.GetMaxPairs()
+			}
+			if keys := b.singular(fd.MapKey(), r.GetKeys()); len(keys) > 1 {
+				delete(keys, "type") // Keys of JSON objects are always strings.
+				s["propertyNames"] = keys
+			}
+		}
+		return s
+	case fd.IsList():
+		s := map[string]any{
+			"type":  "array",
+			"items": b.singular(fd, rules.GetRepeated().GetItems()),
+		}
+		if r := rules.GetRepeated(); r != nil {
+			if r.HasMinItems() {
+				s["minItems"] = r.GetMinItems()
+			}
+			if r.HasMaxItems() {
+				s["maxItems"] = r.GetMaxItems()
+			}
+			if r.GetUnique() {
+				s["uniqueItems"] = true
+			}
+		}
+		return s
+	default:
+		return b.singular(fd, rules)
+	}
+}
+
+// singular returns the schema of a single value of the field.
+func (b *schemaBuilder) singular(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) map[string]any {
+	if rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		rules = nil
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.message(fd.Message())
+	case protoreflect.EnumKind:
+		return enumSchema(fd.Enum(), rules.GetEnum())
+	case protoreflect.BoolKind:
+		s := map[string]any{"type": "boolean"}
+		if r := rules.GetBool(); r.HasConst() {
+			s["const"] = r.GetConst()
+		}
+		return s
+	case protoreflect.StringKind:
+		return stringSchema(rules.GetString())
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return numberSchema(map[string]any{"type": "number"}, numericRules(rules))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64-bit integers can be written in strings in JSON.
+		s := map[string]any{"type": []string{"integer", "string"}, "pattern": "^-?[0-9]+$"}
+		return numberSchema(s, numericRules(rules))
+	default:
+		return numberSchema(map[string]any{"type": "integer"}, numericRules(rules))
+	}
+}
+
+// fieldRules returns the buf.validate rules of the field.
+// Nil is returned when the field does not have rules.
+func fieldRules(fd protoreflect.FieldDescriptor) *validate.FieldRules {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+	rules, _ := proto.GetExtension(opts, validate.E_Field).(*validate.FieldRules)
+	return rules
+}
+
+// oneofRequired reports if the oneof is required by the buf.validate rules.
+func oneofRequired(ood protoreflect.OneofDescriptor) bool {
+	opts, ok := ood.Options().(*descriptorpb.OneofOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	rules, _ := proto.GetExtension(opts, validate.E_Oneof).(*validate.OneofRules)
+	return rules.GetRequired()
+}
+
+// numericRules returns the rules of the numeric types.
+// Nil is returned when the rules are not for numeric types.
+func numericRules(rules *validate.FieldRules) protoreflect.Message {
+	var m proto.Message
+	switch {
+	case rules.GetFloat() != nil:
+		m = rules.GetFloat()
+	case rules.GetDouble() != nil:
+		m = rules.GetDouble()
+	case rules.GetInt32() != nil:
+		m = rules.GetInt32()
+	case rules.GetInt64() != nil:
+		m = rules.GetInt64()
+	case rules.GetUint32() != nil:
+		m = rules.GetUint32()
+	case rules.GetUint64() != nil:
+		m = rules.GetUint64()
+	case rules.GetSint32() != nil:
+		m = rules.GetSint32()
+	case rules.GetSint64() != nil:
+		m = rules.GetSint64()
+	case rules.GetFixed32() != nil:
+		m = rules.GetFixed32()
+	case rules.GetFixed64() != nil:
+		m = rules.GetFixed64()
+	case rules.GetSfixed32() != nil:
+		m = rules.GetSfixed32()
+	case rules.GetSfixed64() != nil:
+		m = rules.GetSfixed64()
+	default:
+		return nil
+	}
+	return m.ProtoReflect()
+}
+
+// numberSchema adds the numeric rules to the schema.
+// All numeric rules have the same field names
+// such as "const", "lt", "lte", "gt", "gte" and "in".
+func numberSchema(s map[string]any, rules protoreflect.Message) map[string]any {
+	if rules == nil {
+		return s
+	}
+	keywords := map[protoreflect.Name]string{
+		"const": "const",
+		"lt":    "exclusiveMaximum",
+		"lte":   "maximum",
+		"gt":    "exclusiveMinimum",
+		"gte":   "minimum",
+	}
+	rules.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if kw, ok := keywords[fd.Name()]; ok {
+			s[kw] = v.Interface()
+		}
+		if fd.Name() == "in" && fd.IsList() {
+			values := make([]any, 0, v.List().Len())
+			for i := range v.List().Len() {
+				values = append(values, v.List().Get(i).Interface())
+			}
+			s["enum"] = values
+		}
+		return true
+	})
+	return s
+}
+
+// stringSchema returns the schema of strings with the rules.
+func stringSchema(r *validate.StringRules) map[string]any {
+	s := map[string]any{"type": "string"}
+	if r == nil {
+		return s
+	}
+	if r.HasConst() {
+		s["const"] = r.GetConst()
+	}
+	if r.HasLen() {
+		s["minLength"] = r.GetLen()
+		s["maxLength"] = r.GetLen()
+	}
+	if r.HasMinLen() {
+		s["minLength"] = r.GetMinLen()
+	}
+	if r.HasMaxLen() {
+		s["maxLength"] = r.GetMaxLen()
+	}
+	if r.HasPattern() {
+		s["pattern"] = r.GetPattern()
+	}
+	if len(r.GetIn()) > 0 {
+		s["enum"] = r.GetIn()
+	}
+	if len(r.GetNotIn()) > 0 {
+		s["not"] = map[string]any{"enum": r.GetNotIn()}
+	}
+	formats := []struct {
+		ok     bool
+		format string
+	}{
+		{r.GetEmail(), "email"},
+		{r.GetHostname(), "hostname"},
+		{r.GetIpv4(), "ipv4"},
+		{r.GetIpv6(), "ipv6"},
+		{r.GetUri(), "uri"},
+		{r.GetUriRef(), "uri-reference"},
+		{r.GetUuid(), "uuid"},
+	}
+	for _, f := range formats {
+		if f.ok {
+			s["format"] = f.format
+		}
+	}
+	return s
+}
+
+// enumSchema returns the schema of the enum with the rules.
+// Enums are written in the names of the values.
+func enumSchema(ed protoreflect.EnumDescriptor, r *validate.EnumRules) map[string]any {
+	values := ed.Values()
+	names := make([]string, 0, values.Len())
+	for i := range values.Len() {
+		v := values.Get(i)
+		if r.HasConst() && r.GetConst() != int32(v.Number()) {
+			continue
+		}
+		if len(r.GetIn()) > 0 && !slices.Contains(r.GetIn(), int32(v.Number())) {
+			continue
+		}
+		if slices.Contains(r.GetNotIn(), int32(v.Number())) {
+			continue
+		}
+		names = append(names, string(v.Name()))
+	}
+	return map[string]any{"type": "string", "enum": names}
+}
+
+// wellKnownSchema returns the schema of the well-known types
+// which have special representations in JSON.
+// Nil is returned for other messages.
+func wellKnownSchema(name string) map[string]any {
+	switch name {
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`}
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "google.protobuf.Struct":
+		return map[string]any{"type": "object"}
+	case "google.protobuf.ListValue":
+		return map[string]any{"type": "array"}
+	case "google.protobuf.Value", "google.protobuf.Any":
+		return map[string]any{}
+	case "google.protobuf.FieldMask":
+		return map[string]any{"type": "string"}
+	case "google.protobuf.StringValue":
+		return map[string]any{"type": "string"}
+	case "google.protobuf.BoolValue":
+		return map[string]any{"type": "boolean"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return map[string]any{"type": "number"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return map[string]any{"type": "integer"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return map[string]any{"type": []string{"integer", "string"}, "pattern": "^-?[0-9]+$"}
+	case "google.protobuf.BytesValue":
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api_test
+
+import (
+	"testing"
+
+	v1 "github.com/aileron-gateway/aileron-gateway/apis/core/v1"
+	k "github.com/aileron-gateway/aileron-gateway/apis/kernel"
+	"github.com/aileron-gateway/aileron-gateway/kernel/api"
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJSONSchema(t *testing.T) {
+	type condition struct {
+		key string
+		msg proto.Message
+	}
+
+	type action struct {
+		def    string
+		schema map[string]any
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"kind",
+			&condition{
+				key: "test/v1/Matcher",
+				msg: &k.MatcherSpec{},
+			},
+			&action{
+				def: "",
+				schema: map[string]any{
+					"$ref":  "#/$defs/kernel.MatcherSpec",
+					"title": "test/v1/Matcher",
+					"type":  "object",
+					"properties": map[string]any{
+						"apiVersion": map[string]any{"const": "test/v1"},
+						"kind":       map[string]any{"const": "Matcher"},
+					},
+					"required": []string{"apiVersion", "kind"},
+				},
+			},
+		),
+		gen(
+			"repeated and enum",
+			&condition{
+				msg: &k.MatcherSpec{},
+			},
+			&action{
+				def: "kernel.MatcherSpec",
+				schema: map[string]any{
+					"type": []string{"object", "null"},
+					"properties": map[string]any{
+						"patterns": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"uniqueItems": true,
+						},
+						"matchType": map[string]any{
+							"type": "string",
+							"enum": []string{"Exact", "Prefix", "Suffix", "Contains", "Path", "FilePath", "Regex", "RegexPOSIX"},
+						},
+					},
+					"patternProperties": map[string]any{
+						"^Patterns$": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"uniqueItems": true,
+						},
+						"^MatchType$": map[string]any{
+							"type": "string",
+							"enum": []string{"Exact", "Prefix", "Suffix", "Contains", "Path", "FilePath", "Regex", "RegexPOSIX"},
+						},
+					},
+					"additionalProperties": false,
+				},
+			},
+		),
+		gen(
+			"numeric rules",
+			&condition{
+				msg: &k.ProxyProtocolConfig{},
+			},
+			&action{
+				def: "kernel.ProxyProtocolConfig",
+				schema: map[string]any{
+					"type": []string{"object", "null"},
+					"properties": map[string]any{
+						"trustedNetworks": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"minItems":    uint64(1),
+							"uniqueItems": true,
+						},
+						"headerTimeout": map[string]any{"type": "integer", "minimum": int32(0)},
+					},
+					"patternProperties": map[string]any{
+						"^TrustedNetworks$": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"minItems":    uint64(1),
+							"uniqueItems": true,
+						},
+						"^HeaderTimeout$": map[string]any{"type": "integer", "minimum": int32(0)},
+					},
+					"additionalProperties": false,
+				},
+			},
+		),
+		gen(
+			"oneof",
+			&condition{
+				msg: &k.PasswordCryptSpec{},
+			},
+			&action{
+				def: "kernel.PasswordCryptSpec",
+				schema: map[string]any{
+					"type": []string{"object", "null"},
+					"properties": map[string]any{
+						"bcrypt":   map[string]any{"$ref": "#/$defs/kernel.BCryptSpec"},
+						"scrypt":   map[string]any{"$ref": "#/$defs/kernel.SCryptSpec"},
+						"pbkdf2":   map[string]any{"$ref": "#/$defs/kernel.PBKDF2Spec"},
+						"argon2i":  map[string]any{"$ref": "#/$defs/kernel.Argon2Spec"},
+						"argon2id": map[string]any{"$ref": "#/$defs/kernel.Argon2Spec"},
+					},
+					"patternProperties": map[string]any{
+						"^BCrypt$":   map[string]any{"$ref": "#/$defs/kernel.BCryptSpec"},
+						"^SCrypt$":   map[string]any{"$ref": "#/$defs/kernel.SCryptSpec"},
+						"^PBKDF2$":   map[string]any{"$ref": "#/$defs/kernel.PBKDF2Spec"},
+						"^Argon2i$":  map[string]any{"$ref": "#/$defs/kernel.Argon2Spec"},
+						"^Argon2id$": map[string]any{"$ref": "#/$defs/kernel.Argon2Spec"},
+					},
+					"additionalProperties": false,
+					"allOf": []any{
+						map[string]any{"oneOf": []any{
+							map[string]any{"type": "object", "required": []string{"bcrypt"}},
+							map[string]any{"type": "object", "required": []string{"scrypt"}},
+							map[string]any{"type": "object", "required": []string{"pbkdf2"}},
+							map[string]any{"type": "object", "required": []string{"argon2i"}},
+							map[string]any{"type": "object", "required": []string{"argon2id"}},
+							map[string]any{"not": map[string]any{"anyOf": []any{
+								map[string]any{"type": "object", "required": []string{"bcrypt"}},
+								map[string]any{"type": "object", "required": []string{"scrypt"}},
+								map[string]any{"type": "object", "required": []string{"pbkdf2"}},
+								map[string]any{"type": "object", "required": []string{"argon2i"}},
+								map[string]any{"type": "object", "required": []string{"argon2id"}},
+							}}},
+						}},
+					},
+				},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			schema := api.JSONSchema(tt.C.key, tt.C.msg)
+			testutil.Diff(t, api.JSONSchemaDialect, schema["$schema"])
+			defs := schema["$defs"].(map[string]any)
+			if tt.A.def == "" {
+				delete(schema, "$schema")
+				delete(schema, "$defs")
+				testutil.Diff(t, tt.A.schema, schema)
+				return
+			}
+			testutil.Diff(t, tt.A.schema, defs[tt.A.def])
+		})
+	}
+}
+
+func TestJSONSchema_fields(t *testing.T) {
+	schema := api.JSONSchema("core/v1/HTTPServer", &v1.HTTPServer{})
+	testutil.Diff(t, "#/$defs/core.v1.HTTPServer", schema["$ref"])
+	defs := schema["$defs"].(map[string]any)
+	props := func(name string) map[string]any {
+		return defs[name].(map[string]any)["properties"].(map[string]any)
+	}
+
+	// Nested messages are referred.
+	testutil.Diff(t, map[string]any{"$ref": "#/$defs/core.v1.HTTPServerSpec"}, props("core.v1.HTTPServer")["spec"])
+	testutil.Diff(t, map[string]any{"$ref": "#/$defs/kernel.Metadata"}, props("core.v1.HTTPServer")["metadata"])
+
+	// Rules of repeated items.
+	testutil.Diff(t, map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string", "pattern": `^(\*\.)?[0-9a-zA-Z.-]+$`},
+		"uniqueItems": true,
+	}, props("core.v1.VirtualHostSpec")["hosts"])
+}
+
+func TestMergeJSONSchemas(t *testing.T) {
+	s1 := api.JSONSchema("test/v1/Matcher", &k.MatcherSpec{})
+	s2 := api.JSONSchema("test/v2/Proxy", &k.ProxyProtocolConfig{})
+	merged := api.MergeJSONSchemas([]map[string]any{s1, s2, {"type": "object"}})
+
+	testutil.Diff(t, api.JSONSchemaDialect, merged["$schema"])
+	testutil.Diff(t, map[string]any{
+		"apiVersion": map[string]any{"type": "string", "enum": []string{"test/v1", "test/v2"}},
+		"kind":       map[string]any{"type": "string", "enum": []string{"Matcher", "Proxy"}},
+	}, merged["properties"])
+	testutil.Diff(t, []string{"apiVersion", "kind"}, merged["required"])
+
+	defs := merged["$defs"].(map[string]any)
+	testutil.Diff(t, true, defs["kernel.MatcherSpec"] != nil)
+	testutil.Diff(t, true, defs["kernel.ProxyProtocolConfig"] != nil)
+
+	conditions := merged["allOf"].([]any)
+	testutil.Diff(t, 2, len(conditions))
+	testutil.Diff(t, map[string]any{
+		"if": map[string]any{
+			"properties": map[string]any{
+				"apiVersion": map[string]any{"const": "test/v1"},
+				"kind":       map[string]any{"const": "Matcher"},
+			},
+			"required": []string{"apiVersion", "kind"},
+		},
+		"then": map[string]any{
+			"$ref":  "#/$defs/kernel.MatcherSpec",
+			"title": "test/v1/Matcher",
+			"type":  "object",
+			"properties": map[string]any{
+				"apiVersion": map[string]any{"const": "test/v1"},
+				"kind":       map[string]any{"const": "Matcher"},
+			},
+			"required": []string{"apiVersion", "kind"},
+		},
+	}, conditions[0])
+}
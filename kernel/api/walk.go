@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package api
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// walkFunc is called for each value found while walking a message.
+// The path is the JSON path of the value such as "spec.hosts[0]".
+// The fd is the descriptor of the field that holds the value,
+// which is the list or map field for elements of repeated fields and maps.
+// Message values are walked into only when the function returns true.
+type walkFunc func(path string, fd protoreflect.FieldDescriptor, v protoreflect.Value) bool
+
+// walkMessage walks the populated fields of the message at any depth.
+// Fields are walked in the order of their definition,
+// elements of repeated fields in their order
+// and values of maps in the order of the keys.
+func walkMessage(m protoreflect.Message, path string, fn walkFunc) {
+	fds := m.Descriptor().Fields()
+	for i := range fds.Len() {
+		fd := fds.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		p := joinPath(path, fd.JSONName())
+		v := m.Get(fd)
+		switch {
+		case fd.IsMap():
+			mp := v.Map()
+			keys := make([]protoreflect.MapKey, 0, mp.Len())
+			mp.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, key)
+				return true
+			})
+			slices.SortFunc(keys, func(x, y protoreflect.MapKey) int {
+				return cmp.Compare(x.String(), y.String())
+			})
+			for _, key := range keys {
+				walkValue(p+"["+strconv.Quote(key.String())+"]", fd, fd.MapValue().Message() != nil, mp.Get(key), fn)
+			}
+		case fd.IsList():
+			list := v.List()
+			for j := range list.Len() {
+				walkValue(p+"["+strconv.Itoa(j)+"]", fd, fd.Message() != nil, list.Get(j), fn)
+			}
+		default:
+			walkValue(p, fd, fd.Message() != nil, v, fn)
+		}
+	}
+}
+
+// walkValue calls the fn with the value and walks into it
+// when the value is a message and the fn returned true.
+func walkValue(path string, fd protoreflect.FieldDescriptor, isMsg bool, v protoreflect.Value, fn walkFunc) {
+	if fn(path, fd, v) && isMsg {
+		walkMessage(v.Message(), path, fn)
+	}
+}
+
+// joinPath joins the parent JSON path and the field name.
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
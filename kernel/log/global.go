@@ -5,6 +5,7 @@ package log
 
 import (
 	"log/slog"
+	"maps"
 	"os"
 	"sync"
 	"time"
@@ -116,3 +117,18 @@ func SetGlobalLogger(name string, logger Logger) {
 	}
 	loggers[name] = logger
 }
+
+// SnapshotGlobalLoggers saves the loggers in the global log holder
+// and returns the function that restores them.
+// Loggers set after the snapshot are removed by the restore.
+// Use this to undo the loggers set while creating resources that failed.
+func SnapshotGlobalLoggers() (restore func()) {
+	mu.RLock()
+	saved := maps.Clone(loggers)
+	mu.RUnlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		loggers = maps.Clone(saved)
+	}
+}
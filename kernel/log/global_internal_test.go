@@ -297,3 +297,17 @@ func TestGlobalLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotGlobalLoggers(t *testing.T) {
+	defaultLg := GlobalLogger(DefaultLoggerName)
+	restore := SnapshotGlobalLoggers()
+
+	lg := &testLogger{Logger: NewJSONSLogger(os.Stdout, nil), id: "test"}
+	SetGlobalLogger(DefaultLoggerName, lg)
+	SetGlobalLogger("snapshot", lg)
+	testutil.Diff(t, true, GlobalLogger(DefaultLoggerName) == lg)
+
+	restore()
+	testutil.Diff(t, true, GlobalLogger(DefaultLoggerName) == defaultLg)
+	testutil.Diff(t, nil, GlobalLogger("snapshot"))
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aileron-projects/go/zerrors"
+)
+
+// Provider provides secret values.
+// Providers are registered with their names by RegisterProvider
+// and referred from configs as "${<name>:<ref>}".
+type Provider interface {
+	// Secret returns the secret value referred by the ref.
+	// Returned errors must not contain the secret value.
+	Secret(ctx context.Context, ref string) ([]byte, error)
+}
+
+// ProviderFunc is the function that implements the Provider interface.
+type ProviderFunc func(ctx context.Context, ref string) ([]byte, error)
+
+func (f ProviderFunc) Secret(ctx context.Context, ref string) ([]byte, error) {
+	return f(ctx, ref)
+}
+
+var (
+	// nameRe is the pattern of provider names.
+	nameRe = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	// refRe is the pattern of secret references.
+	// Submatches are the provider name, the ref and the options.
+	refRe = regexp.MustCompile(`\$\{([a-z][a-z0-9]*):([^|{}]+)((?:\|[a-z0-9]+)*)\}`)
+)
+
+var (
+	// mu protects providers.
+	mu = sync.RWMutex{}
+	// providers is the registered providers keyed by their names.
+	providers = map[string]Provider{
+		"env":  ProviderFunc(envSecret),
+		"file": ProviderFunc(fileSecret),
+	}
+)
+
+// RegisterProvider registers the provider with the name.
+// The name must match the pattern "^[a-z][a-z0-9]*$".
+// Registered provider is replaced when the same name was given.
+// The provider is unregistered when nil was given.
+// Built-in providers are "env" and "file".
+func RegisterProvider(name string, p Provider) error {
+	if !nameRe.MatchString(name) {
+		return zerrors.NewErr(nil, "kernel/secret: invalid provider name.", "name=%s", name)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if p == nil {
+		delete(providers, name)
+	} else {
+		providers[name] = p
+	}
+	return nil
+}
+
+// provider returns the provider registered with the name.
+func provider(name string) (Provider, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := providers[name]
+	return p, ok
+}
+
+// Reference is a secret reference in the format of
+// "${<provider>:<ref>|<option>|<option>...}".
+type Reference struct {
+	// Raw is the reference as written in configs.
+	Raw string
+	// Provider is the name of the provider.
+	Provider string
+	// Ref is the provider specific reference to the secret.
+	// For example, environmental variable name for "env"
+	// and file path for "file" provider.
+	Ref string
+	// Options are applied to the secret value in the order.
+	//   - trim: trim leading and trailing white spaces including new lines.
+	//   - base64: encode the value with standard base64 encoding.
+	//   - base64decode: decode the value with standard base64 encoding.
+	Options []string
+}
+
+// FindReferences returns the secret references found in the b.
+// Only the references of registered providers are returned
+// so that other expressions such as environmental variables
+// written as "${FOO:-default}" are not considered as references.
+func FindReferences(b []byte) []*Reference {
+	var refs []*Reference
+	for _, m := range refRe.FindAllSubmatch(b, -1) {
+		if _, ok := provider(string(m[1])); !ok {
+			continue
+		}
+		ref := &Reference{
+			Raw:      string(m[0]),
+			Provider: string(m[1]),
+			Ref:      strings.TrimSpace(string(m[2])),
+		}
+		if len(m[3]) > 0 {
+			ref.Options = strings.Split(string(m[3][1:]), "|")
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Check checks that the provider of the ref is registered
+// and the options of the ref are known ones.
+// The secret value is not read.
+func Check(ref *Reference) error {
+	if _, ok := provider(ref.Provider); !ok {
+		return zerrors.NewErr(nil, "kernel/secret: secret provider not found.", "provider=%s", ref.Provider)
+	}
+	return checkOptions(ref)
+}
+
+// checkOptions returns an error when the ref has unknown options.
+func checkOptions(ref *Reference) error {
+	for _, opt := range ref.Options {
+		switch opt {
+		case "trim", "base64", "base64decode":
+		default:
+			return zerrors.NewErr(nil, "kernel/secret: unknown secret option.", "reference=%s option=%s", ref.Raw, opt)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the secret value referred by the ref.
+// Options of the reference are applied to the value.
+// Errors do not contain the secret value.
+func Resolve(ctx context.Context, ref *Reference) (string, error) {
+	p, ok := provider(ref.Provider)
+	if !ok {
+		return "", zerrors.NewErr(nil, "kernel/secret: secret provider not found.", "provider=%s", ref.Provider)
+	}
+	if err := checkOptions(ref); err != nil {
+		return "", err
+	}
+	v, err := p.Secret(ctx, ref.Ref)
+	if err != nil {
+		return "", zerrors.NewErr(err, "kernel/secret: resolving secret failed.", "reference=%s", ref.Raw)
+	}
+	for _, opt := range ref.Options {
+		switch opt {
+		case "trim":
+			v = []byte(strings.TrimSpace(string(v)))
+		case "base64":
+			v = []byte(base64.StdEncoding.EncodeToString(v))
+		case "base64decode":
+			v, err = base64.StdEncoding.DecodeString(string(v))
+			if err != nil {
+				return "", zerrors.NewErr(nil, "kernel/secret: decoding secret failed.", "reference=%s", ref.Raw)
+			}
+		}
+	}
+	return string(v), nil
+}
+
+// envSecret returns the value of the environmental variable.
+// An error is returned when the variable is not set.
+func envSecret(_ context.Context, ref string) ([]byte, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return nil, zerrors.NewErr(nil, "kernel/secret: environmental variable not set.", "name=%s", ref)
+	}
+	return []byte(v), nil
+}
+
+// fileSecret returns the content of the file.
+func fileSecret(_ context.Context, ref string) ([]byte, error) {
+	return os.ReadFile(ref)
+}
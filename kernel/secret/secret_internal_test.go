@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/aileron-gateway/aileron-gateway/kernel/testutil"
+	"github.com/aileron-projects/go/zerrors"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRegisterProvider(t *testing.T) {
+	p := ProviderFunc(func(_ context.Context, ref string) ([]byte, error) {
+		return []byte("value of " + ref), nil
+	})
+
+	err := RegisterProvider("Invalid-Name", p)
+	testutil.DiffError(t, &zerrors.Err{Message: "kernel/secret: invalid provider name."}, nil, err, cmpopts.EquateErrors())
+
+	testutil.Diff(t, nil, RegisterProvider("test1", p))
+	v, err := Resolve(context.Background(), &Reference{Raw: "${test1:foo}", Provider: "test1", Ref: "foo"})
+	testutil.Diff(t, nil, err)
+	testutil.Diff(t, "value of foo", v)
+	testutil.Diff(t, 1, len(FindReferences([]byte("${test1:foo}"))))
+
+	testutil.Diff(t, nil, RegisterProvider("test1", nil))
+	_, ok := provider("test1")
+	testutil.Diff(t, false, ok)
+	testutil.Diff(t, 0, len(FindReferences([]byte("${test1:foo}"))))
+}
+
+func TestFindReferences(t *testing.T) {
+	type condition struct {
+		in string
+	}
+
+	type action struct {
+		refs []*Reference
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"no references",
+			&condition{
+				in: "password: foo",
+			},
+			&action{
+				refs: nil,
+			},
+		),
+		gen(
+			"file reference",
+			&condition{
+				in: "password: ${file:/run/secrets/password}",
+			},
+			&action{
+				refs: []*Reference{
+					{Raw: "${file:/run/secrets/password}", Provider: "file", Ref: "/run/secrets/password"},
+				},
+			},
+		),
+		gen(
+			"references with options",
+			&condition{
+				in: "a: ${env:FOO|trim}\nb: \"${file:./key.bin|trim|base64}\"",
+			},
+			&action{
+				refs: []*Reference{
+					{Raw: "${env:FOO|trim}", Provider: "env", Ref: "FOO", Options: []string{"trim"}},
+					{Raw: "${file:./key.bin|trim|base64}", Provider: "file", Ref: "./key.bin", Options: []string{"trim", "base64"}},
+				},
+			},
+		),
+		gen(
+			"environmental variables are not references",
+			&condition{
+				in: "a: ${FOO}\nb: ${FOO:-default}\nc: ${FOO:1:2}",
+			},
+			&action{
+				refs: nil,
+			},
+		),
+		gen(
+			"unregistered provider",
+			&condition{
+				in: "a: ${vault:secret/data/foo}",
+			},
+			&action{
+				refs: nil,
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			refs := FindReferences([]byte(tt.C.in))
+			testutil.Diff(t, tt.A.refs, refs)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	type condition struct {
+		ref *Reference
+	}
+
+	type action struct {
+		value      string
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "secret.txt")
+	_ = os.WriteFile(file, []byte("  secret\n"), 0o600)
+	t.Setenv("TEST_SECRET", "c2VjcmV0")
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"env",
+			&condition{
+				ref: &Reference{Raw: "${env:TEST_SECRET}", Provider: "env", Ref: "TEST_SECRET"},
+			},
+			&action{
+				value: "c2VjcmV0",
+			},
+		),
+		gen(
+			"env base64decode",
+			&condition{
+				ref: &Reference{Raw: "${env:TEST_SECRET|base64decode}", Provider: "env", Ref: "TEST_SECRET", Options: []string{"base64decode"}},
+			},
+			&action{
+				value: "secret",
+			},
+		),
+		gen(
+			"env not set",
+			&condition{
+				ref: &Reference{Raw: "${env:TEST_NOT_SET}", Provider: "env", Ref: "TEST_NOT_SET"},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: resolving secret failed."},
+				errPattern: regexp.MustCompile(`environmental variable not set`),
+			},
+		),
+		gen(
+			"file",
+			&condition{
+				ref: &Reference{Raw: "${file:" + file + "}", Provider: "file", Ref: file},
+			},
+			&action{
+				value: "  secret\n",
+			},
+		),
+		gen(
+			"file trim and base64",
+			&condition{
+				ref: &Reference{Raw: "${file:" + file + "|trim|base64}", Provider: "file", Ref: file, Options: []string{"trim", "base64"}},
+			},
+			&action{
+				value: "c2VjcmV0",
+			},
+		),
+		gen(
+			"file not found",
+			&condition{
+				ref: &Reference{Raw: "${file:not-exist.txt}", Provider: "file", Ref: "not-exist.txt"},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: resolving secret failed."},
+				errPattern: regexp.MustCompile(`not-exist.txt`),
+			},
+		),
+		gen(
+			"invalid base64",
+			&condition{
+				ref: &Reference{Raw: "${file:" + file + "|base64decode}", Provider: "file", Ref: file, Options: []string{"base64decode"}},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: decoding secret failed."},
+				errPattern: regexp.MustCompile(`base64decode`),
+			},
+		),
+		gen(
+			"unknown option",
+			&condition{
+				ref: &Reference{Raw: "${file:" + file + "|upper}", Provider: "file", Ref: file, Options: []string{"upper"}},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: unknown secret option."},
+				errPattern: regexp.MustCompile(`option=upper`),
+			},
+		),
+		gen(
+			"provider not found",
+			&condition{
+				ref: &Reference{Raw: "${vault:foo}", Provider: "vault", Ref: "foo"},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: secret provider not found."},
+				errPattern: regexp.MustCompile(`provider=vault`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			v, err := Resolve(context.Background(), tt.C.ref)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err, cmpopts.EquateErrors())
+			testutil.Diff(t, tt.A.value, v)
+			if err != nil {
+				// Errors must not contain the secret.
+				testutil.Diff(t, false, regexp.MustCompile(`c2VjcmV0|secret\n`).MatchString(err.Error()))
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	type condition struct {
+		ref *Reference
+	}
+
+	type action struct {
+		err        any // error or errorutil.Kind
+		errPattern *regexp.Regexp
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"env not set",
+			&condition{
+				ref: &Reference{Raw: "${env:TEST_NOT_SET}", Provider: "env", Ref: "TEST_NOT_SET"},
+			},
+			&action{},
+		),
+		gen(
+			"file not found with options",
+			&condition{
+				ref: &Reference{Raw: "${file:not-exist.txt|trim|base64}", Provider: "file", Ref: "not-exist.txt", Options: []string{"trim", "base64"}},
+			},
+			&action{},
+		),
+		gen(
+			"unknown option",
+			&condition{
+				ref: &Reference{Raw: "${file:not-exist.txt|upper}", Provider: "file", Ref: "not-exist.txt", Options: []string{"upper"}},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: unknown secret option."},
+				errPattern: regexp.MustCompile(`option=upper`),
+			},
+		),
+		gen(
+			"provider not found",
+			&condition{
+				ref: &Reference{Raw: "${vault:foo}", Provider: "vault", Ref: "foo"},
+			},
+			&action{
+				err:        &zerrors.Err{Message: "kernel/secret: secret provider not found."},
+				errPattern: regexp.MustCompile(`provider=vault`),
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := Check(tt.C.ref)
+			testutil.DiffError(t, tt.A.err, tt.A.errPattern, err, cmpopts.EquateErrors())
+		})
+	}
+}
+
+func TestProviderFunc(t *testing.T) {
+	testErr := errors.New("test error")
+	p := ProviderFunc(func(_ context.Context, _ string) ([]byte, error) {
+		return nil, testErr
+	})
+	_, err := p.Secret(context.Background(), "foo")
+	testutil.Diff(t, testErr, err, cmpopts.EquateErrors())
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright The AILERON Gateway Authors
+
+//go:build wasip1
+
+// This is a WasmMiddleware plugin for unit tests.
+// Build with the command below.
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o plugin.wasm .
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const (
+	kindRequest  = 0
+	kindResponse = 1
+)
+
+//go:wasmimport aileron config_get
+func configGet(buf unsafe.Pointer, bufLen int32) int32
+
+//go:wasmimport aileron log
+func logMessage(level int32, msg unsafe.Pointer, msgLen int32)
+
+//go:wasmimport aileron request_get
+func requestGet(field int32, buf unsafe.Pointer, bufLen int32) int32
+
+//go:wasmimport aileron header_get
+func headerGet(kind int32, name unsafe.Pointer, nameLen int32, buf unsafe.Pointer, bufLen int32) int32
+
+//go:wasmimport aileron header_names
+func headerNames(kind int32, buf unsafe.Pointer, bufLen int32) int32
+
+//go:wasmimport aileron header_set
+func headerSet(kind int32, name unsafe.Pointer, nameLen int32, value unsafe.Pointer, valueLen int32)
+
+//go:wasmimport aileron header_add
+func headerAdd(kind int32, name unsafe.Pointer, nameLen int32, value unsafe.Pointer, valueLen int32)
+
+//go:wasmimport aileron header_del
+func headerDel(kind int32, name unsafe.Pointer, nameLen int32)
+
+//go:wasmimport aileron body_get
+func bodyGet(kind int32, buf unsafe.Pointer, bufLen int32) int32
+
+//go:wasmimport aileron body_set
+func bodySet(kind int32, body unsafe.Pointer, bodyLen int32)
+
+//go:wasmimport aileron status_get
+func statusGet() int32
+
+//go:wasmimport aileron status_set
+func statusSet(code int32)
+
+var config string
+
+func ptr(s string) (unsafe.Pointer, int32) {
+	if s == "" {
+		return nil, 0
+	}
+	return unsafe.Pointer(unsafe.StringData(s)), int32(len(s))
+}
+
+// read calls the getter with a buffer and retries with
+// a larger buffer when the buffer was too small.
+func read(get func(unsafe.Pointer, int32) int32) (string, int32) {
+	buf := make([]byte, 16)
+	for {
+		n := get(unsafe.Pointer(&buf[0]), int32(len(buf)))
+		if n < 0 {
+			return "", n
+		}
+		if int(n) <= len(buf) {
+			return string(buf[:n]), n
+		}
+		buf = make([]byte, n)
+	}
+}
+
+func getHeader(kind int32, name string) (string, bool) {
+	np, nl := ptr(name)
+	v, n := read(func(p unsafe.Pointer, l int32) int32 { return headerGet(kind, np, nl, p, l) })
+	return v, n >= 0
+}
+
+func setHeader(kind int32, name, value string) {
+	np, nl := ptr(name)
+	vp, vl := ptr(value)
+	headerSet(kind, np, nl, vp, vl)
+}
+
+func addHeader(kind int32, name, value string) {
+	np, nl := ptr(name)
+	vp, vl := ptr(value)
+	headerAdd(kind, np, nl, vp, vl)
+}
+
+func setBody(kind int32, body string) {
+	p, l := ptr(body)
+	bodySet(kind, p, l)
+}
+
+//go:wasmexport aileron_on_configure
+func onConfigure() int32 {
+	config, _ = read(configGet)
+	if config == "fail" {
+		return 1
+	}
+	return 0
+}
+
+//go:wasmexport aileron_on_request
+func onRequest() int32 {
+	msg := "on_request"
+	p, l := ptr(msg)
+	logMessage(0, p, l)
+
+	if _, ok := getHeader(kindRequest, "X-Trap"); ok {
+		panic("trap")
+	}
+	if _, ok := getHeader(kindRequest, "X-Deny"); ok {
+		statusSet(403)
+		setHeader(kindResponse, "X-Plugin", "denied")
+		setBody(kindResponse, "denied: "+config)
+		return 1
+	}
+
+	method, _ := read(func(p unsafe.Pointer, l int32) int32 { return requestGet(0, p, l) })
+	path, _ := read(func(p unsafe.Pointer, l int32) int32 { return requestGet(1, p, l) })
+	query, _ := read(func(p unsafe.Pointer, l int32) int32 { return requestGet(2, p, l) })
+	setHeader(kindRequest, "X-Request", method+" "+path+"?"+query)
+	addHeader(kindRequest, "X-Added", "1")
+	addHeader(kindRequest, "X-Added", "2")
+	np, nl := ptr("X-Remove")
+	headerDel(kindRequest, np, nl)
+	names, _ := read(func(p unsafe.Pointer, l int32) int32 { return headerNames(kindRequest, p, l) })
+	setHeader(kindRequest, "X-Names", strings.ReplaceAll(names, "\n", ","))
+
+	body, n := read(func(p unsafe.Pointer, l int32) int32 { return bodyGet(kindRequest, p, l) })
+	switch {
+	case n == -2:
+		setHeader(kindRequest, "X-Body-Too-Large", "true")
+	case body != "":
+		setBody(kindRequest, strings.ToUpper(body))
+	}
+
+	setHeader(kindResponse, "X-Config", config)
+	return 0
+}
+
+//go:wasmexport aileron_on_response
+func onResponse() int32 {
+	status := statusGet()
+	if status == 404 {
+		statusSet(200)
+		setBody(kindResponse, "replaced")
+		return 0
+	}
+	if status == 500 {
+		return 1
+	}
+	body, _ := read(func(p unsafe.Pointer, l int32) int32 { return bodyGet(kindResponse, p, l) })
+	setBody(kindResponse, body+"!")
+	setHeader(kindResponse, "X-Status", strconv.Itoa(int(status)))
+	return 0
+}
+
+func main() {}
@@ -0,0 +1,14 @@
+{
+  server(name, addr, hosts):: {
+    apiVersion: 'core/v1',
+    kind: 'HTTPServer',
+    metadata: { name: name },
+    spec: {
+      addr: addr,
+      virtualHosts: [{
+        hosts: hosts,
+        handlers: [{ handler: { apiVersion: 'core/v1', kind: 'TemplateHandler' } }],
+      }],
+    },
+  },
+}
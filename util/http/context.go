@@ -90,3 +90,39 @@ func PreProxyHookFromContext(ctx context.Context) []func(r *http.Request) error
 	}
 	return nil
 }
+
+type hostParams struct{}
+
+var hostParamsContextKey = hostParams{}
+
+// ContextWithHostParams saves the parameters captured from the
+// request host by the virtual host matching in the context.
+// context.Background() will be used if nil context was given.
+// The given context is returned as-is if the params is empty.
+//
+//	ctx := r.Context()
+//	ctx = ContextWithHostParams(ctx, map[string]string{"wildcard": "foo"})
+//	r = r.WithContext(ctx)
+func ContextWithHostParams(ctx context.Context, params map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(params) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, hostParamsContextKey, params)
+}
+
+// HostParamsFromContext returns the parameters captured from the request host.
+// nil will be returned if no params were found in the context
+// or nil context was given.
+// The returned map must not be modified.
+func HostParamsFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	if v := ctx.Value(hostParamsContextKey); v != nil {
+		return v.(map[string]string)
+	}
+	return nil
+}
@@ -258,3 +258,107 @@ func TestPreProxyHookFromContext(t *testing.T) {
 		})
 	}
 }
+
+func TestContextWithHostParams(t *testing.T) {
+	type condition struct {
+		ctx    context.Context
+		params map[string]string
+	}
+
+	type action struct {
+		params map[string]string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil context", &condition{
+				ctx:    nil,
+				params: map[string]string{"wildcard": "foo"},
+			},
+			&action{
+				params: map[string]string{"wildcard": "foo"},
+			},
+		),
+		gen(
+			"nil params", &condition{
+				ctx:    context.Background(),
+				params: nil,
+			},
+			&action{
+				params: nil,
+			},
+		),
+		gen(
+			"empty params", &condition{
+				ctx:    context.Background(),
+				params: map[string]string{},
+			},
+			&action{
+				params: nil,
+			},
+		),
+		gen(
+			"non empty params", &condition{
+				ctx:    context.Background(),
+				params: map[string]string{"1": "foo", "tenant": "foo"},
+			},
+			&action{
+				params: map[string]string{"1": "foo", "tenant": "foo"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctx := ContextWithHostParams(tt.C.ctx, tt.C.params)
+			params, _ := ctx.Value(hostParamsContextKey).(map[string]string)
+			testutil.Diff(t, tt.A.params, params)
+		})
+	}
+}
+
+func TestHostParamsFromContext(t *testing.T) {
+	type condition struct {
+		ctx context.Context
+	}
+
+	type action struct {
+		params map[string]string
+	}
+
+	gen := testutil.NewCase[*condition, *action]
+	testCases := []*testutil.Case[*condition, *action]{
+		gen(
+			"nil context", &condition{
+				ctx: nil,
+			},
+			&action{
+				params: nil,
+			},
+		),
+		gen(
+			"empty context", &condition{
+				ctx: context.Background(),
+			},
+			&action{
+				params: nil,
+			},
+		),
+		gen(
+			"context with params", &condition{
+				ctx: context.WithValue(context.Background(), hostParamsContextKey, map[string]string{"wildcard": "foo"}),
+			},
+			&action{
+				params: map[string]string{"wildcard": "foo"},
+			},
+		),
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			params := HostParamsFromContext(tt.C.ctx)
+			testutil.Diff(t, tt.A.params, params)
+		})
+	}
+}
@@ -5,6 +5,7 @@ package http
 
 import (
 	"cmp"
+	"maps"
 	"math"
 	"mime"
 	"net/http"
@@ -118,8 +119,8 @@ func GlobalErrorHandler(name string) core.ErrorHandler {
 //	eh = <error handler you want to use>
 //	http.SetGlobalErrorHandler(http.DefaultErrorHandlerName, eh)
 func SetGlobalErrorHandler(name string, handler core.ErrorHandler) {
-	mu.RLock()
-	defer mu.RUnlock()
+	mu.Lock()
+	defer mu.Unlock()
 	if handler == nil {
 		if name != DefaultErrorHandlerName {
 			delete(handlers, name)
@@ -129,6 +130,21 @@ func SetGlobalErrorHandler(name string, handler core.ErrorHandler) {
 	handlers[name] = handler
 }
 
+// SnapshotGlobalErrorHandlers saves the error handlers in the global error handler holder
+// and returns the function that restores them.
+// Error handlers set after the snapshot are removed by the restore.
+// Use this to undo the error handlers set while creating resources that failed.
+func SnapshotGlobalErrorHandlers() (restore func()) {
+	mu.RLock()
+	saved := maps.Clone(handlers)
+	mu.RUnlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		handlers = maps.Clone(saved)
+	}
+}
+
 // ErrorHandler returns a error handler by getting it from the given api.
 // The default error handler will be returned when a nil reference was given by the
 // second argument ref.
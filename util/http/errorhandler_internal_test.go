@@ -1054,3 +1054,17 @@ func testResourceRef(name string) *k.Reference {
 		Name:       name,
 	}
 }
+
+func TestSnapshotGlobalErrorHandlers(t *testing.T) {
+	defaultEH := GlobalErrorHandler(DefaultErrorHandlerName)
+	restore := SnapshotGlobalErrorHandlers()
+
+	eh := &testErrorHandler{id: "test"}
+	SetGlobalErrorHandler(DefaultErrorHandlerName, eh)
+	SetGlobalErrorHandler("snapshot", eh)
+	testutil.Diff(t, true, GlobalErrorHandler(DefaultErrorHandlerName) == eh)
+
+	restore()
+	testutil.Diff(t, true, GlobalErrorHandler(DefaultErrorHandlerName) == defaultEH)
+	testutil.Diff(t, nil, GlobalErrorHandler("snapshot"))
+}
@@ -4,31 +4,41 @@
 package register
 
 import (
+	"slices"
+
 	"github.com/aileron-gateway/aileron-gateway/app/authn/authn"
 	"github.com/aileron-gateway/aileron-gateway/app/authn/basic"
 	"github.com/aileron-gateway/aileron-gateway/app/authn/digest"
 	"github.com/aileron-gateway/aileron-gateway/app/authn/idkey"
 	"github.com/aileron-gateway/aileron-gateway/app/authn/key"
 	"github.com/aileron-gateway/aileron-gateway/app/authn/oauth"
+	"github.com/aileron-gateway/aileron-gateway/app/extauthz"
 	"github.com/aileron-gateway/aileron-gateway/app/handler/echo"
 	"github.com/aileron-gateway/aileron-gateway/app/handler/healthcheck"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/bodylimit"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/compression"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/cors"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/csrf"
+	"github.com/aileron-gateway/aileron-gateway/app/middleware/extproc"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/header"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/headercert"
+	"github.com/aileron-gateway/aileron-gateway/app/middleware/maintenance"
+	"github.com/aileron-gateway/aileron-gateway/app/middleware/script"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/session"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/soaprest"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/throttle"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/timeout"
 	"github.com/aileron-gateway/aileron-gateway/app/middleware/tracking"
+	"github.com/aileron-gateway/aileron-gateway/app/middleware/wasm"
+	"github.com/aileron-gateway/aileron-gateway/app/middleware/xfcc"
 	"github.com/aileron-gateway/aileron-gateway/app/opa"
 	"github.com/aileron-gateway/aileron-gateway/app/otelmeter"
 	"github.com/aileron-gateway/aileron-gateway/app/oteltracer"
 	"github.com/aileron-gateway/aileron-gateway/app/prommeter"
 	"github.com/aileron-gateway/aileron-gateway/app/skipper"
 	"github.com/aileron-gateway/aileron-gateway/app/storage/redis"
+	"github.com/aileron-gateway/aileron-gateway/core/acme"
+	"github.com/aileron-gateway/aileron-gateway/core/admin"
 	"github.com/aileron-gateway/aileron-gateway/core/entrypoint"
 	"github.com/aileron-gateway/aileron-gateway/core/errhandler"
 	"github.com/aileron-gateway/aileron-gateway/core/goplugin"
@@ -48,6 +58,8 @@ type Registerer interface {
 }
 
 func RegisterAll(r Registerer) {
+	_ = r.Register(acme.Key, acme.Resource)
+	_ = r.Register(admin.Key, admin.Resource)
 	_ = r.Register(entrypoint.Key, entrypoint.Resource)
 	_ = r.Register(errhandler.Key, errhandler.Resource)
 	_ = r.Register(goplugin.Key, goplugin.Resource)
@@ -68,21 +80,44 @@ func RegisterAll(r Registerer) {
 	_ = r.Register(csrf.Key, csrf.Resource)
 	_ = r.Register(digest.Key, digest.Resource)
 	_ = r.Register(echo.Key, echo.Resource)
+	_ = r.Register(extauthz.Key, extauthz.Resource)
+	_ = r.Register(extproc.Key, extproc.Resource)
 	_ = r.Register(header.Key, header.Resource)
 	_ = r.Register(headercert.Key, headercert.Resource)
 	_ = r.Register(healthcheck.Key, healthcheck.Resource)
 	_ = r.Register(idkey.Key, idkey.Resource)
 	_ = r.Register(key.Key, key.Resource)
+	_ = r.Register(maintenance.Key, maintenance.Resource)
 	_ = r.Register(oauth.Key, oauth.Resource)
 	_ = r.Register(opa.Key, opa.Resource)
 	_ = r.Register(otelmeter.Key, otelmeter.Resource)
 	_ = r.Register(oteltracer.Key, oteltracer.Resource)
 	_ = r.Register(prommeter.Key, prommeter.Resource)
 	_ = r.Register(redis.Key, redis.Resource)
+	_ = r.Register(script.Key, script.Resource)
 	_ = r.Register(session.Key, session.Resource)
 	_ = r.Register(skipper.Key, skipper.Resource)
 	_ = r.Register(soaprest.Key, soaprest.Resource)
 	_ = r.Register(throttle.Key, throttle.Resource)
 	_ = r.Register(timeout.Key, timeout.Resource)
 	_ = r.Register(tracking.Key, tracking.Resource)
+	_ = r.Register(wasm.Key, wasm.Resource)
+	_ = r.Register(xfcc.Key, xfcc.Resource)
+}
+
+// keyRecorder records the keys of registered resources.
+type keyRecorder []string
+
+func (r *keyRecorder) Register(key string, _ api.Resource) error {
+	*r = append(*r, key)
+	return nil
+}
+
+// Keys returns the keys of all resources registered by the RegisterAll.
+// Keys are in the format of "APIGroup/APIVersion/Kind" and sorted.
+func Keys() []string {
+	var r keyRecorder
+	RegisterAll(&r)
+	slices.Sort(r)
+	return r
 }